@@ -0,0 +1,13 @@
+package admissionplugin_test
+
+import (
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+
+	"testing"
+)
+
+func TestAdmissionplugin(t *testing.T) {
+	RegisterFailHandler(Fail)
+	RunSpecs(t, "Admissionplugin Suite")
+}
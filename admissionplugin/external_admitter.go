@@ -0,0 +1,94 @@
+package admissionplugin
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os/exec"
+
+	"code.cloudfoundry.org/garden"
+	"code.cloudfoundry.org/guardian/tracing"
+	"code.cloudfoundry.org/lager"
+	"github.com/cloudfoundry/gunk/command_runner"
+)
+
+// RejectedError is returned when the admission plugin refuses a spec
+type RejectedError struct {
+	Reason string
+}
+
+func (e RejectedError) Error() string {
+	return fmt.Sprintf("container spec rejected by admission plugin: %s", e.Reason)
+}
+
+// AdmitOutputs is what the plugin writes to stdout. If Spec is omitted the
+// spec is admitted unchanged.
+type AdmitOutputs struct {
+	Allowed bool                  `json:"allowed"`
+	Reason  string                `json:"reason,omitempty"`
+	Spec    *garden.ContainerSpec `json:"spec,omitempty"`
+}
+
+func New(binPath string, extraArgs []string, commandRunner command_runner.CommandRunner) *ExternalAdmitter {
+	return &ExternalAdmitter{
+		binPath:       binPath,
+		extraArgs:     extraArgs,
+		commandRunner: commandRunner,
+	}
+}
+
+// ExternalAdmitter runs `<bin> [extra args] --action admit --handle <handle>`
+// with the JSON encoded garden.ContainerSpec on stdin
+type ExternalAdmitter struct {
+	binPath       string
+	extraArgs     []string
+	commandRunner command_runner.CommandRunner
+}
+
+func (a *ExternalAdmitter) Admit(log lager.Logger, spec garden.ContainerSpec) (garden.ContainerSpec, error) {
+	log = log.Session("admission-plugin-admit", lager.Data{"handle": spec.Handle})
+	log.Debug("start")
+	defer log.Debug("end")
+
+	stdin, err := json.Marshal(spec)
+	if err != nil {
+		return garden.ContainerSpec{}, fmt.Errorf("marshal spec: %s", err)
+	}
+
+	args := append([]string{}, a.extraArgs...)
+	args = append(args, "--action", "admit", "--handle", spec.Handle)
+
+	cmd := exec.Command(a.binPath, args...)
+	cmd.Stdin = bytes.NewReader(stdin)
+	outBuffer := bytes.NewBuffer([]byte{})
+	cmd.Stdout = outBuffer
+	errBuffer := bytes.NewBuffer([]byte{})
+	cmd.Stderr = errBuffer
+	tracing.SetEnv(log, cmd)
+
+	err = a.commandRunner.Run(cmd)
+
+	logData := lager.Data{"action": "admit", "stderr": errBuffer.String(), "stdout": outBuffer.String()}
+	if err != nil {
+		log.Error("external-admission-plugin-result", err, logData)
+		return garden.ContainerSpec{}, fmt.Errorf("external admission plugin admit failed: %s", err)
+	}
+
+	var outputs AdmitOutputs
+	if err := json.Unmarshal(outBuffer.Bytes(), &outputs); err != nil {
+		log.Error("external-admission-plugin-result", err, logData)
+		return garden.ContainerSpec{}, fmt.Errorf("unmarshaling result from external admission plugin: %s", err)
+	}
+
+	if !outputs.Allowed {
+		log.Info("rejected", lager.Data{"reason": outputs.Reason})
+		return garden.ContainerSpec{}, RejectedError{Reason: outputs.Reason}
+	}
+
+	if outputs.Spec == nil {
+		return spec, nil
+	}
+
+	log.Info("mutated")
+	return *outputs.Spec, nil
+}
@@ -0,0 +1,141 @@
+package admissionplugin_test
+
+import (
+	"encoding/json"
+	"errors"
+	"io/ioutil"
+	"os/exec"
+
+	"code.cloudfoundry.org/garden"
+	"code.cloudfoundry.org/guardian/admissionplugin"
+	"code.cloudfoundry.org/guardian/tracing"
+	"code.cloudfoundry.org/lager"
+	"code.cloudfoundry.org/lager/lagertest"
+	"github.com/cloudfoundry/gunk/command_runner/fake_command_runner"
+	. "github.com/cloudfoundry/gunk/command_runner/fake_command_runner/matchers"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("ExternalAdmitter", func() {
+	var (
+		fakeCommandRunner *fake_command_runner.FakeCommandRunner
+		logger            lager.Logger
+		admitter          *admissionplugin.ExternalAdmitter
+		spec              garden.ContainerSpec
+		pluginStdout      string
+		pluginStdin       []byte
+		pluginErr         error
+	)
+
+	BeforeEach(func() {
+		fakeCommandRunner = fake_command_runner.New()
+		logger = lagertest.NewTestLogger("test")
+		admitter = admissionplugin.New("/admission-plugin", []string{"--config", "/etc/policy.yml"}, fakeCommandRunner)
+
+		spec = garden.ContainerSpec{
+			Handle:     "some-handle",
+			Privileged: true,
+			Properties: garden.Properties{"owner": "alice"},
+		}
+		pluginStdout = `{"allowed": true}`
+		pluginStdin = nil
+		pluginErr = nil
+
+		fakeCommandRunner.WhenRunning(fake_command_runner.CommandSpec{
+			Path: "/admission-plugin",
+		}, func(cmd *exec.Cmd) error {
+			var err error
+			pluginStdin, err = ioutil.ReadAll(cmd.Stdin)
+			Expect(err).NotTo(HaveOccurred())
+
+			cmd.Stdout.Write([]byte(pluginStdout))
+			return pluginErr
+		})
+	})
+
+	It("runs the plugin with the extra args, action and handle", func() {
+		_, err := admitter.Admit(logger, spec)
+		Expect(err).NotTo(HaveOccurred())
+
+		Expect(fakeCommandRunner).To(HaveExecutedSerially(fake_command_runner.CommandSpec{
+			Path: "/admission-plugin",
+			Args: []string{"--config", "/etc/policy.yml", "--action", "admit", "--handle", "some-handle"},
+		}))
+	})
+
+	It("passes the spec as JSON on stdin", func() {
+		_, err := admitter.Admit(logger, spec)
+		Expect(err).NotTo(HaveOccurred())
+
+		var received garden.ContainerSpec
+		Expect(json.Unmarshal(pluginStdin, &received)).To(Succeed())
+		Expect(received).To(Equal(spec))
+	})
+
+	It("passes the trace id of the logger to the plugin", func() {
+		tracedLogger, _ := new(tracing.Tracer).Start(logger, "create")
+		_, err := admitter.Admit(tracedLogger, spec)
+		Expect(err).NotTo(HaveOccurred())
+
+		cmd := fakeCommandRunner.ExecutedCommands()[0]
+		Expect(tracing.FromEnv(cmd.Env)).To(Equal(tracing.TraceID(tracedLogger)))
+	})
+
+	Context("when the plugin allows the spec without changing it", func() {
+		It("returns the original spec", func() {
+			admitted, err := admitter.Admit(logger, spec)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(admitted).To(Equal(spec))
+		})
+	})
+
+	Context("when the plugin returns a changed spec", func() {
+		BeforeEach(func() {
+			mutated := spec
+			mutated.Privileged = false
+			mutated.Properties = garden.Properties{"owner": "alice", "tenant": "blue"}
+
+			encoded, err := json.Marshal(admissionplugin.AdmitOutputs{Allowed: true, Spec: &mutated})
+			Expect(err).NotTo(HaveOccurred())
+			pluginStdout = string(encoded)
+		})
+
+		It("returns the changed spec", func() {
+			admitted, err := admitter.Admit(logger, spec)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(admitted.Privileged).To(BeFalse())
+			Expect(admitted.Properties).To(HaveKeyWithValue("tenant", "blue"))
+		})
+	})
+
+	Context("when the plugin rejects the spec", func() {
+		BeforeEach(func() {
+			pluginStdout = `{"allowed": false, "reason": "privileged containers are not allowed"}`
+		})
+
+		It("returns a RejectedError with the reason", func() {
+			_, err := admitter.Admit(logger, spec)
+			Expect(err).To(Equal(admissionplugin.RejectedError{Reason: "privileged containers are not allowed"}))
+			Expect(err).To(MatchError("container spec rejected by admission plugin: privileged containers are not allowed"))
+		})
+	})
+
+	Context("when the plugin fails", func() {
+		It("returns an error", func() {
+			pluginErr = errors.New("exit status 1")
+
+			_, err := admitter.Admit(logger, spec)
+			Expect(err).To(MatchError("external admission plugin admit failed: exit status 1"))
+		})
+	})
+
+	Context("when the plugin writes invalid JSON", func() {
+		It("returns an error", func() {
+			pluginStdout = "not json"
+
+			_, err := admitter.Admit(logger, spec)
+			Expect(err).To(MatchError(ContainSubstring("unmarshaling result from external admission plugin")))
+		})
+	})
+})
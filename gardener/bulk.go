@@ -0,0 +1,198 @@
+package gardener
+
+import (
+	"fmt"
+	"sync"
+	"time"
+
+	"code.cloudfoundry.org/garden"
+	"code.cloudfoundry.org/lager"
+)
+
+// DefaultBulkWorkers is how many containers BulkInfo and BulkMetrics look at
+// concurrently when BulkWorkers is not set
+const DefaultBulkWorkers = 16
+
+// BulkTimeoutError is the entry for a container which took longer than
+// BulkTimeout to answer a bulk call
+type BulkTimeoutError struct {
+	Handle  string
+	Timeout time.Duration
+}
+
+func (e BulkTimeoutError) Error() string {
+	return fmt.Sprintf("container %s did not respond within %s", e.Handle, e.Timeout)
+}
+
+type bulkResult struct {
+	handle string
+	value  interface{}
+	err    error
+}
+
+func (g *Gardener) BulkInfo(handles []string) (map[string]garden.ContainerInfoEntry, error) {
+	results := g.bulk(handles, func(handle string) (interface{}, error) {
+		return g.lookup(handle).Info()
+	})
+
+	entries := make(map[string]garden.ContainerInfoEntry, len(results))
+	for handle, result := range results {
+		var entry garden.ContainerInfoEntry
+		if info, ok := result.value.(garden.ContainerInfo); ok {
+			entry.Info = info
+		}
+
+		if result.err != nil {
+			entry.Err = garden.NewError(result.err.Error())
+		}
+
+		entries[handle] = entry
+	}
+
+	return entries, nil
+}
+
+func (g *Gardener) BulkMetrics(handles []string) (map[string]garden.ContainerMetricsEntry, error) {
+	results := g.bulk(handles, func(handle string) (interface{}, error) {
+		return g.lookup(handle).Metrics()
+	})
+
+	entries := make(map[string]garden.ContainerMetricsEntry, len(results))
+	for handle, result := range results {
+		var entry garden.ContainerMetricsEntry
+		if metrics, ok := result.value.(garden.Metrics); ok {
+			entry.Metrics = metrics
+		}
+
+		if result.err != nil {
+			entry.Err = garden.NewError(result.err.Error())
+		}
+
+		entries[handle] = entry
+	}
+
+	return entries, nil
+}
+
+// bulk calls fn for every handle on a pool of BulkWorkers goroutines
+func (g *Gardener) bulk(handles []string, fn func(handle string) (interface{}, error)) map[string]bulkResult {
+	workers := g.bulkWorkers()
+	if workers > len(handles) {
+		workers = len(handles)
+	}
+
+	jobs := make(chan string)
+	results := make(chan bulkResult, len(handles))
+
+	var wg sync.WaitGroup
+	for i := 0; i < workers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+
+			for handle := range jobs {
+				results <- g.withBulkTimeout(handle, fn)
+			}
+		}()
+	}
+
+	for _, handle := range handles {
+		jobs <- handle
+	}
+	close(jobs)
+
+	wg.Wait()
+	close(results)
+
+	collected := make(map[string]bulkResult, len(handles))
+	for result := range results {
+		collected[result.handle] = result
+	}
+
+	return collected
+}
+
+// withBulkTimeout gives fn up to BulkTimeout to answer for the handle. A call
+// which times out is left to finish in the background so that one stuck
+// container does not hold up the rest of the response. Until it does, later
+// bulk calls report the container as timed out straight away rather than
+// piling more calls up behind it.
+func (g *Gardener) withBulkTimeout(handle string, fn func(handle string) (interface{}, error)) bulkResult {
+	if g.BulkTimeout <= 0 {
+		value, err := fn(handle)
+		return bulkResult{handle: handle, value: value, err: err}
+	}
+
+	call, stuck := g.startBulkCall(handle)
+	if stuck {
+		g.Logger.Info("bulk-container-still-stuck", lager.Data{"handle": handle})
+		return bulkResult{handle: handle, err: BulkTimeoutError{Handle: handle, Timeout: g.BulkTimeout}}
+	}
+
+	done := make(chan bulkResult, 1)
+	go func() {
+		value, err := fn(handle)
+		g.finishBulkCall(handle, call)
+		done <- bulkResult{handle: handle, value: value, err: err}
+	}()
+
+	select {
+	case result := <-done:
+		return result
+	case <-time.After(g.BulkTimeout):
+		g.markBulkCallStuck(handle, call)
+		return g.bulkTimedOut(handle)
+	}
+}
+
+type bulkCall struct {
+	finished bool
+}
+
+func (g *Gardener) startBulkCall(handle string) (*bulkCall, bool) {
+	g.bulkMu.Lock()
+	defer g.bulkMu.Unlock()
+
+	if _, stuck := g.stuckBulkCalls[handle]; stuck {
+		return nil, true
+	}
+
+	return &bulkCall{}, false
+}
+
+func (g *Gardener) finishBulkCall(handle string, call *bulkCall) {
+	g.bulkMu.Lock()
+	defer g.bulkMu.Unlock()
+
+	call.finished = true
+	if g.stuckBulkCalls[handle] == call {
+		delete(g.stuckBulkCalls, handle)
+	}
+}
+
+func (g *Gardener) markBulkCallStuck(handle string, call *bulkCall) {
+	g.bulkMu.Lock()
+	defer g.bulkMu.Unlock()
+
+	if call.finished {
+		return
+	}
+
+	if g.stuckBulkCalls == nil {
+		g.stuckBulkCalls = make(map[string]*bulkCall)
+	}
+	g.stuckBulkCalls[handle] = call
+}
+
+func (g *Gardener) bulkTimedOut(handle string) bulkResult {
+	g.Logger.Info("bulk-timeout", lager.Data{"handle": handle, "timeout": g.BulkTimeout.String()})
+	return bulkResult{handle: handle, err: BulkTimeoutError{Handle: handle, Timeout: g.BulkTimeout}}
+}
+
+func (g *Gardener) bulkWorkers() int {
+	if g.BulkWorkers <= 0 {
+		return DefaultBulkWorkers
+	}
+
+	return g.BulkWorkers
+}
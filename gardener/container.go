@@ -7,6 +7,7 @@ import (
 	"time"
 
 	"code.cloudfoundry.org/garden"
+	"code.cloudfoundry.org/guardian/tracing"
 	"code.cloudfoundry.org/lager"
 )
 
@@ -14,30 +15,85 @@ type container struct {
 	logger lager.Logger
 
 	handle          string
+	ownerProperty   string
 	containerizer   Containerizer
 	volumeCreator   VolumeCreator
 	networker       Networker
 	propertyManager PropertyManager
+	handles         *HandleRegistry
+	drainer         *drainer
+	events          EventLog
+	stages          StageRecorder
+	tracer          *tracing.Tracer
+	changeResources func(handle string, change func(*Resources)) (func(), error)
 }
 
+var _ Pausable = &container{}
+
 func (c *container) Handle() string {
 	return c.handle
 }
 
-func (c *container) Run(spec garden.ProcessSpec, io garden.ProcessIO) (garden.Process, error) {
-	return c.containerizer.Run(c.logger, c.handle, spec, io)
+// trace starts the trace of an API call on the container
+func (c *container) trace(operation string) (lager.Logger, *tracing.ActiveSpan) {
+	return c.tracer.Start(c.logger, operation, lager.Data{"handle": c.handle})
 }
 
-func (c *container) Attach(processID string, io garden.ProcessIO) (garden.Process, error) {
-	return c.containerizer.Attach(c.logger, c.handle, processID, io)
+func (c *container) Run(spec garden.ProcessSpec, io garden.ProcessIO) (_ garden.Process, err error) {
+	log, span := c.trace("run")
+	defer func() { span.End(err) }()
+
+	unlock := c.handles.RLock(c.handle)
+	defer unlock()
+
+	return c.containerizer.Run(log, c.handle, spec, io)
 }
 
-func (c *container) Stop(kill bool) error {
-	return c.containerizer.Stop(c.logger, c.handle, kill)
+func (c *container) Attach(processID string, io garden.ProcessIO) (_ garden.Process, err error) {
+	log, span := c.trace("attach")
+	defer func() { span.End(err) }()
+
+	unlock := c.handles.RLock(c.handle)
+	defer unlock()
+
+	return c.containerizer.Attach(log, c.handle, processID, io)
+}
+
+func (c *container) Stop(kill bool) (err error) {
+	log, span := c.trace("stop")
+	defer func() { span.End(err) }()
+
+	unlock := c.handles.RLock(c.handle)
+	defer unlock()
+
+	return c.containerizer.Stop(log, c.handle, kill)
+}
+
+// Pause freezes all the processes in the container
+func (c *container) Pause() (err error) {
+	log, span := c.trace("pause")
+	defer func() { span.End(err) }()
+
+	unlock := c.handles.Lock(c.handle)
+	defer unlock()
+
+	return c.containerizer.Pause(log, c.handle)
+}
+
+// Resume thaws the processes of a paused container
+func (c *container) Resume() (err error) {
+	log, span := c.trace("resume")
+	defer func() { span.End(err) }()
+
+	unlock := c.handles.Lock(c.handle)
+	defer unlock()
+
+	return c.containerizer.Resume(log, c.handle)
 }
 
-func (c *container) Info() (garden.ContainerInfo, error) {
-	log := c.logger.Session("info", lager.Data{"handle": c.handle})
+func (c *container) Info() (_ garden.ContainerInfo, err error) {
+	log, span := c.trace("info")
+	defer func() { span.End(err) }()
 
 	log.Debug("starting")
 	defer log.Debug("finished")
@@ -57,7 +113,7 @@ func (c *container) Info() (garden.ContainerInfo, error) {
 		return garden.ContainerInfo{}, fmt.Errorf("info: no property found: %s", ExternalIPKey)
 	}
 
-	actualContainerSpec, err := c.containerizer.Info(c.logger, c.handle)
+	actualContainerSpec, err := c.containerizer.Info(log, c.handle)
 	if err != nil {
 		return garden.ContainerInfo{}, err
 	}
@@ -73,6 +129,8 @@ func (c *container) Info() (garden.ContainerInfo, error) {
 	state := "active"
 	if actualContainerSpec.Stopped {
 		state = "stopped"
+	} else if actualContainerSpec.Paused {
+		state = "paused"
 	}
 
 	json.Unmarshal([]byte(mappedPortsCfg), &mappedPorts)
@@ -83,88 +141,339 @@ func (c *container) Info() (garden.ContainerInfo, error) {
 		ExternalIP:    externalIP,
 		ContainerPath: actualContainerSpec.BundlePath,
 		Events:        actualContainerSpec.Events,
-		Properties:    properties,
+		Properties:    userProperties(properties),
 		MappedPorts:   mappedPorts,
 	}, nil
 }
 
-func (c *container) StreamIn(spec garden.StreamInSpec) error {
-	return c.containerizer.StreamIn(c.logger, c.handle, spec)
+func (c *container) StreamIn(spec garden.StreamInSpec) (err error) {
+	defer c.drainer.start()()
+
+	log, span := c.trace("stream-in")
+	timer := NewStageTimer(c.stages, "stream-in")
+	defer func() {
+		log.Debug("finished", timer.Finish(err))
+		span.End(err)
+	}()
+
+	endStage := timer.Start("lock")
+	unlock := c.handles.RLock(c.handle)
+	endStage(nil)
+	defer unlock()
+
+	return timer.Time("containerizer-stream-in", func() error {
+		return c.containerizer.StreamIn(log, c.handle, spec)
+	})
 }
 
 func (c *container) StreamOut(spec garden.StreamOutSpec) (io.ReadCloser, error) {
-	return c.containerizer.StreamOut(c.logger, c.handle, spec)
+	done := c.drainer.start()
+
+	log, span := c.trace("stream-out")
+	timer := NewStageTimer(c.stages, "stream-out")
+
+	endStage := timer.Start("lock")
+	unlock := c.handles.RLock(c.handle)
+	endStage(nil)
+	defer unlock()
+
+	endStage = timer.Start("containerizer-stream-out")
+	stream, err := c.containerizer.StreamOut(log, c.handle, spec)
+	endStage(err)
+	if err != nil {
+		log.Debug("finished", timer.Finish(err))
+		span.End(err)
+		done()
+		return nil, err
+	}
+
+	// the stream out is in flight, and still being timed, until the client
+	// has read it
+	endTransfer := timer.Start("transfer")
+	return &trackedReadCloser{ReadCloser: stream, done: func() {
+		endTransfer(nil)
+		log.Debug("finished", timer.Finish(nil))
+		span.End(nil)
+		done()
+	}}, nil
 }
 
-func (c *container) LimitBandwidth(limits garden.BandwidthLimits) error {
-	return nil
+func (c *container) LimitBandwidth(limits garden.BandwidthLimits) (err error) {
+	log, span := c.trace("limit-bandwidth")
+	defer func() { span.End(err) }()
+
+	unlock := c.handles.Lock(c.handle)
+	defer unlock()
+
+	return c.networker.LimitBandwidth(log, c.handle, limits)
 }
 
-func (c *container) CurrentBandwidthLimits() (garden.BandwidthLimits, error) {
-	return garden.BandwidthLimits{}, nil
+func (c *container) CurrentBandwidthLimits() (_ garden.BandwidthLimits, err error) {
+	log, span := c.trace("current-bandwidth-limits")
+	defer func() { span.End(err) }()
+
+	return c.networker.BandwidthLimits(log, c.handle)
 }
 
-func (c *container) LimitCPU(limits garden.CPULimits) error {
+func (c *container) LimitCPU(limits garden.CPULimits) (err error) {
+	log, span := c.trace("limit-cpu")
+	defer func() { span.End(err) }()
+
+	unlock := c.handles.Lock(c.handle)
+	defer unlock()
+
+	info, err := c.containerizer.Info(log, c.handle)
+	if err != nil {
+		return err
+	}
+
+	restore, err := c.changeResources(c.handle, func(r *Resources) { r.CPUShares = limits.LimitInShares })
+	if err != nil {
+		return err
+	}
+
+	info.Limits.CPU = limits
+	if err := c.containerizer.UpdateLimits(log, c.handle, info.Limits); err != nil {
+		restore()
+		return err
+	}
+
 	return nil
 }
 
-func (c *container) CurrentCPULimits() (garden.CPULimits, error) {
-	info, err := c.containerizer.Info(c.logger, c.handle)
+func (c *container) CurrentCPULimits() (_ garden.CPULimits, err error) {
+	log, span := c.trace("current-cpu-limits")
+	defer func() { span.End(err) }()
+
+	info, err := c.containerizer.Info(log, c.handle)
 	return info.Limits.CPU, err
 }
 
-func (c *container) LimitDisk(limits garden.DiskLimits) error {
+func (c *container) LimitDisk(limits garden.DiskLimits) (err error) {
+	log, span := c.trace("limit-disk")
+	defer func() { span.End(err) }()
+
+	unlock := c.handles.Lock(c.handle)
+	defer unlock()
+
+	log.Info("started")
+	defer log.Info("finished")
+
+	actualSpec, err := c.containerizer.Info(log, c.handle)
+	if err != nil {
+		return err
+	}
+
+	restore, err := c.changeResources(c.handle, func(r *Resources) { r.DiskInBytes = limits.ByteHard })
+	if err != nil {
+		return err
+	}
+
+	if err := c.volumeCreator.Resize(log, c.handle, actualSpec.RootFSPath, limits); err != nil {
+		log.Error("resize-failed", err)
+		restore()
+		return err
+	}
+
+	limitsCfg, err := json.Marshal(limits)
+	if err != nil {
+		return err
+	}
+
+	c.propertyManager.Set(c.handle, DiskLimitsKey, string(limitsCfg))
 	return nil
 }
 
-func (c *container) CurrentDiskLimits() (garden.DiskLimits, error) {
-	return garden.DiskLimits{}, nil
+func (c *container) CurrentDiskLimits() (_ garden.DiskLimits, err error) {
+	_, span := c.trace("current-disk-limits")
+	defer func() { span.End(err) }()
+
+	var limits garden.DiskLimits
+
+	limitsCfg, ok := c.propertyManager.Get(c.handle, DiskLimitsKey)
+	if !ok {
+		return limits, nil
+	}
+
+	if err := json.Unmarshal([]byte(limitsCfg), &limits); err != nil {
+		return garden.DiskLimits{}, fmt.Errorf("unmarshal disk limits: %s", err)
+	}
+
+	return limits, nil
 }
 
-func (c *container) LimitMemory(limits garden.MemoryLimits) error {
+func (c *container) LimitMemory(limits garden.MemoryLimits) (err error) {
+	log, span := c.trace("limit-memory")
+	defer func() { span.End(err) }()
+
+	unlock := c.handles.Lock(c.handle)
+	defer unlock()
+
+	info, err := c.containerizer.Info(log, c.handle)
+	if err != nil {
+		return err
+	}
+
+	restore, err := c.changeResources(c.handle, func(r *Resources) { r.MemoryInBytes = limits.LimitInBytes })
+	if err != nil {
+		return err
+	}
+
+	info.Limits.Memory = limits
+	if err := c.containerizer.UpdateLimits(log, c.handle, info.Limits); err != nil {
+		restore()
+		return err
+	}
+
 	return nil
 }
 
-func (c *container) CurrentMemoryLimits() (garden.MemoryLimits, error) {
-	info, err := c.containerizer.Info(c.logger, c.handle)
+func (c *container) CurrentMemoryLimits() (_ garden.MemoryLimits, err error) {
+	log, span := c.trace("current-memory-limits")
+	defer func() { span.End(err) }()
+
+	info, err := c.containerizer.Info(log, c.handle)
 	return info.Limits.Memory, err
 }
 
-func (c *container) NetIn(hostPort, containerPort uint32) (uint32, uint32, error) {
-	return c.networker.NetIn(c.logger, c.handle, hostPort, containerPort)
+func (c *container) NetIn(hostPort, containerPort uint32) (_ uint32, _ uint32, err error) {
+	log, span := c.trace("net-in")
+	defer func() { span.End(err) }()
+
+	unlock := c.handles.RLock(c.handle)
+	defer unlock()
+
+	actualHostPort, actualContainerPort, err := c.networker.NetIn(log, c.handle, hostPort, containerPort)
+	if err != nil {
+		return 0, 0, err
+	}
+
+	c.recordEvent(EventNetIn, map[string]string{
+		"host_port":      fmt.Sprintf("%d", actualHostPort),
+		"container_port": fmt.Sprintf("%d", actualContainerPort),
+	})
+
+	return actualHostPort, actualContainerPort, nil
+}
+
+func (c *container) NetOut(netOutRule garden.NetOutRule) (err error) {
+	log, span := c.trace("net-out")
+	defer func() { span.End(err) }()
+
+	unlock := c.handles.RLock(c.handle)
+	defer unlock()
+
+	if err := c.networker.NetOut(log, c.handle, netOutRule); err != nil {
+		return err
+	}
+
+	c.recordEvent(EventNetOut, map[string]string{"rules": "1"})
+	return nil
 }
 
-func (c *container) NetOut(netOutRule garden.NetOutRule) error {
-	return c.networker.NetOut(c.logger, c.handle, netOutRule)
+func (c *container) BulkNetOut(netOutRules []garden.NetOutRule) (err error) {
+	log, span := c.trace("bulk-net-out")
+	defer func() { span.End(err) }()
+
+	unlock := c.handles.RLock(c.handle)
+	defer unlock()
+
+	if err := c.networker.BulkNetOut(log, c.handle, netOutRules); err != nil {
+		return err
+	}
+
+	c.recordEvent(EventNetOut, map[string]string{"rules": fmt.Sprintf("%d", len(netOutRules))})
+	return nil
 }
 
-func (c *container) BulkNetOut(netOutRules []garden.NetOutRule) error {
-	return c.networker.BulkNetOut(c.logger, c.handle, netOutRules)
+func (c *container) recordEvent(eventType EventType, data map[string]string) {
+	if err := c.events.OnEvent(c.handle, Event{Type: eventType, Data: data}); err != nil {
+		c.logger.Error("record-event-failed", err, lager.Data{"handle": c.handle, "type": eventType})
+	}
 }
 
-func (c *container) Metrics() (garden.Metrics, error) {
-	actualContainerMetrics, err := c.containerizer.Metrics(c.logger, c.handle)
+// Metrics returns the metrics which garden.Metrics has fields for; see
+// ExtendedMetrics for the rest
+func (c *container) Metrics() (_ garden.Metrics, err error) {
+	log, span := c.trace("metrics")
+	defer func() { span.End(err) }()
+
+	metrics, err := c.extendedMetrics(log)
 	if err != nil {
 		return garden.Metrics{}, err
 	}
 
-	diskMetrics, err := c.volumeCreator.Metrics(c.logger, c.handle)
+	return metrics.Metrics, nil
+}
+
+// ExtendedMetrics is not traced, as it is only called by the metrics
+// collectors rather than through the API
+func (c *container) ExtendedMetrics() (ExtendedMetrics, error) {
+	return c.extendedMetrics(c.logger)
+}
+
+func (c *container) extendedMetrics(log lager.Logger) (ExtendedMetrics, error) {
+	actualContainerMetrics, err := c.containerizer.Metrics(log, c.handle)
+	if err != nil {
+		return ExtendedMetrics{}, err
+	}
+
+	diskMetrics, err := c.volumeCreator.Metrics(log, c.handle)
 	if err != nil {
-		return garden.Metrics{}, err
+		return ExtendedMetrics{}, err
 	}
 
-	return garden.Metrics{
-		CPUStat:    actualContainerMetrics.CPU,
-		MemoryStat: actualContainerMetrics.Memory,
-		DiskStat:   diskMetrics,
-	}, nil
+	metrics := ExtendedMetrics{
+		Metrics: garden.Metrics{
+			CPUStat:    actualContainerMetrics.CPU,
+			MemoryStat: actualContainerMetrics.Memory,
+			DiskStat:   diskMetrics,
+		},
+		PidStat:        actualContainerMetrics.Pid,
+		BlockIOStats:   actualContainerMetrics.BlockIO,
+		ThrottlingStat: actualContainerMetrics.Throttling,
+		HugetlbStats:   actualContainerMetrics.Hugetlb,
+		CPURateStat:    actualContainerMetrics.CPURate,
+	}
+
+	// the rest of the metrics are still worth having without the network
+	// traffic, which is reported as unavailable
+	networkMetrics, err := c.networker.Stats(log, c.handle)
+	if err == ErrNetworkStatsUnavailable {
+		return metrics, nil
+	}
+	if err != nil {
+		log.Error("network-stats-failed", err, lager.Data{"handle": c.handle})
+		return metrics, nil
+	}
+
+	metrics.NetworkStat = garden.ContainerNetworkStat{
+		RxBytes: networkMetrics.RxBytes,
+		TxBytes: networkMetrics.TxBytes,
+	}
+	metrics.NetworkTraffic = &networkMetrics
+
+	return metrics, nil
 }
 
-func (c *container) Properties() (garden.Properties, error) {
-	return c.propertyManager.All(c.handle)
+// Properties returns the properties set by clients; guardian's internal
+// state can only be read by asking for it by name
+func (c *container) Properties() (_ garden.Properties, err error) {
+	_, span := c.trace("properties")
+	defer func() { span.End(err) }()
+
+	properties, err := c.propertyManager.All(c.handle)
+	if err != nil {
+		return nil, err
+	}
+
+	return userProperties(properties), nil
 }
 
-func (c *container) Property(name string) (string, error) {
+func (c *container) Property(name string) (_ string, err error) {
+	_, span := c.trace("property")
+	defer func() { span.End(err) }()
+
 	if prop, ok := c.propertyManager.Get(c.handle, name); ok {
 		return prop, nil
 	}
@@ -172,16 +481,44 @@ func (c *container) Property(name string) (string, error) {
 	return "", fmt.Errorf("property does not exist: %s", name)
 }
 
-func (c *container) SetProperty(name string, value string) error {
+func (c *container) SetProperty(name string, value string) (err error) {
+	_, span := c.trace("set-property")
+	defer func() { span.End(err) }()
+
+	if err := c.checkWritable(name); err != nil {
+		return err
+	}
+
 	c.propertyManager.Set(c.handle, name, value)
 	return nil
 }
 
-func (c *container) RemoveProperty(name string) error {
+func (c *container) RemoveProperty(name string) (err error) {
+	_, span := c.trace("remove-property")
+	defer func() { span.End(err) }()
+
+	if err := c.checkWritable(name); err != nil {
+		return err
+	}
+
 	c.propertyManager.Remove(c.handle, name)
 	return nil
 }
 
+// checkWritable rejects changes to guardian's internal state and to the
+// owner, which quotas are charged to for the lifetime of the container
+func (c *container) checkWritable(name string) error {
+	if IsReservedProperty(name) {
+		return ReservedPropertyError{Name: name}
+	}
+
+	if c.ownerProperty != "" && name == c.ownerProperty {
+		return ImmutablePropertyError{Name: name}
+	}
+
+	return nil
+}
+
 func (c *container) SetGraceTime(t time.Duration) error {
 	c.propertyManager.Set(c.handle, GraceTimeKey, fmt.Sprintf("%d", t))
 	return nil
@@ -0,0 +1,115 @@
+package gardener
+
+import (
+	"errors"
+	"io"
+	"sync"
+	"time"
+)
+
+var ErrDraining = errors.New("guardian is draining: new containers cannot be created")
+
+// drainer tracks in-flight operations so that Stop can let them finish, and
+// rejects creates once draining has begun. The zero value is ready to use.
+type drainer struct {
+	mu       sync.Mutex
+	draining bool
+	inFlight int
+	idle     chan struct{}
+}
+
+// startCreate tracks a create, unless draining has begun
+func (d *drainer) startCreate() (func(), error) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	if d.draining {
+		return nil, ErrDraining
+	}
+
+	d.inFlight++
+	return d.done, nil
+}
+
+// start tracks an operation which must be allowed to finish, and may still
+// be started, while draining
+func (d *drainer) start() func() {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	d.inFlight++
+	return d.done
+}
+
+func (d *drainer) done() {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	d.inFlight--
+	if d.draining && d.inFlight == 0 {
+		d.closeIdle()
+	}
+}
+
+// drain stops any new creates and waits up to timeout for in-flight
+// operations to finish. It returns false if the timeout expired first.
+func (d *drainer) drain(timeout time.Duration) bool {
+	d.mu.Lock()
+	if !d.draining {
+		d.draining = true
+		d.idle = make(chan struct{})
+		if d.inFlight == 0 {
+			d.closeIdle()
+		}
+	}
+	idle := d.idle
+	idleAlready := d.inFlight == 0
+	d.mu.Unlock()
+
+	// with a zero timeout select could pick the timeout even though there is
+	// nothing to wait for
+	if idleAlready {
+		return true
+	}
+
+	select {
+	case <-idle:
+		return true
+	case <-time.After(timeout):
+		return false
+	}
+}
+
+func (d *drainer) closeIdle() {
+	select {
+	case <-d.idle:
+	default:
+		close(d.idle)
+	}
+}
+
+func (d *drainer) isDraining() bool {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	return d.draining
+}
+
+func (d *drainer) inFlightOperations() int {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	return d.inFlight
+}
+
+// trackedReadCloser keeps an operation in flight until the stream is closed
+type trackedReadCloser struct {
+	io.ReadCloser
+	once sync.Once
+	done func()
+}
+
+func (r *trackedReadCloser) Close() error {
+	r.once.Do(r.done)
+	return r.ReadCloser.Close()
+}
@@ -0,0 +1,70 @@
+package gardener
+
+import (
+	"fmt"
+	"time"
+)
+
+//go:generate counterfeiter . EventLog
+
+// EventType identifies the kind of thing which happened to a container
+type EventType string
+
+const (
+	EventOutOfMemory   EventType = "oom"
+	EventProcessExited EventType = "process-exited"
+	EventCreated       EventType = "created"
+	EventStopped       EventType = "stopped"
+	EventDestroyed     EventType = "destroyed"
+	EventNetIn         EventType = "net-in"
+	EventNetOut        EventType = "net-out"
+	EventQuotaExceeded EventType = "quota-exceeded"
+)
+
+// Event is something which happened to a container at a point in time
+type Event struct {
+	Handle string            `json:"handle"`
+	Type   EventType         `json:"type"`
+	Time   time.Time         `json:"time"`
+	Data   map[string]string `json:"data,omitempty"`
+}
+
+// String describes the event in the form reported by ContainerInfo.Events
+func (e Event) String() string {
+	switch e.Type {
+	case EventOutOfMemory:
+		return "Out of memory"
+	case EventProcessExited:
+		return fmt.Sprintf("Process %s exited with status %s", e.Data["process_id"], e.Data["exit_status"])
+	case EventCreated:
+		return "Container created"
+	case EventStopped:
+		return "Container stopped"
+	case EventDestroyed:
+		return "Container destroyed"
+	case EventNetIn:
+		return fmt.Sprintf("Mapped host port %s to container port %s", e.Data["host_port"], e.Data["container_port"])
+	case EventNetOut:
+		return fmt.Sprintf("Added %s net-out rule(s)", e.Data["rules"])
+	case EventQuotaExceeded:
+		return fmt.Sprintf("Exceeded %s quota", e.Data["resource"])
+	}
+
+	if description, ok := e.Data["description"]; ok {
+		return description
+	}
+
+	return string(e.Type)
+}
+
+// EventLog records a bounded history of events per container and lets
+// consumers follow new events as they happen
+type EventLog interface {
+	OnEvent(handle string, event Event) error
+	Events(handle string) []Event
+
+	// Subscribe returns a channel of events for the given handle, or for all
+	// containers if handle is empty, and a function which ends the
+	// subscription and closes the channel
+	Subscribe(handle string) (<-chan Event, func())
+}
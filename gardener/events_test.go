@@ -0,0 +1,34 @@
+package gardener_test
+
+import (
+	"code.cloudfoundry.org/guardian/gardener"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/ginkgo/extensions/table"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Event", func() {
+	DescribeTable("describing events",
+		func(event gardener.Event, description string) {
+			Expect(event.String()).To(Equal(description))
+		},
+		Entry("oom", gardener.Event{Type: gardener.EventOutOfMemory}, "Out of memory"),
+		Entry("process exit", gardener.Event{
+			Type: gardener.EventProcessExited,
+			Data: map[string]string{"process_id": "abc", "exit_status": "3"},
+		}, "Process abc exited with status 3"),
+		Entry("net in", gardener.Event{
+			Type: gardener.EventNetIn,
+			Data: map[string]string{"host_port": "61001", "container_port": "8080"},
+		}, "Mapped host port 61001 to container port 8080"),
+		Entry("quota", gardener.Event{
+			Type: gardener.EventQuotaExceeded,
+			Data: map[string]string{"resource": "disk"},
+		}, "Exceeded disk quota"),
+		Entry("unknown with a description", gardener.Event{
+			Type: "legacy",
+			Data: map[string]string{"description": "something happened"},
+		}, "something happened"),
+		Entry("unknown without a description", gardener.Event{Type: "weird"}, "weird"),
+	)
+})
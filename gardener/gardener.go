@@ -1,13 +1,17 @@
 package gardener
 
 import (
+	"encoding/json"
 	"fmt"
 	"io"
 	"net/url"
+	"strings"
+	"sync"
 	"time"
 
 	"code.cloudfoundry.org/garden"
 	"code.cloudfoundry.org/garden-shed/rootfs_provider"
+	"code.cloudfoundry.org/guardian/tracing"
 	"code.cloudfoundry.org/lager"
 )
 
@@ -19,18 +23,22 @@ import (
 //go:generate counterfeiter . PropertyManager
 //go:generate counterfeiter . Restorer
 //go:generate counterfeiter . Starter
+//go:generate counterfeiter . CreationJournal
+//go:generate counterfeiter . Admitter
 
 const ContainerIPKey = "garden.network.container-ip"
 const BridgeIPKey = "garden.network.host-ip"
 const ExternalIPKey = "garden.network.external-ip"
 const MappedPortsKey = "garden.network.mapped-ports"
 const GraceTimeKey = "garden.grace-time"
+const DiskLimitsKey = "garden.disk-limits"
 
 const RawRootFSScheme = "raw"
 
 type SysInfoProvider interface {
 	TotalMemory() (uint64, error)
 	TotalDisk() (uint64, error)
+	CPUCores() (int, error)
 }
 
 type Containerizer interface {
@@ -43,11 +51,16 @@ type Containerizer interface {
 	Run(log lager.Logger, handle string, spec garden.ProcessSpec, io garden.ProcessIO) (garden.Process, error)
 	Attach(log lager.Logger, handle string, processGUID string, io garden.ProcessIO) (garden.Process, error)
 	Stop(log lager.Logger, handle string, kill bool) error
+	Pause(log lager.Logger, handle string) error
+	Resume(log lager.Logger, handle string) error
+	Checkpoint(log lager.Logger, handle, archivePath string, properties garden.Properties) error
+	Restore(log lager.Logger, handle, archivePath string, prepare RestorePreparer) (garden.Properties, error)
 	Destroy(log lager.Logger, handle string) error
 	RemoveBundle(log lager.Logger, handle string) error
 
 	Info(log lager.Logger, handle string) (ActualContainerSpec, error)
 	Metrics(log lager.Logger, handle string) (ActualContainerMetrics, error)
+	UpdateLimits(log lager.Logger, handle string, limits garden.Limits) error
 }
 
 type Networker interface {
@@ -57,6 +70,9 @@ type Networker interface {
 	NetIn(log lager.Logger, handle string, hostPort, containerPort uint32) (uint32, uint32, error)
 	BulkNetOut(log lager.Logger, handle string, rules []garden.NetOutRule) error
 	NetOut(log lager.Logger, handle string, rule garden.NetOutRule) error
+	LimitBandwidth(log lager.Logger, handle string, limits garden.BandwidthLimits) error
+	BandwidthLimits(log lager.Logger, handle string) (garden.BandwidthLimits, error)
+	Stats(log lager.Logger, handle string) (ContainerNetworkStat, error)
 	Restore(log lager.Logger, handle string) error
 }
 
@@ -65,6 +81,20 @@ type VolumeCreator interface {
 	Destroy(log lager.Logger, handle, rootFSPath string) error
 	Metrics(log lager.Logger, handle string) (garden.ContainerDiskStat, error)
 	GC(log lager.Logger) error
+	Resize(log lager.Logger, handle, rootFSPath string, limits garden.DiskLimits) error
+}
+
+// Pausable is implemented by containers whose processes can be frozen and
+// thawed without losing their memory state
+type Pausable interface {
+	Pause() error
+	Resume() error
+}
+
+// Admitter validates the spec of a container before it is created, and may
+// return a changed spec to enforce policy
+type Admitter interface {
+	Admit(log lager.Logger, spec garden.ContainerSpec) (garden.ContainerSpec, error)
 }
 
 type UidGenerator interface {
@@ -76,7 +106,7 @@ type PropertyManager interface {
 	Set(handle string, name string, value string)
 	Remove(handle string, name string) error
 	Get(handle string, name string) (string, bool)
-	MatchesAll(handle string, props garden.Properties) bool
+	MatchingHandles(props garden.Properties) ([]string, error)
 	DestroyKeySpace(string) error
 }
 
@@ -88,6 +118,38 @@ type Restorer interface {
 	Restore(logger lager.Logger, handles []string) []string
 }
 
+// CreationStage is a step of container creation which has completed
+type CreationStage string
+
+const (
+	CreationStageVolume    CreationStage = "volume-created"
+	CreationStageContainer CreationStage = "container-created"
+	CreationStageNetwork   CreationStage = "network-configured"
+	CreationStageHooks     CreationStage = "post-network-hooks-run"
+)
+
+type CreationJournalEntry struct {
+	Spec       garden.ContainerSpec `json:"spec"`
+	RootFSPath string               `json:"rootfs_path,omitempty"`
+	Stages     []CreationStage      `json:"stages"`
+}
+
+func (e CreationJournalEntry) Completed(stage CreationStage) bool {
+	for _, s := range e.Stages {
+		if s == stage {
+			return true
+		}
+	}
+
+	return false
+}
+
+type CreationJournal interface {
+	Write(log lager.Logger, entry CreationJournalEntry) error
+	Remove(log lager.Logger, handle string) error
+	Entries(log lager.Logger) ([]CreationJournalEntry, error)
+}
+
 type UidGeneratorFunc func() string
 
 func (fn UidGeneratorFunc) Generate() string {
@@ -114,6 +176,21 @@ type DesiredContainerSpec struct {
 	Env []string
 }
 
+// RestoreSpec describes the container stored in a checkpoint archive
+type RestoreSpec struct {
+	Properties garden.Properties
+	Limits     garden.Limits
+
+	// ArchivedRootFSPath is the root filesystem stored in the archive. It only
+	// exists until the restore returns.
+	ArchivedRootFSPath string
+}
+
+// RestorePreparer is called once a checkpoint archive has been read, before
+// anything is created for the restored container, and returns the path of the
+// root filesystem to restore it on to
+type RestorePreparer func(spec RestoreSpec) (rootFSPath string, err error)
+
 type ActualContainerSpec struct {
 	// The PID of the container's init process
 	Pid int
@@ -127,6 +204,9 @@ type ActualContainerSpec struct {
 	// Whether the container is stopped
 	Stopped bool
 
+	// Whether the container's processes are frozen
+	Paused bool
+
 	// Process IDs (not PIDs) of processes in the container
 	ProcessIDs []string
 
@@ -138,8 +218,13 @@ type ActualContainerSpec struct {
 }
 
 type ActualContainerMetrics struct {
-	CPU    garden.ContainerCPUStat
-	Memory garden.ContainerMemoryStat
+	CPU        garden.ContainerCPUStat
+	Memory     garden.ContainerMemoryStat
+	Pid        ContainerPidStat
+	BlockIO    []ContainerBlockIOStat
+	Throttling ContainerCPUThrottlingStat
+	Hugetlb    map[string]ContainerHugetlbStat
+	CPURate    ContainerCPURateStat
 }
 
 // Gardener orchestrates other components to implement the Garden API
@@ -170,23 +255,134 @@ type Gardener struct {
 	// MaxContainers limits the advertised container capacity
 	MaxContainers uint64
 
+	// OwnerProperty is the property which identifies the owner of a
+	// container for OwnerQuotas
+	OwnerProperty string
+
+	// OwnerQuotas limits what the containers of each owner may commit
+	OwnerQuotas OwnerQuotas
+
+	// OvercommitRatio is how many times over the host's memory, disk and CPU
+	// may be committed through container limits. Zero disables the check.
+	OvercommitRatio float64
+
 	Restorer Restorer
+
+	// CreationJournal records the progress of creates so that they can be
+	// reconciled after a crash
+	CreationJournal CreationJournal
+
+	// Admitter accepts, rejects or rewrites specs before anything is created
+	Admitter Admitter
+
+	// Hooks runs operator configured binaries before create, after the
+	// network is configured and after destroy
+	Hooks HookRunner
+
+	// EventLog records what happens to containers
+	EventLog EventLog
+
+	// DrainTimeout is how long Stop waits for in-flight operations to finish
+	DrainTimeout time.Duration
+
+	// BulkWorkers is how many containers BulkInfo and BulkMetrics look at
+	// concurrently within each bulk call, defaulting to DefaultBulkWorkers
+	BulkWorkers int
+
+	// BulkTimeout is how long each container has to answer a bulk call
+	// before its entry is a BulkTimeoutError. Zero waits for ever.
+	BulkTimeout time.Duration
+
+	// StageRecorder records how long each stage of creates, destroys and
+	// streams took, and whether it failed. It may be nil.
+	StageRecorder StageRecorder
+
+	// Tracer starts a trace for each API call, whose id is logged and passed
+	// on to plugins. It may be nil, in which case spans are not exported.
+	Tracer *tracing.Tracer
+
+	// handles reserves handles and serializes operations on each container
+	handles HandleRegistry
+
+	// resources tracks the limits committed to live containers
+	resources resourceAccountant
+
+	// stuckBulkCalls holds, by handle, the bulk calls which timed out and
+	// have not yet returned
+	bulkMu         sync.Mutex
+	stuckBulkCalls map[string]*bulkCall
+
+	drainer  drainer
+	stopOnce sync.Once
 }
 
 // Create creates a container by combining the results of networker.Network,
 // volumizer.Create and containzer.Create.
 func (g *Gardener) Create(spec garden.ContainerSpec) (ctr garden.Container, err error) {
-	if err := g.checkDuplicateHandle(spec.Handle); err != nil {
+	done, err := g.drainer.startCreate()
+	if err != nil {
 		return nil, err
 	}
+	defer done()
 
 	if spec.Handle == "" {
 		spec.Handle = g.UidGenerator.Generate()
 	}
 
-	log := g.Logger.Session("create", lager.Data{"handle": spec.Handle})
+	if err := g.handles.Reserve(spec.Handle); err != nil {
+		return nil, err
+	}
+	defer g.handles.Release(spec.Handle)
+
+	unlock := g.handles.Lock(spec.Handle)
+	defer unlock()
+
+	if err := g.checkDuplicateHandle(spec.Handle); err != nil {
+		return nil, err
+	}
+
+	log, span := g.Tracer.Start(g.Logger, "create", lager.Data{"handle": spec.Handle})
 
 	log.Info("start")
+
+	timer := NewStageTimer(g.StageRecorder, "create")
+	defer func() {
+		log.Info("finished", timer.Finish(err))
+		span.End(err)
+	}()
+
+	handle := spec.Handle
+	endStage := timer.Start("admit")
+	spec, err = g.Admitter.Admit(log, spec)
+	endStage(err)
+	if err != nil {
+		log.Error("admission-failed", err)
+		return nil, err
+	}
+	spec.Handle = handle
+
+	if err := checkNoReservedProperties(spec.Properties); err != nil {
+		log.Error("reserved-property", err)
+		return nil, err
+	}
+
+	if _, err := g.reserveResources(spec.Handle, g.ownerOf(spec.Properties), ResourcesFromLimits(spec.Limits)); err != nil {
+		log.Error("reserve-resources-failed", err)
+		return nil, err
+	}
+	defer func() {
+		if err != nil {
+			g.resources.release(spec.Handle)
+		}
+	}()
+
+	if err := timer.Time("pre-create-hooks", func() error {
+		return g.runHooksWithProperties(log, HookPreCreate, spec, spec.Properties)
+	}); err != nil {
+		return nil, err
+	}
+
+	journal := CreationJournalEntry{Spec: spec}
 	defer func() {
 		if err != nil {
 			log := log.Session("create-failed-cleaningup", lager.Data{
@@ -195,9 +391,15 @@ func (g *Gardener) Create(spec garden.ContainerSpec) (ctr garden.Container, err
 
 			log.Info("start")
 
-			err := g.destroy(log, spec.Handle)
+			// the container may not have got as far as having a bundle, which
+			// destroy needs, so roll back using what the journal recorded
+			err := timer.Time("rollback", func() error { return g.rollback(log, journal) })
 			if err != nil {
-				log.Error("destroy-failed", err)
+				// the journal is left in place, so that the next start will
+				// roll back whatever is left of the container
+				log.Error("roll-back-failed", err)
+			} else if err := g.CreationJournal.Remove(log, spec.Handle); err != nil {
+				log.Error("remove-journal-failed", err)
 			}
 
 			log.Info("cleanedup")
@@ -211,7 +413,11 @@ func (g *Gardener) Create(spec garden.ContainerSpec) (ctr garden.Container, err
 		return nil, err
 	}
 
-	if err := g.VolumeCreator.GC(log); err != nil {
+	if err := g.CreationJournal.Write(log, journal); err != nil {
+		return nil, err
+	}
+
+	if err := timer.Time("volume-gc", func() error { return g.VolumeCreator.GC(log) }); err != nil {
 		log.Error("graph-cleanup-failed", err)
 	}
 
@@ -222,35 +428,62 @@ func (g *Gardener) Create(spec garden.ContainerSpec) (ctr garden.Container, err
 		rootFSPath = rootFSURL.Path
 	} else {
 		var err error
+		endStage := timer.Start("volume-create")
 		rootFSPath, env, err = g.VolumeCreator.Create(log, spec.Handle, rootfs_provider.Spec{
 			RootFS:     rootFSURL,
 			QuotaSize:  int64(spec.Limits.Disk.ByteHard),
 			QuotaScope: spec.Limits.Disk.Scope,
 			Namespaced: !spec.Privileged,
 		})
+		endStage(err)
 		if err != nil {
 			return nil, err
 		}
 	}
 
-	if err := g.Containerizer.Create(log, DesiredContainerSpec{
-		Handle:     spec.Handle,
-		RootFSPath: rootFSPath,
-		Hostname:   spec.Handle,
-		Privileged: spec.Privileged,
-		BindMounts: spec.BindMounts,
-		Limits:     spec.Limits,
-		Env:        append(env, spec.Env...),
+	journal.RootFSPath = rootFSPath
+	if err := g.recordStage(log, &journal, CreationStageVolume); err != nil {
+		return nil, err
+	}
+
+	if err := timer.Time("containerizer-create", func() error {
+		return g.Containerizer.Create(log, DesiredContainerSpec{
+			Handle:     spec.Handle,
+			RootFSPath: rootFSPath,
+			Hostname:   spec.Handle,
+			Privileged: spec.Privileged,
+			BindMounts: spec.BindMounts,
+			Limits:     spec.Limits,
+			Env:        append(env, spec.Env...),
+		})
 	}); err != nil {
 		return nil, err
 	}
 
+	if err := g.recordStage(log, &journal, CreationStageContainer); err != nil {
+		return nil, err
+	}
+
+	endStage = timer.Start("containerizer-info")
 	actualSpec, err := g.Containerizer.Info(log, spec.Handle)
+	endStage(err)
 	if err != nil {
 		return nil, err
 	}
 
-	if err = g.Networker.Network(log, spec, actualSpec.Pid); err != nil {
+	if err = timer.Time("network", func() error { return g.Networker.Network(log, spec, actualSpec.Pid) }); err != nil {
+		return nil, err
+	}
+
+	if err := g.recordStage(log, &journal, CreationStageNetwork); err != nil {
+		return nil, err
+	}
+
+	if err := timer.Time("post-network-hooks", func() error { return g.runHooks(log, HookPostNetwork, spec) }); err != nil {
+		return nil, err
+	}
+
+	if err := g.recordStage(log, &journal, CreationStageHooks); err != nil {
 		return nil, err
 	}
 
@@ -259,45 +492,338 @@ func (g *Gardener) Create(spec garden.ContainerSpec) (ctr garden.Container, err
 		return nil, err
 	}
 
+	if err := timer.Time("set-properties", func() error { return g.setProperties(container, spec) }); err != nil {
+		return nil, err
+	}
+
+	if err := g.CreationJournal.Remove(log, spec.Handle); err != nil {
+		log.Error("remove-journal-failed", err)
+	}
+
+	return container, nil
+}
+
+func (g *Gardener) recordStage(log lager.Logger, journal *CreationJournalEntry, stage CreationStage) error {
+	journal.Stages = append(journal.Stages, stage)
+	return g.CreationJournal.Write(log, *journal)
+}
+
+// setProperties stores the properties of a newly created container; it is
+// idempotent, so that a create can be finished after a crash
+func (g *Gardener) setProperties(container garden.Container, spec garden.ContainerSpec) error {
 	if spec.GraceTime != 0 {
 		if err := container.SetGraceTime(spec.GraceTime); err != nil {
-			return nil, err
+			return err
 		}
 	}
 
+	if spec.Limits.Disk != (garden.DiskLimits{}) {
+		diskLimits, err := json.Marshal(spec.Limits.Disk)
+		if err != nil {
+			return err
+		}
+
+		g.PropertyManager.Set(spec.Handle, DiskLimitsKey, string(diskLimits))
+	}
+
+	// the properties were checked when the container was admitted; the owner
+	// can only be set here
 	for name, value := range spec.Properties {
-		if err := container.SetProperty(name, value); err != nil {
-			return nil, err
+		g.PropertyManager.Set(spec.Handle, name, value)
+	}
+
+	g.PropertyManager.Set(spec.Handle, StateKey, "created")
+	return nil
+}
+
+// Checkpoint saves the state of a running container, along with its bundle
+// and properties, to archivePath. The container is left stopped.
+func (g *Gardener) Checkpoint(handle, archivePath string) (err error) {
+	log, span := g.Tracer.Start(g.Logger, "checkpoint", lager.Data{"handle": handle, "archive": archivePath})
+
+	log.Info("start")
+	defer func() {
+		log.Info("finished")
+		span.End(err)
+	}()
+
+	unlock := g.handles.Lock(handle)
+	defer unlock()
+
+	properties, err := g.PropertyManager.All(handle)
+	if err != nil {
+		return err
+	}
+
+	return g.Containerizer.Checkpoint(log, handle, archivePath, properties)
+}
+
+// Restore creates a container from a checkpoint archive. The container gets
+// a fresh subnet and fresh host ports for the port mappings it had when it
+// was checkpointed.
+func (g *Gardener) Restore(handle, archivePath string) (ctr garden.Container, err error) {
+	done, err := g.drainer.startCreate()
+	if err != nil {
+		return nil, err
+	}
+	defer done()
+
+	if handle == "" {
+		handle = g.UidGenerator.Generate()
+	}
+
+	if err := g.handles.Reserve(handle); err != nil {
+		return nil, err
+	}
+	defer g.handles.Release(handle)
+
+	unlock := g.handles.Lock(handle)
+	defer unlock()
+
+	if err := g.checkDuplicateHandle(handle); err != nil {
+		return nil, err
+	}
+
+	log, span := g.Tracer.Start(g.Logger, "restore", lager.Data{"handle": handle, "archive": archivePath})
+
+	log.Info("start")
+
+	timer := NewStageTimer(g.StageRecorder, "restore")
+	defer func() {
+		log.Info("finished", timer.Finish(err))
+		span.End(err)
+	}()
+
+	// the restored container gets a volume of its own, created from the root
+	// filesystem in the archive. A restore is journalled like a create, so
+	// that it is rolled back, or finished, if it is interrupted.
+	journal := CreationJournalEntry{Spec: garden.ContainerSpec{Handle: handle}}
+	defer func() {
+		if err != nil {
+			log := log.Session("restore-failed-cleaningup", lager.Data{
+				"cause": err.Error(),
+			})
+
+			log.Info("start")
+
+			g.resources.release(handle)
+
+			err := timer.Time("rollback", func() error { return g.rollback(log, journal) })
+			if err != nil {
+				log.Error("roll-back-failed", err)
+			} else if err := g.CreationJournal.Remove(log, handle); err != nil {
+				log.Error("remove-journal-failed", err)
+			}
+
+			log.Info("cleanedup")
+		} else {
+			log.Info("restored")
 		}
+	}()
+
+	var properties garden.Properties
+	if err := timer.Time("containerizer-restore", func() error {
+		var err error
+		properties, err = g.Containerizer.Restore(log, handle, archivePath, func(spec RestoreSpec) (string, error) {
+			return g.prepareRestore(log, timer, &journal, spec)
+		})
+		return err
+	}); err != nil {
+		return nil, err
 	}
 
-	if err := container.SetProperty("garden.state", "created"); err != nil {
+	if err := g.recordStage(log, &journal, CreationStageContainer); err != nil {
 		return nil, err
 	}
 
+	endStage := timer.Start("containerizer-info")
+	actualSpec, err := g.Containerizer.Info(log, handle)
+	endStage(err)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := timer.Time("network", func() error {
+		return g.restoreNetwork(log, handle, actualSpec.Pid, properties)
+	}); err != nil {
+		return nil, err
+	}
+
+	if err := g.recordStage(log, &journal, CreationStageNetwork); err != nil {
+		return nil, err
+	}
+
+	if err := timer.Time("post-network-hooks", func() error { return g.runHooks(log, HookPostNetwork, journal.Spec) }); err != nil {
+		return nil, err
+	}
+
+	if err := g.recordStage(log, &journal, CreationStageHooks); err != nil {
+		return nil, err
+	}
+
+	container, err := g.Lookup(handle)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := timer.Time("set-properties", func() error { return g.setProperties(container, journal.Spec) }); err != nil {
+		return nil, err
+	}
+
+	if err := g.CreationJournal.Remove(log, handle); err != nil {
+		log.Error("remove-journal-failed", err)
+	}
+
 	return container, nil
 }
 
+// restoreNetwork networks a restored container from scratch, mapping fresh
+// host ports to the container ports which were mapped when it was
+// checkpointed
+func (g *Gardener) restoreNetwork(log lager.Logger, handle string, pid int, properties garden.Properties) error {
+	if err := g.Networker.Network(log, garden.ContainerSpec{Handle: handle}, pid); err != nil {
+		return err
+	}
+
+	var mappings []garden.PortMapping
+	if mappedPorts, ok := properties[MappedPortsKey]; ok {
+		if err := json.Unmarshal([]byte(mappedPorts), &mappings); err != nil {
+			return fmt.Errorf("restore port mappings: %s", err)
+		}
+	}
+
+	for _, mapping := range mappings {
+		if _, _, err := g.Networker.NetIn(log, handle, 0, mapping.ContainerPort); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// prepareRestore puts the container stored in a checkpoint archive through
+// the same admission, resource checks and pre-create hooks as a create, and
+// creates a volume for it from the archived root filesystem. The properties
+// which do not describe the old network go in the spec of the journal entry,
+// and are set once the restore has finished.
+func (g *Gardener) prepareRestore(log lager.Logger, timer *StageTimer, journal *CreationJournalEntry, restoreSpec RestoreSpec) (string, error) {
+	handle := journal.Spec.Handle
+
+	properties := garden.Properties{}
+	for name, value := range restoreSpec.Properties {
+		if !isNetworkProperty(name) {
+			properties[name] = value
+		}
+	}
+
+	spec := garden.ContainerSpec{
+		Handle:     handle,
+		Properties: properties,
+		Limits:     restoreSpec.Limits,
+	}
+
+	if diskLimits, ok := spec.Properties[DiskLimitsKey]; ok {
+		if err := json.Unmarshal([]byte(diskLimits), &spec.Limits.Disk); err != nil {
+			return "", fmt.Errorf("restore disk limits: %s", err)
+		}
+	}
+
+	// the limits of the container are part of its checkpoint, so the
+	// admitter can reject a restore but not change it
+	endStage := timer.Start("admit")
+	_, err := g.Admitter.Admit(log, spec)
+	endStage(err)
+	if err != nil {
+		log.Error("admission-failed", err)
+		return "", err
+	}
+
+	if _, err := g.reserveResources(handle, g.ownerOf(spec.Properties), ResourcesFromLimits(spec.Limits)); err != nil {
+		log.Error("reserve-resources-failed", err)
+		return "", err
+	}
+
+	if err := timer.Time("pre-create-hooks", func() error {
+		return g.runHooksWithProperties(log, HookPreCreate, spec, spec.Properties)
+	}); err != nil {
+		return "", err
+	}
+
+	journal.Spec = spec
+	if err := g.CreationJournal.Write(log, *journal); err != nil {
+		return "", err
+	}
+
+	// the archived files are owned by the host ids they had in the
+	// checkpointed container, so they are copied as they are
+	endStage = timer.Start("volume-create")
+	rootFSPath, _, err := g.VolumeCreator.Create(log, handle, rootfs_provider.Spec{
+		RootFS:     &url.URL{Path: restoreSpec.ArchivedRootFSPath},
+		QuotaSize:  int64(spec.Limits.Disk.ByteHard),
+		QuotaScope: spec.Limits.Disk.Scope,
+		Namespaced: false,
+	})
+	endStage(err)
+	if err != nil {
+		log.Error("volume-create-failed", err)
+		return "", err
+	}
+
+	journal.RootFSPath = rootFSPath
+	if err := g.recordStage(log, journal, CreationStageVolume); err != nil {
+		return "", err
+	}
+
+	return rootFSPath, nil
+}
+
+// isNetworkProperty returns true for properties which describe the network
+// of a container on a particular host
+func isNetworkProperty(name string) bool {
+	return strings.HasPrefix(name, "kawasaki.") || strings.HasPrefix(name, "garden.network.")
+}
+
 func (g *Gardener) Lookup(handle string) (garden.Container, error) {
 	return g.lookup(handle), nil
 }
 
-func (g *Gardener) lookup(handle string) garden.Container {
+func (g *Gardener) lookup(handle string) *container {
 	return &container{
 		logger:          g.Logger,
 		handle:          handle,
+		ownerProperty:   g.OwnerProperty,
 		containerizer:   g.Containerizer,
 		volumeCreator:   g.VolumeCreator,
 		networker:       g.Networker,
 		propertyManager: g.PropertyManager,
+		handles:         &g.handles,
+		drainer:         &g.drainer,
+		events:          g.EventLog,
+		stages:          g.StageRecorder,
+		tracer:          g.Tracer,
+		changeResources: g.changeResources,
 	}
 }
 
-func (g *Gardener) Destroy(handle string) error {
-	log := g.Logger.Session("destroy", lager.Data{"handle": handle})
+// SubscribeEvents follows the events of one container, or of all containers
+// if handle is empty, until the returned function is called
+func (g *Gardener) SubscribeEvents(handle string) (<-chan Event, func()) {
+	return g.EventLog.Subscribe(handle)
+}
+
+func (g *Gardener) Destroy(handle string) (err error) {
+	log, span := g.Tracer.Start(g.Logger, "destroy", lager.Data{"handle": handle})
 
 	log.Info("start")
-	defer log.Info("finished")
+	defer func() {
+		log.Info("finished")
+		span.End(err)
+	}()
+
+	defer g.drainer.start()()
+
+	unlock := g.handles.Lock(handle)
+	defer unlock()
 
 	handles, err := g.Containerizer.Handles()
 	if err != nil {
@@ -312,21 +838,35 @@ func (g *Gardener) Destroy(handle string) error {
 }
 
 // destroy idempotently destroys any resources associated with the given handle
-func (g *Gardener) destroy(log lager.Logger, handle string) error {
+func (g *Gardener) destroy(log lager.Logger, handle string) (err error) {
+	timer := NewStageTimer(g.StageRecorder, "destroy")
+	defer func() {
+		log.Info("destroy-stages", timer.Finish(err))
+	}()
+
+	endStage := timer.Start("containerizer-info")
 	actualContainerSpec, err := g.Containerizer.Info(log, handle)
+	endStage(err)
 	if err != nil {
 		return err
 	}
 
-	if err := g.Containerizer.Destroy(g.Logger, handle); err != nil {
+	properties, err := g.PropertyManager.All(handle)
+	if err != nil {
+		properties = garden.Properties{}
+	}
+
+	if err := timer.Time("containerizer-destroy", func() error { return g.Containerizer.Destroy(log, handle) }); err != nil {
 		return err
 	}
 
-	if err := g.Networker.Destroy(g.Logger, handle); err != nil {
+	if err := timer.Time("network-destroy", func() error { return g.Networker.Destroy(log, handle) }); err != nil {
 		return err
 	}
 
-	if err := g.VolumeCreator.Destroy(g.Logger, handle, actualContainerSpec.RootFSPath); err != nil {
+	if err := timer.Time("volume-destroy", func() error {
+		return g.VolumeCreator.Destroy(log, handle, actualContainerSpec.RootFSPath)
+	}); err != nil {
 		return err
 	}
 
@@ -334,10 +874,40 @@ func (g *Gardener) destroy(log lager.Logger, handle string) error {
 		return err
 	}
 
-	return g.Containerizer.RemoveBundle(g.Logger, handle)
+	if err := timer.Time("remove-bundle", func() error { return g.Containerizer.RemoveBundle(log, handle) }); err != nil {
+		return err
+	}
+
+	g.resources.release(handle)
+
+	return timer.Time("post-destroy-hooks", func() error {
+		return g.runHooksWithProperties(log, HookPostDestroy, garden.ContainerSpec{Handle: handle, Properties: properties}, properties)
+	})
 }
 
-func (g *Gardener) Stop() {}
+// Stop drains the gardener: new creates are rejected, and in-flight creates,
+// destroys and streams are given up to DrainTimeout to finish
+func (g *Gardener) Stop() {
+	g.stopOnce.Do(func() {
+		log := g.Logger.Session("stop", lager.Data{"drain-timeout": g.DrainTimeout.String()})
+
+		log.Info("draining")
+		if !g.drainer.drain(g.DrainTimeout) {
+			log.Info("drain-timed-out", lager.Data{"in-flight": g.drainer.inFlightOperations()})
+			return
+		}
+
+		log.Info("drained")
+	})
+}
+
+func (g *Gardener) Draining() bool {
+	return g.drainer.isDraining()
+}
+
+func (g *Gardener) InFlightOperations() int {
+	return g.drainer.inFlightOperations()
+}
 
 func (g *Gardener) GraceTime(container garden.Container) time.Duration {
 	property, ok := g.PropertyManager.Get(container.Handle(), GraceTimeKey)
@@ -356,15 +926,22 @@ func (g *Gardener) GraceTime(container garden.Container) time.Duration {
 
 func (g *Gardener) Ping() error { return nil }
 
+// Capacity reports the memory and disk which can still be committed to new
+// containers, allowing for the configured overcommit ratio. Without a ratio
+// nothing limits what is committed, so it reports the host's memory and disk.
 func (g *Gardener) Capacity() (garden.Capacity, error) {
-	mem, err := g.SysInfoProvider.TotalMemory()
+	remaining, err := g.hostResources(1)
 	if err != nil {
 		return garden.Capacity{}, err
 	}
 
-	disk, err := g.SysInfoProvider.TotalDisk()
-	if err != nil {
-		return garden.Capacity{}, err
+	if g.OvercommitRatio > 0 {
+		host, err := g.hostResources(g.OvercommitRatio)
+		if err != nil {
+			return garden.Capacity{}, err
+		}
+
+		remaining = host.sub(g.resources.total())
 	}
 
 	cap := g.Networker.Capacity()
@@ -373,8 +950,8 @@ func (g *Gardener) Capacity() (garden.Capacity, error) {
 	}
 
 	return garden.Capacity{
-		MemoryInBytes: mem,
-		DiskInBytes:   disk,
+		MemoryInBytes: remaining.MemoryInBytes,
+		DiskInBytes:   remaining.DiskInBytes,
 		MaxContainers: cap,
 	}, nil
 }
@@ -394,54 +971,27 @@ func (g *Gardener) Containers(props garden.Properties) ([]garden.Container, erro
 	if props == nil {
 		props = garden.Properties{}
 	}
-	props["garden.state"] = "created"
+	props[StateKey] = "created"
 
-	var containers []garden.Container
-	for _, handle := range handles {
-		matched := g.PropertyManager.MatchesAll(handle, props)
-		if matched {
-			containers = append(containers, g.lookup(handle))
-		}
+	matching, err := g.PropertyManager.MatchingHandles(props)
+	if err != nil {
+		log.Error("matching-handles-failed", err)
+		return []garden.Container{}, err
 	}
 
-	return containers, nil
-}
-
-func (g *Gardener) BulkInfo(handles []string) (map[string]garden.ContainerInfoEntry, error) {
-	result := make(map[string]garden.ContainerInfoEntry)
-	for _, handle := range handles {
-		container := g.lookup(handle)
-
-		var infoErr *garden.Error = nil
-		info, err := container.Info()
-		if err != nil {
-			infoErr = garden.NewError(err.Error())
-		}
-		result[handle] = garden.ContainerInfoEntry{
-			Info: info,
-			Err:  infoErr,
-		}
+	matched := make(map[string]bool, len(matching))
+	for _, handle := range matching {
+		matched[handle] = true
 	}
 
-	return result, nil
-}
-
-func (g *Gardener) BulkMetrics(handles []string) (map[string]garden.ContainerMetricsEntry, error) {
-	result := make(map[string]garden.ContainerMetricsEntry)
+	var containers []garden.Container
 	for _, handle := range handles {
-		var e *garden.Error
-		m, err := g.lookup(handle).Metrics()
-		if err != nil {
-			e = garden.NewError(err.Error())
-		}
-
-		result[handle] = garden.ContainerMetricsEntry{
-			Err:     e,
-			Metrics: m,
+		if matched[handle] {
+			containers = append(containers, g.lookup(handle))
 		}
 	}
 
-	return result, nil
+	return containers, nil
 }
 
 func (g *Gardener) checkDuplicateHandle(handle string) error {
@@ -479,6 +1029,10 @@ func (g *Gardener) Start() error {
 		}
 	}
 
+	if err := g.reconcileCreations(log); err != nil {
+		return err
+	}
+
 	handles, err := g.Containerizer.Handles()
 	if err != nil {
 		return err
@@ -496,5 +1050,95 @@ func (g *Gardener) Start() error {
 		destroyLog.Info("cleaned-up")
 	}
 
+	return g.rebuildResources(log)
+}
+
+// reconcileCreations finishes creates which were interrupted after the
+// network was configured, running the post-network hooks if they had not
+// run, and rolls back any other interrupted creates
+func (g *Gardener) reconcileCreations(log lager.Logger) error {
+	log = log.Session("reconcile-creations")
+
+	entries, err := g.CreationJournal.Entries(log)
+	if err != nil {
+		return fmt.Errorf("reading creation journal: %s", err)
+	}
+
+	finished := []string{}
+	rolledBack := []string{}
+	failed := []string{}
+
+	for _, entry := range entries {
+		handle := entry.Spec.Handle
+		entryLog := log.Session("reconcile", lager.Data{"handle": handle, "stages": entry.Stages})
+
+		if entry.Completed(CreationStageNetwork) && !entry.Completed(CreationStageHooks) {
+			if err := g.runHooks(entryLog, HookPostNetwork, entry.Spec); err != nil {
+				entryLog.Error("post-network-hooks-failed", err)
+			} else if err := g.recordStage(entryLog, &entry, CreationStageHooks); err != nil {
+				entryLog.Error("record-stage-failed", err)
+			}
+		}
+
+		if entry.Completed(CreationStageHooks) {
+			if err := g.setProperties(g.lookup(handle), entry.Spec); err != nil {
+				entryLog.Error("finish-failed", err)
+				failed = append(failed, handle)
+				continue
+			}
+
+			if err := g.CreationJournal.Remove(entryLog, handle); err != nil {
+				entryLog.Error("remove-journal-failed", err)
+			}
+
+			finished = append(finished, handle)
+			continue
+		}
+
+		if err := g.rollback(entryLog, entry); err != nil {
+			entryLog.Error("roll-back-failed", err)
+			failed = append(failed, handle)
+			continue
+		}
+
+		if err := g.CreationJournal.Remove(entryLog, handle); err != nil {
+			entryLog.Error("remove-journal-failed", err)
+		}
+
+		rolledBack = append(rolledBack, handle)
+	}
+
+	log.Info("reconciled", lager.Data{
+		"finished":    finished,
+		"rolled-back": rolledBack,
+		"failed":      failed,
+	})
+
 	return nil
 }
+
+// rollback idempotently destroys whatever resources a partial create may have
+// left behind. Unlike destroy it does not require the bundle to exist.
+func (g *Gardener) rollback(log lager.Logger, entry CreationJournalEntry) error {
+	handle := entry.Spec.Handle
+
+	if err := g.Containerizer.Destroy(log, handle); err != nil {
+		return err
+	}
+
+	if err := g.Networker.Destroy(log, handle); err != nil {
+		return err
+	}
+
+	if entry.RootFSPath != "" {
+		if err := g.VolumeCreator.Destroy(log, handle, entry.RootFSPath); err != nil {
+			return err
+		}
+	}
+
+	if err := g.PropertyManager.DestroyKeySpace(handle); err != nil {
+		return err
+	}
+
+	return g.Containerizer.RemoveBundle(log, handle)
+}
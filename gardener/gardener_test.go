@@ -1,18 +1,24 @@
 package gardener_test
 
 import (
+	"encoding/json"
 	"errors"
 	"fmt"
+	"io/ioutil"
 	"net"
+	"strings"
 	"time"
 
 	"code.cloudfoundry.org/garden"
 	"code.cloudfoundry.org/garden-shed/rootfs_provider"
 	"code.cloudfoundry.org/guardian/gardener"
 	fakes "code.cloudfoundry.org/guardian/gardener/gardenerfakes"
+	"code.cloudfoundry.org/guardian/tracing"
+	"code.cloudfoundry.org/guardian/tracing/tracingfakes"
 	"code.cloudfoundry.org/lager"
 	"code.cloudfoundry.org/lager/lagertest"
 	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/ginkgo/extensions/table"
 	. "github.com/onsi/gomega"
 	"github.com/onsi/gomega/gbytes"
 )
@@ -26,6 +32,12 @@ var _ = Describe("Gardener", func() {
 		sysinfoProvider *fakes.FakeSysInfoProvider
 		propertyManager *fakes.FakePropertyManager
 		restorer        *fakes.FakeRestorer
+		journal         *fakes.FakeCreationJournal
+		eventLog        *fakes.FakeEventLog
+		hooks           *fakes.FakeHookRunner
+		admitter        *fakes.FakeAdmitter
+		stageRecorder   *fakes.FakeStageRecorder
+		exporter        *tracingfakes.FakeExporter
 
 		logger lager.Logger
 
@@ -41,6 +53,15 @@ var _ = Describe("Gardener", func() {
 		sysinfoProvider = new(fakes.FakeSysInfoProvider)
 		propertyManager = new(fakes.FakePropertyManager)
 		restorer = new(fakes.FakeRestorer)
+		journal = new(fakes.FakeCreationJournal)
+		eventLog = new(fakes.FakeEventLog)
+		hooks = new(fakes.FakeHookRunner)
+		admitter = new(fakes.FakeAdmitter)
+		stageRecorder = new(fakes.FakeStageRecorder)
+		exporter = new(tracingfakes.FakeExporter)
+		admitter.AdmitStub = func(_ lager.Logger, spec garden.ContainerSpec) (garden.ContainerSpec, error) {
+			return spec, nil
+		}
 
 		propertyManager.GetReturns("", true)
 		containerizer.HandlesReturns([]string{"some-handle"}, nil)
@@ -55,12 +76,20 @@ var _ = Describe("Gardener", func() {
 			Logger:          logger,
 			PropertyManager: propertyManager,
 			Restorer:        restorer,
+			CreationJournal: journal,
+			EventLog:        eventLog,
+			Hooks:           hooks,
+			Admitter:        admitter,
+			StageRecorder:   stageRecorder,
+			Tracer:          &tracing.Tracer{Exporter: exporter},
 		}
 	})
 
 	Describe("creating a container", func() {
-		ItDestroysEverything := func(rootfsPath string) {
+		ItDestroysEverything := func(rootfsPath string, volumeCreated bool) {
 			BeforeEach(func() {
+				containerizer.InfoReturns(gardener.ActualContainerSpec{}, errors.New("no bundle"))
+
 				_, err := gdnr.Create(garden.ContainerSpec{
 					RootFSPath: rootfsPath,
 					Handle:     "poor-banana",
@@ -74,18 +103,33 @@ var _ = Describe("Gardener", func() {
 				Expect(handle).To(Equal("poor-banana"))
 			})
 
-			It("should clean up any created volumes", func() {
-				Expect(volumeCreator.DestroyCallCount()).To(Equal(1))
-				_, handle, rootFSPath := volumeCreator.DestroyArgsForCall(0)
-				Expect(handle).To(Equal("poor-banana"))
-				Expect(rootFSPath).To(Equal("rootfs"))
-			})
+			if volumeCreated {
+				It("should clean up the created volume", func() {
+					Expect(volumeCreator.DestroyCallCount()).To(Equal(1))
+					_, handle, rootFSPath := volumeCreator.DestroyArgsForCall(0)
+					Expect(handle).To(Equal("poor-banana"))
+					Expect(rootFSPath).To(Equal("rootfs"))
+				})
+			} else {
+				It("should not destroy a volume, as none was created", func() {
+					Expect(volumeCreator.DestroyCallCount()).To(Equal(0))
+				})
+			}
 
 			It("should destroy any container state", func() {
 				Expect(containerizer.DestroyCallCount()).To(Equal(1))
 				_, handle := containerizer.DestroyArgsForCall(0)
 				Expect(handle).To(Equal("poor-banana"))
 			})
+
+			It("should remove the depot directory and the properties", func() {
+				Expect(containerizer.RemoveBundleCallCount()).To(Equal(1))
+				Expect(propertyManager.DestroyKeySpaceCallCount()).To(Equal(1))
+			})
+
+			It("should remove the journal", func() {
+				Expect(journal.RemoveCallCount()).To(Equal(1))
+			})
 		}
 
 		It("assigns a random handle to the container", func() {
@@ -133,7 +177,7 @@ var _ = Describe("Gardener", func() {
 				Expect(err).To(HaveOccurred())
 			})
 
-			ItDestroysEverything("://banana")
+			ItDestroysEverything("://banana", false)
 		})
 
 		Context("when the rootfs path is raw", func() {
@@ -207,7 +251,7 @@ var _ = Describe("Gardener", func() {
 				Expect(containerizer.CreateCallCount()).To(Equal(0))
 			})
 
-			ItDestroysEverything("")
+			ItDestroysEverything("", false)
 		})
 
 		It("asks the containerizer to create a container", func() {
@@ -230,6 +274,7 @@ var _ = Describe("Gardener", func() {
 
 		Context("when the containerizer fails to create the container", func() {
 			BeforeEach(func() {
+				volumeCreator.CreateReturns("rootfs", nil, nil)
 				containerizer.CreateReturns(errors.New("failed to create the banana"))
 			})
 
@@ -240,7 +285,7 @@ var _ = Describe("Gardener", func() {
 				Expect(err).To(HaveOccurred())
 			})
 
-			ItDestroysEverything("")
+			ItDestroysEverything("", true)
 
 			It("logs the underlying error", func() {
 				_, err := gdnr.Create(garden.ContainerSpec{Handle: "bob"})
@@ -266,6 +311,21 @@ var _ = Describe("Gardener", func() {
 				Expect(rpSpec.QuotaSize).To(BeEquivalentTo(spec.Limits.Disk.ByteHard))
 				Expect(rpSpec.QuotaScope).To(Equal(garden.DiskLimitScopeTotal))
 			})
+
+			It("remembers the disk limit", func() {
+				spec.Handle = "some-handle"
+				_, err := gdnr.Create(spec)
+				Expect(err).NotTo(HaveOccurred())
+
+				Expect(propertyManager.SetCallCount()).To(Equal(2))
+				handle, name, value := propertyManager.SetArgsForCall(0)
+				Expect(handle).To(Equal("some-handle"))
+				Expect(name).To(Equal(gardener.DiskLimitsKey))
+
+				var limits garden.DiskLimits
+				Expect(json.Unmarshal([]byte(value), &limits)).To(Succeed())
+				Expect(limits).To(Equal(spec.Limits.Disk))
+			})
 		})
 
 		It("should ask the networker to configure the network", func() {
@@ -402,6 +462,86 @@ var _ = Describe("Gardener", func() {
 			})
 		})
 
+		Context("when the same handle is being created concurrently", func() {
+			var (
+				creating chan struct{}
+				proceed  chan struct{}
+			)
+
+			BeforeEach(func() {
+				creating = make(chan struct{})
+				proceed = make(chan struct{})
+
+				containerizer.CreateStub = func(_ lager.Logger, spec gardener.DesiredContainerSpec) error {
+					if spec.Handle == "banana" {
+						close(creating)
+						<-proceed
+					}
+					return nil
+				}
+			})
+
+			It("reserves the handle for the first create", func() {
+				go func() {
+					defer GinkgoRecover()
+					_, err := gdnr.Create(garden.ContainerSpec{Handle: "banana"})
+					Expect(err).NotTo(HaveOccurred())
+				}()
+				Eventually(creating).Should(BeClosed())
+
+				_, err := gdnr.Create(garden.ContainerSpec{Handle: "banana"})
+				Expect(err).To(MatchError("Handle 'banana' already in use"))
+
+				close(proceed)
+			})
+
+			It("does not block creates of other handles", func() {
+				go func() {
+					defer GinkgoRecover()
+					gdnr.Create(garden.ContainerSpec{Handle: "banana"})
+				}()
+				Eventually(creating).Should(BeClosed())
+
+				_, err := gdnr.Create(garden.ContainerSpec{Handle: "apple"})
+				Expect(err).NotTo(HaveOccurred())
+
+				close(proceed)
+			})
+
+			It("makes a destroy of the handle wait for the create to finish", func() {
+				go func() {
+					defer GinkgoRecover()
+					gdnr.Create(garden.ContainerSpec{Handle: "banana"})
+				}()
+				Eventually(creating).Should(BeClosed())
+
+				destroyed := make(chan struct{})
+				go func() {
+					defer GinkgoRecover()
+					gdnr.Destroy("banana")
+					close(destroyed)
+				}()
+
+				Consistently(destroyed).ShouldNot(BeClosed())
+				Expect(containerizer.DestroyCallCount()).To(Equal(0))
+
+				close(proceed)
+				Eventually(destroyed).Should(BeClosed())
+			})
+		})
+
+		Context("when reserved properties are specified", func() {
+			It("rejects the create before doing anything", func() {
+				_, err := gdnr.Create(garden.ContainerSpec{
+					Handle:     "something",
+					Properties: garden.Properties{"kawasaki.subnet": "10.0.0.0/30"},
+				})
+				Expect(err).To(MatchError("property 'kawasaki.subnet' is reserved for internal use"))
+				Expect(containerizer.CreateCallCount()).To(Equal(0))
+				Expect(networker.NetworkCallCount()).To(Equal(0))
+			})
+		})
+
 		Context("when properties are specified", func() {
 			var startingProperties garden.Properties
 
@@ -576,12 +716,28 @@ var _ = Describe("Gardener", func() {
 				Expect(networker.NetInCallCount()).To(Equal(1))
 
 				actualLogger, actualHandle, actualExtPort, actualContainerPort := networker.NetInArgsForCall(0)
-				Expect(actualLogger).To(Equal(logger))
+				Expect(tracing.TraceID(actualLogger)).NotTo(BeEmpty())
 				Expect(actualHandle).To(Equal(container.Handle()))
 				Expect(actualExtPort).To(Equal(externalPort))
 				Expect(actualContainerPort).To(Equal(contianerPort))
 			})
 
+			It("records a net-in event with the ports which were mapped", func() {
+				networker.NetInReturns(9999, contianerPort, nil)
+
+				_, _, err := container.NetIn(0, contianerPort)
+				Expect(err).NotTo(HaveOccurred())
+
+				Expect(eventLog.OnEventCallCount()).To(Equal(1))
+				handle, event := eventLog.OnEventArgsForCall(0)
+				Expect(handle).To(Equal("banana"))
+				Expect(event.Type).To(Equal(gardener.EventNetIn))
+				Expect(event.Data).To(Equal(map[string]string{
+					"host_port":      "9999",
+					"container_port": "8080",
+				}))
+			})
+
 			Context("when networker returns an error", func() {
 				It("returns the error", func() {
 					networker.NetInReturns(uint32(0), uint32(0), fmt.Errorf("error"))
@@ -590,6 +746,13 @@ var _ = Describe("Gardener", func() {
 
 					Expect(err).To(MatchError("error"))
 				})
+
+				It("does not record an event", func() {
+					networker.NetInReturns(uint32(0), uint32(0), fmt.Errorf("error"))
+
+					container.NetIn(externalPort, contianerPort)
+					Expect(eventLog.OnEventCallCount()).To(Equal(0))
+				})
 			})
 		})
 
@@ -612,6 +775,15 @@ var _ = Describe("Gardener", func() {
 				Expect(actualRule).To(Equal(rule))
 			})
 
+			It("records a net-out event", func() {
+				Expect(container.NetOut(rule)).To(Succeed())
+
+				Expect(eventLog.OnEventCallCount()).To(Equal(1))
+				handle, event := eventLog.OnEventArgsForCall(0)
+				Expect(handle).To(Equal("banana"))
+				Expect(event.Type).To(Equal(gardener.EventNetOut))
+			})
+
 			Context("when networker returns an error", func() {
 				It("return the error", func() {
 					networker.NetOutReturns(fmt.Errorf("banana republic"))
@@ -645,6 +817,22 @@ var _ = Describe("Gardener", func() {
 				Expect(actualRules).To(ConsistOf(rules))
 			})
 
+			It("records a single net-out event for all the rules", func() {
+				Expect(container.BulkNetOut(rules)).To(Succeed())
+
+				Expect(eventLog.OnEventCallCount()).To(Equal(1))
+				_, event := eventLog.OnEventArgsForCall(0)
+				Expect(event.Type).To(Equal(gardener.EventNetOut))
+				Expect(event.Data).To(HaveKeyWithValue("rules", "2"))
+			})
+
+			Context("when recording the event fails", func() {
+				It("still succeeds", func() {
+					eventLog.OnEventReturns(errors.New("no room"))
+					Expect(container.BulkNetOut(rules)).To(Succeed())
+				})
+			})
+
 			Context("when networker returns an error", func() {
 				It("return the error", func() {
 					networker.BulkNetOutReturns(fmt.Errorf("banana republic"))
@@ -654,189 +842,1308 @@ var _ = Describe("Gardener", func() {
 		})
 	})
 
-	Describe("starting up gardener", func() {
-		BeforeEach(func() {
-			containers := []string{"container1", "container2"}
-			containerizer.HandlesReturns(containers, nil)
+	Describe("admission", func() {
+		It("admits the spec before running hooks or creating anything", func() {
+			admitter.AdmitStub = func(_ lager.Logger, spec garden.ContainerSpec) (garden.ContainerSpec, error) {
+				Expect(hooks.RunCallCount()).To(Equal(0))
+				Expect(journal.WriteCallCount()).To(Equal(0))
+				return spec, nil
+			}
+
+			spec := garden.ContainerSpec{Handle: "bob", Privileged: true}
+			_, err := gdnr.Create(spec)
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(admitter.AdmitCallCount()).To(Equal(1))
+			_, admittedSpec := admitter.AdmitArgsForCall(0)
+			Expect(admittedSpec).To(Equal(spec))
 		})
 
-		Context("when it has starters", func() {
-			var (
-				starterA, starterB *fakes.FakeStarter
-			)
+		It("passes the generated handle to the admitter", func() {
+			uidGenerator.GenerateReturns("generated-handle")
 
-			BeforeEach(func() {
-				starterA = new(fakes.FakeStarter)
-				starterB = new(fakes.FakeStarter)
-				gdnr.Starters = []gardener.Starter{starterA, starterB}
-			})
+			_, err := gdnr.Create(garden.ContainerSpec{})
+			Expect(err).NotTo(HaveOccurred())
 
-			It("calls the provided starters", func() {
-				Expect(gdnr.Start()).To(Succeed())
+			_, admittedSpec := admitter.AdmitArgsForCall(0)
+			Expect(admittedSpec.Handle).To(Equal("generated-handle"))
+		})
 
-				Expect(starterA.StartCallCount()).To(Equal(1))
-				Expect(starterB.StartCallCount()).To(Equal(1))
+		Context("when the admitter rewrites the spec", func() {
+			BeforeEach(func() {
+				admitter.AdmitStub = func(_ lager.Logger, spec garden.ContainerSpec) (garden.ContainerSpec, error) {
+					spec.Privileged = false
+					spec.Limits.Memory.LimitInBytes = 1024
+					spec.Properties = garden.Properties{"tenant": "forced"}
+					spec.Handle = "sneaky"
+					return spec, nil
+				}
 			})
 
-			Context("when a starter fails", func() {
-				BeforeEach(func() {
-					starterB.StartReturns(errors.New("boom"))
-				})
+			It("creates the container from the rewritten spec", func() {
+				_, err := gdnr.Create(garden.ContainerSpec{Handle: "bob", Privileged: true})
+				Expect(err).NotTo(HaveOccurred())
 
-				It("returns the error", func() {
-					Expect(gdnr.Start()).To(MatchError(ContainSubstring("boom")))
-				})
+				_, desiredSpec := containerizer.CreateArgsForCall(0)
+				Expect(desiredSpec.Privileged).To(BeFalse())
+				Expect(desiredSpec.Limits.Memory.LimitInBytes).To(BeEquivalentTo(1024))
 
-				It("does not restore the containers", func() {
-					Expect(gdnr.Start()).NotTo(Succeed())
-					Expect(restorer.RestoreCallCount()).To(Equal(0))
-				})
+				_, networkSpec, _ := networker.NetworkArgsForCall(0)
+				Expect(networkSpec.Properties).To(Equal(garden.Properties{"tenant": "forced"}))
 			})
-		})
-
-		It("should restore the containers", func() {
-			Expect(gdnr.Start()).To(Succeed())
-			Expect(restorer.RestoreCallCount()).To(Equal(1))
-			_, handles := restorer.RestoreArgsForCall(0)
-			Expect(handles).To(Equal([]string{"container1", "container2"}))
-		})
 
-		It("should blow up containers that couldn't restore", func() {
-			restorer.RestoreReturns([]string{"container2"})
-			Expect(gdnr.Start()).To(Succeed())
-			Expect(containerizer.DestroyCallCount()).To(Equal(1))
-			_, handle := containerizer.DestroyArgsForCall(0)
-			Expect(handle).To(Equal("container2"))
-		})
+			It("does not let the admitter change the handle", func() {
+				container, err := gdnr.Create(garden.ContainerSpec{Handle: "bob"})
+				Expect(err).NotTo(HaveOccurred())
+				Expect(container.Handle()).To(Equal("bob"))
 
-		It("should return the error when it failes to get a list of handles", func() {
-			containerizer.HandlesReturns([]string{}, errors.New("banana"))
-			Expect(gdnr.Start()).To(MatchError("banana"))
+				_, desiredSpec := containerizer.CreateArgsForCall(0)
+				Expect(desiredSpec.Handle).To(Equal("bob"))
+			})
 		})
-	})
 
-	Describe("listing containers", func() {
-		BeforeEach(func() {
-			containerizer.HandlesReturns([]string{"banana", "banana2", "cola"}, nil)
-		})
+		Context("when the admitter rejects the spec", func() {
+			BeforeEach(func() {
+				admitter.AdmitReturns(garden.ContainerSpec{}, errors.New("privileged containers are not allowed"))
+			})
 
-		itOnlyMatchesFullyCreatedContainers := func(props garden.Properties) {
-			It("only matches fully created containers", func() {
-				_, err := gdnr.Containers(props)
-				Expect(err).NotTo(HaveOccurred())
+			It("returns the error without creating or destroying anything", func() {
+				_, err := gdnr.Create(garden.ContainerSpec{Handle: "bob", Privileged: true})
+				Expect(err).To(MatchError("privileged containers are not allowed"))
 
-				_, props := propertyManager.MatchesAllArgsForCall(0)
-				Expect(props).To(HaveKeyWithValue("garden.state", "created"))
+				Expect(hooks.RunCallCount()).To(Equal(0))
+				Expect(volumeCreator.CreateCallCount()).To(Equal(0))
+				Expect(containerizer.CreateCallCount()).To(Equal(0))
+				Expect(containerizer.DestroyCallCount()).To(Equal(0))
 			})
-		}
-
-		Context("when passed nil properties to match against", func() {
-			itOnlyMatchesFullyCreatedContainers(nil)
-		})
 
-		Context("when garden.Properties are passed", func() {
-			props := garden.Properties{"somename": "somevalue"}
+			It("releases the handle", func() {
+				_, err := gdnr.Create(garden.ContainerSpec{Handle: "bob"})
+				Expect(err).To(HaveOccurred())
 
-			It("only returns matching containers", func() {
-				propertyManager.MatchesAllStub = func(handle string, props garden.Properties) bool {
-					return handle != "banana"
+				admitter.AdmitStub = func(_ lager.Logger, spec garden.ContainerSpec) (garden.ContainerSpec, error) {
+					return spec, nil
 				}
-
-				c, err := gdnr.Containers(props)
+				_, err = gdnr.Create(garden.ContainerSpec{Handle: "bob"})
 				Expect(err).NotTo(HaveOccurred())
-				Expect(c).To(HaveLen(2))
-				Expect(c[0].Handle()).To(Equal("banana2"))
-				Expect(c[1].Handle()).To(Equal("cola"))
 			})
-
-			itOnlyMatchesFullyCreatedContainers(props)
 		})
 	})
 
-	Context("when no containers exist", func() {
-		BeforeEach(func() {
-			containerizer.HandlesReturns([]string{}, nil)
-		})
+	Describe("lifecycle hooks", func() {
+		It("runs the pre-create hooks with the spec before creating anything", func() {
+			hooks.RunStub = func(_ lager.Logger, stage gardener.HookStage, _ gardener.HookPayload) error {
+				if stage == gardener.HookPreCreate {
+					Expect(volumeCreator.CreateCallCount()).To(Equal(0))
+					Expect(journal.WriteCallCount()).To(Equal(0))
+				}
+				return nil
+			}
 
-		It("should return an empty list", func() {
-			containers, err := gdnr.Containers(garden.Properties{})
+			spec := garden.ContainerSpec{Handle: "bob", Properties: garden.Properties{"owner": "alice"}}
+			_, err := gdnr.Create(spec)
 			Expect(err).NotTo(HaveOccurred())
 
-			Expect(containers).To(BeEmpty())
+			_, stage, payload := hooks.RunArgsForCall(0)
+			Expect(stage).To(Equal(gardener.HookPreCreate))
+			Expect(payload.Handle).To(Equal("bob"))
+			Expect(payload.Spec).To(Equal(spec))
+			Expect(payload.NetworkProperties).To(BeEmpty())
 		})
-	})
 
-	Context("when the containerizer returns an error", func() {
-		testErr := errors.New("failure")
+		Context("when a pre-create hook fails", func() {
+			BeforeEach(func() {
+				hooks.RunStub = func(_ lager.Logger, stage gardener.HookStage, _ gardener.HookPayload) error {
+					if stage == gardener.HookPreCreate {
+						return errors.New("not in inventory")
+					}
+					return nil
+				}
+			})
 
-		BeforeEach(func() {
-			containerizer.HandlesReturns([]string{}, testErr)
-		})
+			It("fails the create without creating or destroying anything", func() {
+				_, err := gdnr.Create(garden.ContainerSpec{Handle: "bob"})
+				Expect(err).To(MatchError("not in inventory"))
 
-		It("should return an error", func() {
-			_, err := gdnr.Containers(garden.Properties{})
-			Expect(err).To(MatchError(testErr))
+				Expect(volumeCreator.CreateCallCount()).To(Equal(0))
+				Expect(containerizer.CreateCallCount()).To(Equal(0))
+				Expect(containerizer.DestroyCallCount()).To(Equal(0))
+			})
 		})
-	})
 
-	Describe("stopping a container", func() {
-		It("asks the containerizer to stop all processes", func() {
-			container, err := gdnr.Lookup("banana")
-			Expect(err).NotTo(HaveOccurred())
+		It("runs the post-network hooks with the network properties once the network is configured", func() {
+			propertyManager.AllReturns(garden.Properties{
+				gardener.ContainerIPKey:     "10.0.0.2",
+				"kawasaki.bridge-interface": "w1b-1",
+				"owner":                     "alice",
+			}, nil)
 
-			Expect(container.Stop(true)).To(Succeed())
-			Expect(containerizer.StopCallCount()).To(Equal(1))
+			_, err := gdnr.Create(garden.ContainerSpec{Handle: "bob"})
+			Expect(err).NotTo(HaveOccurred())
 
-			_, handle, kill := containerizer.StopArgsForCall(0)
-			Expect(handle).To(Equal("banana"))
-			Expect(kill).To(Equal(true))
+			Expect(hooks.RunCallCount()).To(Equal(2))
+			_, stage, payload := hooks.RunArgsForCall(1)
+			Expect(stage).To(Equal(gardener.HookPostNetwork))
+			Expect(payload.Handle).To(Equal("bob"))
+			Expect(payload.NetworkProperties).To(Equal(garden.Properties{
+				gardener.ContainerIPKey:     "10.0.0.2",
+				"kawasaki.bridge-interface": "w1b-1",
+			}))
 		})
-	})
 
-	Describe("Destroy", func() {
-		It("returns garden.ContainreNotFoundError if the container handle isn't in the depot", func() {
-			containerizer.HandlesReturns([]string{}, nil)
-			Expect(gdnr.Destroy("cake!")).To(MatchError(garden.ContainerNotFoundError{Handle: "cake!"}))
+		Context("when a post-network hook fails", func() {
+			BeforeEach(func() {
+				hooks.RunStub = func(_ lager.Logger, stage gardener.HookStage, _ gardener.HookPayload) error {
+					if stage == gardener.HookPostNetwork {
+						return errors.New("mount failed")
+					}
+					return nil
+				}
+			})
+
+			It("fails the create and cleans up the container", func() {
+				_, err := gdnr.Create(garden.ContainerSpec{Handle: "bob"})
+				Expect(err).To(MatchError("mount failed"))
+
+				Expect(containerizer.DestroyCallCount()).To(Equal(1))
+				Expect(networker.DestroyCallCount()).To(Equal(1))
+			})
 		})
 
-		It("asks the containerizer to destroy the container", func() {
+		It("runs the post-destroy hooks with the properties the container had", func() {
+			propertyManager.AllReturns(garden.Properties{
+				gardener.ContainerIPKey: "10.0.0.2",
+				"owner":                 "alice",
+			}, nil)
+			hooks.RunStub = func(_ lager.Logger, stage gardener.HookStage, _ gardener.HookPayload) error {
+				Expect(containerizer.RemoveBundleCallCount()).To(Equal(1))
+				return nil
+			}
+
 			Expect(gdnr.Destroy("some-handle")).To(Succeed())
-			Expect(containerizer.DestroyCallCount()).To(Equal(1))
-			_, handle := containerizer.DestroyArgsForCall(0)
-			Expect(handle).To(Equal("some-handle"))
+
+			Expect(hooks.RunCallCount()).To(Equal(1))
+			_, stage, payload := hooks.RunArgsForCall(0)
+			Expect(stage).To(Equal(gardener.HookPostDestroy))
+			Expect(payload.Handle).To(Equal("some-handle"))
+			Expect(payload.Spec.Properties).To(HaveKeyWithValue("owner", "alice"))
+			Expect(payload.NetworkProperties).To(Equal(garden.Properties{
+				gardener.ContainerIPKey: "10.0.0.2",
+			}))
 		})
 
-		It("asks the networker to destroy the container network", func() {
-			gdnr.Destroy("some-handle")
-			Expect(networker.DestroyCallCount()).To(Equal(1))
-			networkLogger, handleToDestroy := networker.DestroyArgsForCall(0)
-			Expect(handleToDestroy).To(Equal("some-handle"))
-			Expect(networkLogger).To(Equal(logger))
+		Context("when a post-destroy hook fails", func() {
+			It("returns the error", func() {
+				hooks.RunReturns(errors.New("audit cleanup failed"))
+				Expect(gdnr.Destroy("some-handle")).To(MatchError("audit cleanup failed"))
+			})
 		})
 
-		It("asks the volume creator to destroy the container rootfs", func() {
-			gdnr.Destroy("some-handle")
-			Expect(volumeCreator.DestroyCallCount()).To(Equal(1))
-			_, handleToDestroy, rootFSPathToDestroy := volumeCreator.DestroyArgsForCall(0)
-			Expect(handleToDestroy).To(Equal("some-handle"))
-			Expect(rootFSPathToDestroy).To(Equal("rootfs"))
+		Context("when destroying fails", func() {
+			It("does not run the post-destroy hooks", func() {
+				containerizer.DestroyReturns(errors.New("boom"))
+				Expect(gdnr.Destroy("some-handle")).NotTo(Succeed())
+				Expect(hooks.RunCallCount()).To(Equal(0))
+			})
 		})
+	})
 
-		It("should destroy the key space of the property manager", func() {
-			gdnr.Destroy("some-handle")
+	Describe("journalling creation", func() {
+		It("records each completed stage of the create", func() {
+			_, err := gdnr.Create(garden.ContainerSpec{Handle: "bob"})
+			Expect(err).NotTo(HaveOccurred())
 
-			Expect(propertyManager.DestroyKeySpaceCallCount()).To(Equal(1))
-			Expect(propertyManager.DestroyKeySpaceArgsForCall(0)).To(Equal("some-handle"))
-		})
+			Expect(journal.WriteCallCount()).To(Equal(5))
 
-		It("asks the containerizer to remove the bundle from the depot", func() {
-			Expect(gdnr.Destroy("some-handle")).To(Succeed())
-			_, handle := containerizer.RemoveBundleArgsForCall(0)
-			Expect(handle).To(Equal("some-handle"))
+			_, entry := journal.WriteArgsForCall(0)
+			Expect(entry.Spec.Handle).To(Equal("bob"))
+			Expect(entry.Stages).To(BeEmpty())
+
+			_, entry = journal.WriteArgsForCall(4)
+			Expect(entry.Spec.Handle).To(Equal("bob"))
+			Expect(entry.Stages).To(Equal([]gardener.CreationStage{
+				gardener.CreationStageVolume,
+				gardener.CreationStageContainer,
+				gardener.CreationStageNetwork,
+				gardener.CreationStageHooks,
+			}))
 		})
 
-		Context("when containerizer fails to destroy the container", func() {
-			BeforeEach(func() {
+		It("records the path of the created volume", func() {
+			volumeCreator.CreateReturns("/path/to/rootfs", nil, nil)
+
+			_, err := gdnr.Create(garden.ContainerSpec{Handle: "bob"})
+			Expect(err).NotTo(HaveOccurred())
+
+			_, entry := journal.WriteArgsForCall(1)
+			Expect(entry.RootFSPath).To(Equal("/path/to/rootfs"))
+		})
+
+		It("removes the journal once the container is created", func() {
+			_, err := gdnr.Create(garden.ContainerSpec{Handle: "bob"})
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(journal.RemoveCallCount()).To(Equal(1))
+			_, handle := journal.RemoveArgsForCall(0)
+			Expect(handle).To(Equal("bob"))
+		})
+
+		Context("when the journal cannot be written", func() {
+			It("does not create the container", func() {
+				journal.WriteReturns(errors.New("disk-full"))
+
+				_, err := gdnr.Create(garden.ContainerSpec{Handle: "bob"})
+				Expect(err).To(MatchError("disk-full"))
+				Expect(volumeCreator.CreateCallCount()).To(Equal(0))
+			})
+		})
+
+		Context("when the create fails and is cleaned up", func() {
+			It("removes the journal", func() {
+				networker.NetworkReturns(errors.New("network-failed"))
+
+				_, err := gdnr.Create(garden.ContainerSpec{Handle: "bob"})
+				Expect(err).To(HaveOccurred())
+
+				Expect(journal.RemoveCallCount()).To(Equal(1))
+			})
+		})
+
+		Context("when the create fails after writing the journal", func() {
+			It("rolls back using the stages the journal recorded", func() {
+				volumeCreator.CreateReturns("/path/to/rootfs", nil, nil)
+				networker.NetworkReturns(errors.New("network-failed"))
+
+				_, err := gdnr.Create(garden.ContainerSpec{Handle: "bob"})
+				Expect(err).To(HaveOccurred())
+
+				Expect(volumeCreator.DestroyCallCount()).To(Equal(1))
+				_, _, rootFSPath := volumeCreator.DestroyArgsForCall(0)
+				Expect(rootFSPath).To(Equal("/path/to/rootfs"))
+			})
+		})
+
+		Context("when the create fails and cannot be cleaned up", func() {
+			It("keeps the journal so that the create is rolled back on start", func() {
+				networker.NetworkReturns(errors.New("network-failed"))
+				containerizer.DestroyReturns(errors.New("destroy-failed"))
+
+				_, err := gdnr.Create(garden.ContainerSpec{Handle: "bob"})
+				Expect(err).To(HaveOccurred())
+
+				Expect(journal.RemoveCallCount()).To(Equal(0))
+			})
+		})
+	})
+
+	Describe("timing stages", func() {
+		type recordedStage struct {
+			operation, stage string
+			failed           bool
+		}
+
+		recordedStages := func() []recordedStage {
+			stages := []recordedStage{}
+			for i := 0; i < stageRecorder.RecordStageCallCount(); i++ {
+				operation, stage, took, err := stageRecorder.RecordStageArgsForCall(i)
+				Expect(took).To(BeNumerically(">=", 0))
+				stages = append(stages, recordedStage{operation, stage, err != nil})
+			}
+			return stages
+		}
+
+		It("records each stage of a create, and the create as a whole", func() {
+			_, err := gdnr.Create(garden.ContainerSpec{Handle: "bob", RootFSPath: "docker:///banana"})
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(recordedStages()).To(Equal([]recordedStage{
+				{"create", "admit", false},
+				{"create", "pre-create-hooks", false},
+				{"create", "volume-gc", false},
+				{"create", "volume-create", false},
+				{"create", "containerizer-create", false},
+				{"create", "containerizer-info", false},
+				{"create", "network", false},
+				{"create", "post-network-hooks", false},
+				{"create", "set-properties", false},
+				{"create", gardener.StageTotal, false},
+			}))
+		})
+
+		It("logs how long the create and each of its stages took", func() {
+			_, err := gdnr.Create(garden.ContainerSpec{Handle: "bob"})
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(logger).To(gbytes.Say(`test.create.finished.*"stages":{.*"network":"[^"]+".*},"took":"[^"]+"`))
+		})
+
+		Context("when a stage fails", func() {
+			BeforeEach(func() {
+				networker.NetworkReturns(errors.New("banana"))
+			})
+
+			It("records the failure of the stage and of the create, as well as the clean up", func() {
+				_, err := gdnr.Create(garden.ContainerSpec{Handle: "bob"})
+				Expect(err).To(HaveOccurred())
+
+				stages := recordedStages()
+				Expect(stages).To(ContainElement(recordedStage{"create", "network", true}))
+				Expect(stages).To(ContainElement(recordedStage{"create", "rollback", false}))
+				Expect(stages[len(stages)-1]).To(Equal(recordedStage{"create", gardener.StageTotal, true}))
+			})
+		})
+
+		It("records each stage of a destroy", func() {
+			Expect(gdnr.Destroy("some-handle")).To(Succeed())
+
+			Expect(recordedStages()).To(Equal([]recordedStage{
+				{"destroy", "containerizer-info", false},
+				{"destroy", "containerizer-destroy", false},
+				{"destroy", "network-destroy", false},
+				{"destroy", "volume-destroy", false},
+				{"destroy", "remove-bundle", false},
+				{"destroy", "post-destroy-hooks", false},
+				{"destroy", gardener.StageTotal, false},
+			}))
+		})
+
+		It("records each stage of a stream in", func() {
+			container, err := gdnr.Lookup("some-handle")
+			Expect(err).NotTo(HaveOccurred())
+
+			containerizer.StreamInReturns(errors.New("quota exceeded"))
+			Expect(container.StreamIn(garden.StreamInSpec{})).NotTo(Succeed())
+
+			Expect(recordedStages()).To(Equal([]recordedStage{
+				{"stream-in", "lock", false},
+				{"stream-in", "containerizer-stream-in", true},
+				{"stream-in", gardener.StageTotal, true},
+			}))
+		})
+
+		It("records a stream out as finished once the stream is closed", func() {
+			containerizer.StreamOutReturns(ioutil.NopCloser(strings.NewReader("stuff")), nil)
+
+			container, err := gdnr.Lookup("some-handle")
+			Expect(err).NotTo(HaveOccurred())
+
+			stream, err := container.StreamOut(garden.StreamOutSpec{})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(recordedStages()).To(HaveLen(2))
+
+			Expect(stream.Close()).To(Succeed())
+			Expect(recordedStages()).To(Equal([]recordedStage{
+				{"stream-out", "lock", false},
+				{"stream-out", "containerizer-stream-out", false},
+				{"stream-out", "transfer", false},
+				{"stream-out", gardener.StageTotal, false},
+			}))
+		})
+	})
+
+	Describe("tracing", func() {
+		It("traces a create, passing the trace on to the components", func() {
+			_, err := gdnr.Create(garden.ContainerSpec{Handle: "bob"})
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(exporter.ExportCallCount()).To(Equal(1))
+			span := exporter.ExportArgsForCall(0)
+			Expect(span.Name).To(Equal("create"))
+			Expect(span.Attributes).To(HaveKeyWithValue("handle", "bob"))
+			Expect(span.Error).To(BeEmpty())
+
+			volumeLog, _, _ := volumeCreator.CreateArgsForCall(0)
+			Expect(tracing.TraceID(volumeLog)).To(Equal(span.TraceID))
+
+			containerizerLog, _ := containerizer.CreateArgsForCall(0)
+			Expect(tracing.TraceID(containerizerLog)).To(Equal(span.TraceID))
+
+			networkLog, _, _ := networker.NetworkArgsForCall(0)
+			Expect(tracing.TraceID(networkLog)).To(Equal(span.TraceID))
+		})
+
+		It("logs the trace id", func() {
+			_, err := gdnr.Create(garden.ContainerSpec{Handle: "bob"})
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(logger).To(gbytes.Say(`"trace-id":"` + exporter.ExportArgsForCall(0).TraceID + `"`))
+		})
+
+		It("traces a destroy, passing the trace on to the components", func() {
+			Expect(gdnr.Destroy("some-handle")).To(Succeed())
+
+			span := exporter.ExportArgsForCall(0)
+			Expect(span.Name).To(Equal("destroy"))
+
+			containerizerLog, _ := containerizer.DestroyArgsForCall(0)
+			Expect(tracing.TraceID(containerizerLog)).To(Equal(span.TraceID))
+
+			volumeLog, _, _ := volumeCreator.DestroyArgsForCall(0)
+			Expect(tracing.TraceID(volumeLog)).To(Equal(span.TraceID))
+		})
+
+		It("records the failure of a traced call", func() {
+			containerizer.RunReturns(nil, errors.New("no such file"))
+
+			container, err := gdnr.Lookup("some-handle")
+			Expect(err).NotTo(HaveOccurred())
+
+			_, err = container.Run(garden.ProcessSpec{}, garden.ProcessIO{})
+			Expect(err).To(HaveOccurred())
+
+			span := exporter.ExportArgsForCall(0)
+			Expect(span.Name).To(Equal("run"))
+			Expect(span.Error).To(Equal("no such file"))
+
+			runLog, _, _, _ := containerizer.RunArgsForCall(0)
+			Expect(tracing.TraceID(runLog)).To(Equal(span.TraceID))
+		})
+
+		It("starts a new trace for each call", func() {
+			container, err := gdnr.Lookup("some-handle")
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(container.Stop(false)).To(Succeed())
+			Expect(container.Stop(false)).To(Succeed())
+
+			Expect(exporter.ExportArgsForCall(0).TraceID).NotTo(Equal(exporter.ExportArgsForCall(1).TraceID))
+		})
+
+		It("traces the calls which read and change a container", func() {
+			propertyManager.AllReturns(garden.Properties{}, nil)
+
+			container, err := gdnr.Lookup("some-handle")
+			Expect(err).NotTo(HaveOccurred())
+
+			container.Info()
+			container.Metrics()
+			Expect(container.LimitCPU(garden.CPULimits{LimitInShares: 10})).To(Succeed())
+			Expect(container.LimitMemory(garden.MemoryLimits{LimitInBytes: 10})).To(Succeed())
+			Expect(container.LimitBandwidth(garden.BandwidthLimits{RateInBytesPerSecond: 10})).To(Succeed())
+			Expect(container.SetProperty("foo", "bar")).To(Succeed())
+
+			names := []string{}
+			for i := 0; i < exporter.ExportCallCount(); i++ {
+				span := exporter.ExportArgsForCall(i)
+				Expect(span.Attributes).To(HaveKeyWithValue("handle", "some-handle"))
+				names = append(names, span.Name)
+			}
+			Expect(names).To(Equal([]string{"info", "metrics", "limit-cpu", "limit-memory", "limit-bandwidth", "set-property"}))
+
+			infoLog, _ := containerizer.InfoArgsForCall(0)
+			Expect(tracing.TraceID(infoLog)).To(Equal(exporter.ExportArgsForCall(0).TraceID))
+
+			metricsLog, _ := containerizer.MetricsArgsForCall(0)
+			Expect(tracing.TraceID(metricsLog)).To(Equal(exporter.ExportArgsForCall(1).TraceID))
+
+			bandwidthLog, _, _ := networker.LimitBandwidthArgsForCall(0)
+			Expect(tracing.TraceID(bandwidthLog)).To(Equal(exporter.ExportArgsForCall(4).TraceID))
+		})
+	})
+
+	Describe("draining", func() {
+		It("is not draining until stopped", func() {
+			Expect(gdnr.Draining()).To(BeFalse())
+			gdnr.Stop()
+			Expect(gdnr.Draining()).To(BeTrue())
+		})
+
+		It("rejects new creates once stopped", func() {
+			gdnr.Stop()
+
+			_, err := gdnr.Create(garden.ContainerSpec{Handle: "banana"})
+			Expect(err).To(MatchError(gardener.ErrDraining))
+			Expect(containerizer.CreateCallCount()).To(Equal(0))
+		})
+
+		It("still allows containers to be destroyed", func() {
+			gdnr.Stop()
+			Expect(gdnr.Destroy("some-handle")).To(Succeed())
+		})
+
+		It("does not time out when there is nothing in flight, even without a drain timeout", func() {
+			gdnr.DrainTimeout = 0
+
+			gdnr.Stop()
+			Expect(logger).To(gbytes.Say("drained"))
+		})
+
+		Context("when a create is in flight", func() {
+			var (
+				creating chan struct{}
+				proceed  chan struct{}
+				created  chan struct{}
+			)
+
+			BeforeEach(func() {
+				creating = make(chan struct{})
+				proceed = make(chan struct{})
+				created = make(chan struct{})
+
+				containerizer.CreateStub = func(_ lager.Logger, _ gardener.DesiredContainerSpec) error {
+					close(creating)
+					<-proceed
+					return nil
+				}
+
+				go func() {
+					defer GinkgoRecover()
+					_, err := gdnr.Create(garden.ContainerSpec{Handle: "banana"})
+					Expect(err).NotTo(HaveOccurred())
+					close(created)
+				}()
+
+				Eventually(creating).Should(BeClosed())
+			})
+
+			It("counts it as in flight", func() {
+				Expect(gdnr.InFlightOperations()).To(Equal(1))
+				close(proceed)
+				Eventually(created).Should(BeClosed())
+				Expect(gdnr.InFlightOperations()).To(Equal(0))
+			})
+
+			It("waits for it to finish", func() {
+				gdnr.DrainTimeout = time.Minute
+
+				stopped := make(chan struct{})
+				go func() {
+					defer GinkgoRecover()
+					gdnr.Stop()
+					close(stopped)
+				}()
+
+				Consistently(stopped).ShouldNot(BeClosed())
+				close(proceed)
+				Eventually(stopped).Should(BeClosed())
+				Eventually(created).Should(BeClosed())
+			})
+
+			It("gives up waiting after the drain timeout", func() {
+				gdnr.DrainTimeout = 10 * time.Millisecond
+
+				gdnr.Stop()
+				Expect(logger).To(gbytes.Say("drain-timed-out"))
+
+				close(proceed)
+				Eventually(created).Should(BeClosed())
+			})
+		})
+
+		Context("when a stream out is in flight", func() {
+			It("waits until the stream is closed", func() {
+				containerizer.StreamOutReturns(ioutil.NopCloser(strings.NewReader("potato")), nil)
+
+				container, err := gdnr.Lookup("banana")
+				Expect(err).NotTo(HaveOccurred())
+
+				stream, err := container.StreamOut(garden.StreamOutSpec{})
+				Expect(err).NotTo(HaveOccurred())
+				Expect(gdnr.InFlightOperations()).To(Equal(1))
+
+				Expect(stream.Close()).To(Succeed())
+				Expect(gdnr.InFlightOperations()).To(Equal(0))
+			})
+		})
+	})
+
+	Describe("checkpointing a container", func() {
+		It("asks the containerizer to checkpoint the container with its properties", func() {
+			propertyManager.AllReturns(garden.Properties{"foo": "bar"}, nil)
+
+			Expect(gdnr.Checkpoint("some-handle", "/path/to/archive.tar")).To(Succeed())
+
+			Expect(propertyManager.AllArgsForCall(0)).To(Equal("some-handle"))
+
+			Expect(containerizer.CheckpointCallCount()).To(Equal(1))
+			_, handle, archivePath, properties := containerizer.CheckpointArgsForCall(0)
+			Expect(handle).To(Equal("some-handle"))
+			Expect(archivePath).To(Equal("/path/to/archive.tar"))
+			Expect(properties).To(Equal(garden.Properties{"foo": "bar"}))
+		})
+
+		Context("when the containerizer fails", func() {
+			It("returns the error", func() {
+				containerizer.CheckpointReturns(errors.New("criu-says-no"))
+				Expect(gdnr.Checkpoint("some-handle", "/path/to/archive")).To(MatchError("criu-says-no"))
+			})
+		})
+	})
+
+	Describe("restoring a container", func() {
+		var (
+			archivedProperties garden.Properties
+			preparedRootFSPath string
+		)
+
+		BeforeEach(func() {
+			diskLimits, err := json.Marshal(garden.DiskLimits{ByteHard: 100})
+			Expect(err).NotTo(HaveOccurred())
+
+			archivedProperties = garden.Properties{
+				"foo":                   "bar",
+				"kawasaki.subnet":       "10.0.0.0/30",
+				gardener.ContainerIPKey: "10.0.0.2",
+				gardener.MappedPortsKey: `[{"HostPort":60001,"ContainerPort":8080},{"HostPort":60002,"ContainerPort":9090}]`,
+				gardener.DiskLimitsKey:  string(diskLimits),
+			}
+			preparedRootFSPath = ""
+
+			containerizer.HandlesReturns([]string{}, nil)
+			containerizer.InfoReturns(gardener.ActualContainerSpec{Pid: 42}, nil)
+			containerizer.RestoreStub = func(_ lager.Logger, _, _ string, prepare gardener.RestorePreparer) (garden.Properties, error) {
+				var err error
+				preparedRootFSPath, err = prepare(gardener.RestoreSpec{
+					Properties:         archivedProperties,
+					Limits:             garden.Limits{Memory: garden.MemoryLimits{LimitInBytes: 300}},
+					ArchivedRootFSPath: "/tmp/archive/rootfs",
+				})
+				if err != nil {
+					return nil, err
+				}
+
+				return archivedProperties, nil
+			}
+			volumeCreator.CreateReturns("/path/to/new/rootfs", nil, nil)
+		})
+
+		It("asks the containerizer to restore the archive under the handle", func() {
+			container, err := gdnr.Restore("new-handle", "/path/to/archive")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(container.Handle()).To(Equal("new-handle"))
+
+			Expect(containerizer.RestoreCallCount()).To(Equal(1))
+			_, handle, archivePath, _ := containerizer.RestoreArgsForCall(0)
+			Expect(handle).To(Equal("new-handle"))
+			Expect(archivePath).To(Equal("/path/to/archive"))
+		})
+
+		It("creates a volume for the container from the archived root filesystem", func() {
+			_, err := gdnr.Restore("new-handle", "/path/to/archive")
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(volumeCreator.CreateCallCount()).To(Equal(1))
+			_, handle, spec := volumeCreator.CreateArgsForCall(0)
+			Expect(handle).To(Equal("new-handle"))
+			Expect(spec.RootFS.Path).To(Equal("/tmp/archive/rootfs"))
+			Expect(spec.QuotaSize).To(BeEquivalentTo(100))
+			Expect(spec.Namespaced).To(BeFalse())
+
+			Expect(preparedRootFSPath).To(Equal("/path/to/new/rootfs"))
+		})
+
+		It("admits the container with its archived properties and limits", func() {
+			_, err := gdnr.Restore("new-handle", "/path/to/archive")
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(admitter.AdmitCallCount()).To(Equal(1))
+			_, spec := admitter.AdmitArgsForCall(0)
+			Expect(spec.Handle).To(Equal("new-handle"))
+			Expect(spec.Properties).To(HaveKeyWithValue("foo", "bar"))
+			Expect(spec.Limits.Memory.LimitInBytes).To(BeEquivalentTo(300))
+			Expect(spec.Limits.Disk.ByteHard).To(BeEquivalentTo(100))
+		})
+
+		Context("when the admitter rejects the container", func() {
+			BeforeEach(func() {
+				admitter.AdmitReturns(garden.ContainerSpec{}, errors.New("not-welcome"))
+			})
+
+			It("fails the restore without creating a volume", func() {
+				_, err := gdnr.Restore("new-handle", "/path/to/archive")
+				Expect(err).To(MatchError("not-welcome"))
+				Expect(volumeCreator.CreateCallCount()).To(Equal(0))
+				Expect(networker.NetworkCallCount()).To(Equal(0))
+			})
+		})
+
+		Context("when the container would exceed the overcommit ratio", func() {
+			BeforeEach(func() {
+				sysinfoProvider.TotalMemoryReturns(200, nil)
+				sysinfoProvider.TotalDiskReturns(1000, nil)
+				sysinfoProvider.CPUCoresReturns(2, nil)
+				gdnr.OvercommitRatio = 1
+			})
+
+			It("fails the restore without creating a volume", func() {
+				_, err := gdnr.Restore("new-handle", "/path/to/archive")
+				Expect(err).To(Equal(gardener.InsufficientResourcesError{Resource: "memory", Requested: 300, Available: 200}))
+				Expect(volumeCreator.CreateCallCount()).To(Equal(0))
+			})
+		})
+
+		It("generates a handle when none is given", func() {
+			uidGenerator.GenerateReturns("generated-handle")
+
+			container, err := gdnr.Restore("", "/path/to/archive")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(container.Handle()).To(Equal("generated-handle"))
+		})
+
+		It("networks the restored container from scratch", func() {
+			_, err := gdnr.Restore("new-handle", "/path/to/archive")
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(networker.NetworkCallCount()).To(Equal(1))
+			_, spec, pid := networker.NetworkArgsForCall(0)
+			Expect(spec).To(Equal(garden.ContainerSpec{Handle: "new-handle"}))
+			Expect(pid).To(Equal(42))
+		})
+
+		It("restores the properties which do not describe the old network", func() {
+			_, err := gdnr.Restore("new-handle", "/path/to/archive")
+			Expect(err).NotTo(HaveOccurred())
+
+			restored := garden.Properties{}
+			for i := 0; i < propertyManager.SetCallCount(); i++ {
+				handle, name, value := propertyManager.SetArgsForCall(i)
+				Expect(handle).To(Equal("new-handle"))
+				restored[name] = value
+			}
+			Expect(restored).To(Equal(garden.Properties{
+				"foo":                  "bar",
+				gardener.DiskLimitsKey: archivedProperties[gardener.DiskLimitsKey],
+				gardener.StateKey:      "created",
+			}))
+		})
+
+		It("journals each stage of the restore, and removes the entry once it has finished", func() {
+			_, err := gdnr.Restore("new-handle", "/path/to/archive")
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(journal.WriteCallCount()).To(Equal(5))
+			_, entry := journal.WriteArgsForCall(0)
+			Expect(entry.Spec.Handle).To(Equal("new-handle"))
+			Expect(entry.Spec.Properties).To(Equal(garden.Properties{
+				"foo":                  "bar",
+				gardener.DiskLimitsKey: archivedProperties[gardener.DiskLimitsKey],
+			}))
+			Expect(entry.Stages).To(BeEmpty())
+
+			_, entry = journal.WriteArgsForCall(4)
+			Expect(entry.RootFSPath).To(Equal("/path/to/new/rootfs"))
+			Expect(entry.Stages).To(Equal([]gardener.CreationStage{
+				gardener.CreationStageVolume,
+				gardener.CreationStageContainer,
+				gardener.CreationStageNetwork,
+				gardener.CreationStageHooks,
+			}))
+
+			Expect(journal.RemoveCallCount()).To(Equal(1))
+			_, handle := journal.RemoveArgsForCall(0)
+			Expect(handle).To(Equal("new-handle"))
+		})
+
+		It("runs the pre-create and post-network hooks", func() {
+			_, err := gdnr.Restore("new-handle", "/path/to/archive")
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(hooks.RunCallCount()).To(Equal(2))
+			_, stage, payload := hooks.RunArgsForCall(0)
+			Expect(stage).To(Equal(gardener.HookPreCreate))
+			Expect(payload.Handle).To(Equal("new-handle"))
+			Expect(payload.Spec.Properties).To(HaveKeyWithValue("foo", "bar"))
+
+			_, stage, _ = hooks.RunArgsForCall(1)
+			Expect(stage).To(Equal(gardener.HookPostNetwork))
+		})
+
+		It("times each stage of the restore", func() {
+			_, err := gdnr.Restore("new-handle", "/path/to/archive")
+			Expect(err).NotTo(HaveOccurred())
+
+			stages := []string{}
+			for i := 0; i < stageRecorder.RecordStageCallCount(); i++ {
+				operation, stage, _, err := stageRecorder.RecordStageArgsForCall(i)
+				Expect(operation).To(Equal("restore"))
+				Expect(err).NotTo(HaveOccurred())
+				stages = append(stages, stage)
+			}
+
+			Expect(stages).To(Equal([]string{
+				"admit",
+				"pre-create-hooks",
+				"volume-create",
+				"containerizer-restore",
+				"containerizer-info",
+				"network",
+				"post-network-hooks",
+				"set-properties",
+				gardener.StageTotal,
+			}))
+		})
+
+		Context("when a pre-create hook fails", func() {
+			BeforeEach(func() {
+				hooks.RunReturns(errors.New("audit failed"))
+			})
+
+			It("fails the restore without creating a volume", func() {
+				_, err := gdnr.Restore("new-handle", "/path/to/archive")
+				Expect(err).To(MatchError("audit failed"))
+				Expect(volumeCreator.CreateCallCount()).To(Equal(0))
+			})
+		})
+
+		It("maps fresh host ports to the previously mapped container ports", func() {
+			_, err := gdnr.Restore("new-handle", "/path/to/archive")
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(networker.NetInCallCount()).To(Equal(2))
+			_, handle, hostPort, containerPort := networker.NetInArgsForCall(0)
+			Expect(handle).To(Equal("new-handle"))
+			Expect(hostPort).To(BeEquivalentTo(0))
+			Expect(containerPort).To(BeEquivalentTo(8080))
+
+			_, _, hostPort, containerPort = networker.NetInArgsForCall(1)
+			Expect(hostPort).To(BeEquivalentTo(0))
+			Expect(containerPort).To(BeEquivalentTo(9090))
+		})
+
+		Context("when the handle is already in use", func() {
+			It("returns an error without restoring", func() {
+				containerizer.HandlesReturns([]string{"new-handle"}, nil)
+
+				_, err := gdnr.Restore("new-handle", "/path/to/archive")
+				Expect(err).To(MatchError("Handle 'new-handle' already in use"))
+				Expect(containerizer.RestoreCallCount()).To(Equal(0))
+			})
+		})
+
+		Context("when networking fails", func() {
+			BeforeEach(func() {
+				networker.NetworkReturns(errors.New("no-subnets-left"))
+			})
+
+			It("returns the error", func() {
+				_, err := gdnr.Restore("new-handle", "/path/to/archive")
+				Expect(err).To(MatchError("no-subnets-left"))
+			})
+
+			It("cleans up the restored container along with the volume created for it", func() {
+				gdnr.Restore("new-handle", "/path/to/archive")
+
+				Expect(containerizer.DestroyCallCount()).To(Equal(1))
+				Expect(containerizer.RemoveBundleCallCount()).To(Equal(1))
+				Expect(networker.DestroyCallCount()).To(Equal(1))
+
+				Expect(volumeCreator.DestroyCallCount()).To(Equal(1))
+				_, handle, rootFSPath := volumeCreator.DestroyArgsForCall(0)
+				Expect(handle).To(Equal("new-handle"))
+				Expect(rootFSPath).To(Equal("/path/to/new/rootfs"))
+			})
+
+			It("removes the journal entry once it has rolled back", func() {
+				gdnr.Restore("new-handle", "/path/to/archive")
+
+				Expect(journal.RemoveCallCount()).To(Equal(1))
+			})
+
+			Context("and the roll back fails", func() {
+				BeforeEach(func() {
+					containerizer.DestroyReturns(errors.New("still-running"))
+				})
+
+				It("leaves the journal entry so that the next start rolls it back", func() {
+					gdnr.Restore("new-handle", "/path/to/archive")
+
+					Expect(journal.RemoveCallCount()).To(Equal(0))
+				})
+			})
+
+			It("releases the resources it reserved", func() {
+				gdnr.OvercommitRatio = 1
+				sysinfoProvider.TotalMemoryReturns(1000, nil)
+				sysinfoProvider.TotalDiskReturns(1000, nil)
+				sysinfoProvider.CPUCoresReturns(2, nil)
+
+				gdnr.Restore("new-handle", "/path/to/archive")
+
+				capacity, err := gdnr.Capacity()
+				Expect(err).NotTo(HaveOccurred())
+				Expect(capacity.MemoryInBytes).To(BeEquivalentTo(1000))
+			})
+		})
+
+		Context("when draining", func() {
+			It("rejects the restore", func() {
+				gdnr.Stop()
+
+				_, err := gdnr.Restore("new-handle", "/path/to/archive")
+				Expect(err).To(MatchError(gardener.ErrDraining))
+			})
+		})
+	})
+
+	Describe("starting up gardener", func() {
+		BeforeEach(func() {
+			containers := []string{"container1", "container2"}
+			containerizer.HandlesReturns(containers, nil)
+		})
+
+		Context("when it has starters", func() {
+			var (
+				starterA, starterB *fakes.FakeStarter
+			)
+
+			BeforeEach(func() {
+				starterA = new(fakes.FakeStarter)
+				starterB = new(fakes.FakeStarter)
+				gdnr.Starters = []gardener.Starter{starterA, starterB}
+			})
+
+			It("calls the provided starters", func() {
+				Expect(gdnr.Start()).To(Succeed())
+
+				Expect(starterA.StartCallCount()).To(Equal(1))
+				Expect(starterB.StartCallCount()).To(Equal(1))
+			})
+
+			Context("when a starter fails", func() {
+				BeforeEach(func() {
+					starterB.StartReturns(errors.New("boom"))
+				})
+
+				It("returns the error", func() {
+					Expect(gdnr.Start()).To(MatchError(ContainSubstring("boom")))
+				})
+
+				It("does not restore the containers", func() {
+					Expect(gdnr.Start()).NotTo(Succeed())
+					Expect(restorer.RestoreCallCount()).To(Equal(0))
+				})
+			})
+		})
+
+		It("should restore the containers", func() {
+			Expect(gdnr.Start()).To(Succeed())
+			Expect(restorer.RestoreCallCount()).To(Equal(1))
+			_, handles := restorer.RestoreArgsForCall(0)
+			Expect(handles).To(Equal([]string{"container1", "container2"}))
+		})
+
+		It("should blow up containers that couldn't restore", func() {
+			restorer.RestoreReturns([]string{"container2"})
+			Expect(gdnr.Start()).To(Succeed())
+			Expect(containerizer.DestroyCallCount()).To(Equal(1))
+			_, handle := containerizer.DestroyArgsForCall(0)
+			Expect(handle).To(Equal("container2"))
+		})
+
+		It("should return the error when it failes to get a list of handles", func() {
+			containerizer.HandlesReturns([]string{}, errors.New("banana"))
+			Expect(gdnr.Start()).To(MatchError("banana"))
+		})
+
+		Describe("reconciling interrupted creates", func() {
+			var entries []gardener.CreationJournalEntry
+
+			BeforeEach(func() {
+				entries = []gardener.CreationJournalEntry{
+					{
+						Spec:       garden.ContainerSpec{Handle: "half-built"},
+						RootFSPath: "/path/to/rootfs",
+						Stages:     []gardener.CreationStage{gardener.CreationStageVolume, gardener.CreationStageContainer},
+					},
+					{
+						Spec: garden.ContainerSpec{
+							Handle:     "nearly-done",
+							Properties: garden.Properties{"foo": "bar"},
+						},
+						RootFSPath: "/path/to/other/rootfs",
+						Stages:     []gardener.CreationStage{gardener.CreationStageVolume, gardener.CreationStageContainer, gardener.CreationStageNetwork, gardener.CreationStageHooks},
+					},
+				}
+
+				journal.EntriesReturns(entries, nil)
+			})
+
+			It("rolls back creates which did not finish setting up the container", func() {
+				Expect(gdnr.Start()).To(Succeed())
+
+				Expect(containerizer.DestroyCallCount()).To(Equal(1))
+				_, handle := containerizer.DestroyArgsForCall(0)
+				Expect(handle).To(Equal("half-built"))
+
+				Expect(networker.DestroyCallCount()).To(Equal(1))
+				_, handle = networker.DestroyArgsForCall(0)
+				Expect(handle).To(Equal("half-built"))
+
+				Expect(volumeCreator.DestroyCallCount()).To(Equal(1))
+				_, handle, rootFSPath := volumeCreator.DestroyArgsForCall(0)
+				Expect(handle).To(Equal("half-built"))
+				Expect(rootFSPath).To(Equal("/path/to/rootfs"))
+
+				Expect(propertyManager.DestroyKeySpaceCallCount()).To(Equal(1))
+				Expect(propertyManager.DestroyKeySpaceArgsForCall(0)).To(Equal("half-built"))
+
+				Expect(containerizer.RemoveBundleCallCount()).To(Equal(1))
+				_, handle = containerizer.RemoveBundleArgsForCall(0)
+				Expect(handle).To(Equal("half-built"))
+			})
+
+			It("removes the journal entries of the creates it rolled back", func() {
+				Expect(gdnr.Start()).To(Succeed())
+
+				Expect(journal.RemoveCallCount()).To(Equal(2))
+				_, handle := journal.RemoveArgsForCall(0)
+				Expect(handle).To(Equal("half-built"))
+			})
+
+			It("does not destroy a volume which was never created", func() {
+				entries[0].RootFSPath = ""
+				entries[0].Stages = nil
+
+				Expect(gdnr.Start()).To(Succeed())
+				Expect(volumeCreator.DestroyCallCount()).To(Equal(0))
+			})
+
+			It("finishes creates which only had properties left to set", func() {
+				Expect(gdnr.Start()).To(Succeed())
+
+				Expect(propertyManager.SetCallCount()).To(Equal(2))
+				handle, name, value := propertyManager.SetArgsForCall(0)
+				Expect(handle).To(Equal("nearly-done"))
+				Expect(name).To(Equal("foo"))
+				Expect(value).To(Equal("bar"))
+
+				handle, name, value = propertyManager.SetArgsForCall(1)
+				Expect(handle).To(Equal("nearly-done"))
+				Expect(name).To(Equal("garden.state"))
+				Expect(value).To(Equal("created"))
+
+				Expect(journal.RemoveCallCount()).To(Equal(2))
+				_, handle = journal.RemoveArgsForCall(1)
+				Expect(handle).To(Equal("nearly-done"))
+			})
+
+			It("does not run the post-network hooks again", func() {
+				Expect(gdnr.Start()).To(Succeed())
+				Expect(hooks.RunCallCount()).To(Equal(0))
+			})
+
+			Context("when the create was interrupted before the post-network hooks ran", func() {
+				BeforeEach(func() {
+					entries[1].Stages = []gardener.CreationStage{gardener.CreationStageVolume, gardener.CreationStageContainer, gardener.CreationStageNetwork}
+					propertyManager.AllReturns(garden.Properties{gardener.ContainerIPKey: "10.0.0.2"}, nil)
+				})
+
+				It("runs them before finishing the create", func() {
+					hooks.RunStub = func(_ lager.Logger, _ gardener.HookStage, _ gardener.HookPayload) error {
+						Expect(propertyManager.SetCallCount()).To(Equal(0))
+						return nil
+					}
+
+					Expect(gdnr.Start()).To(Succeed())
+
+					Expect(hooks.RunCallCount()).To(Equal(1))
+					_, stage, payload := hooks.RunArgsForCall(0)
+					Expect(stage).To(Equal(gardener.HookPostNetwork))
+					Expect(payload.Handle).To(Equal("nearly-done"))
+					Expect(payload.NetworkProperties).To(Equal(garden.Properties{gardener.ContainerIPKey: "10.0.0.2"}))
+
+					Expect(journal.RemoveCallCount()).To(Equal(2))
+					_, handle := journal.RemoveArgsForCall(1)
+					Expect(handle).To(Equal("nearly-done"))
+				})
+
+				It("records that the hooks have run", func() {
+					Expect(gdnr.Start()).To(Succeed())
+
+					Expect(journal.WriteCallCount()).To(Equal(1))
+					_, entry := journal.WriteArgsForCall(0)
+					Expect(entry.Stages).To(ContainElement(gardener.CreationStageHooks))
+				})
+
+				Context("and a hook fails", func() {
+					BeforeEach(func() {
+						hooks.RunReturns(errors.New("mount failed"))
+					})
+
+					It("rolls the create back", func() {
+						Expect(gdnr.Start()).To(Succeed())
+
+						Expect(containerizer.DestroyCallCount()).To(Equal(2))
+						_, handle := containerizer.DestroyArgsForCall(1)
+						Expect(handle).To(Equal("nearly-done"))
+						Expect(journal.RemoveCallCount()).To(Equal(2))
+					})
+				})
+			})
+
+			It("reconciles before restoring the containers", func() {
+				containerizer.HandlesStub = func() ([]string, error) {
+					Expect(containerizer.RemoveBundleCallCount()).To(Equal(1))
+					return []string{"nearly-done"}, nil
+				}
+
+				Expect(gdnr.Start()).To(Succeed())
+
+				_, handles := restorer.RestoreArgsForCall(0)
+				Expect(handles).To(ConsistOf("nearly-done"))
+			})
+
+			It("reports what it did", func() {
+				Expect(gdnr.Start()).To(Succeed())
+
+				Expect(logger).To(gbytes.Say(`"finished":\["nearly-done"\]`))
+			})
+
+			Context("when a roll back fails", func() {
+				It("carries on with the other entries", func() {
+					containerizer.DestroyReturns(errors.New("runc-is-sad"))
+
+					Expect(gdnr.Start()).To(Succeed())
+					Expect(journal.RemoveCallCount()).To(Equal(1))
+					Expect(restorer.RestoreCallCount()).To(Equal(1))
+				})
+			})
+
+			Context("when the journal cannot be read", func() {
+				It("returns an error", func() {
+					journal.EntriesReturns(nil, errors.New("bad-disk"))
+					Expect(gdnr.Start()).To(MatchError("reading creation journal: bad-disk"))
+				})
+			})
+		})
+	})
+
+	Describe("listing containers", func() {
+		BeforeEach(func() {
+			containerizer.HandlesReturns([]string{"banana", "banana2", "cola"}, nil)
+		})
+
+		itOnlyMatchesFullyCreatedContainers := func(props garden.Properties) {
+			It("only matches fully created containers", func() {
+				_, err := gdnr.Containers(props)
+				Expect(err).NotTo(HaveOccurred())
+
+				props := propertyManager.MatchingHandlesArgsForCall(0)
+				Expect(props).To(HaveKeyWithValue("garden.state", "created"))
+			})
+		}
+
+		Context("when passed nil properties to match against", func() {
+			itOnlyMatchesFullyCreatedContainers(nil)
+		})
+
+		Context("when garden.Properties are passed", func() {
+			props := garden.Properties{"somename": "somevalue"}
+
+			It("only returns matching containers which the containerizer knows about", func() {
+				propertyManager.MatchingHandlesReturns([]string{"banana2", "cola", "orphan"}, nil)
+
+				c, err := gdnr.Containers(props)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(c).To(HaveLen(2))
+				Expect(c[0].Handle()).To(Equal("banana2"))
+				Expect(c[1].Handle()).To(Equal("cola"))
+			})
+
+			It("passes the properties on to be matched", func() {
+				_, err := gdnr.Containers(props)
+				Expect(err).NotTo(HaveOccurred())
+
+				Expect(propertyManager.MatchingHandlesArgsForCall(0)).To(HaveKeyWithValue("somename", "somevalue"))
+			})
+
+			itOnlyMatchesFullyCreatedContainers(props)
+		})
+
+		Context("when the properties cannot be matched", func() {
+			It("returns the error", func() {
+				propertyManager.MatchingHandlesReturns(nil, errors.New("invalid selector"))
+
+				_, err := gdnr.Containers(garden.Properties{"garden.selector": "a in ("})
+				Expect(err).To(MatchError("invalid selector"))
+			})
+		})
+	})
+
+	Context("when no containers exist", func() {
+		BeforeEach(func() {
+			containerizer.HandlesReturns([]string{}, nil)
+		})
+
+		It("should return an empty list", func() {
+			containers, err := gdnr.Containers(garden.Properties{})
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(containers).To(BeEmpty())
+		})
+	})
+
+	Context("when the containerizer returns an error", func() {
+		testErr := errors.New("failure")
+
+		BeforeEach(func() {
+			containerizer.HandlesReturns([]string{}, testErr)
+		})
+
+		It("should return an error", func() {
+			_, err := gdnr.Containers(garden.Properties{})
+			Expect(err).To(MatchError(testErr))
+		})
+	})
+
+	Describe("stopping a container", func() {
+		It("asks the containerizer to stop all processes", func() {
+			container, err := gdnr.Lookup("banana")
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(container.Stop(true)).To(Succeed())
+			Expect(containerizer.StopCallCount()).To(Equal(1))
+
+			_, handle, kill := containerizer.StopArgsForCall(0)
+			Expect(handle).To(Equal("banana"))
+			Expect(kill).To(Equal(true))
+		})
+	})
+
+	Describe("Destroy", func() {
+		It("returns garden.ContainreNotFoundError if the container handle isn't in the depot", func() {
+			containerizer.HandlesReturns([]string{}, nil)
+			Expect(gdnr.Destroy("cake!")).To(MatchError(garden.ContainerNotFoundError{Handle: "cake!"}))
+		})
+
+		It("asks the containerizer to destroy the container", func() {
+			Expect(gdnr.Destroy("some-handle")).To(Succeed())
+			Expect(containerizer.DestroyCallCount()).To(Equal(1))
+			_, handle := containerizer.DestroyArgsForCall(0)
+			Expect(handle).To(Equal("some-handle"))
+		})
+
+		It("asks the networker to destroy the container network", func() {
+			gdnr.Destroy("some-handle")
+			Expect(networker.DestroyCallCount()).To(Equal(1))
+			networkLogger, handleToDestroy := networker.DestroyArgsForCall(0)
+			Expect(handleToDestroy).To(Equal("some-handle"))
+			Expect(tracing.TraceID(networkLogger)).NotTo(BeEmpty())
+		})
+
+		It("asks the volume creator to destroy the container rootfs", func() {
+			gdnr.Destroy("some-handle")
+			Expect(volumeCreator.DestroyCallCount()).To(Equal(1))
+			_, handleToDestroy, rootFSPathToDestroy := volumeCreator.DestroyArgsForCall(0)
+			Expect(handleToDestroy).To(Equal("some-handle"))
+			Expect(rootFSPathToDestroy).To(Equal("rootfs"))
+		})
+
+		It("should destroy the key space of the property manager", func() {
+			gdnr.Destroy("some-handle")
+
+			Expect(propertyManager.DestroyKeySpaceCallCount()).To(Equal(1))
+			Expect(propertyManager.DestroyKeySpaceArgsForCall(0)).To(Equal("some-handle"))
+		})
+
+		It("asks the containerizer to remove the bundle from the depot", func() {
+			Expect(gdnr.Destroy("some-handle")).To(Succeed())
+			_, handle := containerizer.RemoveBundleArgsForCall(0)
+			Expect(handle).To(Equal("some-handle"))
+		})
+
+		Context("when containerizer fails to destroy the container", func() {
+			BeforeEach(func() {
 				containerizer.DestroyReturns(errors.New("containerized deletion failed"))
 			})
 
@@ -849,69 +2156,397 @@ var _ = Describe("Gardener", func() {
 				err := gdnr.Destroy("some-handle")
 				Expect(err).To(HaveOccurred())
 
-				Expect(networker.DestroyCallCount()).To(Equal(0))
+				Expect(networker.DestroyCallCount()).To(Equal(0))
+			})
+		})
+
+		Context("when containerizer fails to remove the bundle from the depot", func() {
+			BeforeEach(func() {
+				containerizer.DestroyReturns(errors.New("containerized deletion failed"))
+			})
+
+			It("return the error", func() {
+				err := gdnr.Destroy("some-handle")
+				Expect(err).To(MatchError("containerized deletion failed"))
+			})
+		})
+
+		Context("when network deletion fails", func() {
+			BeforeEach(func() {
+				networker.DestroyReturns(errors.New("network deletion failed"))
+			})
+
+			It("returns the error", func() {
+				err := gdnr.Destroy("some-handle")
+				Expect(err).To(MatchError("network deletion failed"))
+			})
+
+			It("should not destroy the key space of the property manager", func() {
+				err := gdnr.Destroy("some-handle")
+				Expect(err).To(HaveOccurred())
+
+				Expect(propertyManager.DestroyKeySpaceCallCount()).To(Equal(0))
+			})
+		})
+
+		Context("when destroying the rootfs fails", func() {
+			BeforeEach(func() {
+				volumeCreator.DestroyReturns(errors.New("rootfs deletion failed"))
+			})
+
+			It("returns the error", func() {
+				err := gdnr.Destroy("some-handle")
+				Expect(err).To(MatchError("rootfs deletion failed"))
+			})
+		})
+
+		Context("when destroying key space fails", func() {
+			BeforeEach(func() {
+				propertyManager.DestroyKeySpaceReturns(errors.New("key space destruction failed"))
+			})
+
+			It("returns the error", func() {
+				err := gdnr.Destroy("some-handle")
+				Expect(err).To(MatchError("key space destruction failed"))
+			})
+		})
+
+		Context("when containerizer fails to remove the bundle from the depot", func() {
+			BeforeEach(func() {
+				containerizer.RemoveBundleReturns(errors.New("bundle removal failed"))
+			})
+
+			It("return the error", func() {
+				err := gdnr.Destroy("some-handle")
+				Expect(err).To(MatchError("bundle removal failed"))
+			})
+		})
+	})
+
+	Describe("committing resources", func() {
+		memoryLimits := func(bytes uint64) garden.Limits {
+			return garden.Limits{Memory: garden.MemoryLimits{LimitInBytes: bytes}}
+		}
+
+		BeforeEach(func() {
+			sysinfoProvider.TotalMemoryReturns(1000, nil)
+			sysinfoProvider.TotalDiskReturns(1000, nil)
+			sysinfoProvider.CPUCoresReturns(2, nil)
+			networker.CapacityReturns(100)
+		})
+
+		Context("when no overcommit ratio is configured", func() {
+			It("does not limit what can be committed", func() {
+				_, err := gdnr.Create(garden.ContainerSpec{Handle: "big", Limits: memoryLimits(5000)})
+				Expect(err).NotTo(HaveOccurred())
+			})
+
+			It("reports the host's memory and disk as capacity, whatever has been committed", func() {
+				_, err := gdnr.Create(garden.ContainerSpec{
+					Handle: "bob",
+					Limits: garden.Limits{
+						Memory: garden.MemoryLimits{LimitInBytes: 300},
+						Disk:   garden.DiskLimits{ByteHard: 100},
+					},
+				})
+				Expect(err).NotTo(HaveOccurred())
+
+				capacity, err := gdnr.Capacity()
+				Expect(err).NotTo(HaveOccurred())
+				Expect(capacity.MemoryInBytes).To(BeEquivalentTo(1000))
+				Expect(capacity.DiskInBytes).To(BeEquivalentTo(1000))
+			})
+		})
+
+		Context("when an overcommit ratio is configured", func() {
+			BeforeEach(func() {
+				gdnr.OvercommitRatio = 1.5
+			})
+
+			It("allows commitments up to the ratio", func() {
+				_, err := gdnr.Create(garden.ContainerSpec{Handle: "first", Limits: memoryLimits(1200)})
+				Expect(err).NotTo(HaveOccurred())
+
+				_, err = gdnr.Create(garden.ContainerSpec{Handle: "second", Limits: memoryLimits(300)})
+				Expect(err).NotTo(HaveOccurred())
+			})
+
+			It("rejects a create which would exceed the ratio before allocating anything", func() {
+				_, err := gdnr.Create(garden.ContainerSpec{Handle: "first", Limits: memoryLimits(1200)})
+				Expect(err).NotTo(HaveOccurred())
+
+				_, err = gdnr.Create(garden.ContainerSpec{Handle: "second", Limits: memoryLimits(400)})
+				Expect(err).To(Equal(gardener.InsufficientResourcesError{Resource: "memory", Requested: 400, Available: 300}))
+				Expect(volumeCreator.CreateCallCount()).To(Equal(1))
+			})
+
+			It("accounts for CPU shares against the number of cores", func() {
+				_, err := gdnr.Create(garden.ContainerSpec{
+					Handle: "greedy",
+					Limits: garden.Limits{CPU: garden.CPULimits{LimitInShares: 3073}},
+				})
+				Expect(err).To(MatchError("insufficient cpu shares: requested 3073, 3072 available"))
+			})
+
+			It("reports the allocatable capacity which remains", func() {
+				_, err := gdnr.Create(garden.ContainerSpec{Handle: "first", Limits: memoryLimits(1200)})
+				Expect(err).NotTo(HaveOccurred())
+
+				capacity, err := gdnr.Capacity()
+				Expect(err).NotTo(HaveOccurred())
+				Expect(capacity.MemoryInBytes).To(BeEquivalentTo(300))
+				Expect(capacity.DiskInBytes).To(BeEquivalentTo(1500))
+			})
+
+			It("releases the commitment when the container is destroyed", func() {
+				_, err := gdnr.Create(garden.ContainerSpec{Handle: "first", Limits: memoryLimits(1200)})
+				Expect(err).NotTo(HaveOccurred())
+
+				containerizer.HandlesReturns([]string{"first"}, nil)
+				Expect(gdnr.Destroy("first")).To(Succeed())
+
+				_, err = gdnr.Create(garden.ContainerSpec{Handle: "second", Limits: memoryLimits(1500)})
+				Expect(err).NotTo(HaveOccurred())
+			})
+
+			It("releases the commitment when the create fails", func() {
+				containerizer.CreateReturns(errors.New("boom"))
+				_, err := gdnr.Create(garden.ContainerSpec{Handle: "first", Limits: memoryLimits(1200)})
+				Expect(err).To(MatchError("boom"))
+
+				containerizer.CreateReturns(nil)
+				_, err = gdnr.Create(garden.ContainerSpec{Handle: "second", Limits: memoryLimits(1500)})
+				Expect(err).NotTo(HaveOccurred())
+			})
+
+			Describe("changing the limits of a container", func() {
+				var container garden.Container
+
+				BeforeEach(func() {
+					var err error
+					container, err = gdnr.Create(garden.ContainerSpec{Handle: "first", Limits: memoryLimits(1000)})
+					Expect(err).NotTo(HaveOccurred())
+				})
+
+				It("allows a change which fits", func() {
+					Expect(container.LimitMemory(garden.MemoryLimits{LimitInBytes: 1500})).To(Succeed())
+					Expect(containerizer.UpdateLimitsCallCount()).To(Equal(1))
+				})
+
+				It("rejects a change which does not fit without applying it", func() {
+					err := container.LimitMemory(garden.MemoryLimits{LimitInBytes: 1501})
+					Expect(err).To(MatchError("insufficient memory: requested 1501, 1500 available"))
+					Expect(containerizer.UpdateLimitsCallCount()).To(Equal(0))
+				})
+
+				It("keeps the old commitment if applying the change fails", func() {
+					containerizer.UpdateLimitsReturns(errors.New("cgroup says no"))
+					Expect(container.LimitMemory(garden.MemoryLimits{LimitInBytes: 200})).NotTo(Succeed())
+
+					capacity, err := gdnr.Capacity()
+					Expect(err).NotTo(HaveOccurred())
+					Expect(capacity.MemoryInBytes).To(BeEquivalentTo(500))
+				})
+			})
+
+			Context("when gardener restarts", func() {
+				BeforeEach(func() {
+					containerizer.HandlesReturns([]string{"survivor"}, nil)
+					containerizer.InfoReturns(gardener.ActualContainerSpec{Limits: memoryLimits(1000)}, nil)
+					restorer.RestoreReturns(nil)
+				})
+
+				It("rebuilds the commitments of the surviving containers", func() {
+					Expect(gdnr.Start()).To(Succeed())
+
+					_, err := gdnr.Create(garden.ContainerSpec{Handle: "new", Limits: memoryLimits(501)})
+					Expect(err).To(MatchError("insufficient memory: requested 501, 500 available"))
+				})
+			})
+		})
+	})
+
+	Describe("owner quotas", func() {
+		ownedBy := func(handle, owner string, memory uint64) garden.ContainerSpec {
+			return garden.ContainerSpec{
+				Handle:     handle,
+				Properties: garden.Properties{"owner": owner},
+				Limits:     garden.Limits{Memory: garden.MemoryLimits{LimitInBytes: memory}},
+			}
+		}
+
+		BeforeEach(func() {
+			gdnr.OwnerProperty = "owner"
+			gdnr.OwnerQuotas = gardener.OwnerQuotas{
+				Owners: map[string]gardener.Quota{
+					"alice": {MaxContainers: 1},
+					"bob":   {MemoryInBytes: 100},
+					"carol": {DiskInBytes: 100},
+				},
+			}
+		})
+
+		It("rejects a create which would exceed the owner's container count", func() {
+			_, err := gdnr.Create(ownedBy("first", "alice", 0))
+			Expect(err).NotTo(HaveOccurred())
+
+			_, err = gdnr.Create(ownedBy("second", "alice", 0))
+			Expect(err).To(Equal(gardener.QuotaExceededError{Owner: "alice", Resource: "containers", Limit: 1, Requested: 2}))
+			Expect(containerizer.CreateCallCount()).To(Equal(1))
+		})
+
+		It("rejects a create which would exceed the owner's memory", func() {
+			_, err := gdnr.Create(ownedBy("first", "bob", 60))
+			Expect(err).NotTo(HaveOccurred())
+
+			_, err = gdnr.Create(ownedBy("second", "bob", 41))
+			Expect(err).To(MatchError("quota exceeded for owner 'bob': memory would be 101, limit is 100"))
+		})
+
+		It("rejects a create which would exceed the owner's disk", func() {
+			spec := ownedBy("first", "carol", 0)
+			spec.Limits.Disk.ByteHard = 101
+
+			_, err := gdnr.Create(spec)
+			Expect(err).To(Equal(gardener.QuotaExceededError{Owner: "carol", Resource: "disk", Limit: 100, Requested: 101}))
+		})
+
+		It("does not count other owners' containers", func() {
+			_, err := gdnr.Create(ownedBy("first", "alice", 0))
+			Expect(err).NotTo(HaveOccurred())
+
+			_, err = gdnr.Create(ownedBy("second", "bob", 100))
+			Expect(err).NotTo(HaveOccurred())
+		})
+
+		It("does not limit containers without an owner", func() {
+			_, err := gdnr.Create(garden.ContainerSpec{Handle: "first"})
+			Expect(err).NotTo(HaveOccurred())
+
+			_, err = gdnr.Create(garden.ContainerSpec{Handle: "second"})
+			Expect(err).NotTo(HaveOccurred())
+		})
+
+		It("does not limit owners without a quota", func() {
+			_, err := gdnr.Create(ownedBy("first", "dave", 1000))
+			Expect(err).NotTo(HaveOccurred())
+
+			_, err = gdnr.Create(ownedBy("second", "dave", 1000))
+			Expect(err).NotTo(HaveOccurred())
+		})
+
+		Context("when there is a default quota", func() {
+			BeforeEach(func() {
+				gdnr.OwnerQuotas.Default = &gardener.Quota{MaxContainers: 1}
+			})
+
+			It("applies it to owners without a quota of their own", func() {
+				_, err := gdnr.Create(ownedBy("first", "dave", 0))
+				Expect(err).NotTo(HaveOccurred())
+
+				_, err = gdnr.Create(ownedBy("second", "dave", 0))
+				Expect(err).To(MatchError("quota exceeded for owner 'dave': containers would be 2, limit is 1"))
+			})
+
+			It("does not apply it to containers without an owner", func() {
+				_, err := gdnr.Create(garden.ContainerSpec{Handle: "first"})
+				Expect(err).NotTo(HaveOccurred())
+
+				_, err = gdnr.Create(garden.ContainerSpec{Handle: "second"})
+				Expect(err).NotTo(HaveOccurred())
+			})
+
+			It("does not apply it to owners with a quota of their own", func() {
+				_, err := gdnr.Create(ownedBy("first", "bob", 10))
+				Expect(err).NotTo(HaveOccurred())
+
+				_, err = gdnr.Create(ownedBy("second", "bob", 10))
+				Expect(err).NotTo(HaveOccurred())
 			})
 		})
 
-		Context("when containerizer fails to remove the bundle from the depot", func() {
+		Context("when there is an unowned quota", func() {
 			BeforeEach(func() {
-				containerizer.DestroyReturns(errors.New("containerized deletion failed"))
+				gdnr.OwnerQuotas.Unowned = &gardener.Quota{MaxContainers: 1}
 			})
 
-			It("return the error", func() {
-				err := gdnr.Destroy("some-handle")
-				Expect(err).To(MatchError("containerized deletion failed"))
+			It("applies it to the containers without an owner together", func() {
+				_, err := gdnr.Create(garden.ContainerSpec{Handle: "first"})
+				Expect(err).NotTo(HaveOccurred())
+
+				_, err = gdnr.Create(garden.ContainerSpec{Handle: "second"})
+				Expect(err).To(Equal(gardener.QuotaExceededError{Resource: "containers", Limit: 1, Requested: 2}))
 			})
 		})
 
-		Context("when network deletion fails", func() {
-			BeforeEach(func() {
-				networker.DestroyReturns(errors.New("network deletion failed"))
-			})
+		It("starts the message of the error with a stable prefix", func() {
+			_, err := gdnr.Create(ownedBy("first", "alice", 0))
+			Expect(err).NotTo(HaveOccurred())
 
-			It("returns the error", func() {
-				err := gdnr.Destroy("some-handle")
-				Expect(err).To(MatchError("network deletion failed"))
-			})
+			_, err = gdnr.Create(ownedBy("second", "alice", 0))
+			Expect(err.Error()).To(HavePrefix(gardener.QuotaExceededPrefix))
+		})
 
-			It("should not destroy the key space of the property manager", func() {
-				err := gdnr.Destroy("some-handle")
-				Expect(err).To(HaveOccurred())
+		It("records the owner when the container is created", func() {
+			_, err := gdnr.Create(ownedBy("first", "alice", 0))
+			Expect(err).NotTo(HaveOccurred())
 
-				Expect(propertyManager.DestroyKeySpaceCallCount()).To(Equal(0))
-			})
+			Expect(propertyManager.SetCallCount()).To(Equal(2))
+			handle, name, value := propertyManager.SetArgsForCall(0)
+			Expect(handle).To(Equal("first"))
+			Expect(name).To(Equal("owner"))
+			Expect(value).To(Equal("alice"))
 		})
 
-		Context("when destroying the rootfs fails", func() {
-			BeforeEach(func() {
-				volumeCreator.DestroyReturns(errors.New("rootfs deletion failed"))
-			})
+		It("does not allow the owner to be changed once the container is created", func() {
+			container, err := gdnr.Create(ownedBy("first", "alice", 0))
+			Expect(err).NotTo(HaveOccurred())
 
-			It("returns the error", func() {
-				err := gdnr.Destroy("some-handle")
-				Expect(err).To(MatchError("rootfs deletion failed"))
-			})
+			Expect(container.SetProperty("owner", "dave")).To(Equal(gardener.ImmutablePropertyError{Name: "owner"}))
+			Expect(container.RemoveProperty("owner")).To(Equal(gardener.ImmutablePropertyError{Name: "owner"}))
+
+			Expect(propertyManager.SetCallCount()).To(Equal(2))
+			Expect(propertyManager.RemoveCallCount()).To(Equal(0))
 		})
 
-		Context("when destroying key space fails", func() {
-			BeforeEach(func() {
-				propertyManager.DestroyKeySpaceReturns(errors.New("key space destruction failed"))
-			})
+		It("gives the quota back when a container is destroyed", func() {
+			_, err := gdnr.Create(ownedBy("first", "alice", 0))
+			Expect(err).NotTo(HaveOccurred())
 
-			It("returns the error", func() {
-				err := gdnr.Destroy("some-handle")
-				Expect(err).To(MatchError("key space destruction failed"))
-			})
+			containerizer.HandlesReturns([]string{"first"}, nil)
+			Expect(gdnr.Destroy("first")).To(Succeed())
+
+			_, err = gdnr.Create(ownedBy("second", "alice", 0))
+			Expect(err).NotTo(HaveOccurred())
 		})
 
-		Context("when containerizer fails to remove the bundle from the depot", func() {
+		It("rejects a memory limit change which would exceed the owner's quota", func() {
+			container, err := gdnr.Create(ownedBy("first", "bob", 50))
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(container.LimitMemory(garden.MemoryLimits{LimitInBytes: 100})).To(Succeed())
+			Expect(container.LimitMemory(garden.MemoryLimits{LimitInBytes: 101})).To(MatchError(ContainSubstring("quota exceeded for owner 'bob'")))
+			Expect(containerizer.UpdateLimitsCallCount()).To(Equal(1))
+		})
+
+		Context("when gardener restarts", func() {
 			BeforeEach(func() {
-				containerizer.RemoveBundleReturns(errors.New("bundle removal failed"))
+				containerizer.HandlesReturns([]string{"survivor"}, nil)
+				propertyManager.GetStub = func(handle, name string) (string, bool) {
+					if handle == "survivor" && name == "owner" {
+						return "alice", true
+					}
+
+					return "", false
+				}
+				restorer.RestoreReturns(nil)
 			})
 
-			It("return the error", func() {
-				err := gdnr.Destroy("some-handle")
-				Expect(err).To(MatchError("bundle removal failed"))
+			It("counts the surviving containers against their owner", func() {
+				Expect(gdnr.Start()).To(Succeed())
+
+				_, err := gdnr.Create(ownedBy("new", "alice", 0))
+				Expect(err).To(BeAssignableToTypeOf(gardener.QuotaExceededError{}))
 			})
 		})
 	})
@@ -1036,6 +2671,46 @@ var _ = Describe("Gardener", func() {
 			Expect(handle).To(Equal("some-handle"))
 			Expect(name).To(Equal("name"))
 		})
+
+		Context("when the properties include internal state", func() {
+			BeforeEach(func() {
+				propertyManager.AllReturns(garden.Properties{
+					"name":                  "value",
+					"kawasaki.subnet":       "10.0.0.0/30",
+					"rundmc.events":         "[]",
+					"rundmc.state":          "running",
+					gardener.ContainerIPKey: "10.0.0.2",
+					gardener.GraceTimeKey:   "1000",
+					gardener.DiskLimitsKey:  "{}",
+					gardener.StateKey:       "created",
+				}, nil)
+			})
+
+			It("leaves it out of Properties", func() {
+				Expect(container.Properties()).To(Equal(garden.Properties{"name": "value"}))
+			})
+
+			It("still returns it from Property when asked for by name", func() {
+				propertyManager.GetReturns("10.0.0.0/30", true)
+				Expect(container.Property("kawasaki.subnet")).To(Equal("10.0.0.0/30"))
+			})
+		})
+
+		DescribeTable("writing to an internal property",
+			func(name string) {
+				Expect(container.SetProperty(name, "corrupt")).To(Equal(gardener.ReservedPropertyError{Name: name}))
+				Expect(container.RemoveProperty(name)).To(Equal(gardener.ReservedPropertyError{Name: name}))
+
+				Expect(propertyManager.SetCallCount()).To(Equal(0))
+				Expect(propertyManager.RemoveCallCount()).To(Equal(0))
+			},
+			Entry("kawasaki", "kawasaki.iptable-prefix"),
+			Entry("rundmc events", "rundmc.events"),
+			Entry("rundmc state", "rundmc.state"),
+			Entry("network", gardener.ContainerIPKey),
+			Entry("grace time", gardener.GraceTimeKey),
+			Entry("container state", gardener.StateKey),
+		)
 	})
 
 	Describe("Info", func() {
@@ -1074,6 +2749,16 @@ var _ = Describe("Gardener", func() {
 			Expect(info.State).To(Equal("stopped"))
 		})
 
+		It("returns state as 'paused' when the actual container is paused", func() {
+			containerizer.InfoReturns(gardener.ActualContainerSpec{
+				Paused: true,
+			}, nil)
+
+			info, err := container.Info()
+			Expect(err).NotTo(HaveOccurred())
+			Expect(info.State).To(Equal("paused"))
+		})
+
 		It("returns the garden.network.container-ip property from the propertyManager as the ContainerIP", func() {
 			info, err := container.Info()
 			Expect(err).NotTo(HaveOccurred())
@@ -1137,10 +2822,12 @@ var _ = Describe("Gardener", func() {
 			})
 		})
 
-		It("returns the container properties", func() {
+		It("returns the container properties without internal state", func() {
 			propertyManager.AllReturns(garden.Properties{
-				"spider": "man",
-				"super":  "man",
+				"spider":                "man",
+				"super":                 "man",
+				"kawasaki.mtu":          "1500",
+				gardener.ContainerIPKey: "1.2.3.4",
 			}, nil)
 
 			info, err := container.Info()
@@ -1251,6 +2938,97 @@ var _ = Describe("Gardener", func() {
 		})
 	})
 
+	Describe("bulk calls", func() {
+		var (
+			release chan struct{}
+			running chan string
+		)
+
+		BeforeEach(func() {
+			release = make(chan struct{})
+			running = make(chan string, 10)
+
+			containerizer.MetricsStub = func(_ lager.Logger, handle string) (gardener.ActualContainerMetrics, error) {
+				running <- handle
+				if handle != "fast" {
+					<-release
+				}
+
+				return gardener.ActualContainerMetrics{}, nil
+			}
+		})
+
+		AfterEach(func() {
+			close(release)
+		})
+
+		It("looks at no more containers at once than there are workers", func() {
+			gdnr.BulkWorkers = 2
+
+			go gdnr.BulkMetrics([]string{"a", "b", "c", "d"})
+
+			Eventually(running).Should(HaveLen(2))
+			Consistently(running).Should(HaveLen(2))
+		})
+
+		Context("when a container is slower than the timeout", func() {
+			BeforeEach(func() {
+				gdnr.BulkTimeout = 50 * time.Millisecond
+			})
+
+			It("returns an error entry for it and the others' results", func() {
+				metrics, err := gdnr.BulkMetrics([]string{"slow", "fast"})
+				Expect(err).NotTo(HaveOccurred())
+
+				Expect(metrics["slow"].Err).To(MatchError("container slow did not respond within 50ms"))
+				Expect(metrics["fast"].Err).To(BeNil())
+			})
+
+			It("does not call it again while it is still stuck, but still answers for the others", func() {
+				gdnr.BulkWorkers = 1
+
+				metrics, err := gdnr.BulkMetrics([]string{"slow"})
+				Expect(err).NotTo(HaveOccurred())
+				Expect(metrics["slow"].Err).To(HaveOccurred())
+
+				metrics, err = gdnr.BulkMetrics([]string{"slow", "fast"})
+				Expect(err).NotTo(HaveOccurred())
+				Expect(metrics["slow"].Err).To(MatchError("container slow did not respond within 50ms"))
+				Expect(metrics["fast"].Err).To(BeNil())
+
+				Expect(containerizer.MetricsCallCount()).To(Equal(2))
+			})
+
+			It("calls it again once the stuck call has returned", func() {
+				metrics, err := gdnr.BulkMetrics([]string{"slow"})
+				Expect(err).NotTo(HaveOccurred())
+				Expect(metrics["slow"].Err).To(HaveOccurred())
+
+				release <- struct{}{}
+				Eventually(func() int {
+					gdnr.BulkMetrics([]string{"slow"})
+					return containerizer.MetricsCallCount()
+				}).Should(Equal(2))
+			})
+
+			It("does the same for info", func() {
+				containerizer.InfoStub = func(_ lager.Logger, handle string) (gardener.ActualContainerSpec, error) {
+					if handle == "slow" {
+						<-release
+					}
+
+					return gardener.ActualContainerSpec{}, nil
+				}
+
+				infos, err := gdnr.BulkInfo([]string{"slow", "fast"})
+				Expect(err).NotTo(HaveOccurred())
+
+				Expect(infos["slow"].Err).To(MatchError("container slow did not respond within 50ms"))
+				Expect(infos["fast"].Err).To(BeNil())
+			})
+		})
+	})
+
 	Describe("Metrics", func() {
 		var (
 			container garden.Container
@@ -1328,6 +3106,125 @@ var _ = Describe("Gardener", func() {
 			})
 		})
 
+		It("should return the network metrics from the networker", func() {
+			networker.StatsReturns(gardener.ContainerNetworkStat{RxBytes: 1, TxBytes: 2, RxPackets: 3, TxPackets: 4}, nil)
+
+			metrics, err := container.Metrics()
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(metrics.NetworkStat).To(Equal(garden.ContainerNetworkStat{RxBytes: 1, TxBytes: 2}))
+			_, handle := networker.StatsArgsForCall(0)
+			Expect(handle).To(Equal("some-handle"))
+		})
+
+		Context("when network metrics cannot be acquired", func() {
+			BeforeEach(func() {
+				networker.StatsReturns(gardener.ContainerNetworkStat{RxBytes: 1}, errors.New("banana"))
+				containerizer.MetricsReturns(gardener.ActualContainerMetrics{CPU: cpuStat}, nil)
+			})
+
+			It("returns the other metrics with no network traffic", func() {
+				metrics, err := container.Metrics()
+				Expect(err).NotTo(HaveOccurred())
+
+				Expect(metrics.CPUStat).To(Equal(cpuStat))
+				Expect(metrics.NetworkStat).To(Equal(garden.ContainerNetworkStat{}))
+			})
+
+			It("logs the error", func() {
+				_, err := container.Metrics()
+				Expect(err).NotTo(HaveOccurred())
+
+				Expect(logger).To(gbytes.Say("network-stats-failed"))
+			})
+
+			It("does not fail the container's bulk metrics entry", func() {
+				metrics, err := gdnr.BulkMetrics([]string{"some-handle"})
+				Expect(err).NotTo(HaveOccurred())
+
+				Expect(metrics["some-handle"].Err).To(BeNil())
+				Expect(metrics["some-handle"].Metrics.CPUStat).To(Equal(cpuStat))
+			})
+
+			It("reports the network traffic as unavailable in the extended metrics", func() {
+				metrics, err := gdnr.ExtendedMetrics("some-handle")
+				Expect(err).NotTo(HaveOccurred())
+
+				Expect(metrics.NetworkTraffic).To(BeNil())
+			})
+		})
+
+		Context("when the networker cannot report network stats", func() {
+			BeforeEach(func() {
+				networker.StatsReturns(gardener.ContainerNetworkStat{}, gardener.ErrNetworkStatsUnavailable)
+				containerizer.MetricsReturns(gardener.ActualContainerMetrics{CPU: cpuStat}, nil)
+			})
+
+			It("reports the network traffic as unavailable without logging an error", func() {
+				metrics, err := gdnr.ExtendedMetrics("some-handle")
+				Expect(err).NotTo(HaveOccurred())
+
+				Expect(metrics.CPUStat).To(Equal(cpuStat))
+				Expect(metrics.NetworkTraffic).To(BeNil())
+				Expect(logger).NotTo(gbytes.Say("network-stats-failed"))
+			})
+		})
+
+		Describe("extended metrics", func() {
+			BeforeEach(func() {
+				networker.StatsReturns(gardener.ContainerNetworkStat{RxBytes: 1, TxBytes: 2, RxPackets: 3, TxPackets: 4}, nil)
+				containerizer.MetricsReturns(gardener.ActualContainerMetrics{
+					CPU:        cpuStat,
+					Memory:     memoryStat,
+					Pid:        gardener.ContainerPidStat{Current: 5, Max: 100},
+					BlockIO:    []gardener.ContainerBlockIOStat{{Major: 8, ReadBytes: 10}},
+					Throttling: gardener.ContainerCPUThrottlingStat{Periods: 10, ThrottledPeriods: 2},
+					Hugetlb:    map[string]gardener.ContainerHugetlbStat{"2MB": {Usage: 1}},
+					CPURate:    gardener.ContainerCPURateStat{Interval: time.Second, Percent: 50},
+				}, nil)
+			})
+
+			It("returns the garden metrics along with the extended ones", func() {
+				metrics, err := gdnr.ExtendedMetrics("some-handle")
+				Expect(err).NotTo(HaveOccurred())
+
+				Expect(metrics).To(Equal(gardener.ExtendedMetrics{
+					Metrics: garden.Metrics{
+						CPUStat:    cpuStat,
+						MemoryStat: memoryStat,
+						DiskStat:   diskStat,
+						NetworkStat: garden.ContainerNetworkStat{
+							RxBytes: 1,
+							TxBytes: 2,
+						},
+					},
+					PidStat:        gardener.ContainerPidStat{Current: 5, Max: 100},
+					BlockIOStats:   []gardener.ContainerBlockIOStat{{Major: 8, ReadBytes: 10}},
+					ThrottlingStat: gardener.ContainerCPUThrottlingStat{Periods: 10, ThrottledPeriods: 2},
+					HugetlbStats:   map[string]gardener.ContainerHugetlbStat{"2MB": {Usage: 1}},
+					CPURateStat:    gardener.ContainerCPURateStat{Interval: time.Second, Percent: 50},
+					NetworkTraffic: &gardener.ContainerNetworkStat{RxBytes: 1, TxBytes: 2, RxPackets: 3, TxPackets: 4},
+				}))
+			})
+
+			It("returns them in bulk", func() {
+				containerizer.MetricsStub = func(_ lager.Logger, id string) (gardener.ActualContainerMetrics, error) {
+					if id == "potato" {
+						return gardener.ActualContainerMetrics{}, errors.New("potatoError")
+					}
+
+					return gardener.ActualContainerMetrics{Pid: gardener.ContainerPidStat{Current: 5}}, nil
+				}
+
+				metrics, err := gdnr.BulkExtendedMetrics([]string{"some-handle", "potato"})
+				Expect(err).NotTo(HaveOccurred())
+
+				Expect(metrics["some-handle"].Metrics.PidStat.Current).To(BeEquivalentTo(5))
+				Expect(metrics["some-handle"].Err).To(BeNil())
+				Expect(metrics["potato"].Err).To(Equal(garden.NewError("potatoError")))
+			})
+		})
+
 		It("should return BulkMetrics", func() {
 			containerizer.MetricsStub = func(_ lager.Logger, id string) (gardener.ActualContainerMetrics, error) {
 				if id == "potato" {
@@ -1403,10 +3300,202 @@ var _ = Describe("Gardener", func() {
 
 				_, err = container.CurrentMemoryLimits()
 				Expect(err).To(MatchError("some-error"))
+
+				Expect(container.LimitCPU(garden.CPULimits{})).To(MatchError("some-error"))
+				Expect(container.LimitMemory(garden.MemoryLimits{})).To(MatchError("some-error"))
+				Expect(containerizer.UpdateLimitsCallCount()).To(Equal(0))
+			})
+		})
+
+		It("asks the networker to limit the bandwidth", func() {
+			limits := garden.BandwidthLimits{RateInBytesPerSecond: 10, BurstRateInBytesPerSecond: 20}
+			Expect(container.LimitBandwidth(limits)).To(Succeed())
+
+			Expect(networker.LimitBandwidthCallCount()).To(Equal(1))
+			_, handle, appliedLimits := networker.LimitBandwidthArgsForCall(0)
+			Expect(handle).To(Equal("some-handle"))
+			Expect(appliedLimits).To(Equal(limits))
+		})
+
+		It("gets the bandwidth limits from the networker", func() {
+			networker.BandwidthLimitsReturns(garden.BandwidthLimits{RateInBytesPerSecond: 10}, nil)
+
+			currentBandwidthLimits, err := container.CurrentBandwidthLimits()
+			Expect(err).ToNot(HaveOccurred())
+			Expect(currentBandwidthLimits.RateInBytesPerSecond).To(BeEquivalentTo(10))
+		})
+
+		Context("when the networker fails to limit the bandwidth", func() {
+			It("forwards the error", func() {
+				networker.LimitBandwidthReturns(errors.New("tc-error"))
+				Expect(container.LimitBandwidth(garden.BandwidthLimits{})).To(MatchError("tc-error"))
+			})
+		})
+
+		Describe("disk limits", func() {
+			BeforeEach(func() {
+				containerizer.InfoReturns(gardener.ActualContainerSpec{
+					RootFSPath: "/path/to/rootfs",
+				}, nil)
+			})
+
+			It("asks the volume creator to resize the container's rootfs", func() {
+				limits := garden.DiskLimits{ByteHard: 1024, Scope: garden.DiskLimitScopeExclusive}
+				Expect(container.LimitDisk(limits)).To(Succeed())
+
+				Expect(volumeCreator.ResizeCallCount()).To(Equal(1))
+				_, handle, rootFSPath, resizedLimits := volumeCreator.ResizeArgsForCall(0)
+				Expect(handle).To(Equal("some-handle"))
+				Expect(rootFSPath).To(Equal("/path/to/rootfs"))
+				Expect(resizedLimits).To(Equal(limits))
+			})
+
+			It("remembers the new disk limit", func() {
+				Expect(container.LimitDisk(garden.DiskLimits{ByteHard: 1024})).To(Succeed())
+
+				Expect(propertyManager.SetCallCount()).To(Equal(1))
+				handle, name, value := propertyManager.SetArgsForCall(0)
+				Expect(handle).To(Equal("some-handle"))
+				Expect(name).To(Equal(gardener.DiskLimitsKey))
+
+				var limits garden.DiskLimits
+				Expect(json.Unmarshal([]byte(value), &limits)).To(Succeed())
+				Expect(limits).To(Equal(garden.DiskLimits{ByteHard: 1024}))
+			})
+
+			It("gets the remembered disk limit", func() {
+				limitsCfg, err := json.Marshal(garden.DiskLimits{ByteHard: 1024, Scope: garden.DiskLimitScopeExclusive})
+				Expect(err).NotTo(HaveOccurred())
+				propertyManager.GetReturns(string(limitsCfg), true)
+
+				currentDiskLimits, err := container.CurrentDiskLimits()
+				Expect(err).NotTo(HaveOccurred())
+				Expect(currentDiskLimits).To(Equal(garden.DiskLimits{ByteHard: 1024, Scope: garden.DiskLimitScopeExclusive}))
+
+				_, name := propertyManager.GetArgsForCall(0)
+				Expect(name).To(Equal(gardener.DiskLimitsKey))
+			})
+
+			Context("when no disk limit was set", func() {
+				It("returns empty limits", func() {
+					propertyManager.GetReturns("", false)
+
+					Expect(container.CurrentDiskLimits()).To(Equal(garden.DiskLimits{}))
+				})
+			})
+
+			Context("when the volume creator fails to resize", func() {
+				BeforeEach(func() {
+					volumeCreator.ResizeReturns(errors.New("resize-error"))
+				})
+
+				It("forwards the error", func() {
+					Expect(container.LimitDisk(garden.DiskLimits{ByteHard: 1024})).To(MatchError("resize-error"))
+				})
+
+				It("does not remember the new limit", func() {
+					container.LimitDisk(garden.DiskLimits{ByteHard: 1024})
+					Expect(propertyManager.SetCallCount()).To(Equal(0))
+				})
+			})
+		})
+
+		Describe("updating limits", func() {
+			BeforeEach(func() {
+				containerizer.InfoReturns(gardener.ActualContainerSpec{
+					Limits: garden.Limits{
+						CPU:    garden.CPULimits{LimitInShares: 10},
+						Memory: garden.MemoryLimits{LimitInBytes: 20},
+					},
+				}, nil)
+			})
+
+			It("asks the containerizer to update the CPU limit, keeping the memory limit", func() {
+				Expect(container.LimitCPU(garden.CPULimits{LimitInShares: 30})).To(Succeed())
+
+				Expect(containerizer.UpdateLimitsCallCount()).To(Equal(1))
+				_, handle, limits := containerizer.UpdateLimitsArgsForCall(0)
+				Expect(handle).To(Equal("some-handle"))
+				Expect(limits.CPU.LimitInShares).To(BeEquivalentTo(30))
+				Expect(limits.Memory.LimitInBytes).To(BeEquivalentTo(20))
+			})
+
+			It("asks the containerizer to update the memory limit, keeping the CPU limit", func() {
+				Expect(container.LimitMemory(garden.MemoryLimits{LimitInBytes: 40})).To(Succeed())
+
+				Expect(containerizer.UpdateLimitsCallCount()).To(Equal(1))
+				_, handle, limits := containerizer.UpdateLimitsArgsForCall(0)
+				Expect(handle).To(Equal("some-handle"))
+				Expect(limits.CPU.LimitInShares).To(BeEquivalentTo(10))
+				Expect(limits.Memory.LimitInBytes).To(BeEquivalentTo(40))
+			})
+
+			Context("when the containerizer fails to update the limits", func() {
+				It("forwards the error", func() {
+					containerizer.UpdateLimitsReturns(errors.New("update-error"))
+
+					Expect(container.LimitCPU(garden.CPULimits{})).To(MatchError("update-error"))
+					Expect(container.LimitMemory(garden.MemoryLimits{})).To(MatchError("update-error"))
+				})
+			})
+		})
+	})
+
+	Describe("pausing and resuming", func() {
+		var container gardener.Pausable
+
+		BeforeEach(func() {
+			c, err := gdnr.Lookup("some-handle")
+			Expect(err).NotTo(HaveOccurred())
+
+			var ok bool
+			container, ok = c.(gardener.Pausable)
+			Expect(ok).To(BeTrue())
+		})
+
+		It("asks the containerizer to pause the container", func() {
+			Expect(container.Pause()).To(Succeed())
+
+			Expect(containerizer.PauseCallCount()).To(Equal(1))
+			_, handle := containerizer.PauseArgsForCall(0)
+			Expect(handle).To(Equal("some-handle"))
+		})
+
+		It("asks the containerizer to resume the container", func() {
+			Expect(container.Resume()).To(Succeed())
+
+			Expect(containerizer.ResumeCallCount()).To(Equal(1))
+			_, handle := containerizer.ResumeArgsForCall(0)
+			Expect(handle).To(Equal("some-handle"))
+		})
+
+		Context("when the containerizer fails", func() {
+			It("forwards the error", func() {
+				containerizer.PauseReturns(errors.New("frozen-solid"))
+				containerizer.ResumeReturns(errors.New("still-frozen"))
+
+				Expect(container.Pause()).To(MatchError("frozen-solid"))
+				Expect(container.Resume()).To(MatchError("still-frozen"))
 			})
 		})
 	})
 
+	Describe("subscribing to events", func() {
+		It("subscribes to the event log for the handle", func() {
+			events := make(chan gardener.Event)
+			unsubscribed := false
+			eventLog.SubscribeReturns(events, func() { unsubscribed = true })
+
+			ch, unsubscribe := gdnr.SubscribeEvents("some-handle")
+			Expect(eventLog.SubscribeCallCount()).To(Equal(1))
+			Expect(eventLog.SubscribeArgsForCall(0)).To(Equal("some-handle"))
+			Expect(ch).To(Equal((<-chan gardener.Event)(events)))
+
+			unsubscribe()
+			Expect(unsubscribed).To(BeTrue())
+		})
+	})
+
 	Describe("GraceTime", func() {
 		var container garden.Container
 
@@ -0,0 +1,81 @@
+// This file was generated by counterfeiter
+package gardenerfakes
+
+import (
+	"code.cloudfoundry.org/garden"
+	"code.cloudfoundry.org/guardian/gardener"
+	"code.cloudfoundry.org/lager"
+	"sync"
+)
+
+type FakeAdmitter struct {
+	AdmitStub        func(log lager.Logger, spec garden.ContainerSpec) (garden.ContainerSpec, error)
+	admitMutex       sync.RWMutex
+	admitArgsForCall []struct {
+		log  lager.Logger
+		spec garden.ContainerSpec
+	}
+	admitReturns struct {
+		result1 garden.ContainerSpec
+		result2 error
+	}
+	invocations      map[string][][]interface{}
+	invocationsMutex sync.RWMutex
+}
+
+func (fake *FakeAdmitter) Admit(log lager.Logger, spec garden.ContainerSpec) (garden.ContainerSpec, error) {
+	fake.admitMutex.Lock()
+	fake.admitArgsForCall = append(fake.admitArgsForCall, struct {
+		log  lager.Logger
+		spec garden.ContainerSpec
+	}{log, spec})
+	fake.recordInvocation("Admit", []interface{}{log, spec})
+	fake.admitMutex.Unlock()
+	if fake.AdmitStub != nil {
+		return fake.AdmitStub(log, spec)
+	} else {
+		return fake.admitReturns.result1, fake.admitReturns.result2
+	}
+}
+
+func (fake *FakeAdmitter) AdmitCallCount() int {
+	fake.admitMutex.RLock()
+	defer fake.admitMutex.RUnlock()
+	return len(fake.admitArgsForCall)
+}
+
+func (fake *FakeAdmitter) AdmitArgsForCall(i int) (lager.Logger, garden.ContainerSpec) {
+	fake.admitMutex.RLock()
+	defer fake.admitMutex.RUnlock()
+	return fake.admitArgsForCall[i].log, fake.admitArgsForCall[i].spec
+}
+
+func (fake *FakeAdmitter) AdmitReturns(result1 garden.ContainerSpec, result2 error) {
+	fake.AdmitStub = nil
+	fake.admitReturns = struct {
+		result1 garden.ContainerSpec
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeAdmitter) Invocations() map[string][][]interface{} {
+	fake.invocationsMutex.RLock()
+	defer fake.invocationsMutex.RUnlock()
+	fake.admitMutex.RLock()
+	defer fake.admitMutex.RUnlock()
+	return fake.invocations
+}
+
+func (fake *FakeAdmitter) recordInvocation(key string, args []interface{}) {
+	fake.invocationsMutex.Lock()
+	defer fake.invocationsMutex.Unlock()
+	if fake.invocations == nil {
+		fake.invocations = map[string][][]interface{}{}
+	}
+	if fake.invocations[key] == nil {
+		fake.invocations[key] = [][]interface{}{}
+	}
+	fake.invocations[key] = append(fake.invocations[key], args)
+}
+
+var _ gardener.Admitter = new(FakeAdmitter)
@@ -120,6 +120,57 @@ type FakeContainerizer struct {
 		result1 gardener.ActualContainerMetrics
 		result2 error
 	}
+	UpdateLimitsStub        func(log lager.Logger, handle string, limits garden.Limits) error
+	updateLimitsMutex       sync.RWMutex
+	updateLimitsArgsForCall []struct {
+		log    lager.Logger
+		handle string
+		limits garden.Limits
+	}
+	updateLimitsReturns struct {
+		result1 error
+	}
+	PauseStub        func(log lager.Logger, handle string) error
+	pauseMutex       sync.RWMutex
+	pauseArgsForCall []struct {
+		log    lager.Logger
+		handle string
+	}
+	pauseReturns struct {
+		result1 error
+	}
+	ResumeStub        func(log lager.Logger, handle string) error
+	resumeMutex       sync.RWMutex
+	resumeArgsForCall []struct {
+		log    lager.Logger
+		handle string
+	}
+	resumeReturns struct {
+		result1 error
+	}
+	CheckpointStub        func(log lager.Logger, handle, archivePath string, properties garden.Properties) error
+	checkpointMutex       sync.RWMutex
+	checkpointArgsForCall []struct {
+		log         lager.Logger
+		handle      string
+		archivePath string
+		properties  garden.Properties
+	}
+	checkpointReturns struct {
+		result1 error
+	}
+	RestoreStub        func(log lager.Logger, handle, archivePath string, prepare gardener.RestorePreparer) (garden.Properties, error)
+	restoreMutex       sync.RWMutex
+	restoreArgsForCall []struct {
+		log         lager.Logger
+		handle      string
+		archivePath string
+		prepare     gardener.RestorePreparer
+	}
+	restoreReturns struct {
+		result1 garden.Properties
+		result2 error
+	}
 	invocations      map[string][][]interface{}
 	invocationsMutex sync.RWMutex
 }
@@ -502,6 +553,182 @@ func (fake *FakeContainerizer) MetricsReturns(result1 gardener.ActualContainerMe
 	}{result1, result2}
 }
 
+func (fake *FakeContainerizer) UpdateLimits(log lager.Logger, handle string, limits garden.Limits) error {
+	fake.updateLimitsMutex.Lock()
+	fake.updateLimitsArgsForCall = append(fake.updateLimitsArgsForCall, struct {
+		log    lager.Logger
+		handle string
+		limits garden.Limits
+	}{log, handle, limits})
+	fake.recordInvocation("UpdateLimits", []interface{}{log, handle, limits})
+	fake.updateLimitsMutex.Unlock()
+	if fake.UpdateLimitsStub != nil {
+		return fake.UpdateLimitsStub(log, handle, limits)
+	} else {
+		return fake.updateLimitsReturns.result1
+	}
+}
+
+func (fake *FakeContainerizer) UpdateLimitsCallCount() int {
+	fake.updateLimitsMutex.RLock()
+	defer fake.updateLimitsMutex.RUnlock()
+	return len(fake.updateLimitsArgsForCall)
+}
+
+func (fake *FakeContainerizer) UpdateLimitsArgsForCall(i int) (lager.Logger, string, garden.Limits) {
+	fake.updateLimitsMutex.RLock()
+	defer fake.updateLimitsMutex.RUnlock()
+	return fake.updateLimitsArgsForCall[i].log, fake.updateLimitsArgsForCall[i].handle, fake.updateLimitsArgsForCall[i].limits
+}
+
+func (fake *FakeContainerizer) UpdateLimitsReturns(result1 error) {
+	fake.UpdateLimitsStub = nil
+	fake.updateLimitsReturns = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *FakeContainerizer) Pause(log lager.Logger, handle string) error {
+	fake.pauseMutex.Lock()
+	fake.pauseArgsForCall = append(fake.pauseArgsForCall, struct {
+		log    lager.Logger
+		handle string
+	}{log, handle})
+	fake.recordInvocation("Pause", []interface{}{log, handle})
+	fake.pauseMutex.Unlock()
+	if fake.PauseStub != nil {
+		return fake.PauseStub(log, handle)
+	} else {
+		return fake.pauseReturns.result1
+	}
+}
+
+func (fake *FakeContainerizer) PauseCallCount() int {
+	fake.pauseMutex.RLock()
+	defer fake.pauseMutex.RUnlock()
+	return len(fake.pauseArgsForCall)
+}
+
+func (fake *FakeContainerizer) PauseArgsForCall(i int) (lager.Logger, string) {
+	fake.pauseMutex.RLock()
+	defer fake.pauseMutex.RUnlock()
+	return fake.pauseArgsForCall[i].log, fake.pauseArgsForCall[i].handle
+}
+
+func (fake *FakeContainerizer) PauseReturns(result1 error) {
+	fake.PauseStub = nil
+	fake.pauseReturns = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *FakeContainerizer) Resume(log lager.Logger, handle string) error {
+	fake.resumeMutex.Lock()
+	fake.resumeArgsForCall = append(fake.resumeArgsForCall, struct {
+		log    lager.Logger
+		handle string
+	}{log, handle})
+	fake.recordInvocation("Resume", []interface{}{log, handle})
+	fake.resumeMutex.Unlock()
+	if fake.ResumeStub != nil {
+		return fake.ResumeStub(log, handle)
+	} else {
+		return fake.resumeReturns.result1
+	}
+}
+
+func (fake *FakeContainerizer) ResumeCallCount() int {
+	fake.resumeMutex.RLock()
+	defer fake.resumeMutex.RUnlock()
+	return len(fake.resumeArgsForCall)
+}
+
+func (fake *FakeContainerizer) ResumeArgsForCall(i int) (lager.Logger, string) {
+	fake.resumeMutex.RLock()
+	defer fake.resumeMutex.RUnlock()
+	return fake.resumeArgsForCall[i].log, fake.resumeArgsForCall[i].handle
+}
+
+func (fake *FakeContainerizer) ResumeReturns(result1 error) {
+	fake.ResumeStub = nil
+	fake.resumeReturns = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *FakeContainerizer) Checkpoint(log lager.Logger, handle string, archivePath string, properties garden.Properties) error {
+	fake.checkpointMutex.Lock()
+	fake.checkpointArgsForCall = append(fake.checkpointArgsForCall, struct {
+		log         lager.Logger
+		handle      string
+		archivePath string
+		properties  garden.Properties
+	}{log, handle, archivePath, properties})
+	fake.recordInvocation("Checkpoint", []interface{}{log, handle, archivePath, properties})
+	fake.checkpointMutex.Unlock()
+	if fake.CheckpointStub != nil {
+		return fake.CheckpointStub(log, handle, archivePath, properties)
+	} else {
+		return fake.checkpointReturns.result1
+	}
+}
+
+func (fake *FakeContainerizer) CheckpointCallCount() int {
+	fake.checkpointMutex.RLock()
+	defer fake.checkpointMutex.RUnlock()
+	return len(fake.checkpointArgsForCall)
+}
+
+func (fake *FakeContainerizer) CheckpointArgsForCall(i int) (lager.Logger, string, string, garden.Properties) {
+	fake.checkpointMutex.RLock()
+	defer fake.checkpointMutex.RUnlock()
+	return fake.checkpointArgsForCall[i].log, fake.checkpointArgsForCall[i].handle, fake.checkpointArgsForCall[i].archivePath, fake.checkpointArgsForCall[i].properties
+}
+
+func (fake *FakeContainerizer) CheckpointReturns(result1 error) {
+	fake.CheckpointStub = nil
+	fake.checkpointReturns = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *FakeContainerizer) Restore(log lager.Logger, handle string, archivePath string, prepare gardener.RestorePreparer) (garden.Properties, error) {
+	fake.restoreMutex.Lock()
+	fake.restoreArgsForCall = append(fake.restoreArgsForCall, struct {
+		log         lager.Logger
+		handle      string
+		archivePath string
+		prepare     gardener.RestorePreparer
+	}{log, handle, archivePath, prepare})
+	fake.recordInvocation("Restore", []interface{}{log, handle, archivePath, prepare})
+	fake.restoreMutex.Unlock()
+	if fake.RestoreStub != nil {
+		return fake.RestoreStub(log, handle, archivePath, prepare)
+	} else {
+		return fake.restoreReturns.result1, fake.restoreReturns.result2
+	}
+}
+
+func (fake *FakeContainerizer) RestoreCallCount() int {
+	fake.restoreMutex.RLock()
+	defer fake.restoreMutex.RUnlock()
+	return len(fake.restoreArgsForCall)
+}
+
+func (fake *FakeContainerizer) RestoreArgsForCall(i int) (lager.Logger, string, string, gardener.RestorePreparer) {
+	fake.restoreMutex.RLock()
+	defer fake.restoreMutex.RUnlock()
+	return fake.restoreArgsForCall[i].log, fake.restoreArgsForCall[i].handle, fake.restoreArgsForCall[i].archivePath, fake.restoreArgsForCall[i].prepare
+}
+
+func (fake *FakeContainerizer) RestoreReturns(result1 garden.Properties, result2 error) {
+	fake.RestoreStub = nil
+	fake.restoreReturns = struct {
+		result1 garden.Properties
+		result2 error
+	}{result1, result2}
+}
+
 func (fake *FakeContainerizer) Invocations() map[string][][]interface{} {
 	fake.invocationsMutex.RLock()
 	defer fake.invocationsMutex.RUnlock()
@@ -527,6 +754,16 @@ func (fake *FakeContainerizer) Invocations() map[string][][]interface{} {
 	defer fake.infoMutex.RUnlock()
 	fake.metricsMutex.RLock()
 	defer fake.metricsMutex.RUnlock()
+	fake.updateLimitsMutex.RLock()
+	defer fake.updateLimitsMutex.RUnlock()
+	fake.pauseMutex.RLock()
+	defer fake.pauseMutex.RUnlock()
+	fake.resumeMutex.RLock()
+	defer fake.resumeMutex.RUnlock()
+	fake.checkpointMutex.RLock()
+	defer fake.checkpointMutex.RUnlock()
+	fake.restoreMutex.RLock()
+	defer fake.restoreMutex.RUnlock()
 	return fake.invocations
 }
 
@@ -0,0 +1,169 @@
+// This file was generated by counterfeiter
+package gardenerfakes
+
+import (
+	"sync"
+
+	"code.cloudfoundry.org/guardian/gardener"
+	"code.cloudfoundry.org/lager"
+)
+
+type FakeCreationJournal struct {
+	WriteStub        func(log lager.Logger, entry gardener.CreationJournalEntry) error
+	writeMutex       sync.RWMutex
+	writeArgsForCall []struct {
+		log   lager.Logger
+		entry gardener.CreationJournalEntry
+	}
+	writeReturns struct {
+		result1 error
+	}
+	RemoveStub        func(log lager.Logger, handle string) error
+	removeMutex       sync.RWMutex
+	removeArgsForCall []struct {
+		log    lager.Logger
+		handle string
+	}
+	removeReturns struct {
+		result1 error
+	}
+	EntriesStub        func(log lager.Logger) ([]gardener.CreationJournalEntry, error)
+	entriesMutex       sync.RWMutex
+	entriesArgsForCall []struct {
+		log lager.Logger
+	}
+	entriesReturns struct {
+		result1 []gardener.CreationJournalEntry
+		result2 error
+	}
+	invocations      map[string][][]interface{}
+	invocationsMutex sync.RWMutex
+}
+
+func (fake *FakeCreationJournal) Write(log lager.Logger, entry gardener.CreationJournalEntry) error {
+	fake.writeMutex.Lock()
+	fake.writeArgsForCall = append(fake.writeArgsForCall, struct {
+		log   lager.Logger
+		entry gardener.CreationJournalEntry
+	}{log, entry})
+	fake.recordInvocation("Write", []interface{}{log, entry})
+	fake.writeMutex.Unlock()
+	if fake.WriteStub != nil {
+		return fake.WriteStub(log, entry)
+	} else {
+		return fake.writeReturns.result1
+	}
+}
+
+func (fake *FakeCreationJournal) WriteCallCount() int {
+	fake.writeMutex.RLock()
+	defer fake.writeMutex.RUnlock()
+	return len(fake.writeArgsForCall)
+}
+
+func (fake *FakeCreationJournal) WriteArgsForCall(i int) (lager.Logger, gardener.CreationJournalEntry) {
+	fake.writeMutex.RLock()
+	defer fake.writeMutex.RUnlock()
+	return fake.writeArgsForCall[i].log, fake.writeArgsForCall[i].entry
+}
+
+func (fake *FakeCreationJournal) WriteReturns(result1 error) {
+	fake.WriteStub = nil
+	fake.writeReturns = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *FakeCreationJournal) Remove(log lager.Logger, handle string) error {
+	fake.removeMutex.Lock()
+	fake.removeArgsForCall = append(fake.removeArgsForCall, struct {
+		log    lager.Logger
+		handle string
+	}{log, handle})
+	fake.recordInvocation("Remove", []interface{}{log, handle})
+	fake.removeMutex.Unlock()
+	if fake.RemoveStub != nil {
+		return fake.RemoveStub(log, handle)
+	} else {
+		return fake.removeReturns.result1
+	}
+}
+
+func (fake *FakeCreationJournal) RemoveCallCount() int {
+	fake.removeMutex.RLock()
+	defer fake.removeMutex.RUnlock()
+	return len(fake.removeArgsForCall)
+}
+
+func (fake *FakeCreationJournal) RemoveArgsForCall(i int) (lager.Logger, string) {
+	fake.removeMutex.RLock()
+	defer fake.removeMutex.RUnlock()
+	return fake.removeArgsForCall[i].log, fake.removeArgsForCall[i].handle
+}
+
+func (fake *FakeCreationJournal) RemoveReturns(result1 error) {
+	fake.RemoveStub = nil
+	fake.removeReturns = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *FakeCreationJournal) Entries(log lager.Logger) ([]gardener.CreationJournalEntry, error) {
+	fake.entriesMutex.Lock()
+	fake.entriesArgsForCall = append(fake.entriesArgsForCall, struct {
+		log lager.Logger
+	}{log})
+	fake.recordInvocation("Entries", []interface{}{log})
+	fake.entriesMutex.Unlock()
+	if fake.EntriesStub != nil {
+		return fake.EntriesStub(log)
+	} else {
+		return fake.entriesReturns.result1, fake.entriesReturns.result2
+	}
+}
+
+func (fake *FakeCreationJournal) EntriesCallCount() int {
+	fake.entriesMutex.RLock()
+	defer fake.entriesMutex.RUnlock()
+	return len(fake.entriesArgsForCall)
+}
+
+func (fake *FakeCreationJournal) EntriesArgsForCall(i int) lager.Logger {
+	fake.entriesMutex.RLock()
+	defer fake.entriesMutex.RUnlock()
+	return fake.entriesArgsForCall[i].log
+}
+
+func (fake *FakeCreationJournal) EntriesReturns(result1 []gardener.CreationJournalEntry, result2 error) {
+	fake.EntriesStub = nil
+	fake.entriesReturns = struct {
+		result1 []gardener.CreationJournalEntry
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeCreationJournal) Invocations() map[string][][]interface{} {
+	fake.invocationsMutex.RLock()
+	defer fake.invocationsMutex.RUnlock()
+	fake.writeMutex.RLock()
+	defer fake.writeMutex.RUnlock()
+	fake.removeMutex.RLock()
+	defer fake.removeMutex.RUnlock()
+	fake.entriesMutex.RLock()
+	defer fake.entriesMutex.RUnlock()
+	return fake.invocations
+}
+
+func (fake *FakeCreationJournal) recordInvocation(key string, args []interface{}) {
+	fake.invocationsMutex.Lock()
+	defer fake.invocationsMutex.Unlock()
+	if fake.invocations == nil {
+		fake.invocations = map[string][][]interface{}{}
+	}
+	if fake.invocations[key] == nil {
+		fake.invocations[key] = [][]interface{}{}
+	}
+	fake.invocations[key] = append(fake.invocations[key], args)
+}
+
+var _ gardener.CreationJournal = new(FakeCreationJournal)
@@ -0,0 +1,166 @@
+// This file was generated by counterfeiter
+package gardenerfakes
+
+import (
+	"sync"
+
+	"code.cloudfoundry.org/guardian/gardener"
+)
+
+type FakeEventLog struct {
+	OnEventStub        func(handle string, event gardener.Event) error
+	onEventMutex       sync.RWMutex
+	onEventArgsForCall []struct {
+		handle string
+		event  gardener.Event
+	}
+	onEventReturns struct {
+		result1 error
+	}
+	EventsStub        func(handle string) []gardener.Event
+	eventsMutex       sync.RWMutex
+	eventsArgsForCall []struct {
+		handle string
+	}
+	eventsReturns struct {
+		result1 []gardener.Event
+	}
+	SubscribeStub        func(handle string) (<-chan gardener.Event, func())
+	subscribeMutex       sync.RWMutex
+	subscribeArgsForCall []struct {
+		handle string
+	}
+	subscribeReturns struct {
+		result1 <-chan gardener.Event
+		result2 func()
+	}
+	invocations      map[string][][]interface{}
+	invocationsMutex sync.RWMutex
+}
+
+func (fake *FakeEventLog) OnEvent(handle string, event gardener.Event) error {
+	fake.onEventMutex.Lock()
+	fake.onEventArgsForCall = append(fake.onEventArgsForCall, struct {
+		handle string
+		event  gardener.Event
+	}{handle, event})
+	fake.recordInvocation("OnEvent", []interface{}{handle, event})
+	fake.onEventMutex.Unlock()
+	if fake.OnEventStub != nil {
+		return fake.OnEventStub(handle, event)
+	} else {
+		return fake.onEventReturns.result1
+	}
+}
+
+func (fake *FakeEventLog) OnEventCallCount() int {
+	fake.onEventMutex.RLock()
+	defer fake.onEventMutex.RUnlock()
+	return len(fake.onEventArgsForCall)
+}
+
+func (fake *FakeEventLog) OnEventArgsForCall(i int) (string, gardener.Event) {
+	fake.onEventMutex.RLock()
+	defer fake.onEventMutex.RUnlock()
+	return fake.onEventArgsForCall[i].handle, fake.onEventArgsForCall[i].event
+}
+
+func (fake *FakeEventLog) OnEventReturns(result1 error) {
+	fake.OnEventStub = nil
+	fake.onEventReturns = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *FakeEventLog) Events(handle string) []gardener.Event {
+	fake.eventsMutex.Lock()
+	fake.eventsArgsForCall = append(fake.eventsArgsForCall, struct {
+		handle string
+	}{handle})
+	fake.recordInvocation("Events", []interface{}{handle})
+	fake.eventsMutex.Unlock()
+	if fake.EventsStub != nil {
+		return fake.EventsStub(handle)
+	} else {
+		return fake.eventsReturns.result1
+	}
+}
+
+func (fake *FakeEventLog) EventsCallCount() int {
+	fake.eventsMutex.RLock()
+	defer fake.eventsMutex.RUnlock()
+	return len(fake.eventsArgsForCall)
+}
+
+func (fake *FakeEventLog) EventsArgsForCall(i int) string {
+	fake.eventsMutex.RLock()
+	defer fake.eventsMutex.RUnlock()
+	return fake.eventsArgsForCall[i].handle
+}
+
+func (fake *FakeEventLog) EventsReturns(result1 []gardener.Event) {
+	fake.EventsStub = nil
+	fake.eventsReturns = struct {
+		result1 []gardener.Event
+	}{result1}
+}
+
+func (fake *FakeEventLog) Subscribe(handle string) (<-chan gardener.Event, func()) {
+	fake.subscribeMutex.Lock()
+	fake.subscribeArgsForCall = append(fake.subscribeArgsForCall, struct {
+		handle string
+	}{handle})
+	fake.recordInvocation("Subscribe", []interface{}{handle})
+	fake.subscribeMutex.Unlock()
+	if fake.SubscribeStub != nil {
+		return fake.SubscribeStub(handle)
+	} else {
+		return fake.subscribeReturns.result1, fake.subscribeReturns.result2
+	}
+}
+
+func (fake *FakeEventLog) SubscribeCallCount() int {
+	fake.subscribeMutex.RLock()
+	defer fake.subscribeMutex.RUnlock()
+	return len(fake.subscribeArgsForCall)
+}
+
+func (fake *FakeEventLog) SubscribeArgsForCall(i int) string {
+	fake.subscribeMutex.RLock()
+	defer fake.subscribeMutex.RUnlock()
+	return fake.subscribeArgsForCall[i].handle
+}
+
+func (fake *FakeEventLog) SubscribeReturns(result1 <-chan gardener.Event, result2 func()) {
+	fake.SubscribeStub = nil
+	fake.subscribeReturns = struct {
+		result1 <-chan gardener.Event
+		result2 func()
+	}{result1, result2}
+}
+
+func (fake *FakeEventLog) Invocations() map[string][][]interface{} {
+	fake.invocationsMutex.RLock()
+	defer fake.invocationsMutex.RUnlock()
+	fake.onEventMutex.RLock()
+	defer fake.onEventMutex.RUnlock()
+	fake.eventsMutex.RLock()
+	defer fake.eventsMutex.RUnlock()
+	fake.subscribeMutex.RLock()
+	defer fake.subscribeMutex.RUnlock()
+	return fake.invocations
+}
+
+func (fake *FakeEventLog) recordInvocation(key string, args []interface{}) {
+	fake.invocationsMutex.Lock()
+	defer fake.invocationsMutex.Unlock()
+	if fake.invocations == nil {
+		fake.invocations = map[string][][]interface{}{}
+	}
+	if fake.invocations[key] == nil {
+		fake.invocations[key] = [][]interface{}{}
+	}
+	fake.invocations[key] = append(fake.invocations[key], args)
+}
+
+var _ gardener.EventLog = new(FakeEventLog)
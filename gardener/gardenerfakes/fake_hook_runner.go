@@ -0,0 +1,81 @@
+// This file was generated by counterfeiter
+package gardenerfakes
+
+import (
+	"sync"
+
+	"code.cloudfoundry.org/guardian/gardener"
+	"code.cloudfoundry.org/lager"
+)
+
+type FakeHookRunner struct {
+	RunStub        func(log lager.Logger, stage gardener.HookStage, payload gardener.HookPayload) error
+	runMutex       sync.RWMutex
+	runArgsForCall []struct {
+		log     lager.Logger
+		stage   gardener.HookStage
+		payload gardener.HookPayload
+	}
+	runReturns struct {
+		result1 error
+	}
+	invocations      map[string][][]interface{}
+	invocationsMutex sync.RWMutex
+}
+
+func (fake *FakeHookRunner) Run(log lager.Logger, stage gardener.HookStage, payload gardener.HookPayload) error {
+	fake.runMutex.Lock()
+	fake.runArgsForCall = append(fake.runArgsForCall, struct {
+		log     lager.Logger
+		stage   gardener.HookStage
+		payload gardener.HookPayload
+	}{log, stage, payload})
+	fake.recordInvocation("Run", []interface{}{log, stage, payload})
+	fake.runMutex.Unlock()
+	if fake.RunStub != nil {
+		return fake.RunStub(log, stage, payload)
+	} else {
+		return fake.runReturns.result1
+	}
+}
+
+func (fake *FakeHookRunner) RunCallCount() int {
+	fake.runMutex.RLock()
+	defer fake.runMutex.RUnlock()
+	return len(fake.runArgsForCall)
+}
+
+func (fake *FakeHookRunner) RunArgsForCall(i int) (lager.Logger, gardener.HookStage, gardener.HookPayload) {
+	fake.runMutex.RLock()
+	defer fake.runMutex.RUnlock()
+	return fake.runArgsForCall[i].log, fake.runArgsForCall[i].stage, fake.runArgsForCall[i].payload
+}
+
+func (fake *FakeHookRunner) RunReturns(result1 error) {
+	fake.RunStub = nil
+	fake.runReturns = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *FakeHookRunner) Invocations() map[string][][]interface{} {
+	fake.invocationsMutex.RLock()
+	defer fake.invocationsMutex.RUnlock()
+	fake.runMutex.RLock()
+	defer fake.runMutex.RUnlock()
+	return fake.invocations
+}
+
+func (fake *FakeHookRunner) recordInvocation(key string, args []interface{}) {
+	fake.invocationsMutex.Lock()
+	defer fake.invocationsMutex.Unlock()
+	if fake.invocations == nil {
+		fake.invocations = map[string][][]interface{}{}
+	}
+	if fake.invocations[key] == nil {
+		fake.invocations[key] = [][]interface{}{}
+	}
+	fake.invocations[key] = append(fake.invocations[key], args)
+}
+
+var _ gardener.HookRunner = new(FakeHookRunner)
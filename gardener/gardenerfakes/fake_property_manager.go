@@ -44,14 +44,14 @@ type FakePropertyManager struct {
 		result1 string
 		result2 bool
 	}
-	MatchesAllStub        func(handle string, props garden.Properties) bool
-	matchesAllMutex       sync.RWMutex
-	matchesAllArgsForCall []struct {
-		handle string
-		props  garden.Properties
+	MatchingHandlesStub        func(props garden.Properties) ([]string, error)
+	matchingHandlesMutex       sync.RWMutex
+	matchingHandlesArgsForCall []struct {
+		props garden.Properties
 	}
-	matchesAllReturns struct {
-		result1 bool
+	matchingHandlesReturns struct {
+		result1 []string
+		result2 error
 	}
 	DestroyKeySpaceStub        func(string) error
 	destroyKeySpaceMutex       sync.RWMutex
@@ -194,38 +194,38 @@ func (fake *FakePropertyManager) GetReturns(result1 string, result2 bool) {
 	}{result1, result2}
 }
 
-func (fake *FakePropertyManager) MatchesAll(handle string, props garden.Properties) bool {
-	fake.matchesAllMutex.Lock()
-	fake.matchesAllArgsForCall = append(fake.matchesAllArgsForCall, struct {
-		handle string
-		props  garden.Properties
-	}{handle, props})
-	fake.recordInvocation("MatchesAll", []interface{}{handle, props})
-	fake.matchesAllMutex.Unlock()
-	if fake.MatchesAllStub != nil {
-		return fake.MatchesAllStub(handle, props)
+func (fake *FakePropertyManager) MatchingHandles(props garden.Properties) ([]string, error) {
+	fake.matchingHandlesMutex.Lock()
+	fake.matchingHandlesArgsForCall = append(fake.matchingHandlesArgsForCall, struct {
+		props garden.Properties
+	}{props})
+	fake.recordInvocation("MatchingHandles", []interface{}{props})
+	fake.matchingHandlesMutex.Unlock()
+	if fake.MatchingHandlesStub != nil {
+		return fake.MatchingHandlesStub(props)
 	} else {
-		return fake.matchesAllReturns.result1
+		return fake.matchingHandlesReturns.result1, fake.matchingHandlesReturns.result2
 	}
 }
 
-func (fake *FakePropertyManager) MatchesAllCallCount() int {
-	fake.matchesAllMutex.RLock()
-	defer fake.matchesAllMutex.RUnlock()
-	return len(fake.matchesAllArgsForCall)
+func (fake *FakePropertyManager) MatchingHandlesCallCount() int {
+	fake.matchingHandlesMutex.RLock()
+	defer fake.matchingHandlesMutex.RUnlock()
+	return len(fake.matchingHandlesArgsForCall)
 }
 
-func (fake *FakePropertyManager) MatchesAllArgsForCall(i int) (string, garden.Properties) {
-	fake.matchesAllMutex.RLock()
-	defer fake.matchesAllMutex.RUnlock()
-	return fake.matchesAllArgsForCall[i].handle, fake.matchesAllArgsForCall[i].props
+func (fake *FakePropertyManager) MatchingHandlesArgsForCall(i int) garden.Properties {
+	fake.matchingHandlesMutex.RLock()
+	defer fake.matchingHandlesMutex.RUnlock()
+	return fake.matchingHandlesArgsForCall[i].props
 }
 
-func (fake *FakePropertyManager) MatchesAllReturns(result1 bool) {
-	fake.MatchesAllStub = nil
-	fake.matchesAllReturns = struct {
-		result1 bool
-	}{result1}
+func (fake *FakePropertyManager) MatchingHandlesReturns(result1 []string, result2 error) {
+	fake.MatchingHandlesStub = nil
+	fake.matchingHandlesReturns = struct {
+		result1 []string
+		result2 error
+	}{result1, result2}
 }
 
 func (fake *FakePropertyManager) DestroyKeySpace(arg1 string) error {
@@ -272,8 +272,8 @@ func (fake *FakePropertyManager) Invocations() map[string][][]interface{} {
 	defer fake.removeMutex.RUnlock()
 	fake.getMutex.RLock()
 	defer fake.getMutex.RUnlock()
-	fake.matchesAllMutex.RLock()
-	defer fake.matchesAllMutex.RUnlock()
+	fake.matchingHandlesMutex.RLock()
+	defer fake.matchingHandlesMutex.RUnlock()
 	fake.destroyKeySpaceMutex.RLock()
 	defer fake.destroyKeySpaceMutex.RUnlock()
 	return fake.invocations
@@ -0,0 +1,70 @@
+// This file was generated by counterfeiter
+package gardenerfakes
+
+import (
+	"code.cloudfoundry.org/guardian/gardener"
+	"sync"
+	"time"
+)
+
+type FakeStageRecorder struct {
+	RecordStageStub        func(operation string, stage string, took time.Duration, err error)
+	recordStageMutex       sync.RWMutex
+	recordStageArgsForCall []struct {
+		operation string
+		stage     string
+		took      time.Duration
+		err       error
+	}
+	invocations      map[string][][]interface{}
+	invocationsMutex sync.RWMutex
+}
+
+func (fake *FakeStageRecorder) RecordStage(operation string, stage string, took time.Duration, err error) {
+	fake.recordStageMutex.Lock()
+	fake.recordStageArgsForCall = append(fake.recordStageArgsForCall, struct {
+		operation string
+		stage     string
+		took      time.Duration
+		err       error
+	}{operation, stage, took, err})
+	fake.recordInvocation("RecordStage", []interface{}{operation, stage, took, err})
+	fake.recordStageMutex.Unlock()
+	if fake.RecordStageStub != nil {
+		fake.RecordStageStub(operation, stage, took, err)
+	}
+}
+
+func (fake *FakeStageRecorder) RecordStageCallCount() int {
+	fake.recordStageMutex.RLock()
+	defer fake.recordStageMutex.RUnlock()
+	return len(fake.recordStageArgsForCall)
+}
+
+func (fake *FakeStageRecorder) RecordStageArgsForCall(i int) (string, string, time.Duration, error) {
+	fake.recordStageMutex.RLock()
+	defer fake.recordStageMutex.RUnlock()
+	return fake.recordStageArgsForCall[i].operation, fake.recordStageArgsForCall[i].stage, fake.recordStageArgsForCall[i].took, fake.recordStageArgsForCall[i].err
+}
+
+func (fake *FakeStageRecorder) Invocations() map[string][][]interface{} {
+	fake.invocationsMutex.RLock()
+	defer fake.invocationsMutex.RUnlock()
+	fake.recordStageMutex.RLock()
+	defer fake.recordStageMutex.RUnlock()
+	return fake.invocations
+}
+
+func (fake *FakeStageRecorder) recordInvocation(key string, args []interface{}) {
+	fake.invocationsMutex.Lock()
+	defer fake.invocationsMutex.Unlock()
+	if fake.invocations == nil {
+		fake.invocations = map[string][][]interface{}{}
+	}
+	if fake.invocations[key] == nil {
+		fake.invocations[key] = [][]interface{}{}
+	}
+	fake.invocations[key] = append(fake.invocations[key], args)
+}
+
+var _ gardener.StageRecorder = new(FakeStageRecorder)
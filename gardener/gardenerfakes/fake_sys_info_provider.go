@@ -22,6 +22,13 @@ type FakeSysInfoProvider struct {
 		result1 uint64
 		result2 error
 	}
+	CPUCoresStub        func() (int, error)
+	cPUCoresMutex       sync.RWMutex
+	cPUCoresArgsForCall []struct{}
+	cPUCoresReturns     struct {
+		result1 int
+		result2 error
+	}
 	invocations      map[string][][]interface{}
 	invocationsMutex sync.RWMutex
 }
@@ -78,6 +85,32 @@ func (fake *FakeSysInfoProvider) TotalDiskReturns(result1 uint64, result2 error)
 	}{result1, result2}
 }
 
+func (fake *FakeSysInfoProvider) CPUCores() (int, error) {
+	fake.cPUCoresMutex.Lock()
+	fake.cPUCoresArgsForCall = append(fake.cPUCoresArgsForCall, struct{}{})
+	fake.recordInvocation("CPUCores", []interface{}{})
+	fake.cPUCoresMutex.Unlock()
+	if fake.CPUCoresStub != nil {
+		return fake.CPUCoresStub()
+	} else {
+		return fake.cPUCoresReturns.result1, fake.cPUCoresReturns.result2
+	}
+}
+
+func (fake *FakeSysInfoProvider) CPUCoresCallCount() int {
+	fake.cPUCoresMutex.RLock()
+	defer fake.cPUCoresMutex.RUnlock()
+	return len(fake.cPUCoresArgsForCall)
+}
+
+func (fake *FakeSysInfoProvider) CPUCoresReturns(result1 int, result2 error) {
+	fake.CPUCoresStub = nil
+	fake.cPUCoresReturns = struct {
+		result1 int
+		result2 error
+	}{result1, result2}
+}
+
 func (fake *FakeSysInfoProvider) Invocations() map[string][][]interface{} {
 	fake.invocationsMutex.RLock()
 	defer fake.invocationsMutex.RUnlock()
@@ -85,6 +118,8 @@ func (fake *FakeSysInfoProvider) Invocations() map[string][][]interface{} {
 	defer fake.totalMemoryMutex.RUnlock()
 	fake.totalDiskMutex.RLock()
 	defer fake.totalDiskMutex.RUnlock()
+	fake.cPUCoresMutex.RLock()
+	defer fake.cPUCoresMutex.RUnlock()
 	return fake.invocations
 }
 
@@ -51,6 +51,17 @@ type FakeVolumeCreator struct {
 	gCReturns struct {
 		result1 error
 	}
+	ResizeStub        func(log lager.Logger, handle, rootFSPath string, limits garden.DiskLimits) error
+	resizeMutex       sync.RWMutex
+	resizeArgsForCall []struct {
+		log        lager.Logger
+		handle     string
+		rootFSPath string
+		limits     garden.DiskLimits
+	}
+	resizeReturns struct {
+		result1 error
+	}
 	invocations      map[string][][]interface{}
 	invocationsMutex sync.RWMutex
 }
@@ -195,6 +206,42 @@ func (fake *FakeVolumeCreator) GCReturns(result1 error) {
 	}{result1}
 }
 
+func (fake *FakeVolumeCreator) Resize(log lager.Logger, handle string, rootFSPath string, limits garden.DiskLimits) error {
+	fake.resizeMutex.Lock()
+	fake.resizeArgsForCall = append(fake.resizeArgsForCall, struct {
+		log        lager.Logger
+		handle     string
+		rootFSPath string
+		limits     garden.DiskLimits
+	}{log, handle, rootFSPath, limits})
+	fake.recordInvocation("Resize", []interface{}{log, handle, rootFSPath, limits})
+	fake.resizeMutex.Unlock()
+	if fake.ResizeStub != nil {
+		return fake.ResizeStub(log, handle, rootFSPath, limits)
+	} else {
+		return fake.resizeReturns.result1
+	}
+}
+
+func (fake *FakeVolumeCreator) ResizeCallCount() int {
+	fake.resizeMutex.RLock()
+	defer fake.resizeMutex.RUnlock()
+	return len(fake.resizeArgsForCall)
+}
+
+func (fake *FakeVolumeCreator) ResizeArgsForCall(i int) (lager.Logger, string, string, garden.DiskLimits) {
+	fake.resizeMutex.RLock()
+	defer fake.resizeMutex.RUnlock()
+	return fake.resizeArgsForCall[i].log, fake.resizeArgsForCall[i].handle, fake.resizeArgsForCall[i].rootFSPath, fake.resizeArgsForCall[i].limits
+}
+
+func (fake *FakeVolumeCreator) ResizeReturns(result1 error) {
+	fake.ResizeStub = nil
+	fake.resizeReturns = struct {
+		result1 error
+	}{result1}
+}
+
 func (fake *FakeVolumeCreator) Invocations() map[string][][]interface{} {
 	fake.invocationsMutex.RLock()
 	defer fake.invocationsMutex.RUnlock()
@@ -206,6 +253,8 @@ func (fake *FakeVolumeCreator) Invocations() map[string][][]interface{} {
 	defer fake.metricsMutex.RUnlock()
 	fake.gCMutex.RLock()
 	defer fake.gCMutex.RUnlock()
+	fake.resizeMutex.RLock()
+	defer fake.resizeMutex.RUnlock()
 	return fake.invocations
 }
 
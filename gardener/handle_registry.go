@@ -0,0 +1,97 @@
+package gardener
+
+import (
+	"fmt"
+	"sync"
+)
+
+// HandleRegistry atomically reserves container handles and serializes
+// operations on a single handle, while operations on different handles
+// proceed in parallel. The zero value is ready to use.
+type HandleRegistry struct {
+	mu       sync.Mutex
+	reserved map[string]struct{}
+	locks    map[string]*handleLock
+}
+
+type handleLock struct {
+	sync.RWMutex
+	refs int
+}
+
+// Reserve claims the handle for a create, failing if it is already claimed
+func (r *HandleRegistry) Reserve(handle string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.reserved == nil {
+		r.reserved = map[string]struct{}{}
+	}
+
+	if _, ok := r.reserved[handle]; ok {
+		return fmt.Errorf("Handle '%s' already in use", handle)
+	}
+
+	r.reserved[handle] = struct{}{}
+	return nil
+}
+
+func (r *HandleRegistry) Release(handle string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	delete(r.reserved, handle)
+}
+
+// Lock waits until no other operation holds the handle, and holds it
+// exclusively until the returned function is called
+func (r *HandleRegistry) Lock(handle string) func() {
+	l := r.acquire(handle)
+	l.Lock()
+
+	return func() {
+		l.Unlock()
+		r.release(handle)
+	}
+}
+
+// RLock holds the handle shared with other RLock callers, excluding only
+// exclusive operations such as create and destroy
+func (r *HandleRegistry) RLock(handle string) func() {
+	l := r.acquire(handle)
+	l.RLock()
+
+	return func() {
+		l.RUnlock()
+		r.release(handle)
+	}
+}
+
+func (r *HandleRegistry) acquire(handle string) *handleLock {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.locks == nil {
+		r.locks = map[string]*handleLock{}
+	}
+
+	l, ok := r.locks[handle]
+	if !ok {
+		l = &handleLock{}
+		r.locks[handle] = l
+	}
+
+	l.refs++
+	return l
+}
+
+func (r *HandleRegistry) release(handle string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	l := r.locks[handle]
+	l.refs--
+	if l.refs == 0 {
+		delete(r.locks, handle)
+	}
+}
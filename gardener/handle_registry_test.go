@@ -0,0 +1,111 @@
+package gardener_test
+
+import (
+	"code.cloudfoundry.org/guardian/gardener"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("HandleRegistry", func() {
+	var registry *gardener.HandleRegistry
+
+	BeforeEach(func() {
+		registry = &gardener.HandleRegistry{}
+	})
+
+	Describe("Reserve", func() {
+		It("reserves a handle only once", func() {
+			Expect(registry.Reserve("some-handle")).To(Succeed())
+			Expect(registry.Reserve("some-handle")).To(MatchError("Handle 'some-handle' already in use"))
+		})
+
+		It("allows other handles to be reserved", func() {
+			Expect(registry.Reserve("some-handle")).To(Succeed())
+			Expect(registry.Reserve("other-handle")).To(Succeed())
+		})
+
+		It("allows a released handle to be reserved again", func() {
+			Expect(registry.Reserve("some-handle")).To(Succeed())
+			registry.Release("some-handle")
+			Expect(registry.Reserve("some-handle")).To(Succeed())
+		})
+	})
+
+	Describe("Lock", func() {
+		It("serializes operations on the same handle", func() {
+			unlock := registry.Lock("some-handle")
+
+			locked := make(chan struct{})
+			go func() {
+				defer GinkgoRecover()
+				registry.Lock("some-handle")()
+				close(locked)
+			}()
+
+			Consistently(locked).ShouldNot(BeClosed())
+			unlock()
+			Eventually(locked).Should(BeClosed())
+		})
+
+		It("excludes shared operations on the same handle", func() {
+			unlock := registry.Lock("some-handle")
+
+			locked := make(chan struct{})
+			go func() {
+				defer GinkgoRecover()
+				registry.RLock("some-handle")()
+				close(locked)
+			}()
+
+			Consistently(locked).ShouldNot(BeClosed())
+			unlock()
+			Eventually(locked).Should(BeClosed())
+		})
+
+		It("does not block operations on other handles", func() {
+			unlock := registry.Lock("some-handle")
+			defer unlock()
+
+			locked := make(chan struct{})
+			go func() {
+				defer GinkgoRecover()
+				registry.Lock("other-handle")()
+				close(locked)
+			}()
+
+			Eventually(locked).Should(BeClosed())
+		})
+	})
+
+	Describe("RLock", func() {
+		It("allows shared operations on the same handle to proceed in parallel", func() {
+			unlock := registry.RLock("some-handle")
+			defer unlock()
+
+			locked := make(chan struct{})
+			go func() {
+				defer GinkgoRecover()
+				registry.RLock("some-handle")()
+				close(locked)
+			}()
+
+			Eventually(locked).Should(BeClosed())
+		})
+
+		It("blocks exclusive operations until released", func() {
+			unlock := registry.RLock("some-handle")
+
+			locked := make(chan struct{})
+			go func() {
+				defer GinkgoRecover()
+				registry.Lock("some-handle")()
+				close(locked)
+			}()
+
+			Consistently(locked).ShouldNot(BeClosed())
+			unlock()
+			Eventually(locked).Should(BeClosed())
+		})
+	})
+})
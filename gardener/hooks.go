@@ -0,0 +1,55 @@
+package gardener
+
+import (
+	"code.cloudfoundry.org/garden"
+	"code.cloudfoundry.org/lager"
+)
+
+//go:generate counterfeiter . HookRunner
+
+// HookStage is a point in the container lifecycle at which operator
+// configured hooks run
+type HookStage string
+
+const (
+	HookPreCreate   HookStage = "pre-create"
+	HookPostNetwork HookStage = "post-network"
+	HookPostDestroy HookStage = "post-destroy"
+)
+
+// HookPayload is passed to each hook as JSON
+type HookPayload struct {
+	Handle            string               `json:"handle"`
+	Spec              garden.ContainerSpec `json:"spec"`
+	NetworkProperties garden.Properties    `json:"network_properties"`
+}
+
+// HookRunner runs the hooks configured for a stage, returning an error if a
+// hook which fails closed does not succeed
+type HookRunner interface {
+	Run(log lager.Logger, stage HookStage, payload HookPayload) error
+}
+
+func (g *Gardener) runHooks(log lager.Logger, stage HookStage, spec garden.ContainerSpec) error {
+	properties, err := g.PropertyManager.All(spec.Handle)
+	if err != nil {
+		properties = garden.Properties{}
+	}
+
+	return g.runHooksWithProperties(log, stage, spec, properties)
+}
+
+func (g *Gardener) runHooksWithProperties(log lager.Logger, stage HookStage, spec garden.ContainerSpec, properties garden.Properties) error {
+	networkProperties := garden.Properties{}
+	for name, value := range properties {
+		if isNetworkProperty(name) {
+			networkProperties[name] = value
+		}
+	}
+
+	return g.Hooks.Run(log, stage, HookPayload{
+		Handle:            spec.Handle,
+		Spec:              spec,
+		NetworkProperties: networkProperties,
+	})
+}
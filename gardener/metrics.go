@@ -0,0 +1,119 @@
+package gardener
+
+import (
+	"errors"
+	"time"
+
+	"code.cloudfoundry.org/garden"
+)
+
+// ContainerPidStat is the number of tasks in a container and its limit, where
+// a Max of zero means unlimited
+type ContainerPidStat struct {
+	Current uint64
+	Max     uint64
+}
+
+// ContainerBlockIOStat is the IO done by a container on one block device
+type ContainerBlockIOStat struct {
+	Major      uint64
+	Minor      uint64
+	ReadBytes  uint64
+	WriteBytes uint64
+	ReadOps    uint64
+	WriteOps   uint64
+}
+
+// ContainerCPUThrottlingStat is how often a container was held back by its
+// CPU quota. ThrottledTime is in nanoseconds.
+type ContainerCPUThrottlingStat struct {
+	Periods          uint64
+	ThrottledPeriods uint64
+	ThrottledTime    uint64
+}
+
+// ContainerHugetlbStat is the use of huge pages of one size
+type ContainerHugetlbStat struct {
+	Usage    uint64
+	MaxUsage uint64
+	Failcnt  uint64
+}
+
+// ContainerCPURateStat is the CPU a container used between its two most
+// recent samples, where 100 percent is one core kept busy for the whole
+// Interval. It is zero until two samples have been taken.
+type ContainerCPURateStat struct {
+	Interval time.Duration
+	Percent  float64
+}
+
+// ContainerNetworkStat is the traffic a container has sent and received,
+// counted on its host interface
+type ContainerNetworkStat struct {
+	RxBytes   uint64
+	TxBytes   uint64
+	RxPackets uint64
+	TxPackets uint64
+}
+
+// ErrNetworkStatsUnavailable is returned by networkers which cannot report
+// the network traffic of containers
+var ErrNetworkStatsUnavailable = errors.New("network stats are not available")
+
+// ExtendedMetrics are the garden.Metrics of a container along with those
+// which the garden API has no room for. The pids, block IO, CPU throttling,
+// hugetlb and CPU rate stats and the packet counts are not returned by
+// container.Metrics or BulkMetrics: garden.Metrics, which is what they send
+// over the garden API, has no fields for them. They can only be read through
+// ExtendedMetrics and BulkExtendedMetrics, the Prometheus endpoint on the
+// debug server and the metrics emitted through dropsonde.
+type ExtendedMetrics struct {
+	garden.Metrics
+
+	PidStat        ContainerPidStat
+	BlockIOStats   []ContainerBlockIOStat
+	ThrottlingStat ContainerCPUThrottlingStat
+	CPURateStat    ContainerCPURateStat
+
+	// NetworkTraffic is garden.Metrics.NetworkStat along with packet counts.
+	// It is nil when the networker cannot report it, in which case
+	// garden.Metrics.NetworkStat is left zero.
+	NetworkTraffic *ContainerNetworkStat
+
+	// HugetlbStats are keyed by page size, e.g. "2MB"
+	HugetlbStats map[string]ContainerHugetlbStat
+}
+
+type ExtendedMetricsEntry struct {
+	Metrics ExtendedMetrics
+	Err     *garden.Error
+}
+
+// ExtendedMetrics returns every metric known for the container
+func (g *Gardener) ExtendedMetrics(handle string) (ExtendedMetrics, error) {
+	return g.lookup(handle).ExtendedMetrics()
+}
+
+// BulkExtendedMetrics is BulkMetrics with the extended metrics of each
+// container
+func (g *Gardener) BulkExtendedMetrics(handles []string) (map[string]ExtendedMetricsEntry, error) {
+	results := g.bulk(handles, func(handle string) (interface{}, error) {
+		return g.ExtendedMetrics(handle)
+	})
+
+	entries := make(map[string]ExtendedMetricsEntry, len(results))
+	for handle, result := range results {
+		var entry ExtendedMetricsEntry
+		if metrics, ok := result.value.(ExtendedMetrics); ok {
+			entry.Metrics = metrics
+		}
+
+		if result.err != nil {
+			entry.Err = garden.NewError(result.err.Error())
+		}
+
+		entries[handle] = entry
+	}
+
+	return entries, nil
+}
@@ -0,0 +1,12 @@
+package gardener
+
+import (
+	"code.cloudfoundry.org/garden"
+	"code.cloudfoundry.org/lager"
+)
+
+type NoopAdmitter struct{}
+
+func (NoopAdmitter) Admit(_ lager.Logger, spec garden.ContainerSpec) (garden.ContainerSpec, error) {
+	return spec, nil
+}
@@ -0,0 +1,18 @@
+package gardener_test
+
+import (
+	"code.cloudfoundry.org/garden"
+	"code.cloudfoundry.org/guardian/gardener"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("NoopAdmitter", func() {
+	It("admits the spec unchanged", func() {
+		spec := garden.ContainerSpec{Handle: "banana", Privileged: true}
+
+		admitted, err := gardener.NoopAdmitter{}.Admit(nil, spec)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(admitted).To(Equal(spec))
+	})
+})
@@ -27,3 +27,7 @@ func (NoopVolumeCreator) Metrics(lager.Logger, string) (garden.ContainerDiskStat
 func (NoopVolumeCreator) GC(lager.Logger) error {
 	return nil
 }
+
+func (NoopVolumeCreator) Resize(lager.Logger, string, string, garden.DiskLimits) error {
+	return ErrGraphDisabled
+}
@@ -45,4 +45,10 @@ var _ = Describe("NoopVolumeCreator", func() {
 			Expect(volumeCreator.GC(logger)).To(BeNil())
 		})
 	})
+
+	Describe("Resize", func() {
+		It("returns ErrGraphDisabled", func() {
+			Expect(volumeCreator.Resize(logger, "some-handle", "rootfs", garden.DiskLimits{})).To(Equal(gardener.ErrGraphDisabled))
+		})
+	})
 })
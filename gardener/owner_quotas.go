@@ -0,0 +1,95 @@
+package gardener
+
+import (
+	"fmt"
+
+	"code.cloudfoundry.org/garden"
+)
+
+// Quota limits the number of containers, and the memory and disk committed
+// through their limits, for one owner. Zero means unlimited.
+type Quota struct {
+	MaxContainers uint64 `json:"max_containers"`
+	MemoryInBytes uint64 `json:"memory_in_bytes"`
+	DiskInBytes   uint64 `json:"disk_in_bytes"`
+}
+
+// OwnerQuotas holds the quota of each owner. Owners without a quota of their
+// own get the Default quota, if there is one. Containers without an owner are
+// not limited, unless there is an Unowned quota, which they all share.
+type OwnerQuotas struct {
+	Default *Quota           `json:"default,omitempty"`
+	Unowned *Quota           `json:"unowned,omitempty"`
+	Owners  map[string]Quota `json:"owners"`
+}
+
+func (q OwnerQuotas) For(owner string) (Quota, bool) {
+	if owner == "" {
+		if q.Unowned != nil {
+			return *q.Unowned, true
+		}
+
+		return Quota{}, false
+	}
+
+	if quota, ok := q.Owners[owner]; ok {
+		return quota, true
+	}
+
+	if q.Default != nil {
+		return *q.Default, true
+	}
+
+	return Quota{}, false
+}
+
+// QuotaExceededPrefix starts the message of every QuotaExceededError. Only the
+// message reaches clients of the garden API, so this prefix is what they can
+// recognise a rejection by and must not change.
+const QuotaExceededPrefix = "quota exceeded"
+
+// QuotaExceededError is returned when a container would take its owner over
+// their quota
+type QuotaExceededError struct {
+	Owner     string
+	Resource  string
+	Limit     uint64
+	Requested uint64
+}
+
+func (e QuotaExceededError) Error() string {
+	return fmt.Sprintf("%s for owner '%s': %s would be %d, limit is %d", QuotaExceededPrefix, e.Owner, e.Resource, e.Requested, e.Limit)
+}
+
+func (g *Gardener) ownerOf(properties garden.Properties) string {
+	if g.OwnerProperty == "" {
+		return ""
+	}
+
+	return properties[g.OwnerProperty]
+}
+
+func (q Quota) check(owner string, requested Resources, others []commitment) error {
+	containers := uint64(1)
+	total := requested
+	for _, other := range others {
+		if other.owner == owner {
+			containers++
+			total = total.add(other.resources)
+		}
+	}
+
+	if q.MaxContainers > 0 && containers > q.MaxContainers {
+		return QuotaExceededError{Owner: owner, Resource: "containers", Limit: q.MaxContainers, Requested: containers}
+	}
+
+	if q.MemoryInBytes > 0 && total.MemoryInBytes > q.MemoryInBytes {
+		return QuotaExceededError{Owner: owner, Resource: "memory", Limit: q.MemoryInBytes, Requested: total.MemoryInBytes}
+	}
+
+	if q.DiskInBytes > 0 && total.DiskInBytes > q.DiskInBytes {
+		return QuotaExceededError{Owner: owner, Resource: "disk", Limit: q.DiskInBytes, Requested: total.DiskInBytes}
+	}
+
+	return nil
+}
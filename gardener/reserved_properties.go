@@ -0,0 +1,74 @@
+package gardener
+
+import (
+	"fmt"
+	"strings"
+
+	"code.cloudfoundry.org/garden"
+)
+
+// StateKey records that a container has been fully created
+const StateKey = "garden.state"
+
+// reservedPropertyPrefixes are the namespaces in which guardian keeps its own
+// state about a container. Clients may not write to them.
+var reservedPropertyPrefixes = []string{
+	"kawasaki.",
+	"rundmc.",
+	"garden.network.",
+	GraceTimeKey,
+	DiskLimitsKey,
+	StateKey,
+}
+
+func IsReservedProperty(name string) bool {
+	for _, prefix := range reservedPropertyPrefixes {
+		if strings.HasPrefix(name, prefix) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// ReservedPropertyError is returned when a client tries to write to a
+// property which guardian keeps for itself
+type ReservedPropertyError struct {
+	Name string
+}
+
+func (e ReservedPropertyError) Error() string {
+	return fmt.Sprintf("property '%s' is reserved for internal use", e.Name)
+}
+
+// ImmutablePropertyError is returned when a client tries to change a property
+// which can only be set when the container is created, such as its owner
+type ImmutablePropertyError struct {
+	Name string
+}
+
+func (e ImmutablePropertyError) Error() string {
+	return fmt.Sprintf("property '%s' can only be set when the container is created", e.Name)
+}
+
+func checkNoReservedProperties(properties garden.Properties) error {
+	for name := range properties {
+		if IsReservedProperty(name) {
+			return ReservedPropertyError{Name: name}
+		}
+	}
+
+	return nil
+}
+
+// userProperties returns the properties without guardian's internal state
+func userProperties(properties garden.Properties) garden.Properties {
+	user := garden.Properties{}
+	for name, value := range properties {
+		if !IsReservedProperty(name) {
+			user[name] = value
+		}
+	}
+
+	return user
+}
@@ -0,0 +1,281 @@
+package gardener
+
+import (
+	"encoding/json"
+	"fmt"
+	"sync"
+
+	"code.cloudfoundry.org/garden"
+	"code.cloudfoundry.org/lager"
+)
+
+// SharesPerCPU is the number of CPU shares which make up one core when
+// accounting for committed CPU
+const SharesPerCPU = 1024
+
+// Resources is an amount of memory, disk and CPU shares
+type Resources struct {
+	MemoryInBytes uint64
+	DiskInBytes   uint64
+	CPUShares     uint64
+}
+
+func ResourcesFromLimits(limits garden.Limits) Resources {
+	return Resources{
+		MemoryInBytes: limits.Memory.LimitInBytes,
+		DiskInBytes:   limits.Disk.ByteHard,
+		CPUShares:     limits.CPU.LimitInShares,
+	}
+}
+
+func (r Resources) add(other Resources) Resources {
+	return Resources{
+		MemoryInBytes: r.MemoryInBytes + other.MemoryInBytes,
+		DiskInBytes:   r.DiskInBytes + other.DiskInBytes,
+		CPUShares:     r.CPUShares + other.CPUShares,
+	}
+}
+
+func (r Resources) sub(other Resources) Resources {
+	return Resources{
+		MemoryInBytes: saturatingSub(r.MemoryInBytes, other.MemoryInBytes),
+		DiskInBytes:   saturatingSub(r.DiskInBytes, other.DiskInBytes),
+		CPUShares:     saturatingSub(r.CPUShares, other.CPUShares),
+	}
+}
+
+func saturatingSub(a, b uint64) uint64 {
+	if b > a {
+		return 0
+	}
+
+	return a - b
+}
+
+// InsufficientResourcesError is returned when committing the limits of a
+// container would exceed the allocatable resources of the host
+type InsufficientResourcesError struct {
+	Resource  string
+	Requested uint64
+	Available uint64
+}
+
+func (e InsufficientResourcesError) Error() string {
+	return fmt.Sprintf("insufficient %s: requested %d, %d available", e.Resource, e.Requested, e.Available)
+}
+
+// commitment is what a container, belonging to an owner, has committed
+type commitment struct {
+	owner     string
+	resources Resources
+}
+
+// resourceAccountant tracks the resources committed to each live container
+type resourceAccountant struct {
+	mu        sync.Mutex
+	committed map[string]commitment
+}
+
+// reserve commits resources to the handle in place of whatever it had
+// before, provided check accepts it given what the other containers have
+// committed. The returned function restores the previous reservation.
+func (a *resourceAccountant) reserve(handle string, requested commitment, check func(requested commitment, others []commitment) error) (func(), error) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	if a.committed == nil {
+		a.committed = make(map[string]commitment)
+	}
+
+	if check != nil {
+		var others []commitment
+		for h, c := range a.committed {
+			if h != handle {
+				others = append(others, c)
+			}
+		}
+
+		if err := check(requested, others); err != nil {
+			return nil, err
+		}
+	}
+
+	previous, hadPrevious := a.committed[handle]
+	a.committed[handle] = requested
+
+	return func() {
+		a.mu.Lock()
+		defer a.mu.Unlock()
+
+		if hadPrevious {
+			a.committed[handle] = previous
+		} else {
+			delete(a.committed, handle)
+		}
+	}, nil
+}
+
+func (a *resourceAccountant) release(handle string) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	delete(a.committed, handle)
+}
+
+func (a *resourceAccountant) get(handle string) commitment {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	return a.committed[handle]
+}
+
+func (a *resourceAccountant) total() Resources {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	var total Resources
+	for _, c := range a.committed {
+		total = total.add(c.resources)
+	}
+
+	return total
+}
+
+func checkFits(requested, available Resources) error {
+	if requested.MemoryInBytes > available.MemoryInBytes {
+		return InsufficientResourcesError{Resource: "memory", Requested: requested.MemoryInBytes, Available: available.MemoryInBytes}
+	}
+
+	if requested.DiskInBytes > available.DiskInBytes {
+		return InsufficientResourcesError{Resource: "disk", Requested: requested.DiskInBytes, Available: available.DiskInBytes}
+	}
+
+	if requested.CPUShares > available.CPUShares {
+		return InsufficientResourcesError{Resource: "cpu shares", Requested: requested.CPUShares, Available: available.CPUShares}
+	}
+
+	return nil
+}
+
+// allocatable is the host's resources scaled by the overcommit ratio, or nil
+// if no ratio is configured and commitments are not enforced
+func (g *Gardener) allocatable() (*Resources, error) {
+	if g.OvercommitRatio <= 0 {
+		return nil, nil
+	}
+
+	allocatable, err := g.hostResources(g.OvercommitRatio)
+	if err != nil {
+		return nil, err
+	}
+
+	return &allocatable, nil
+}
+
+func (g *Gardener) hostResources(ratio float64) (Resources, error) {
+	mem, err := g.SysInfoProvider.TotalMemory()
+	if err != nil {
+		return Resources{}, err
+	}
+
+	disk, err := g.SysInfoProvider.TotalDisk()
+	if err != nil {
+		return Resources{}, err
+	}
+
+	cpus, err := g.SysInfoProvider.CPUCores()
+	if err != nil {
+		return Resources{}, err
+	}
+
+	return Resources{
+		MemoryInBytes: uint64(float64(mem) * ratio),
+		DiskInBytes:   uint64(float64(disk) * ratio),
+		CPUShares:     uint64(float64(uint64(cpus)*SharesPerCPU) * ratio),
+	}, nil
+}
+
+func (g *Gardener) reserveResources(handle, owner string, requested Resources) (func(), error) {
+	allocatable, err := g.allocatable()
+	if err != nil {
+		return nil, err
+	}
+
+	quota, hasQuota := g.OwnerQuotas.For(owner)
+
+	return g.resources.reserve(handle, commitment{owner: owner, resources: requested}, func(requested commitment, others []commitment) error {
+		if allocatable != nil {
+			var committed Resources
+			for _, other := range others {
+				committed = committed.add(other.resources)
+			}
+
+			if err := checkFits(requested.resources, allocatable.sub(committed)); err != nil {
+				return err
+			}
+		}
+
+		if hasQuota {
+			return quota.check(owner, requested.resources, others)
+		}
+
+		return nil
+	})
+}
+
+// changeResources recommits the resources of a container with the change
+// applied, returning a function which undoes it
+func (g *Gardener) changeResources(handle string, change func(*Resources)) (func(), error) {
+	current := g.resources.get(handle)
+	change(&current.resources)
+	return g.reserveResources(handle, current.owner, current.resources)
+}
+
+// committedResources works out what a live container has committed from its
+// applied limits and its stored disk limits
+func (g *Gardener) committedResources(log lager.Logger, handle string) (Resources, error) {
+	actualSpec, err := g.Containerizer.Info(log, handle)
+	if err != nil {
+		return Resources{}, err
+	}
+
+	limits := actualSpec.Limits
+	if diskLimits, ok := g.PropertyManager.Get(handle, DiskLimitsKey); ok && diskLimits != "" {
+		if err := json.Unmarshal([]byte(diskLimits), &limits.Disk); err != nil {
+			return Resources{}, fmt.Errorf("unmarshal disk limits: %s", err)
+		}
+	}
+
+	return ResourcesFromLimits(limits), nil
+}
+
+// rebuildResources recomputes what has been committed to the containers
+// which survived a restart
+func (g *Gardener) rebuildResources(log lager.Logger) error {
+	log = log.Session("rebuild-resources")
+
+	handles, err := g.Containerizer.Handles()
+	if err != nil {
+		return err
+	}
+
+	for _, handle := range handles {
+		resources, err := g.committedResources(log, handle)
+		if err != nil {
+			log.Error("committed-resources-failed", err, lager.Data{"handle": handle})
+			continue
+		}
+
+		var owner string
+		if g.OwnerProperty != "" {
+			owner, _ = g.PropertyManager.Get(handle, g.OwnerProperty)
+		}
+
+		if _, err := g.resources.reserve(handle, commitment{owner: owner, resources: resources}, nil); err != nil {
+			return err
+		}
+	}
+
+	log.Info("rebuilt", lager.Data{"committed": g.resources.total()})
+	return nil
+}
@@ -0,0 +1,89 @@
+package gardener
+
+import (
+	"sync"
+	"time"
+
+	"code.cloudfoundry.org/lager"
+)
+
+//go:generate counterfeiter . StageRecorder
+
+// StageTotal is the stage under which the duration of a whole operation is
+// recorded
+const StageTotal = "total"
+
+// StageRecorder records how long each stage of an operation took, and
+// whether it failed
+type StageRecorder interface {
+	RecordStage(operation, stage string, took time.Duration, err error)
+}
+
+// StageTimer times the stages of one run of an operation. The durations are
+// passed to a StageRecorder as each stage ends, and are returned by Finish so
+// that they can be logged.
+type StageTimer struct {
+	operation string
+	recorder  StageRecorder
+	started   time.Time
+
+	mu     sync.Mutex
+	stages map[string]string
+}
+
+// NewStageTimer starts timing an operation; recorder may be nil, in which
+// case the durations are only logged
+func NewStageTimer(recorder StageRecorder, operation string) *StageTimer {
+	return &StageTimer{
+		operation: operation,
+		recorder:  recorder,
+		started:   time.Now(),
+		stages:    make(map[string]string),
+	}
+}
+
+// Start starts timing a stage, which ends when the returned function is
+// called with its outcome
+func (t *StageTimer) Start(stage string) func(error) {
+	started := time.Now()
+
+	return func(err error) {
+		took := time.Since(started)
+		t.record(stage, took, err)
+
+		t.mu.Lock()
+		defer t.mu.Unlock()
+		t.stages[stage] = took.String()
+	}
+}
+
+// Time runs fn as a stage
+func (t *StageTimer) Time(stage string, fn func() error) error {
+	end := t.Start(stage)
+	err := fn()
+	end(err)
+	return err
+}
+
+// Finish records the duration of the whole operation, and returns it along
+// with the duration of each stage which ended, for logging
+func (t *StageTimer) Finish(err error) lager.Data {
+	took := time.Since(t.started)
+	t.record(StageTotal, took, err)
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	stages := make(map[string]string, len(t.stages))
+	for stage, stageTook := range t.stages {
+		stages[stage] = stageTook
+	}
+
+	return lager.Data{"took": took.String(), "stages": stages}
+}
+
+func (t *StageTimer) record(stage string, took time.Duration, err error) {
+	if t.recorder != nil {
+		t.recorder.RecordStage(t.operation, stage, took, err)
+	}
+}
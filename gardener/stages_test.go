@@ -0,0 +1,77 @@
+package gardener_test
+
+import (
+	"errors"
+	"time"
+
+	"code.cloudfoundry.org/guardian/gardener"
+	fakes "code.cloudfoundry.org/guardian/gardener/gardenerfakes"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("StageTimer", func() {
+	var (
+		recorder *fakes.FakeStageRecorder
+		timer    *gardener.StageTimer
+	)
+
+	BeforeEach(func() {
+		recorder = new(fakes.FakeStageRecorder)
+		timer = gardener.NewStageTimer(recorder, "create")
+	})
+
+	It("records each stage as it ends", func() {
+		end := timer.Start("volume")
+		time.Sleep(10 * time.Millisecond)
+		Expect(recorder.RecordStageCallCount()).To(Equal(0))
+
+		end(errors.New("boom"))
+		Expect(recorder.RecordStageCallCount()).To(Equal(1))
+
+		operation, stage, took, err := recorder.RecordStageArgsForCall(0)
+		Expect(operation).To(Equal("create"))
+		Expect(stage).To(Equal("volume"))
+		Expect(took).To(BeNumerically(">=", 10*time.Millisecond))
+		Expect(err).To(MatchError("boom"))
+	})
+
+	It("times a function as a stage and returns its error", func() {
+		Expect(timer.Time("network", func() error { return errors.New("boom") })).To(MatchError("boom"))
+
+		_, stage, _, err := recorder.RecordStageArgsForCall(0)
+		Expect(stage).To(Equal("network"))
+		Expect(err).To(MatchError("boom"))
+	})
+
+	It("records the whole operation when it finishes", func() {
+		timer.Time("network", func() error { return nil })
+		timer.Finish(nil)
+
+		Expect(recorder.RecordStageCallCount()).To(Equal(2))
+		operation, stage, _, err := recorder.RecordStageArgsForCall(1)
+		Expect(operation).To(Equal("create"))
+		Expect(stage).To(Equal(gardener.StageTotal))
+		Expect(err).NotTo(HaveOccurred())
+	})
+
+	It("returns the durations of the operation and of its stages for logging", func() {
+		timer.Time("volume", func() error { return nil })
+		timer.Time("network", func() error { return nil })
+
+		data := timer.Finish(nil)
+		Expect(data).To(HaveKey("took"))
+		Expect(data["stages"]).To(HaveLen(2))
+		Expect(data["stages"]).To(HaveKey("volume"))
+		Expect(data["stages"]).To(HaveKey("network"))
+	})
+
+	Context("when there is no recorder", func() {
+		It("still returns the durations", func() {
+			timer = gardener.NewStageTimer(nil, "create")
+			timer.Time("volume", func() error { return nil })
+
+			Expect(timer.Finish(nil)["stages"]).To(HaveKey("volume"))
+		})
+	})
+})
@@ -1,6 +1,12 @@
 package gqt_test
 
 import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+
+	"code.cloudfoundry.org/garden"
+	"code.cloudfoundry.org/guardian/gardener"
 	"code.cloudfoundry.org/guardian/gqt/runner"
 	. "github.com/onsi/ginkgo"
 	. "github.com/onsi/gomega"
@@ -58,4 +64,34 @@ var _ = Describe("Garden API", func() {
 			})
 		})
 	})
+
+	Describe("Create", func() {
+		Context("when a quota config is given", func() {
+			var quotaDir string
+
+			BeforeEach(func() {
+				var err error
+				quotaDir, err = ioutil.TempDir("", "quotas")
+				Expect(err).NotTo(HaveOccurred())
+
+				quotaPath := filepath.Join(quotaDir, "quotas.json")
+				Expect(ioutil.WriteFile(quotaPath, []byte(`{"owners": {"alice": {"max_containers": 1}}}`), 0600)).To(Succeed())
+
+				args = append(args, "--quota-config", quotaPath)
+			})
+
+			AfterEach(func() {
+				Expect(os.RemoveAll(quotaDir)).To(Succeed())
+			})
+
+			It("returns an error starting with the quota exceeded prefix when the owner's quota is exceeded", func() {
+				_, err := client.Create(garden.ContainerSpec{Properties: garden.Properties{"owner": "alice"}})
+				Expect(err).NotTo(HaveOccurred())
+
+				_, err = client.Create(garden.ContainerSpec{Properties: garden.Properties{"owner": "alice"}})
+				Expect(err).To(HaveOccurred())
+				Expect(err.Error()).To(HavePrefix(gardener.QuotaExceededPrefix))
+			})
+		})
+	})
 })
@@ -421,7 +421,6 @@ var _ = Describe("Networking", func() {
 					"network.some-other-key":    "some-other-value",
 					"some-other-key":            "do-not-propagate",
 					"garden.whatever":           "do-not-propagate",
-					"kawasaki.nope":             "do-not-propagate",
 				}
 			})
 
@@ -467,11 +466,9 @@ var _ = Describe("Networking", func() {
 				info, err := container.Info()
 				Expect(err).NotTo(HaveOccurred())
 
-				containerProperties := info.Properties
-
-				Expect(containerProperties["foo"]).To(Equal("bar"))
-				Expect(containerProperties["garden.network.container-ip"]).To(Equal("10.255.10.10"))
-				Expect(containerProperties["garden.network.host-ip"]).To(Equal("255.255.255.255"))
+				Expect(info.Properties["foo"]).To(Equal("bar"))
+				Expect(container.Property("garden.network.container-ip")).To(Equal("10.255.10.10"))
+				Expect(container.Property("garden.network.host-ip")).To(Equal("255.255.255.255"))
 			})
 
 			It("doesn't remove existing properties", func() {
@@ -606,15 +603,15 @@ var _ = Describe("IPTables Binary Flags", func() {
 })
 
 func externalIP(container garden.Container) string {
-	properties, err := container.Properties()
+	ip, err := container.Property(gardener.ExternalIPKey)
 	Expect(err).NotTo(HaveOccurred())
-	return properties[gardener.ExternalIPKey]
+	return ip
 }
 
 func containerIP(container garden.Container) string {
-	properties, err := container.Properties()
+	ip, err := container.Property(gardener.ContainerIPKey)
 	Expect(err).NotTo(HaveOccurred())
-	return properties[gardener.ContainerIPKey]
+	return ip
 }
 
 func checkConnection(container garden.Container, ip string, port int) error {
@@ -676,15 +673,15 @@ func getContent(filename string) func() []byte {
 }
 
 func containerIfName(container garden.Container) string {
-	properties, err := container.Properties()
+	name, err := container.Property("kawasaki.container-interface")
 	Expect(err).NotTo(HaveOccurred())
-	return properties["kawasaki.container-interface"]
+	return name
 }
 
 func hostIfName(container garden.Container) string {
-	properties, err := container.Properties()
+	name, err := container.Property("kawasaki.host-interface")
 	Expect(err).NotTo(HaveOccurred())
-	return properties["kawasaki.host-interface"]
+	return name
 }
 
 func getFlagValue(contentFile, flagName string) func() []byte {
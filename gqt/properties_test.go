@@ -91,19 +91,34 @@ var _ = Describe("Properties", func() {
 		Expect(containers).To(ConsistOf(container))
 	})
 
-	It("can get the default properties", func() {
+	It("can get the default properties by name", func() {
+		for _, name := range []string{
+			"kawasaki.bridge-interface",
+			gardener.BridgeIPKey,
+			gardener.ContainerIPKey,
+			"kawasaki.host-interface",
+			"kawasaki.iptable-inst",
+			"kawasaki.subnet",
+			"kawasaki.container-interface",
+			gardener.ExternalIPKey,
+			"kawasaki.mtu",
+		} {
+			_, err := container.Property(name)
+			Expect(err).NotTo(HaveOccurred(), name)
+		}
+	})
+
+	It("does not list the default properties", func() {
 		props, err := container.Properties()
 		Expect(err).ToNot(HaveOccurred())
 
-		Expect(props).To(HaveKey("kawasaki.bridge-interface"))
-		Expect(props).To(HaveKey(gardener.BridgeIPKey))
-		Expect(props).To(HaveKey(gardener.ContainerIPKey))
-		Expect(props).To(HaveKey("kawasaki.host-interface"))
-		Expect(props).To(HaveKey("kawasaki.iptable-inst"))
-		Expect(props).To(HaveKey("kawasaki.subnet"))
-		Expect(props).To(HaveKey("kawasaki.container-interface"))
-		Expect(props).To(HaveKey(gardener.ExternalIPKey))
-		Expect(props).To(HaveKey("kawasaki.mtu"))
+		Expect(props).NotTo(HaveKey("kawasaki.subnet"))
+		Expect(props).NotTo(HaveKey(gardener.ContainerIPKey))
+	})
+
+	It("does not allow the default properties to be changed", func() {
+		Expect(container.SetProperty("kawasaki.subnet", "10.0.0.0/8")).NotTo(Succeed())
+		Expect(container.RemoveProperty(gardener.ContainerIPKey)).NotTo(Succeed())
 	})
 
 	Context("after a server restart", func() {
@@ -83,7 +83,8 @@ var _ = Describe("Surviving Restarts", func() {
 			info, err := container.Info()
 			Expect(err).NotTo(HaveOccurred())
 			externalIP = info.ExternalIP
-			interfacePrefix = info.Properties["kawasaki.iptable-prefix"]
+			interfacePrefix, err = container.Property("kawasaki.iptable-prefix")
+			Expect(err).NotTo(HaveOccurred())
 
 			out := gbytes.NewBuffer()
 			existingProc, err = container.Run(
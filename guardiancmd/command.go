@@ -23,7 +23,9 @@ import (
 	"code.cloudfoundry.org/garden-shed/repository_fetcher"
 	"code.cloudfoundry.org/garden-shed/rootfs_provider"
 	"code.cloudfoundry.org/garden/server"
+	"code.cloudfoundry.org/guardian/admissionplugin"
 	"code.cloudfoundry.org/guardian/gardener"
+	"code.cloudfoundry.org/guardian/hooks"
 	"code.cloudfoundry.org/guardian/imageplugin"
 	"code.cloudfoundry.org/guardian/kawasaki"
 	"code.cloudfoundry.org/guardian/kawasaki/dns"
@@ -31,11 +33,13 @@ import (
 	"code.cloudfoundry.org/guardian/kawasaki/iptables"
 	"code.cloudfoundry.org/guardian/kawasaki/ports"
 	"code.cloudfoundry.org/guardian/kawasaki/subnets"
+	"code.cloudfoundry.org/guardian/kawasaki/tc"
 	"code.cloudfoundry.org/guardian/logging"
 	"code.cloudfoundry.org/guardian/metrics"
 	"code.cloudfoundry.org/guardian/netplugin"
 	locksmithpkg "code.cloudfoundry.org/guardian/pkg/locksmith"
 	"code.cloudfoundry.org/guardian/properties"
+	"code.cloudfoundry.org/guardian/quota"
 	"code.cloudfoundry.org/guardian/rundmc"
 	"code.cloudfoundry.org/guardian/rundmc/bundlerules"
 	"code.cloudfoundry.org/guardian/rundmc/dadoo"
@@ -45,6 +49,7 @@ import (
 	"code.cloudfoundry.org/guardian/rundmc/runrunc"
 	"code.cloudfoundry.org/guardian/rundmc/stopper"
 	"code.cloudfoundry.org/guardian/sysinfo"
+	"code.cloudfoundry.org/guardian/tracing"
 	"github.com/cloudfoundry/dropsonde"
 	"github.com/cloudfoundry/gunk/command_runner/linux_command_runner"
 	"github.com/docker/docker/daemon/graphdriver"
@@ -144,11 +149,17 @@ type GuardianCommand struct {
 
 		Tag      string `long:"tag" description:"Optional 2-character identifier used for namespacing global configuration."`
 		Rootless bool   `long:"rootless" description:"Run server in rootless mode."`
+
+		DrainTimeout time.Duration `long:"drain-timeout" default:"30s" description:"Time to wait on shutdown for in-flight creates, destroys and streams to finish. New creates are rejected meanwhile."`
+
+		BulkWorkers int           `long:"bulk-workers" default:"16" description:"Number of containers to query concurrently when serving bulk info and metrics requests."`
+		BulkTimeout time.Duration `long:"bulk-timeout" default:"10s" description:"Time each container has to answer a bulk info or metrics request before an error is returned for it."`
 	} `group:"Server Configuration"`
 
 	Containers struct {
 		Dir            DirFlag `long:"depot" required:"true" description:"Directory in which to store container data."`
-		PropertiesPath string  `long:"properties-path" description:"Path in which to store properties."`
+		PropertiesPath string  `long:"properties-path" description:"Path in which to store properties. Every change is also logged to PATH.log so that it survives a crash."`
+		JournalDir     string  `long:"creation-journal-dir" description:"Directory in which to record the progress of container creates, so that interrupted creates are cleaned up on start. Defaults to DEPOT-creation-journal."`
 
 		DefaultRootFSDir           DirFlag       `long:"default-rootfs"     description:"Default rootfs to use when not specified on container creation."`
 		DefaultGraceTime           time.Duration `long:"default-grace-time" description:"Default time after which idle containers should expire."`
@@ -161,9 +172,13 @@ type GuardianCommand struct {
 		NSTar       FileFlag `long:"nstar-bin"     required:"true" description:"Path to the 'nstar' binary."`
 		Tar         FileFlag `long:"tar-bin"       required:"true" description:"Path to the 'tar' binary."`
 		IPTables    FileFlag `long:"iptables-bin"  default:"/sbin/iptables" description:"path to the the iptables binary"`
+		TC          string   `long:"tc-bin"        default:"tc" description:"Path to the 'tc' binary used to limit container bandwidth."`
 		Init        FileFlag `long:"init-bin"      required:"true" description:"Path execute as pid 1 inside each container."`
 		Runc        string   `long:"runc-bin"      default:"runc" description:"Path to the 'runc' binary."`
 		ImagePlugin FileFlag `long:"image-plugin"           description:"Path to image plugin binary."`
+
+		AdmissionPlugin          FileFlag `long:"admission-plugin"           description:"Path to a binary which validates, and may rewrite, the spec of each container before it is created."`
+		AdmissionPluginExtraArgs []string `long:"admission-plugin-extra-arg" description:"Extra argument to pass to the admission plugin. Can be specified multiple times."`
 	} `group:"Binary Tools"`
 
 	Graph struct {
@@ -195,18 +210,38 @@ type GuardianCommand struct {
 
 		Plugin          FileFlag `long:"network-plugin"           description:"Path to network plugin binary."`
 		PluginExtraArgs []string `long:"network-plugin-extra-arg" description:"Extra argument to pass to the network plugin. Can be specified multiple times."`
+		PluginStats     bool     `long:"network-plugin-stats"     description:"Whether the network plugin implements the stats action. Without it, container network traffic is reported as unavailable."`
 	} `group:"Container Networking"`
 
+	Hooks struct {
+		PreCreate   []HookFlag    `long:"pre-create-hook"   description:"Binary to run before a container is created, as PATH[:TIMEOUT[:fail-open|fail-closed]]. Can be specified multiple times."`
+		PostNetwork []HookFlag    `long:"post-network-hook" description:"Binary to run once a container's network is configured, as PATH[:TIMEOUT[:fail-open|fail-closed]]. Can be specified multiple times."`
+		PostDestroy []HookFlag    `long:"post-destroy-hook" description:"Binary to run after a container is destroyed, as PATH[:TIMEOUT[:fail-open|fail-closed]]. Can be specified multiple times."`
+		Timeout     time.Duration `long:"hook-timeout" default:"30s" description:"Time after which a hook without its own timeout is killed."`
+	} `group:"Lifecycle Hooks"`
+
 	Limits struct {
-		MaxContainers uint64 `long:"max-containers" default:"0" description:"Maximum number of containers that can be created."`
+		MaxContainers   uint64  `long:"max-containers" default:"0" description:"Maximum number of containers that can be created."`
+		OvercommitRatio float64 `long:"overcommit-ratio" default:"0" description:"How many times over the host's memory, disk and CPU may be committed through container limits. Creates which would exceed it are rejected. 0 disables the check."`
+
+		QuotaOwnerProperty string   `long:"quota-owner-property" default:"owner" description:"Container property naming the owner whose quota a container counts against. It can only be set when a container is created. Ignored without --quota-config."`
+		QuotaConfig        FileFlag `long:"quota-config"                          description:"Path to a JSON file of per-owner container, memory and disk quotas."`
 	} `group:"Limits"`
 
 	Metrics struct {
 		EmissionInterval time.Duration `long:"metrics-emission-interval" default:"1m" description:"Interval on which to emit metrics."`
 
+		ContainerTagProperties []string `long:"metrics-container-tag-property" description:"Container property whose value tags the emitted metrics of each container, e.g. an application id. Can be specified multiple times."`
+
 		DropsondeOrigin      string `long:"dropsonde-origin"      default:"garden-linux"   description:"Origin identifier for Dropsonde-emitted metrics."`
 		DropsondeDestination string `long:"dropsonde-destination" default:"127.0.0.1:3457" description:"Destination for Dropsonde-emitted metrics."`
 	} `group:"Metrics"`
+
+	Tracing struct {
+		File         string        `long:"trace-file"                        description:"Path to a file to append each span of each traced API call to as a line of JSON."`
+		OTLPEndpoint string        `long:"trace-otlp-endpoint"               description:"URL of an OTLP/HTTP collector to send the spans of each traced API call to, e.g. http://localhost:4318/v1/traces."`
+		OTLPInterval time.Duration `long:"trace-otlp-interval" default:"5s" description:"Interval on which to send spans to the OTLP collector."`
+	} `group:"Tracing"`
 }
 
 var idMappings rootfs_provider.MappingList
@@ -265,7 +300,10 @@ func (cmd *GuardianCommand) Run(signals <-chan os.Signal, ready chan<- struct{})
 		return fmt.Errorf("invalid pool range: %s", err)
 	}
 
-	networker, iptablesStarter, err := cmd.wireNetworker(logger, propManager, portPool)
+	subnetPool := subnets.NewPool(cmd.Network.Pool.CIDR())
+	stages := metrics.NewStageHistograms(metrics.DefaultStageBuckets)
+
+	networker, iptablesStarter, err := cmd.wireNetworker(logger, propManager, subnetPool, portPool, stages)
 	if err != nil {
 		logger.Error("failed-to-wire-networker", err)
 		return err
@@ -286,16 +324,42 @@ func (cmd *GuardianCommand) Run(signals <-chan os.Signal, ready chan<- struct{})
 		starters = []gardener.Starter{cmd.wireRunDMCStarter(logger), iptablesStarter}
 	}
 
+	eventLog := rundmc.NewEventStore(logger, propManager, clock.NewClock(), rundmc.DefaultMaxEvents)
+
+	ownerQuotas, err := cmd.wireOwnerQuotas()
+	if err != nil {
+		logger.Error("failed-to-load-quota-config", err)
+		return err
+	}
+
+	tracer, stopTracer, err := cmd.wireTracer(logger)
+	if err != nil {
+		logger.Error("failed-to-wire-tracer", err)
+		return err
+	}
+
 	backend := &gardener.Gardener{
 		UidGenerator:    cmd.wireUidGenerator(),
 		Starters:        starters,
 		SysInfoProvider: sysinfo.NewProvider(cmd.Containers.Dir.Path()),
 		Networker:       networker,
 		VolumeCreator:   volumeCreator,
-		Containerizer:   cmd.wireContainerizer(logger, cmd.Containers.Dir.Path(), cmd.Bin.Dadoo.Path(), cmd.Bin.Runc, cmd.Bin.NSTar.Path(), cmd.Bin.Tar.Path(), cmd.Containers.DefaultRootFSDir.Path(), cmd.Containers.ApparmorProfile, propManager),
+		Containerizer:   cmd.wireContainerizer(logger, cmd.Containers.Dir.Path(), cmd.Bin.Dadoo.Path(), cmd.Bin.Runc, cmd.Bin.NSTar.Path(), cmd.Bin.Tar.Path(), cmd.Containers.DefaultRootFSDir.Path(), cmd.Containers.ApparmorProfile, propManager, eventLog, stages),
 		PropertyManager: propManager,
 		MaxContainers:   cmd.Limits.MaxContainers,
+		OvercommitRatio: cmd.Limits.OvercommitRatio,
+		OwnerProperty:   cmd.quotaOwnerProperty(),
+		OwnerQuotas:     ownerQuotas,
 		Restorer:        restorer,
+		CreationJournal: depot.NewCreationJournal(cmd.creationJournalDir()),
+		EventLog:        eventLog,
+		Admitter:        cmd.wireAdmitter(),
+		Hooks:           cmd.wireHooks(),
+		DrainTimeout:    cmd.Server.DrainTimeout,
+		BulkWorkers:     cmd.Server.BulkWorkers,
+		BulkTimeout:     cmd.Server.BulkTimeout,
+		StageRecorder:   stages,
+		Tracer:          tracer,
 
 		Logger: logger,
 	}
@@ -309,7 +373,8 @@ func (cmd *GuardianCommand) Run(signals <-chan os.Signal, ready chan<- struct{})
 		listenAddr = cmd.Server.BindSocket
 	}
 
-	gardenServer := server.New(listenNetwork, listenAddr, cmd.Containers.DefaultGraceTime, backend, logger.Session("api"))
+	operations := metrics.NewOperationCounters()
+	gardenServer := server.New(listenNetwork, listenAddr, cmd.Containers.DefaultGraceTime, metrics.NewInstrumentedBackend(backend, operations), logger.Session("api"))
 
 	cmd.initializeDropsonde(logger)
 
@@ -318,9 +383,21 @@ func (cmd *GuardianCommand) Run(signals <-chan os.Signal, ready chan<- struct{})
 	metronNotifier := cmd.wireMetronNotifier(logger, metricsProvider)
 	metronNotifier.Start()
 
+	containerMetronNotifier := cmd.wireContainerMetronNotifier(logger, backend)
+	containerMetronNotifier.Start()
+
 	if cmd.Server.DebugBindIP != nil {
 		addr := fmt.Sprintf("%s:%d", cmd.Server.DebugBindIP.IP(), cmd.Server.DebugBindPort)
-		metrics.StartDebugServer(addr, reconfigurableSink, metricsProvider)
+		metrics.StartDebugServer(addr, reconfigurableSink, metricsProvider, backend, &metrics.Prometheus{
+			Logger:     logger.Session("prometheus"),
+			Metrics:    metricsProvider,
+			DrainState: backend,
+			Containers: backend,
+			SubnetPool: subnetPool,
+			PortPool:   portPool,
+			Operations: operations,
+			Stages:     stages,
+		})
 	}
 
 	err = gardenServer.Start()
@@ -338,9 +415,13 @@ func (cmd *GuardianCommand) Run(signals <-chan os.Signal, ready chan<- struct{})
 
 	<-signals
 
+	// drain while the server is still up, so that clients are told that new
+	// creates are being rejected rather than failing to connect
+	backend.Stop()
 	gardenServer.Stop()
 
-	cmd.saveProperties(logger, cmd.Containers.PropertiesPath, propManager)
+	cmd.closeProperties(logger, propManager)
+	stopTracer()
 
 	portPoolState = portPool.RefreshState()
 	ports.SaveState(cmd.Network.PortPoolPropertiesPath, portPoolState)
@@ -349,7 +430,11 @@ func (cmd *GuardianCommand) Run(signals <-chan os.Signal, ready chan<- struct{})
 }
 
 func (cmd *GuardianCommand) loadProperties(logger lager.Logger, propertiesPath string) (*properties.Manager, error) {
-	propManager, err := properties.Load(propertiesPath)
+	if propertiesPath == "" {
+		return properties.NewManager(), nil
+	}
+
+	propManager, err := properties.Open(logger, propertiesPath)
 	if err != nil {
 		logger.Error("failed-to-load-properties", err, lager.Data{"propertiesPath": propertiesPath})
 		return &properties.Manager{}, err
@@ -358,12 +443,9 @@ func (cmd *GuardianCommand) loadProperties(logger lager.Logger, propertiesPath s
 	return propManager, nil
 }
 
-func (cmd *GuardianCommand) saveProperties(logger lager.Logger, propertiesPath string, propManager *properties.Manager) {
-	if propertiesPath != "" {
-		err := properties.Save(propertiesPath, propManager)
-		if err != nil {
-			logger.Error("failed-to-save-properties", err, lager.Data{"propertiesPath": propertiesPath})
-		}
+func (cmd *GuardianCommand) closeProperties(logger lager.Logger, propManager *properties.Manager) {
+	if err := propManager.Close(); err != nil {
+		logger.Error("failed-to-save-properties", err, lager.Data{"propertiesPath": cmd.Containers.PropertiesPath})
 	}
 }
 
@@ -382,7 +464,7 @@ func (cmd *GuardianCommand) wireRunDMCStarter(logger lager.Logger) gardener.Star
 	return rundmc.NewStarter(logger, mustOpen("/proc/cgroups"), mustOpen("/proc/self/cgroup"), cgroupsMountpoint, linux_command_runner.New())
 }
 
-func (cmd *GuardianCommand) wireNetworker(log lager.Logger, propManager kawasaki.ConfigStore, portPool *ports.PortPool) (gardener.Networker, gardener.Starter, error) {
+func (cmd *GuardianCommand) wireNetworker(log lager.Logger, propManager kawasaki.ConfigStore, subnetPool subnets.Pool, portPool *ports.PortPool, stages gardener.StageRecorder) (gardener.Networker, gardener.Starter, error) {
 	externalIP, err := defaultExternalIP(cmd.Network.ExternalIP)
 	if err != nil {
 		return nil, nil, err
@@ -408,6 +490,7 @@ func (cmd *GuardianCommand) wireNetworker(log lager.Logger, propManager kawasaki
 			resolvConfigurer,
 			cmd.Network.Plugin.Path(),
 			cmd.Network.PluginExtraArgs,
+			cmd.Network.PluginStats,
 		)
 		return externalNetworker, externalNetworker, nil
 	}
@@ -425,17 +508,20 @@ func (cmd *GuardianCommand) wireNetworker(log lager.Logger, propManager kawasaki
 	ipTables := iptables.New(cmd.Bin.IPTables.Path(), iptRunner, locksmith, chainPrefix)
 	ipTablesStarter := iptables.NewStarter(ipTables, cmd.Network.AllowHostAccess, interfacePrefix, denyNetworksList, cmd.Containers.DestroyContainersOnStartup)
 	ruleTranslator := iptables.NewRuleTranslator()
+	tcRunner := &logging.Runner{CommandRunner: linux_command_runner.New(), Logger: log.Session("tc-runner")}
 
 	networker := kawasaki.New(
 		cmd.Bin.IPTables.Path(),
 		kawasaki.SpecParserFunc(kawasaki.ParseSpec),
-		subnets.NewPool(cmd.Network.Pool.CIDR()),
+		subnetPool,
 		kawasaki.NewConfigCreator(idGenerator, interfacePrefix, chainPrefix, externalIP, dnsServers, cmd.Network.Mtu),
 		propManager,
-		factory.NewDefaultConfigurer(ipTables),
+		factory.NewDefaultConfigurer(ipTables, stages),
 		portPool,
 		iptables.NewPortForwarder(ipTables),
 		iptables.NewFirewallOpener(ruleTranslator, ipTables),
+		tc.NewBandwidthLimiter(cmd.Bin.TC, tcRunner),
+		factory.NewInterfaceStatser(),
 	)
 
 	return networker, ipTablesStarter, nil
@@ -559,14 +645,102 @@ func (cmd *GuardianCommand) wireVolumeCreator(logger lager.Logger, graphRoot str
 		},
 	}
 
-	return rootfs_provider.NewCakeOrdinator(cake,
-		repoFetcher,
-		layerCreator,
-		rootfs_provider.NewMetricsAdapter(quotaManager.GetUsage, quotaedGraphDriver.GetMntPath),
-		ovenCleaner)
+	return &quota.ResizableVolumeCreator{
+		VolumeProvider: rootfs_provider.NewCakeOrdinator(cake,
+			repoFetcher,
+			layerCreator,
+			rootfs_provider.NewMetricsAdapter(quotaManager.GetUsage, quotaedGraphDriver.GetMntPath),
+			ovenCleaner),
+		Resizer: &quota.AUFSResizer{
+			BackingStoresPath: backingStoresPath,
+			BaseSizer:         quotaManager.BaseSizer,
+			Runner:            runner,
+		},
+	}
 }
 
-func (cmd *GuardianCommand) wireContainerizer(log lager.Logger, depotPath, dadooPath, runcPath, nstarPath, tarPath, defaultRootFSPath, appArmorProfile string, properties gardener.PropertyManager) *rundmc.Containerizer {
+func (cmd *GuardianCommand) wireAdmitter() gardener.Admitter {
+	if cmd.Bin.AdmissionPlugin.Path() == "" {
+		return gardener.NoopAdmitter{}
+	}
+
+	return admissionplugin.New(cmd.Bin.AdmissionPlugin.Path(), cmd.Bin.AdmissionPluginExtraArgs, linux_command_runner.New())
+}
+
+func (cmd *GuardianCommand) creationJournalDir() string {
+	if cmd.Containers.JournalDir != "" {
+		return cmd.Containers.JournalDir
+	}
+
+	return filepath.Clean(cmd.Containers.Dir.Path()) + "-creation-journal"
+}
+
+// quotaOwnerProperty returns the property naming the owner of a container,
+// which only matters, and so is only made immutable, when there are quotas
+func (cmd *GuardianCommand) quotaOwnerProperty() string {
+	if cmd.Limits.QuotaConfig.Path() == "" {
+		return ""
+	}
+
+	return cmd.Limits.QuotaOwnerProperty
+}
+
+func (cmd *GuardianCommand) wireOwnerQuotas() (gardener.OwnerQuotas, error) {
+	if cmd.Limits.QuotaConfig.Path() == "" {
+		return gardener.OwnerQuotas{}, nil
+	}
+
+	return quota.LoadOwnerQuotas(cmd.Limits.QuotaConfig.Path())
+}
+
+// wireTracer returns the tracer of API calls, and a function which sends or
+// writes out any spans it has not yet exported
+func (cmd *GuardianCommand) wireTracer(log lager.Logger) (*tracing.Tracer, func(), error) {
+	if cmd.Tracing.File != "" && cmd.Tracing.OTLPEndpoint != "" {
+		return nil, nil, fmt.Errorf("only one of --trace-file and --trace-otlp-endpoint may be given")
+	}
+
+	if cmd.Tracing.File != "" {
+		exporter, err := tracing.NewFileExporter(cmd.Tracing.File)
+		if err != nil {
+			return nil, nil, err
+		}
+
+		return &tracing.Tracer{Exporter: exporter}, func() { exporter.Close() }, nil
+	}
+
+	if cmd.Tracing.OTLPEndpoint != "" {
+		exporter := tracing.NewOTLPExporter(log, cmd.Tracing.OTLPEndpoint, cmd.Tracing.OTLPInterval, clock.NewClock())
+		exporter.Start()
+
+		return &tracing.Tracer{Exporter: exporter}, exporter.Stop, nil
+	}
+
+	return &tracing.Tracer{}, func() {}, nil
+}
+
+func (cmd *GuardianCommand) wireHooks() *hooks.Runner {
+	stages := map[gardener.HookStage][]HookFlag{
+		gardener.HookPreCreate:   cmd.Hooks.PreCreate,
+		gardener.HookPostNetwork: cmd.Hooks.PostNetwork,
+		gardener.HookPostDestroy: cmd.Hooks.PostDestroy,
+	}
+
+	runner := &hooks.Runner{
+		Hooks:         make(map[gardener.HookStage][]hooks.Hook),
+		CommandRunner: linux_command_runner.New(),
+	}
+
+	for stage, flags := range stages {
+		for _, flag := range flags {
+			runner.Hooks[stage] = append(runner.Hooks[stage], flag.Hook(cmd.Hooks.Timeout))
+		}
+	}
+
+	return runner
+}
+
+func (cmd *GuardianCommand) wireContainerizer(log lager.Logger, depotPath, dadooPath, runcPath, nstarPath, tarPath, defaultRootFSPath, appArmorProfile string, properties gardener.PropertyManager, eventStore rundmc.EventStore, stages gardener.StageRecorder) *rundmc.Containerizer {
 	depot := depot.New(depotPath)
 
 	commandRunner := linux_command_runner.New()
@@ -581,6 +755,8 @@ func (cmd *GuardianCommand) wireContainerizer(log lager.Logger, depotPath, dadoo
 		SleepInterval: time.Millisecond * 100,
 	}
 
+	statsStore := rundmc.NewStatsStore(clock.NewClock(), rundmc.DefaultStatsWindow, rundmc.DefaultStatsMaxAge)
+
 	runcrunner := runrunc.New(
 		commandRunner,
 		runrunc.NewLogRunner(commandRunner, runrunc.LogDir(os.TempDir()).GenerateLogFile),
@@ -594,6 +770,7 @@ func (cmd *GuardianCommand) wireContainerizer(log lager.Logger, depotPath, dadoo
 			cmd.wireUidGenerator(),
 			pidFileReader,
 			linux_command_runner.New()),
+		statsStore,
 	)
 
 	mounts := []specs.Mount{
@@ -691,12 +868,20 @@ func (cmd *GuardianCommand) wireContainerizer(log lager.Logger, depotPath, dadoo
 		"unprivileged": unprivilegedBundle,
 	})
 
-	eventStore := rundmc.NewEventStore(properties)
 	stateStore := rundmc.NewStateStore(properties)
 
 	nstar := rundmc.NewNstarRunner(nstarPath, tarPath, linux_command_runner.New())
 	stopper := stopper.New(stopper.NewRuncStateCgroupPathResolver("/run/runc"), nil, retrier.New(retrier.ConstantBackoff(10, 1*time.Second), nil))
-	return rundmc.New(depot, template, runcrunner, &goci.BndlLoader{}, nstar, stopper, eventStore, stateStore)
+	containerizer := rundmc.New(depot, template, runcrunner, &goci.BndlLoader{}, nstar, stopper, eventStore, stateStore, statsStore, stages)
+	if err := containerizer.RebuildCache(log); err != nil {
+		log.Error("failed-to-rebuild-bundle-cache", err)
+	}
+
+	if err := containerizer.WatchExisting(log); err != nil {
+		log.Error("failed-to-watch-existing-containers", err)
+	}
+
+	return containerizer
 }
 
 func (cmd *GuardianCommand) wireMetricsProvider(log lager.Logger, depotPath, graphRoot string) metrics.Metrics {
@@ -714,6 +899,12 @@ func (cmd *GuardianCommand) wireMetronNotifier(log lager.Logger, metricsProvider
 	)
 }
 
+func (cmd *GuardianCommand) wireContainerMetronNotifier(log lager.Logger, source metrics.ContainerMetricsSource) *metrics.PeriodicContainerMetronNotifier {
+	return metrics.NewPeriodicContainerMetronNotifier(
+		log, source, metrics.DropsondeSender{}, cmd.Metrics.ContainerTagProperties, cmd.Metrics.EmissionInterval, clock.NewClock(),
+	)
+}
+
 func (cmd *GuardianCommand) initializeDropsonde(log lager.Logger) {
 	err := dropsonde.Initialize(cmd.Metrics.DropsondeDestination, cmd.Metrics.DropsondeOrigin)
 	if err != nil {
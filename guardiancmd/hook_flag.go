@@ -0,0 +1,58 @@
+package guardiancmd
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"code.cloudfoundry.org/guardian/hooks"
+)
+
+// HookFlag is a hook binary given as PATH[:TIMEOUT[:fail-open|fail-closed]]
+type HookFlag struct {
+	hook hooks.Hook
+}
+
+func (f *HookFlag) UnmarshalFlag(value string) error {
+	parts := strings.Split(value, ":")
+	if len(parts) > 3 {
+		return fmt.Errorf("hook '%s' must be of the form PATH[:TIMEOUT[:fail-open|fail-closed]]", value)
+	}
+
+	var path FileFlag
+	if err := path.UnmarshalFlag(parts[0]); err != nil {
+		return err
+	}
+	f.hook.Path = path.Path()
+
+	if len(parts) > 1 && parts[1] != "" {
+		timeout, err := time.ParseDuration(parts[1])
+		if err != nil {
+			return fmt.Errorf("hook '%s' has an invalid timeout: %s", value, err)
+		}
+
+		f.hook.Timeout = timeout
+	}
+
+	if len(parts) > 2 {
+		switch parts[2] {
+		case "fail-open":
+			f.hook.FailOpen = true
+		case "fail-closed":
+			f.hook.FailOpen = false
+		default:
+			return fmt.Errorf("hook '%s' must fail-open or fail-closed", value)
+		}
+	}
+
+	return nil
+}
+
+func (f HookFlag) Hook(defaultTimeout time.Duration) hooks.Hook {
+	hook := f.hook
+	if hook.Timeout == 0 {
+		hook.Timeout = defaultTimeout
+	}
+
+	return hook
+}
@@ -0,0 +1,106 @@
+package hooks
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os/exec"
+	"time"
+
+	"code.cloudfoundry.org/guardian/gardener"
+	"code.cloudfoundry.org/guardian/tracing"
+	"code.cloudfoundry.org/lager"
+	"github.com/cloudfoundry/gunk/command_runner"
+)
+
+const DefaultTimeout = 30 * time.Second
+
+// Hook is an operator supplied binary run at a stage of the container
+// lifecycle. A hook which fails open only logs its failure; one which fails
+// closed fails the operation which triggered it.
+type Hook struct {
+	Path     string
+	Timeout  time.Duration
+	FailOpen bool
+}
+
+// Runner runs hooks in the order they were configured. Each hook is invoked
+// as `<path> --stage <stage> --handle <handle>` with the JSON encoded
+// gardener.HookPayload on its stdin.
+type Runner struct {
+	Hooks         map[gardener.HookStage][]Hook
+	CommandRunner command_runner.CommandRunner
+}
+
+func (r *Runner) Run(log lager.Logger, stage gardener.HookStage, payload gardener.HookPayload) error {
+	hooks := r.Hooks[stage]
+	if len(hooks) == 0 {
+		return nil
+	}
+
+	log = log.Session("hooks", lager.Data{"stage": stage, "handle": payload.Handle})
+
+	log.Info("started")
+	defer log.Info("finished")
+
+	stdin, err := json.Marshal(payload)
+	if err != nil {
+		return fmt.Errorf("marshal hook payload: %s", err)
+	}
+
+	for _, hook := range hooks {
+		if err := r.run(log, stage, payload.Handle, hook, stdin); err != nil {
+			if hook.FailOpen {
+				log.Error("hook-failed-open", err, lager.Data{"path": hook.Path})
+				continue
+			}
+
+			log.Error("hook-failed", err, lager.Data{"path": hook.Path})
+			return fmt.Errorf("%s hook %s: %s", stage, hook.Path, err)
+		}
+	}
+
+	return nil
+}
+
+func (r *Runner) run(log lager.Logger, stage gardener.HookStage, handle string, hook Hook, stdin []byte) error {
+	timeout := hook.Timeout
+	if timeout == 0 {
+		timeout = DefaultTimeout
+	}
+
+	cmd := exec.Command(hook.Path, "--stage", string(stage), "--handle", handle)
+	cmd.Stdin = bytes.NewReader(stdin)
+	stdout := &bytes.Buffer{}
+	cmd.Stdout = stdout
+	stderr := &bytes.Buffer{}
+	cmd.Stderr = stderr
+	tracing.SetEnv(log, cmd)
+
+	if err := r.CommandRunner.Start(cmd); err != nil {
+		return fmt.Errorf("start: %s", err)
+	}
+
+	exited := make(chan error, 1)
+	go func() {
+		exited <- r.CommandRunner.Wait(cmd)
+	}()
+
+	select {
+	case err := <-exited:
+		logData := lager.Data{"path": hook.Path, "stdout": stdout.String(), "stderr": stderr.String()}
+		if err != nil {
+			log.Info("hook-result", logData)
+			return err
+		}
+
+		log.Debug("hook-result", logData)
+		return nil
+	case <-time.After(timeout):
+		if err := r.CommandRunner.Kill(cmd); err != nil {
+			log.Error("kill-failed", err, lager.Data{"path": hook.Path})
+		}
+
+		return fmt.Errorf("timed out after %s", timeout)
+	}
+}
@@ -0,0 +1,175 @@
+package hooks_test
+
+import (
+	"encoding/json"
+	"errors"
+	"io/ioutil"
+	"os/exec"
+	"time"
+
+	"code.cloudfoundry.org/garden"
+	"code.cloudfoundry.org/guardian/gardener"
+	"code.cloudfoundry.org/guardian/hooks"
+	"code.cloudfoundry.org/guardian/tracing"
+	"code.cloudfoundry.org/lager/lagertest"
+	"github.com/cloudfoundry/gunk/command_runner/fake_command_runner"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"github.com/onsi/gomega/gbytes"
+)
+
+var _ = Describe("Runner", func() {
+	var (
+		commandRunner *fake_command_runner.FakeCommandRunner
+		logger        *lagertest.TestLogger
+		runner        *hooks.Runner
+		payload       gardener.HookPayload
+	)
+
+	BeforeEach(func() {
+		commandRunner = fake_command_runner.New()
+		logger = lagertest.NewTestLogger("test")
+
+		runner = &hooks.Runner{
+			Hooks: map[gardener.HookStage][]hooks.Hook{
+				gardener.HookPreCreate: {
+					{Path: "/hooks/register"},
+					{Path: "/hooks/mount", Timeout: time.Minute},
+				},
+			},
+			CommandRunner: commandRunner,
+		}
+
+		payload = gardener.HookPayload{
+			Handle:            "some-handle",
+			Spec:              garden.ContainerSpec{Handle: "some-handle", RootFSPath: "docker:///busybox"},
+			NetworkProperties: garden.Properties{gardener.ContainerIPKey: "10.0.0.2"},
+		}
+	})
+
+	It("runs each hook configured for the stage in order", func() {
+		Expect(runner.Run(logger, gardener.HookPreCreate, payload)).To(Succeed())
+
+		started := commandRunner.StartedCommands()
+		Expect(started).To(HaveLen(2))
+		Expect(started[0].Path).To(Equal("/hooks/register"))
+		Expect(started[0].Args).To(Equal([]string{"/hooks/register", "--stage", "pre-create", "--handle", "some-handle"}))
+		Expect(started[1].Path).To(Equal("/hooks/mount"))
+	})
+
+	It("passes the payload as JSON on stdin", func() {
+		var received gardener.HookPayload
+		commandRunner.WhenRunning(fake_command_runner.CommandSpec{
+			Path: "/hooks/register",
+		}, func(cmd *exec.Cmd) error {
+			stdin, err := ioutil.ReadAll(cmd.Stdin)
+			Expect(err).NotTo(HaveOccurred())
+			return json.Unmarshal(stdin, &received)
+		})
+
+		Expect(runner.Run(logger, gardener.HookPreCreate, payload)).To(Succeed())
+		Expect(received).To(Equal(payload))
+	})
+
+	It("passes the trace id of the logger to each hook", func() {
+		tracedLogger, _ := new(tracing.Tracer).Start(logger, "create")
+		Expect(runner.Run(tracedLogger, gardener.HookPreCreate, payload)).To(Succeed())
+
+		for _, cmd := range commandRunner.StartedCommands() {
+			Expect(tracing.FromEnv(cmd.Env)).To(Equal(tracing.TraceID(tracedLogger)))
+		}
+	})
+
+	It("does nothing for a stage without hooks", func() {
+		Expect(runner.Run(logger, gardener.HookPostDestroy, payload)).To(Succeed())
+		Expect(commandRunner.StartedCommands()).To(BeEmpty())
+	})
+
+	Context("when a hook which fails closed exits unsuccessfully", func() {
+		BeforeEach(func() {
+			commandRunner.WhenWaitingFor(fake_command_runner.CommandSpec{
+				Path: "/hooks/register",
+			}, func(cmd *exec.Cmd) error {
+				return errors.New("exit status 1")
+			})
+		})
+
+		It("returns an error naming the stage and hook", func() {
+			Expect(runner.Run(logger, gardener.HookPreCreate, payload)).To(MatchError("pre-create hook /hooks/register: exit status 1"))
+		})
+
+		It("does not run the remaining hooks", func() {
+			runner.Run(logger, gardener.HookPreCreate, payload)
+			Expect(commandRunner.StartedCommands()).To(HaveLen(1))
+		})
+	})
+
+	Context("when a hook which fails closed cannot be started", func() {
+		It("returns an error", func() {
+			commandRunner.WhenRunning(fake_command_runner.CommandSpec{
+				Path: "/hooks/register",
+			}, func(cmd *exec.Cmd) error {
+				return errors.New("no such file")
+			})
+
+			Expect(runner.Run(logger, gardener.HookPreCreate, payload)).To(MatchError("pre-create hook /hooks/register: start: no such file"))
+		})
+	})
+
+	Context("when a hook which fails open exits unsuccessfully", func() {
+		BeforeEach(func() {
+			runner.Hooks[gardener.HookPreCreate][0].FailOpen = true
+
+			commandRunner.WhenWaitingFor(fake_command_runner.CommandSpec{
+				Path: "/hooks/register",
+			}, func(cmd *exec.Cmd) error {
+				return errors.New("exit status 1")
+			})
+		})
+
+		It("succeeds and runs the remaining hooks", func() {
+			Expect(runner.Run(logger, gardener.HookPreCreate, payload)).To(Succeed())
+			Expect(commandRunner.StartedCommands()).To(HaveLen(2))
+		})
+
+		It("logs the failure", func() {
+			Expect(runner.Run(logger, gardener.HookPreCreate, payload)).To(Succeed())
+			Expect(logger).To(gbytes.Say("hook-failed-open"))
+		})
+	})
+
+	Context("when a hook runs for longer than its timeout", func() {
+		var release chan struct{}
+
+		BeforeEach(func() {
+			release = make(chan struct{})
+			runner.Hooks[gardener.HookPreCreate][0].Timeout = 10 * time.Millisecond
+
+			commandRunner.WhenWaitingFor(fake_command_runner.CommandSpec{
+				Path: "/hooks/register",
+			}, func(cmd *exec.Cmd) error {
+				<-release
+				return nil
+			})
+		})
+
+		AfterEach(func() {
+			close(release)
+		})
+
+		It("kills the hook and returns an error", func() {
+			Expect(runner.Run(logger, gardener.HookPreCreate, payload)).To(MatchError("pre-create hook /hooks/register: timed out after 10ms"))
+			Expect(commandRunner.KilledCommands()).To(HaveLen(1))
+			Expect(commandRunner.KilledCommands()[0].Path).To(Equal("/hooks/register"))
+		})
+
+		Context("and the hook fails open", func() {
+			It("carries on with the remaining hooks", func() {
+				runner.Hooks[gardener.HookPreCreate][0].FailOpen = true
+
+				Expect(runner.Run(logger, gardener.HookPreCreate, payload)).To(Succeed())
+				Expect(commandRunner.StartedCommands()).To(HaveLen(2))
+			})
+		})
+	})
+})
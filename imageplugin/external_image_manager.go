@@ -12,6 +12,7 @@ import (
 
 	"code.cloudfoundry.org/garden"
 	"code.cloudfoundry.org/garden-shed/rootfs_provider"
+	"code.cloudfoundry.org/guardian/tracing"
 	"code.cloudfoundry.org/lager"
 	"github.com/cloudfoundry/gunk/command_runner"
 	specs "github.com/opencontainers/runtime-spec/specs-go"
@@ -74,7 +75,7 @@ func (p *ExternalImageManager) Create(log lager.Logger, handle string, spec root
 		}
 	}
 
-	if err := p.commandRunner.Run(cmd); err != nil {
+	if err := p.run(log, "image-plugin-create", cmd); err != nil {
 		logData := lager.Data{"action": "create", "stderr": errBuffer.String(), "stdout": outBuffer.String()}
 		log.Error("external-image-manager-result", err, logData)
 		return "", nil, fmt.Errorf("external image manager create failed: %s", err)
@@ -85,6 +86,17 @@ func (p *ExternalImageManager) Create(log lager.Logger, handle string, spec root
 	return rootFS, []string{}, nil
 }
 
+// run runs cmd as a span of the trace log is under, passing the trace id on
+// to the image plugin
+func (p *ExternalImageManager) run(log lager.Logger, name string, cmd *exec.Cmd) error {
+	log, span := tracing.StartSpan(log, name)
+	tracing.SetEnv(log, cmd)
+
+	err := p.commandRunner.Run(cmd)
+	span.End(err)
+	return err
+}
+
 func stringifyMapping(mapping specs.IDMapping) string {
 	return fmt.Sprintf("%d:%d:%d", mapping.ContainerID, mapping.HostID, mapping.Size)
 }
@@ -104,7 +116,7 @@ func (p *ExternalImageManager) Destroy(log lager.Logger, handle, rootfs string)
 	errBuffer := bytes.NewBuffer([]byte{})
 	cmd.Stderr = errBuffer
 
-	if err := p.commandRunner.Run(cmd); err != nil {
+	if err := p.run(log, "image-plugin-destroy", cmd); err != nil {
 		logData := lager.Data{"action": "delete", "stderr": errBuffer.String()}
 		log.Error("external-image-manager-result", err, logData)
 		return fmt.Errorf("external image manager destroy failed: %s", err)
@@ -113,6 +125,31 @@ func (p *ExternalImageManager) Destroy(log lager.Logger, handle, rootfs string)
 	return nil
 }
 
+func (p *ExternalImageManager) Resize(log lager.Logger, handle, rootfs string, limits garden.DiskLimits) error {
+	log = log.Session("image-plugin-resize")
+	log.Debug("start")
+	defer log.Debug("end")
+
+	args := []string{"resize", "--disk-limit-size-bytes", strconv.FormatUint(limits.ByteHard, 10)}
+	if limits.Scope == garden.DiskLimitScopeExclusive {
+		args = append(args, "--exclude-image-from-quota")
+	}
+
+	bundlePath := filepath.Dir(rootfs)
+	cmd := exec.Command(p.binPath, append(args, bundlePath)...)
+
+	errBuffer := bytes.NewBuffer([]byte{})
+	cmd.Stderr = errBuffer
+
+	if err := p.run(log, "image-plugin-resize", cmd); err != nil {
+		logData := lager.Data{"action": "resize", "stderr": errBuffer.String()}
+		log.Error("external-image-manager-result", err, logData)
+		return fmt.Errorf("external image manager resize failed: %s", err)
+	}
+
+	return nil
+}
+
 func (p *ExternalImageManager) Metrics(log lager.Logger, handle string) (garden.ContainerDiskStat, error) {
 	log = log.Session("image-plugin-metrics")
 	log.Debug("start")
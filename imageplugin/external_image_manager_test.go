@@ -7,8 +7,10 @@ import (
 	"os/exec"
 	"strconv"
 
+	"code.cloudfoundry.org/garden"
 	"code.cloudfoundry.org/garden-shed/rootfs_provider"
 	"code.cloudfoundry.org/guardian/imageplugin"
+	"code.cloudfoundry.org/guardian/tracing"
 	"code.cloudfoundry.org/lager/lagertest"
 	"github.com/cloudfoundry/gunk/command_runner/fake_command_runner"
 	specs "github.com/opencontainers/runtime-spec/specs-go"
@@ -250,6 +252,14 @@ var _ = Describe("ExternalImageManager", func() {
 			Expect(imageManagerCmd.Path).To(Equal("/external-image-manager-bin"))
 		})
 
+		It("passes the trace id of the logger to the external-image-manager", func() {
+			tracedLogger, _ := new(tracing.Tracer).Start(logger, "destroy")
+			Expect(externalImageManager.Destroy(tracedLogger, "hello", "/store/0/bundles/123/rootfs")).To(Succeed())
+
+			imageManagerCmd := fakeCommandRunner.ExecutedCommands()[1]
+			Expect(tracing.FromEnv(imageManagerCmd.Env)).To(Equal(tracing.TraceID(tracedLogger)))
+		})
+
 		Describe("external-image-manager parameters", func() {
 			It("uses the correct external-image-manager delete command", func() {
 				Expect(err).ToNot(HaveOccurred())
@@ -289,4 +299,85 @@ var _ = Describe("ExternalImageManager", func() {
 			})
 		})
 	})
+	Describe("Resize", func() {
+		var (
+			limits garden.DiskLimits
+			err    error
+		)
+
+		BeforeEach(func() {
+			limits = garden.DiskLimits{ByteHard: 1024}
+		})
+
+		JustBeforeEach(func() {
+			err = externalImageManager.Resize(logger, "hello", "/store/0/bundles/123/rootfs", limits)
+		})
+
+		It("uses the correct external-image-manager binary", func() {
+			Expect(err).ToNot(HaveOccurred())
+			Expect(len(fakeCommandRunner.ExecutedCommands())).To(Equal(1))
+			imageManagerCmd := fakeCommandRunner.ExecutedCommands()[0]
+
+			Expect(imageManagerCmd.Path).To(Equal("/external-image-manager-bin"))
+		})
+
+		Describe("external-image-manager parameters", func() {
+			It("uses the correct external-image-manager resize command", func() {
+				Expect(err).ToNot(HaveOccurred())
+				imageManagerCmd := fakeCommandRunner.ExecutedCommands()[0]
+
+				Expect(imageManagerCmd.Args[1]).To(Equal("resize"))
+			})
+
+			It("passes the new quota to the external-image-manager", func() {
+				Expect(err).ToNot(HaveOccurred())
+				imageManagerCmd := fakeCommandRunner.ExecutedCommands()[0]
+
+				Expect(imageManagerCmd.Args[2]).To(Equal("--disk-limit-size-bytes"))
+				Expect(imageManagerCmd.Args[3]).To(Equal("1024"))
+				Expect(imageManagerCmd.Args).NotTo(ContainElement("--exclude-image-from-quota"))
+			})
+
+			It("passes the correct bundle path to resize to the external-image-manager", func() {
+				Expect(err).ToNot(HaveOccurred())
+				imageManagerCmd := fakeCommandRunner.ExecutedCommands()[0]
+
+				Expect(imageManagerCmd.Args[len(imageManagerCmd.Args)-1]).To(Equal("/store/0/bundles/123"))
+			})
+
+			Context("when the scope is exclusive", func() {
+				BeforeEach(func() {
+					limits.Scope = garden.DiskLimitScopeExclusive
+				})
+
+				It("asks the external-image-manager to exclude the image from the quota", func() {
+					Expect(err).ToNot(HaveOccurred())
+					imageManagerCmd := fakeCommandRunner.ExecutedCommands()[0]
+
+					Expect(imageManagerCmd.Args).To(ContainElement("--exclude-image-from-quota"))
+				})
+			})
+		})
+
+		Context("when the command fails", func() {
+			BeforeEach(func() {
+				fakeCommandRunner.WhenRunning(fake_command_runner.CommandSpec{
+					Path: "/external-image-manager-bin",
+				}, func(cmd *exec.Cmd) error {
+					cmd.Stderr.Write([]byte("quota exceeded"))
+
+					return errors.New("external-image-manager failure")
+				})
+			})
+
+			It("returns an error", func() {
+				Expect(err).To(MatchError(ContainSubstring("external image manager resize failed")))
+				Expect(err).To(MatchError(ContainSubstring("external-image-manager failure")))
+			})
+
+			It("logs the external-image-manager error output", func() {
+				Expect(logger).To(gbytes.Say("quota exceeded"))
+			})
+		})
+	})
 })
@@ -4,7 +4,9 @@ import (
 	"net"
 	"os"
 
+	"code.cloudfoundry.org/guardian/gardener"
 	"code.cloudfoundry.org/guardian/kawasaki/netns"
+	"code.cloudfoundry.org/guardian/tracing"
 	"code.cloudfoundry.org/lager"
 )
 
@@ -19,6 +21,7 @@ type configurer struct {
 	containerConfigurer  ContainerConfigurer
 	instanceChainCreator InstanceChainCreator
 	fileOpener           netns.Opener
+	stages               gardener.StageRecorder
 }
 
 //go:generate counterfeiter . HostConfigurer
@@ -43,29 +46,39 @@ type DnsResolvConfigurer interface {
 	Configure(log lager.Logger, cfg NetworkConfig, pid int) error
 }
 
-func NewConfigurer(resolvConfigurer DnsResolvConfigurer, hostConfigurer HostConfigurer, containerConfigurer ContainerConfigurer, instanceChainCreator InstanceChainCreator) *configurer {
+func NewConfigurer(resolvConfigurer DnsResolvConfigurer, hostConfigurer HostConfigurer, containerConfigurer ContainerConfigurer, instanceChainCreator InstanceChainCreator, stages gardener.StageRecorder) *configurer {
 	return &configurer{
 		dnsResolvConfigurer:  resolvConfigurer,
 		hostConfigurer:       hostConfigurer,
 		containerConfigurer:  containerConfigurer,
 		instanceChainCreator: instanceChainCreator,
+		stages:               stages,
 	}
 }
 
-func (c *configurer) Apply(log lager.Logger, cfg NetworkConfig, pid int) error {
-	if err := c.dnsResolvConfigurer.Configure(log, cfg, pid); err != nil {
+func (c *configurer) Apply(log lager.Logger, cfg NetworkConfig, pid int) (err error) {
+	log, span := tracing.StartSpan(log, "network-configure")
+	timer := gardener.NewStageTimer(c.stages, "network-configure")
+	defer func() {
+		log.Info("configured", timer.Finish(err))
+		span.End(err)
+	}()
+
+	if err := timer.Time("dns-resolv", func() error { return c.dnsResolvConfigurer.Configure(log, cfg, pid) }); err != nil {
 		return err
 	}
 
-	if err := c.hostConfigurer.Apply(log, cfg, pid); err != nil {
+	if err := timer.Time("host", func() error { return c.hostConfigurer.Apply(log, cfg, pid) }); err != nil {
 		return err
 	}
 
-	if err := c.instanceChainCreator.Create(log, cfg.ContainerHandle, cfg.IPTableInstance, cfg.BridgeName, cfg.ContainerIP, cfg.Subnet); err != nil {
+	if err := timer.Time("instance-chain", func() error {
+		return c.instanceChainCreator.Create(log, cfg.ContainerHandle, cfg.IPTableInstance, cfg.BridgeName, cfg.ContainerIP, cfg.Subnet)
+	}); err != nil {
 		return err
 	}
 
-	return c.containerConfigurer.Apply(log, cfg, pid)
+	return timer.Time("container", func() error { return c.containerConfigurer.Apply(log, cfg, pid) })
 }
 
 func (c *configurer) DestroyBridge(log lager.Logger, cfg NetworkConfig) error {
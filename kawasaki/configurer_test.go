@@ -6,6 +6,8 @@ import (
 	"net"
 	"os"
 
+	"code.cloudfoundry.org/guardian/gardener"
+	"code.cloudfoundry.org/guardian/gardener/gardenerfakes"
 	"code.cloudfoundry.org/guardian/kawasaki"
 	fakes "code.cloudfoundry.org/guardian/kawasaki/kawasakifakes"
 	"code.cloudfoundry.org/guardian/kawasaki/netns"
@@ -22,6 +24,7 @@ var _ = Describe("Configurer", func() {
 		fakeHostConfigurer       *fakes.FakeHostConfigurer
 		fakeContainerConfigurer  *fakes.FakeContainerConfigurer
 		fakeInstanceChainCreator *fakes.FakeInstanceChainCreator
+		fakeStages               *gardenerfakes.FakeStageRecorder
 
 		dummyFileOpener netns.Opener
 
@@ -38,6 +41,7 @@ var _ = Describe("Configurer", func() {
 		fakeHostConfigurer = new(fakes.FakeHostConfigurer)
 		fakeContainerConfigurer = new(fakes.FakeContainerConfigurer)
 		fakeInstanceChainCreator = new(fakes.FakeInstanceChainCreator)
+		fakeStages = new(gardenerfakes.FakeStageRecorder)
 
 		var err error
 		netnsFD, err = ioutil.TempFile("", "")
@@ -47,7 +51,7 @@ var _ = Describe("Configurer", func() {
 			return netnsFD, nil
 		}
 
-		configurer = kawasaki.NewConfigurer(fakeDnsResolvConfigurer, fakeHostConfigurer, fakeContainerConfigurer, fakeInstanceChainCreator)
+		configurer = kawasaki.NewConfigurer(fakeDnsResolvConfigurer, fakeHostConfigurer, fakeContainerConfigurer, fakeInstanceChainCreator, fakeStages)
 
 		logger = lagertest.NewTestLogger("test")
 	})
@@ -70,6 +74,32 @@ var _ = Describe("Configurer", func() {
 				fakeDnsResolvConfigurer.ConfigureReturns(errors.New("baboom"))
 				Expect(configurer.Apply(logger, kawasaki.NetworkConfig{}, 42)).To(MatchError("baboom"))
 			})
+
+			It("records the failure of the stage and of the configuration", func() {
+				fakeDnsResolvConfigurer.ConfigureReturns(errors.New("baboom"))
+				configurer.Apply(logger, kawasaki.NetworkConfig{}, 42)
+
+				Expect(fakeStages.RecordStageCallCount()).To(Equal(2))
+				_, stage, _, err := fakeStages.RecordStageArgsForCall(0)
+				Expect(stage).To(Equal("dns-resolv"))
+				Expect(err).To(MatchError("baboom"))
+
+				_, stage, _, err = fakeStages.RecordStageArgsForCall(1)
+				Expect(stage).To(Equal(gardener.StageTotal))
+				Expect(err).To(MatchError("baboom"))
+			})
+		})
+
+		It("records how long each stage of the configuration took", func() {
+			Expect(configurer.Apply(logger, kawasaki.NetworkConfig{}, 42)).To(Succeed())
+
+			stages := []string{}
+			for i := 0; i < fakeStages.RecordStageCallCount(); i++ {
+				operation, stage, _, _ := fakeStages.RecordStageArgsForCall(i)
+				Expect(operation).To(Equal("network-configure"))
+				stages = append(stages, stage)
+			}
+			Expect(stages).To(Equal([]string{"dns-resolv", "host", "instance-chain", "container", gardener.StageTotal}))
 		})
 
 		It("applies the configuration in the host", func() {
@@ -1,7 +1,7 @@
 package fakedevices
 
 import "net"
-import "code.cloudfoundry.org/garden"
+import "code.cloudfoundry.org/guardian/kawasaki/devices"
 
 type FaveVethCreator struct {
 	CreateCalledWith struct {
@@ -95,14 +95,16 @@ func (f *FakeLink) InterfaceByName(name string) (*net.Interface, bool, error) {
 	return nil, false, nil
 }
 
-func (f *FakeLink) Statistics() (garden.ContainerNetworkStat, error) {
+func (f *FakeLink) Statistics(name string) (devices.Statistics, error) {
 	if f.StatisticsReturns != nil {
-		return garden.ContainerNetworkStat{}, f.StatisticsReturns
+		return devices.Statistics{}, f.StatisticsReturns
 	}
 
-	return garden.ContainerNetworkStat{
-		RxBytes: 1,
-		TxBytes: 2,
+	return devices.Statistics{
+		RxBytes:   1,
+		TxBytes:   2,
+		RxPackets: 3,
+		TxPackets: 4,
 	}, nil
 }
 
@@ -8,7 +8,6 @@ import (
 	"strconv"
 	"strings"
 
-	"code.cloudfoundry.org/garden"
 	"github.com/vishvananda/netlink"
 )
 
@@ -116,21 +115,24 @@ func (Link) List() (names []string, err error) {
 	return names, nil
 }
 
-func (l Link) Statistics(name string) (stats garden.ContainerNetworkStat, err error) {
-	var RxBytes, TxBytes uint64
-
-	if RxBytes, err = intfStat(name, "rx_bytes"); err != nil {
-		return stats, err
+func (l Link) Statistics(name string) (stats Statistics, err error) {
+	counters := []struct {
+		file  string
+		value *uint64
+	}{
+		{"rx_bytes", &stats.RxBytes},
+		{"tx_bytes", &stats.TxBytes},
+		{"rx_packets", &stats.RxPackets},
+		{"tx_packets", &stats.TxPackets},
 	}
 
-	if TxBytes, err = intfStat(name, "tx_bytes"); err != nil {
-		return stats, err
+	for _, counter := range counters {
+		if *counter.value, err = intfStat(name, counter.file); err != nil {
+			return Statistics{}, err
+		}
 	}
 
-	return garden.ContainerNetworkStat{
-		RxBytes: RxBytes,
-		TxBytes: TxBytes,
-	}, nil
+	return stats, nil
 }
 
 func intfStat(intf, statFile string) (stat uint64, err error) {
@@ -236,6 +236,8 @@ var _ = Describe("Link Management", func() {
 				Expect(afterStat.TxBytes).To(BeNumerically("<", beforeStat.TxBytes+(10*(42+80))+1000))
 				Expect(afterStat.RxBytes).To(BeNumerically(">=", beforeStat.RxBytes+(10*(42+80))))
 				Expect(afterStat.RxBytes).To(BeNumerically("<", beforeStat.RxBytes+(10*(42+80))+1000))
+				Expect(afterStat.TxPackets).To(BeNumerically(">=", beforeStat.TxPackets+10))
+				Expect(afterStat.RxPackets).To(BeNumerically(">=", beforeStat.RxPackets+10))
 			})
 		})
 
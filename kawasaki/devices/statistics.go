@@ -0,0 +1,10 @@
+package devices
+
+// Statistics are the traffic counters of an interface, as seen from the
+// interface itself
+type Statistics struct {
+	RxBytes   uint64
+	TxBytes   uint64
+	RxPackets uint64
+	TxPackets uint64
+}
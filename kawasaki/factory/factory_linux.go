@@ -3,6 +3,7 @@ package factory
 import (
 	"os"
 
+	"code.cloudfoundry.org/guardian/gardener"
 	"code.cloudfoundry.org/guardian/kawasaki"
 	"code.cloudfoundry.org/guardian/kawasaki/configure"
 	"code.cloudfoundry.org/guardian/kawasaki/devices"
@@ -11,7 +12,7 @@ import (
 	"code.cloudfoundry.org/guardian/kawasaki/netns"
 )
 
-func NewDefaultConfigurer(ipt *iptables.IPTablesController) kawasaki.Configurer {
+func NewDefaultConfigurer(ipt *iptables.IPTablesController, stages gardener.StageRecorder) kawasaki.Configurer {
 	resolvConfigurer := &kawasaki.ResolvConfigurer{
 		HostsFileCompiler:  &dns.HostsFileCompiler{},
 		ResolvFileCompiler: &dns.ResolvFileCompiler{},
@@ -35,5 +36,10 @@ func NewDefaultConfigurer(ipt *iptables.IPTablesController) kawasaki.Configurer
 		hostConfigurer,
 		containerConfigurer,
 		iptables.NewInstanceChainCreator(ipt),
+		stages,
 	)
 }
+
+func NewInterfaceStatser() kawasaki.InterfaceStatser {
+	return devices.Link{}
+}
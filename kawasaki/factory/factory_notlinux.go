@@ -3,10 +3,15 @@
 package factory
 
 import (
+	"code.cloudfoundry.org/guardian/gardener"
 	"code.cloudfoundry.org/guardian/kawasaki"
 	"code.cloudfoundry.org/guardian/kawasaki/iptables"
 )
 
-func NewDefaultConfigurer(ipt *iptables.IPTablesController) kawasaki.Configurer {
+func NewDefaultConfigurer(ipt *iptables.IPTablesController, stages gardener.StageRecorder) kawasaki.Configurer {
+	panic("not supported on this platform")
+}
+
+func NewInterfaceStatser() kawasaki.InterfaceStatser {
 	panic("not supported on this platform")
 }
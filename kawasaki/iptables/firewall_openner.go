@@ -38,7 +38,7 @@ func (f *FirewallOpener) Open(logger lager.Logger, instance string, rule garden.
 	}
 
 	for _, iptableRules := range iptableRules {
-		if err := f.iptables.PrependRule(chain, iptableRules); err != nil {
+		if err := f.iptables.PrependRule(logger, chain, iptableRules); err != nil {
 			return err
 		}
 	}
@@ -66,5 +66,5 @@ func (f *FirewallOpener) BulkOpen(logger lager.Logger, instance string, rules []
 		collatedIPTablesRules = append(collatedIPTablesRules, iptablesRules...)
 	}
 
-	return f.iptables.BulkPrependRules(chain, collatedIPTablesRules)
+	return f.iptables.BulkPrependRules(logger, chain, collatedIPTablesRules)
 }
@@ -69,9 +69,9 @@ var _ = Describe("FirewallOpenner", func() {
 			Expect(opener.Open(logger, "foo-bar-baz", garden.NetOutRule{})).To(Succeed())
 
 			Expect(fakeIPTablesController.PrependRuleCallCount()).To(Equal(2))
-			_, ruleA := fakeIPTablesController.PrependRuleArgsForCall(0)
+			_, _, ruleA := fakeIPTablesController.PrependRuleArgsForCall(0)
 			Expect(ruleA).To(Equal(rules[0]))
-			_, ruleB := fakeIPTablesController.PrependRuleArgsForCall(1)
+			_, _, ruleB := fakeIPTablesController.PrependRuleArgsForCall(1)
 			Expect(ruleB).To(Equal(rules[1]))
 		})
 
@@ -79,7 +79,7 @@ var _ = Describe("FirewallOpenner", func() {
 			Expect(opener.Open(logger, "foo-bar-baz", garden.NetOutRule{})).To(Succeed())
 
 			Expect(fakeIPTablesController.PrependRuleCallCount()).To(Equal(1))
-			chainName, _ := fakeIPTablesController.PrependRuleArgsForCall(0)
+			_, chainName, _ := fakeIPTablesController.PrependRuleArgsForCall(0)
 			Expect(chainName).To(Equal("prefix-foo-bar-baz"))
 		})
 
@@ -152,7 +152,7 @@ var _ = Describe("FirewallOpenner", func() {
 			Expect(opener.BulkOpen(logger, "foo-bar-baz", rules)).To(Succeed())
 
 			Expect(fakeIPTablesController.BulkPrependRulesCallCount()).To(Equal(1))
-			_, appendedIPTablesRules := fakeIPTablesController.BulkPrependRulesArgsForCall(0)
+			_, _, appendedIPTablesRules := fakeIPTablesController.BulkPrependRulesArgsForCall(0)
 			Expect(appendedIPTablesRules).To(HaveLen(4))
 			Expect(appendedIPTablesRules[0]).To(Equal(iptablesRules[0][0]))
 			Expect(appendedIPTablesRules[1]).To(Equal(iptablesRules[0][1]))
@@ -163,7 +163,7 @@ var _ = Describe("FirewallOpenner", func() {
 		It("prepends to the correct chain name", func() {
 			Expect(opener.BulkOpen(logger, "foo-bar-baz", rules)).To(Succeed())
 			Expect(fakeIPTablesController.BulkPrependRulesCallCount()).To(Equal(1))
-			chainName, _ := fakeIPTablesController.BulkPrependRulesArgsForCall(0)
+			_, chainName, _ := fakeIPTablesController.BulkPrependRulesArgsForCall(0)
 			Expect(chainName).To(Equal("prefix-foo-bar-baz"))
 		})
 
@@ -21,13 +21,13 @@ func NewInstanceChainCreator(iptables *IPTablesController) *InstanceChainCreator
 func (cc *InstanceChainCreator) Create(logger lager.Logger, handle, instanceId, bridgeName string, ip net.IP, network *net.IPNet) error {
 	instanceChain := cc.iptables.InstanceChain(instanceId)
 
-	if err := cc.iptables.CreateChain("nat", instanceChain); err != nil {
+	if err := cc.iptables.CreateChain(logger, "nat", instanceChain); err != nil {
 		return err
 	}
 
 	// Bind nat instance chain to nat prerouting chain
 	cmd := exec.Command(cc.iptables.binPath, "--wait", "--table", "nat", "-A", cc.iptables.preroutingChain, "--jump", instanceChain)
-	if err := cc.iptables.run("create-instance-chains", cmd); err != nil {
+	if err := cc.iptables.runTraced(logger, "create-instance-chains", cmd); err != nil {
 		return err
 	}
 
@@ -37,30 +37,30 @@ func (cc *InstanceChainCreator) Create(logger lager.Logger, handle, instanceId,
 		cc.iptables.binPath, cc.iptables.postroutingChain, network.String(), cc.iptables.binPath, cc.iptables.postroutingChain,
 		network.String(), network.String(),
 	))
-	if err := cc.iptables.run("create-instance-chains", cmd); err != nil {
+	if err := cc.iptables.runTraced(logger, "create-instance-chains", cmd); err != nil {
 		return err
 	}
 
 	// Create filter instance chain
-	if err := cc.iptables.CreateChain("filter", instanceChain); err != nil {
+	if err := cc.iptables.CreateChain(logger, "filter", instanceChain); err != nil {
 		return err
 	}
 
 	// Allow intra-subnet traffic (Linux ethernet bridging goes through ip stack)
 	cmd = exec.Command(cc.iptables.binPath, "--wait", "-A", instanceChain, "-s", network.String(), "-d", network.String(), "-j", "ACCEPT")
-	if err := cc.iptables.run("create-instance-chains", cmd); err != nil {
+	if err := cc.iptables.runTraced(logger, "create-instance-chains", cmd); err != nil {
 		return err
 	}
 
 	// Otherwise, use the default filter chain
 	cmd = exec.Command(cc.iptables.binPath, "--wait", "-A", instanceChain, "--goto", cc.iptables.defaultChain)
-	if err := cc.iptables.run("create-instance-chains", cmd); err != nil {
+	if err := cc.iptables.runTraced(logger, "create-instance-chains", cmd); err != nil {
 		return err
 	}
 
 	// Bind filter instance chain to filter forward chain
 	cmd = exec.Command(cc.iptables.binPath, "--wait", "-I", cc.iptables.forwardChain, "2", "--in-interface", bridgeName, "--source", ip.String(), "--goto", instanceChain)
-	if err := cc.iptables.run("create-instance-chains", cmd); err != nil {
+	if err := cc.iptables.runTraced(logger, "create-instance-chains", cmd); err != nil {
 		return err
 	}
 
@@ -72,7 +72,7 @@ func (cc *InstanceChainCreator) createLoggingChain(logger lager.Logger, handle,
 	instanceChain := cc.iptables.InstanceChain(instanceId)
 	loggingChain := fmt.Sprintf("%s-log", instanceChain)
 
-	if err := cc.iptables.CreateChain("filter", loggingChain); err != nil {
+	if err := cc.iptables.CreateChain(logger, "filter", loggingChain); err != nil {
 		return err
 	}
 
@@ -81,12 +81,12 @@ func (cc *InstanceChainCreator) createLoggingChain(logger lager.Logger, handle,
 	}
 
 	cmd := exec.Command(cc.iptables.binPath, "--wait", "-A", loggingChain, "-m", "conntrack", "--ctstate", "NEW,UNTRACKED,INVALID", "--protocol", "tcp", "--jump", "LOG", "--log-prefix", handle)
-	if err := cc.iptables.run("create-instance-chains", cmd); err != nil {
+	if err := cc.iptables.runTraced(logger, "create-instance-chains", cmd); err != nil {
 		return err
 	}
 
 	cmd = exec.Command(cc.iptables.binPath, "--wait", "-A", loggingChain, "--jump", "RETURN")
-	if err := cc.iptables.run("create-instance-chains", cmd); err != nil {
+	if err := cc.iptables.runTraced(logger, "create-instance-chains", cmd); err != nil {
 		return err
 	}
 
@@ -101,17 +101,17 @@ func (cc *InstanceChainCreator) Destroy(logger lager.Logger, instanceId string)
 		`%s --wait --table nat -S %s 2> /dev/null | grep "\-j %s\b" | sed -e "s/-A/-D/" | xargs --no-run-if-empty --max-lines=1 %s --wait --table nat`,
 		cc.iptables.binPath, cc.iptables.preroutingChain, instanceChain, cc.iptables.binPath,
 	))
-	if err := cc.iptables.run("prune-prerouting-chain", cmd); err != nil {
+	if err := cc.iptables.runTraced(logger, "prune-prerouting-chain", cmd); err != nil {
 		return err
 	}
 
 	// Flush instance chain
-	if err := cc.iptables.FlushChain("nat", instanceChain); err != nil {
+	if err := cc.iptables.FlushChain(logger, "nat", instanceChain); err != nil {
 		return err
 	}
 
 	// Delete nat instance chain
-	if err := cc.iptables.DeleteChain("nat", instanceChain); err != nil {
+	if err := cc.iptables.DeleteChain(logger, "nat", instanceChain); err != nil {
 		return err
 	}
 
@@ -120,20 +120,20 @@ func (cc *InstanceChainCreator) Destroy(logger lager.Logger, instanceId string)
 		`%s --wait -S %s 2> /dev/null | grep "\-g %s\b" | sed -e "s/-A/-D/" | xargs --no-run-if-empty --max-lines=1 %s --wait`,
 		cc.iptables.binPath, cc.iptables.forwardChain, instanceChain, cc.iptables.binPath,
 	))
-	if err := cc.iptables.run("prune-forward-chain", cmd); err != nil {
+	if err := cc.iptables.runTraced(logger, "prune-forward-chain", cmd); err != nil {
 		return err
 	}
 
 	// Flush instance chain
-	cc.iptables.FlushChain("filter", instanceChain)
+	cc.iptables.FlushChain(logger, "filter", instanceChain)
 
 	// delete instance chain
-	cc.iptables.DeleteChain("filter", instanceChain)
+	cc.iptables.DeleteChain(logger, "filter", instanceChain)
 
 	// delete the logging chain
 	instanceLoggingChain := fmt.Sprintf("%s-log", instanceChain)
-	cc.iptables.FlushChain("filter", instanceLoggingChain)
-	cc.iptables.DeleteChain("filter", instanceLoggingChain)
+	cc.iptables.FlushChain(logger, "filter", instanceLoggingChain)
+	cc.iptables.DeleteChain(logger, "filter", instanceLoggingChain)
 
 	return nil
 }
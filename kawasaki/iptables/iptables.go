@@ -7,6 +7,8 @@ import (
 	"strings"
 
 	"code.cloudfoundry.org/guardian/pkg/locksmith"
+	"code.cloudfoundry.org/guardian/tracing"
+	"code.cloudfoundry.org/lager"
 
 	"github.com/cloudfoundry/gunk/command_runner"
 )
@@ -24,12 +26,12 @@ type Rule interface {
 
 //go:generate counterfeiter . IPTables
 type IPTables interface {
-	CreateChain(table, chain string) error
-	DeleteChain(table, chain string) error
-	FlushChain(table, chain string) error
-	DeleteChainReferences(table, targetChain, referencedChain string) error
-	PrependRule(chain string, rule Rule) error
-	BulkPrependRules(chain string, rules []Rule) error
+	CreateChain(logger lager.Logger, table, chain string) error
+	DeleteChain(logger lager.Logger, table, chain string) error
+	FlushChain(logger lager.Logger, table, chain string) error
+	DeleteChainReferences(logger lager.Logger, table, targetChain, referencedChain string) error
+	PrependRule(logger lager.Logger, chain string, rule Rule) error
+	BulkPrependRules(logger lager.Logger, chain string, rules []Rule) error
 	InstanceChain(instanceId string) string
 }
 
@@ -59,39 +61,39 @@ func New(binPath string, runner command_runner.CommandRunner, locksmith Locksmit
 	}
 }
 
-func (iptables *IPTablesController) CreateChain(table, chain string) error {
-	return iptables.run("create-instance-chains", exec.Command(iptables.binPath, "--wait", "--table", table, "-N", chain))
+func (iptables *IPTablesController) CreateChain(logger lager.Logger, table, chain string) error {
+	return iptables.runTraced(logger, "create-instance-chains", exec.Command(iptables.binPath, "--wait", "--table", table, "-N", chain))
 }
 
-func (iptables *IPTablesController) DeleteChain(table, chain string) error {
+func (iptables *IPTablesController) DeleteChain(logger lager.Logger, table, chain string) error {
 	shellCmd := fmt.Sprintf(
 		`%s --wait --table %s -X %s 2> /dev/null || true`,
 		iptables.binPath, table, chain,
 	)
-	return iptables.run("delete-instance-chains", exec.Command("sh", "-c", shellCmd))
+	return iptables.runTraced(logger, "delete-instance-chains", exec.Command("sh", "-c", shellCmd))
 }
 
-func (iptables *IPTablesController) FlushChain(table, chain string) error {
+func (iptables *IPTablesController) FlushChain(logger lager.Logger, table, chain string) error {
 	shellCmd := fmt.Sprintf(
 		`%s --wait --table %s -F %s 2> /dev/null || true`,
 		iptables.binPath, table, chain,
 	)
-	return iptables.run("flush-instance-chains", exec.Command("sh", "-c", shellCmd))
+	return iptables.runTraced(logger, "flush-instance-chains", exec.Command("sh", "-c", shellCmd))
 }
 
-func (iptables *IPTablesController) DeleteChainReferences(table, targetChain, referencedChain string) error {
+func (iptables *IPTablesController) DeleteChainReferences(logger lager.Logger, table, targetChain, referencedChain string) error {
 	shellCmd := fmt.Sprintf(
 		`set -e; %s --wait --table %s -S %s | grep "%s" | sed -e "s/-A/-D/" | xargs --no-run-if-empty --max-lines=1 %s -w -t %s`,
 		iptables.binPath, table, targetChain, referencedChain, iptables.binPath, table,
 	)
-	return iptables.run("delete-referenced-chains", exec.Command("sh", "-c", shellCmd))
+	return iptables.runTraced(logger, "delete-referenced-chains", exec.Command("sh", "-c", shellCmd))
 }
 
-func (iptables *IPTablesController) PrependRule(chain string, rule Rule) error {
-	return iptables.run("prepend", exec.Command(iptables.binPath, append([]string{"-w", "-I", chain, "1"}, rule.Flags(chain)...)...))
+func (iptables *IPTablesController) PrependRule(logger lager.Logger, chain string, rule Rule) error {
+	return iptables.runTraced(logger, "prepend", exec.Command(iptables.binPath, append([]string{"-w", "-I", chain, "1"}, rule.Flags(chain)...)...))
 }
 
-func (iptables *IPTablesController) BulkPrependRules(chain string, rules []Rule) error {
+func (iptables *IPTablesController) BulkPrependRules(logger lager.Logger, chain string, rules []Rule) error {
 	in := bytes.NewBuffer([]byte{})
 	in.WriteString("*filter\n")
 	for _, r := range rules {
@@ -104,13 +106,20 @@ func (iptables *IPTablesController) BulkPrependRules(chain string, rules []Rule)
 	cmd := exec.Command("iptables-restore", "--noflush")
 	cmd.Stdin = in
 
-	return iptables.run("append-rules", cmd)
+	return iptables.runTraced(logger, "append-rules", cmd)
 }
 
 func (iptables *IPTablesController) InstanceChain(instanceId string) string {
 	return iptables.instanceChainPrefix + instanceId
 }
 
+// runTraced runs a command on behalf of an API call, passing on the trace id
+// of its logger
+func (iptables *IPTablesController) runTraced(logger lager.Logger, action string, cmd *exec.Cmd) error {
+	tracing.SetEnv(logger, cmd)
+	return iptables.run(action, cmd)
+}
+
 func (iptables *IPTablesController) run(action string, cmd *exec.Cmd) error {
 	var buff bytes.Buffer
 	cmd.Stdout = &buff
@@ -132,5 +141,9 @@ func (iptables *IPTablesController) run(action string, cmd *exec.Cmd) error {
 }
 
 func (iptables *IPTablesController) appendRule(chain string, rule Rule) error {
-	return iptables.run("append", exec.Command(iptables.binPath, append([]string{"-w", "-A", chain}, rule.Flags(chain)...)...))
+	return iptables.run("append", iptables.appendRuleCommand(chain, rule))
+}
+
+func (iptables *IPTablesController) appendRuleCommand(chain string, rule Rule) *exec.Cmd {
+	return exec.Command(iptables.binPath, append([]string{"-w", "-A", chain}, rule.Flags(chain)...)...)
 }
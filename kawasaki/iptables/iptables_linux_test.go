@@ -8,6 +8,8 @@ import (
 	"code.cloudfoundry.org/guardian/kawasaki/iptables"
 	fakes "code.cloudfoundry.org/guardian/kawasaki/iptables/iptablesfakes"
 	"code.cloudfoundry.org/guardian/pkg/locksmith"
+	"code.cloudfoundry.org/lager"
+	"code.cloudfoundry.org/lager/lagertest"
 	"github.com/cloudfoundry/gunk/command_runner/fake_command_runner"
 	. "github.com/onsi/ginkgo"
 	. "github.com/onsi/gomega"
@@ -21,6 +23,7 @@ var _ = Describe("IPTables controller", func() {
 		prefix             string
 		iptablesController iptables.IPTables
 		fakeLocksmith      *FakeLocksmith
+		logger             lager.Logger
 	)
 
 	BeforeEach(func() {
@@ -35,6 +38,7 @@ var _ = Describe("IPTables controller", func() {
 		)
 
 		fakeLocksmith = NewFakeLocksmith()
+		logger = lagertest.NewTestLogger("test")
 
 		prefix = fmt.Sprintf("g-%d", GinkgoParallelNode())
 		iptablesController = iptables.New("/sbin/iptables", fakeRunner, fakeLocksmith, prefix)
@@ -46,7 +50,7 @@ var _ = Describe("IPTables controller", func() {
 
 	Describe("CreateChain", func() {
 		It("creates the chain", func() {
-			Expect(iptablesController.CreateChain("filter", "test-chain")).To(Succeed())
+			Expect(iptablesController.CreateChain(logger, "filter", "test-chain")).To(Succeed())
 
 			sess, err := gexec.Start(wrapCmdInNs(netnsName, exec.Command("iptables", "-L", "test-chain")), GinkgoWriter, GinkgoWriter)
 			Expect(err).NotTo(HaveOccurred())
@@ -55,7 +59,7 @@ var _ = Describe("IPTables controller", func() {
 
 		Context("when the table is nat", func() {
 			It("creates the nat chain", func() {
-				Expect(iptablesController.CreateChain("nat", "test-chain")).To(Succeed())
+				Expect(iptablesController.CreateChain(logger, "nat", "test-chain")).To(Succeed())
 
 				sess, err := gexec.Start(wrapCmdInNs(netnsName, exec.Command("iptables", "-t", "nat", "-L", "test-chain")), GinkgoWriter, GinkgoWriter)
 				Expect(err).NotTo(HaveOccurred())
@@ -65,11 +69,11 @@ var _ = Describe("IPTables controller", func() {
 
 		Context("when the chain already exists", func() {
 			BeforeEach(func() {
-				Expect(iptablesController.CreateChain("nat", "test-chain")).To(Succeed())
+				Expect(iptablesController.CreateChain(logger, "nat", "test-chain")).To(Succeed())
 			})
 
 			It("returns an error", func() {
-				Expect(iptablesController.CreateChain("nat", "test-chain")).NotTo(Succeed())
+				Expect(iptablesController.CreateChain(logger, "nat", "test-chain")).NotTo(Succeed())
 			})
 		})
 	})
@@ -81,10 +85,10 @@ var _ = Describe("IPTables controller", func() {
 			fakeUDPRule := new(fakes.FakeRule)
 			fakeUDPRule.FlagsReturns([]string{"--protocol", "udp"})
 
-			Expect(iptablesController.CreateChain("filter", "test-chain")).To(Succeed())
+			Expect(iptablesController.CreateChain(logger, "filter", "test-chain")).To(Succeed())
 
-			Expect(iptablesController.PrependRule("test-chain", fakeTCPRule)).To(Succeed())
-			Expect(iptablesController.PrependRule("test-chain", fakeUDPRule)).To(Succeed())
+			Expect(iptablesController.PrependRule(logger, "test-chain", fakeTCPRule)).To(Succeed())
+			Expect(iptablesController.PrependRule(logger, "test-chain", fakeUDPRule)).To(Succeed())
 
 			buff := gbytes.NewBuffer()
 			sess, err := gexec.Start(wrapCmdInNs(netnsName, exec.Command("iptables", "-S", "test-chain")), buff, GinkgoWriter)
@@ -97,7 +101,7 @@ var _ = Describe("IPTables controller", func() {
 			fakeRule := new(fakes.FakeRule)
 			fakeRule.FlagsReturns([]string{})
 
-			Expect(iptablesController.PrependRule("test-chain", fakeRule)).NotTo(Succeed())
+			Expect(iptablesController.PrependRule(logger, "test-chain", fakeRule)).NotTo(Succeed())
 		})
 	})
 
@@ -108,8 +112,8 @@ var _ = Describe("IPTables controller", func() {
 			fakeUDPRule := new(fakes.FakeRule)
 			fakeUDPRule.FlagsReturns([]string{"--protocol", "udp"})
 
-			Expect(iptablesController.CreateChain("filter", "test-chain")).To(Succeed())
-			Expect(iptablesController.BulkPrependRules("test-chain", []iptables.Rule{
+			Expect(iptablesController.CreateChain(logger, "filter", "test-chain")).To(Succeed())
+			Expect(iptablesController.BulkPrependRules(logger, "test-chain", []iptables.Rule{
 				fakeTCPRule,
 				fakeUDPRule,
 			})).To(Succeed())
@@ -125,17 +129,17 @@ var _ = Describe("IPTables controller", func() {
 			fakeRule := new(fakes.FakeRule)
 			fakeRule.FlagsReturns([]string{"--protocol", "tcp"})
 
-			Expect(iptablesController.BulkPrependRules("test-chain", []iptables.Rule{fakeRule})).NotTo(Succeed())
+			Expect(iptablesController.BulkPrependRules(logger, "test-chain", []iptables.Rule{fakeRule})).NotTo(Succeed())
 		})
 	})
 
 	Describe("DeleteChain", func() {
 		BeforeEach(func() {
-			Expect(iptablesController.CreateChain("filter", "test-chain")).To(Succeed())
+			Expect(iptablesController.CreateChain(logger, "filter", "test-chain")).To(Succeed())
 		})
 
 		It("deletes the chain", func() {
-			Expect(iptablesController.DeleteChain("filter", "test-chain")).To(Succeed())
+			Expect(iptablesController.DeleteChain(logger, "filter", "test-chain")).To(Succeed())
 
 			sess, err := gexec.Start(wrapCmdInNs(netnsName, exec.Command("iptables", "-L", "test-chain")), GinkgoWriter, GinkgoWriter)
 			Expect(err).NotTo(HaveOccurred())
@@ -144,11 +148,11 @@ var _ = Describe("IPTables controller", func() {
 
 		Context("when the table is nat", func() {
 			BeforeEach(func() {
-				Expect(iptablesController.CreateChain("nat", "test-chain")).To(Succeed())
+				Expect(iptablesController.CreateChain(logger, "nat", "test-chain")).To(Succeed())
 			})
 
 			It("deletes the nat chain", func() {
-				Expect(iptablesController.DeleteChain("nat", "test-chain")).To(Succeed())
+				Expect(iptablesController.DeleteChain(logger, "nat", "test-chain")).To(Succeed())
 
 				sess, err := gexec.Start(wrapCmdInNs(netnsName, exec.Command("iptables", "-t", "nat", "-L", "test-chain")), GinkgoWriter, GinkgoWriter)
 				Expect(err).NotTo(HaveOccurred())
@@ -158,7 +162,7 @@ var _ = Describe("IPTables controller", func() {
 
 		Context("when the chain does not exist", func() {
 			It("does not return an error", func() {
-				Expect(iptablesController.DeleteChain("filter", "test-non-existing-chain")).To(Succeed())
+				Expect(iptablesController.DeleteChain(logger, "filter", "test-non-existing-chain")).To(Succeed())
 			})
 		})
 	})
@@ -171,7 +175,7 @@ var _ = Describe("IPTables controller", func() {
 		})
 
 		JustBeforeEach(func() {
-			Expect(iptablesController.CreateChain(table, "test-chain")).To(Succeed())
+			Expect(iptablesController.CreateChain(logger, table, "test-chain")).To(Succeed())
 
 			sess, err := gexec.Start(wrapCmdInNs(netnsName, exec.Command("iptables", "-t", table, "-A", "test-chain", "-j", "ACCEPT")), GinkgoWriter, GinkgoWriter)
 			Expect(err).NotTo(HaveOccurred())
@@ -179,7 +183,7 @@ var _ = Describe("IPTables controller", func() {
 		})
 
 		It("flushes the chain", func() {
-			Expect(iptablesController.FlushChain(table, "test-chain")).To(Succeed())
+			Expect(iptablesController.FlushChain(logger, table, "test-chain")).To(Succeed())
 
 			buff := gbytes.NewBuffer()
 			sess, err := gexec.Start(wrapCmdInNs(netnsName, exec.Command("iptables", "-t", table, "-S", "test-chain")), buff, GinkgoWriter)
@@ -194,7 +198,7 @@ var _ = Describe("IPTables controller", func() {
 			})
 
 			It("flushes the nat chain", func() {
-				Expect(iptablesController.FlushChain(table, "test-chain")).To(Succeed())
+				Expect(iptablesController.FlushChain(logger, table, "test-chain")).To(Succeed())
 
 				buff := gbytes.NewBuffer()
 				sess, err := gexec.Start(wrapCmdInNs(netnsName, exec.Command("iptables", "-t", table, "-S", "test-chain")), buff, GinkgoWriter)
@@ -206,7 +210,7 @@ var _ = Describe("IPTables controller", func() {
 
 		Context("when the chain does not exist", func() {
 			It("does not return an error", func() {
-				Expect(iptablesController.FlushChain("filter", "test-non-existing-chain")).To(Succeed())
+				Expect(iptablesController.FlushChain(logger, "filter", "test-non-existing-chain")).To(Succeed())
 			})
 		})
 	})
@@ -219,8 +223,8 @@ var _ = Describe("IPTables controller", func() {
 		})
 
 		JustBeforeEach(func() {
-			Expect(iptablesController.CreateChain(table, "test-chain-1")).To(Succeed())
-			Expect(iptablesController.CreateChain(table, "test-chain-2")).To(Succeed())
+			Expect(iptablesController.CreateChain(logger, table, "test-chain-1")).To(Succeed())
+			Expect(iptablesController.CreateChain(logger, table, "test-chain-2")).To(Succeed())
 
 			sess, err := gexec.Start(wrapCmdInNs(netnsName, exec.Command("iptables", "-t", table, "-A", "test-chain-1", "-j", "test-chain-2")), GinkgoWriter, GinkgoWriter)
 			Expect(err).NotTo(HaveOccurred())
@@ -228,7 +232,7 @@ var _ = Describe("IPTables controller", func() {
 		})
 
 		It("deletes the references", func() {
-			Expect(iptablesController.DeleteChainReferences(table, "test-chain-1", "test-chain-2")).To(Succeed())
+			Expect(iptablesController.DeleteChainReferences(logger, table, "test-chain-1", "test-chain-2")).To(Succeed())
 
 			Eventually(func() string {
 				buff := gbytes.NewBuffer()
@@ -255,7 +259,7 @@ var _ = Describe("IPTables controller", func() {
 				done := make(chan struct{})
 				go func() {
 					defer GinkgoRecover()
-					Expect(iptablesController.CreateChain("filter", "test-chain")).To(Succeed())
+					Expect(iptablesController.CreateChain(logger, "filter", "test-chain")).To(Succeed())
 					close(done)
 				}()
 
@@ -266,13 +270,13 @@ var _ = Describe("IPTables controller", func() {
 		})
 
 		It("should unlock, ensuring future commands can get the lock", func(done Done) {
-			Expect(iptablesController.CreateChain("filter", "test-chain-1")).To(Succeed())
-			Expect(iptablesController.CreateChain("filter", "test-chain-2")).To(Succeed())
+			Expect(iptablesController.CreateChain(logger, "filter", "test-chain-1")).To(Succeed())
+			Expect(iptablesController.CreateChain(logger, "filter", "test-chain-2")).To(Succeed())
 			close(done)
 		}, 2.0)
 
 		It("should lock to correct key", func() {
-			Expect(iptablesController.CreateChain("filter", "test-chain-1")).To(Succeed())
+			Expect(iptablesController.CreateChain(logger, "filter", "test-chain-1")).To(Succeed())
 			Expect(fakeLocksmith.KeyForLastLock()).To(Equal(iptables.LockKey))
 		})
 
@@ -282,15 +286,15 @@ var _ = Describe("IPTables controller", func() {
 			})
 
 			It("returns the error", func() {
-				Expect(iptablesController.CreateChain("filter", "test-chain")).To(MatchError("failed to lock"))
+				Expect(iptablesController.CreateChain(logger, "filter", "test-chain")).To(MatchError("failed to lock"))
 			})
 		})
 
 		Context("when running an iptables command fails", func() {
 			It("still unlocks", func(done Done) {
 				// this is going to fail, because the chain does not exist
-				Expect(iptablesController.PrependRule("non-existent-chain", iptables.SingleFilterRule{})).NotTo(Succeed())
-				Expect(iptablesController.CreateChain("filter", "test-chain-2")).To(Succeed())
+				Expect(iptablesController.PrependRule(logger, "non-existent-chain", iptables.SingleFilterRule{})).NotTo(Succeed())
+				Expect(iptablesController.CreateChain(logger, "filter", "test-chain-2")).To(Succeed())
 				close(done)
 			}, 2.0)
 		})
@@ -301,7 +305,7 @@ var _ = Describe("IPTables controller", func() {
 			})
 
 			It("returns the error", func() {
-				Expect(iptablesController.CreateChain("filter", "test-chain")).To(MatchError("failed to unlock"))
+				Expect(iptablesController.CreateChain(logger, "filter", "test-chain")).To(MatchError("failed to unlock"))
 			})
 		})
 	})
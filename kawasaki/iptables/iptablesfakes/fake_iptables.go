@@ -2,42 +2,46 @@
 package iptablesfakes
 
 import (
-	"sync"
-
 	"code.cloudfoundry.org/guardian/kawasaki/iptables"
+	"code.cloudfoundry.org/lager"
+	"sync"
 )
 
 type FakeIPTables struct {
-	CreateChainStub        func(table, chain string) error
+	CreateChainStub        func(logger lager.Logger, table string, chain string) error
 	createChainMutex       sync.RWMutex
 	createChainArgsForCall []struct {
-		table string
-		chain string
+		logger lager.Logger
+		table  string
+		chain  string
 	}
 	createChainReturns struct {
 		result1 error
 	}
-	DeleteChainStub        func(table, chain string) error
+	DeleteChainStub        func(logger lager.Logger, table string, chain string) error
 	deleteChainMutex       sync.RWMutex
 	deleteChainArgsForCall []struct {
-		table string
-		chain string
+		logger lager.Logger
+		table  string
+		chain  string
 	}
 	deleteChainReturns struct {
 		result1 error
 	}
-	FlushChainStub        func(table, chain string) error
+	FlushChainStub        func(logger lager.Logger, table string, chain string) error
 	flushChainMutex       sync.RWMutex
 	flushChainArgsForCall []struct {
-		table string
-		chain string
+		logger lager.Logger
+		table  string
+		chain  string
 	}
 	flushChainReturns struct {
 		result1 error
 	}
-	DeleteChainReferencesStub        func(table, targetChain, referencedChain string) error
+	DeleteChainReferencesStub        func(logger lager.Logger, table string, targetChain string, referencedChain string) error
 	deleteChainReferencesMutex       sync.RWMutex
 	deleteChainReferencesArgsForCall []struct {
+		logger          lager.Logger
 		table           string
 		targetChain     string
 		referencedChain string
@@ -45,20 +49,22 @@ type FakeIPTables struct {
 	deleteChainReferencesReturns struct {
 		result1 error
 	}
-	PrependRuleStub        func(chain string, rule iptables.Rule) error
+	PrependRuleStub        func(logger lager.Logger, chain string, rule iptables.Rule) error
 	prependRuleMutex       sync.RWMutex
 	prependRuleArgsForCall []struct {
-		chain string
-		rule  iptables.Rule
+		logger lager.Logger
+		chain  string
+		rule   iptables.Rule
 	}
 	prependRuleReturns struct {
 		result1 error
 	}
-	BulkPrependRulesStub        func(chain string, rules []iptables.Rule) error
+	BulkPrependRulesStub        func(logger lager.Logger, chain string, rules []iptables.Rule) error
 	bulkPrependRulesMutex       sync.RWMutex
 	bulkPrependRulesArgsForCall []struct {
-		chain string
-		rules []iptables.Rule
+		logger lager.Logger
+		chain  string
+		rules  []iptables.Rule
 	}
 	bulkPrependRulesReturns struct {
 		result1 error
@@ -75,16 +81,17 @@ type FakeIPTables struct {
 	invocationsMutex sync.RWMutex
 }
 
-func (fake *FakeIPTables) CreateChain(table string, chain string) error {
+func (fake *FakeIPTables) CreateChain(logger lager.Logger, table string, chain string) error {
 	fake.createChainMutex.Lock()
 	fake.createChainArgsForCall = append(fake.createChainArgsForCall, struct {
-		table string
-		chain string
-	}{table, chain})
-	fake.recordInvocation("CreateChain", []interface{}{table, chain})
+		logger lager.Logger
+		table  string
+		chain  string
+	}{logger, table, chain})
+	fake.recordInvocation("CreateChain", []interface{}{logger, table, chain})
 	fake.createChainMutex.Unlock()
 	if fake.CreateChainStub != nil {
-		return fake.CreateChainStub(table, chain)
+		return fake.CreateChainStub(logger, table, chain)
 	} else {
 		return fake.createChainReturns.result1
 	}
@@ -96,10 +103,10 @@ func (fake *FakeIPTables) CreateChainCallCount() int {
 	return len(fake.createChainArgsForCall)
 }
 
-func (fake *FakeIPTables) CreateChainArgsForCall(i int) (string, string) {
+func (fake *FakeIPTables) CreateChainArgsForCall(i int) (lager.Logger, string, string) {
 	fake.createChainMutex.RLock()
 	defer fake.createChainMutex.RUnlock()
-	return fake.createChainArgsForCall[i].table, fake.createChainArgsForCall[i].chain
+	return fake.createChainArgsForCall[i].logger, fake.createChainArgsForCall[i].table, fake.createChainArgsForCall[i].chain
 }
 
 func (fake *FakeIPTables) CreateChainReturns(result1 error) {
@@ -109,16 +116,17 @@ func (fake *FakeIPTables) CreateChainReturns(result1 error) {
 	}{result1}
 }
 
-func (fake *FakeIPTables) DeleteChain(table string, chain string) error {
+func (fake *FakeIPTables) DeleteChain(logger lager.Logger, table string, chain string) error {
 	fake.deleteChainMutex.Lock()
 	fake.deleteChainArgsForCall = append(fake.deleteChainArgsForCall, struct {
-		table string
-		chain string
-	}{table, chain})
-	fake.recordInvocation("DeleteChain", []interface{}{table, chain})
+		logger lager.Logger
+		table  string
+		chain  string
+	}{logger, table, chain})
+	fake.recordInvocation("DeleteChain", []interface{}{logger, table, chain})
 	fake.deleteChainMutex.Unlock()
 	if fake.DeleteChainStub != nil {
-		return fake.DeleteChainStub(table, chain)
+		return fake.DeleteChainStub(logger, table, chain)
 	} else {
 		return fake.deleteChainReturns.result1
 	}
@@ -130,10 +138,10 @@ func (fake *FakeIPTables) DeleteChainCallCount() int {
 	return len(fake.deleteChainArgsForCall)
 }
 
-func (fake *FakeIPTables) DeleteChainArgsForCall(i int) (string, string) {
+func (fake *FakeIPTables) DeleteChainArgsForCall(i int) (lager.Logger, string, string) {
 	fake.deleteChainMutex.RLock()
 	defer fake.deleteChainMutex.RUnlock()
-	return fake.deleteChainArgsForCall[i].table, fake.deleteChainArgsForCall[i].chain
+	return fake.deleteChainArgsForCall[i].logger, fake.deleteChainArgsForCall[i].table, fake.deleteChainArgsForCall[i].chain
 }
 
 func (fake *FakeIPTables) DeleteChainReturns(result1 error) {
@@ -143,16 +151,17 @@ func (fake *FakeIPTables) DeleteChainReturns(result1 error) {
 	}{result1}
 }
 
-func (fake *FakeIPTables) FlushChain(table string, chain string) error {
+func (fake *FakeIPTables) FlushChain(logger lager.Logger, table string, chain string) error {
 	fake.flushChainMutex.Lock()
 	fake.flushChainArgsForCall = append(fake.flushChainArgsForCall, struct {
-		table string
-		chain string
-	}{table, chain})
-	fake.recordInvocation("FlushChain", []interface{}{table, chain})
+		logger lager.Logger
+		table  string
+		chain  string
+	}{logger, table, chain})
+	fake.recordInvocation("FlushChain", []interface{}{logger, table, chain})
 	fake.flushChainMutex.Unlock()
 	if fake.FlushChainStub != nil {
-		return fake.FlushChainStub(table, chain)
+		return fake.FlushChainStub(logger, table, chain)
 	} else {
 		return fake.flushChainReturns.result1
 	}
@@ -164,10 +173,10 @@ func (fake *FakeIPTables) FlushChainCallCount() int {
 	return len(fake.flushChainArgsForCall)
 }
 
-func (fake *FakeIPTables) FlushChainArgsForCall(i int) (string, string) {
+func (fake *FakeIPTables) FlushChainArgsForCall(i int) (lager.Logger, string, string) {
 	fake.flushChainMutex.RLock()
 	defer fake.flushChainMutex.RUnlock()
-	return fake.flushChainArgsForCall[i].table, fake.flushChainArgsForCall[i].chain
+	return fake.flushChainArgsForCall[i].logger, fake.flushChainArgsForCall[i].table, fake.flushChainArgsForCall[i].chain
 }
 
 func (fake *FakeIPTables) FlushChainReturns(result1 error) {
@@ -177,17 +186,18 @@ func (fake *FakeIPTables) FlushChainReturns(result1 error) {
 	}{result1}
 }
 
-func (fake *FakeIPTables) DeleteChainReferences(table string, targetChain string, referencedChain string) error {
+func (fake *FakeIPTables) DeleteChainReferences(logger lager.Logger, table string, targetChain string, referencedChain string) error {
 	fake.deleteChainReferencesMutex.Lock()
 	fake.deleteChainReferencesArgsForCall = append(fake.deleteChainReferencesArgsForCall, struct {
+		logger          lager.Logger
 		table           string
 		targetChain     string
 		referencedChain string
-	}{table, targetChain, referencedChain})
-	fake.recordInvocation("DeleteChainReferences", []interface{}{table, targetChain, referencedChain})
+	}{logger, table, targetChain, referencedChain})
+	fake.recordInvocation("DeleteChainReferences", []interface{}{logger, table, targetChain, referencedChain})
 	fake.deleteChainReferencesMutex.Unlock()
 	if fake.DeleteChainReferencesStub != nil {
-		return fake.DeleteChainReferencesStub(table, targetChain, referencedChain)
+		return fake.DeleteChainReferencesStub(logger, table, targetChain, referencedChain)
 	} else {
 		return fake.deleteChainReferencesReturns.result1
 	}
@@ -199,10 +209,10 @@ func (fake *FakeIPTables) DeleteChainReferencesCallCount() int {
 	return len(fake.deleteChainReferencesArgsForCall)
 }
 
-func (fake *FakeIPTables) DeleteChainReferencesArgsForCall(i int) (string, string, string) {
+func (fake *FakeIPTables) DeleteChainReferencesArgsForCall(i int) (lager.Logger, string, string, string) {
 	fake.deleteChainReferencesMutex.RLock()
 	defer fake.deleteChainReferencesMutex.RUnlock()
-	return fake.deleteChainReferencesArgsForCall[i].table, fake.deleteChainReferencesArgsForCall[i].targetChain, fake.deleteChainReferencesArgsForCall[i].referencedChain
+	return fake.deleteChainReferencesArgsForCall[i].logger, fake.deleteChainReferencesArgsForCall[i].table, fake.deleteChainReferencesArgsForCall[i].targetChain, fake.deleteChainReferencesArgsForCall[i].referencedChain
 }
 
 func (fake *FakeIPTables) DeleteChainReferencesReturns(result1 error) {
@@ -212,16 +222,17 @@ func (fake *FakeIPTables) DeleteChainReferencesReturns(result1 error) {
 	}{result1}
 }
 
-func (fake *FakeIPTables) PrependRule(chain string, rule iptables.Rule) error {
+func (fake *FakeIPTables) PrependRule(logger lager.Logger, chain string, rule iptables.Rule) error {
 	fake.prependRuleMutex.Lock()
 	fake.prependRuleArgsForCall = append(fake.prependRuleArgsForCall, struct {
-		chain string
-		rule  iptables.Rule
-	}{chain, rule})
-	fake.recordInvocation("PrependRule", []interface{}{chain, rule})
+		logger lager.Logger
+		chain  string
+		rule   iptables.Rule
+	}{logger, chain, rule})
+	fake.recordInvocation("PrependRule", []interface{}{logger, chain, rule})
 	fake.prependRuleMutex.Unlock()
 	if fake.PrependRuleStub != nil {
-		return fake.PrependRuleStub(chain, rule)
+		return fake.PrependRuleStub(logger, chain, rule)
 	} else {
 		return fake.prependRuleReturns.result1
 	}
@@ -233,10 +244,10 @@ func (fake *FakeIPTables) PrependRuleCallCount() int {
 	return len(fake.prependRuleArgsForCall)
 }
 
-func (fake *FakeIPTables) PrependRuleArgsForCall(i int) (string, iptables.Rule) {
+func (fake *FakeIPTables) PrependRuleArgsForCall(i int) (lager.Logger, string, iptables.Rule) {
 	fake.prependRuleMutex.RLock()
 	defer fake.prependRuleMutex.RUnlock()
-	return fake.prependRuleArgsForCall[i].chain, fake.prependRuleArgsForCall[i].rule
+	return fake.prependRuleArgsForCall[i].logger, fake.prependRuleArgsForCall[i].chain, fake.prependRuleArgsForCall[i].rule
 }
 
 func (fake *FakeIPTables) PrependRuleReturns(result1 error) {
@@ -246,7 +257,7 @@ func (fake *FakeIPTables) PrependRuleReturns(result1 error) {
 	}{result1}
 }
 
-func (fake *FakeIPTables) BulkPrependRules(chain string, rules []iptables.Rule) error {
+func (fake *FakeIPTables) BulkPrependRules(logger lager.Logger, chain string, rules []iptables.Rule) error {
 	var rulesCopy []iptables.Rule
 	if rules != nil {
 		rulesCopy = make([]iptables.Rule, len(rules))
@@ -254,13 +265,14 @@ func (fake *FakeIPTables) BulkPrependRules(chain string, rules []iptables.Rule)
 	}
 	fake.bulkPrependRulesMutex.Lock()
 	fake.bulkPrependRulesArgsForCall = append(fake.bulkPrependRulesArgsForCall, struct {
-		chain string
-		rules []iptables.Rule
-	}{chain, rulesCopy})
-	fake.recordInvocation("BulkPrependRules", []interface{}{chain, rulesCopy})
+		logger lager.Logger
+		chain  string
+		rules  []iptables.Rule
+	}{logger, chain, rulesCopy})
+	fake.recordInvocation("BulkPrependRules", []interface{}{logger, chain, rulesCopy})
 	fake.bulkPrependRulesMutex.Unlock()
 	if fake.BulkPrependRulesStub != nil {
-		return fake.BulkPrependRulesStub(chain, rules)
+		return fake.BulkPrependRulesStub(logger, chain, rules)
 	} else {
 		return fake.bulkPrependRulesReturns.result1
 	}
@@ -272,10 +284,10 @@ func (fake *FakeIPTables) BulkPrependRulesCallCount() int {
 	return len(fake.bulkPrependRulesArgsForCall)
 }
 
-func (fake *FakeIPTables) BulkPrependRulesArgsForCall(i int) (string, []iptables.Rule) {
+func (fake *FakeIPTables) BulkPrependRulesArgsForCall(i int) (lager.Logger, string, []iptables.Rule) {
 	fake.bulkPrependRulesMutex.RLock()
 	defer fake.bulkPrependRulesMutex.RUnlock()
-	return fake.bulkPrependRulesArgsForCall[i].chain, fake.bulkPrependRulesArgsForCall[i].rules
+	return fake.bulkPrependRulesArgsForCall[i].logger, fake.bulkPrependRulesArgsForCall[i].chain, fake.bulkPrependRulesArgsForCall[i].rules
 }
 
 func (fake *FakeIPTables) BulkPrependRulesReturns(result1 error) {
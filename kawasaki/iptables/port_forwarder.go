@@ -1,6 +1,9 @@
 package iptables
 
-import "code.cloudfoundry.org/guardian/kawasaki"
+import (
+	"code.cloudfoundry.org/guardian/kawasaki"
+	"code.cloudfoundry.org/lager"
+)
 
 type PortForwarder struct {
 	iptables *IPTablesController
@@ -12,14 +15,14 @@ func NewPortForwarder(iptables *IPTablesController) *PortForwarder {
 	}
 }
 
-func (p *PortForwarder) Forward(spec kawasaki.PortForwarderSpec) error {
-	return p.iptables.appendRule(
-		p.iptables.InstanceChain(spec.InstanceID),
-		natRule(
-			spec.ExternalIP.String(),
-			spec.FromPort,
-			spec.ContainerIP.String(),
-			spec.ToPort,
-		),
+func (p *PortForwarder) Forward(logger lager.Logger, spec kawasaki.PortForwarderSpec) error {
+	chain := p.iptables.InstanceChain(spec.InstanceID)
+	rule := natRule(
+		spec.ExternalIP.String(),
+		spec.FromPort,
+		spec.ContainerIP.String(),
+		spec.ToPort,
 	)
+
+	return p.iptables.runTraced(logger, "append", p.iptables.appendRuleCommand(chain, rule))
 }
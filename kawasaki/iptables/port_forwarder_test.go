@@ -5,6 +5,9 @@ import (
 
 	"code.cloudfoundry.org/guardian/kawasaki"
 	"code.cloudfoundry.org/guardian/kawasaki/iptables"
+	"code.cloudfoundry.org/guardian/tracing"
+	"code.cloudfoundry.org/lager"
+	"code.cloudfoundry.org/lager/lagertest"
 	"github.com/cloudfoundry/gunk/command_runner/fake_command_runner"
 	. "github.com/cloudfoundry/gunk/command_runner/fake_command_runner/matchers"
 
@@ -16,6 +19,8 @@ var _ = Describe("PortForwarder", func() {
 	var (
 		fakeRunner *fake_command_runner.FakeCommandRunner
 		forwarder  *iptables.PortForwarder
+		logger     lager.Logger
+		spec       kawasaki.PortForwarderSpec
 	)
 
 	BeforeEach(func() {
@@ -24,16 +29,19 @@ var _ = Describe("PortForwarder", func() {
 		forwarder = iptables.NewPortForwarder(
 			iptables.New("/sbin/iptables", fakeRunner, fakeLocksmith, "prefix-"),
 		)
-	})
+		logger = lagertest.NewTestLogger("test")
 
-	It("adds a NAT rule to forward the port", func() {
-		Expect(forwarder.Forward(kawasaki.PortForwarderSpec{
+		spec = kawasaki.PortForwarderSpec{
 			InstanceID:  "some-instance",
 			ExternalIP:  net.ParseIP("5.6.7.8"),
 			ContainerIP: net.ParseIP("1.2.3.4"),
 			FromPort:    22,
 			ToPort:      33,
-		})).To(Succeed())
+		}
+	})
+
+	It("adds a NAT rule to forward the port", func() {
+		Expect(forwarder.Forward(logger, spec)).To(Succeed())
 
 		Expect(fakeRunner).To(HaveExecutedSerially(
 			fake_command_runner.CommandSpec{
@@ -51,4 +59,12 @@ var _ = Describe("PortForwarder", func() {
 			},
 		))
 	})
+
+	It("passes the trace id of the logger to iptables", func() {
+		tracedLogger, _ := new(tracing.Tracer).Start(logger, "net-in")
+		Expect(forwarder.Forward(tracedLogger, spec)).To(Succeed())
+
+		cmd := fakeRunner.ExecutedCommands()[0]
+		Expect(tracing.FromEnv(cmd.Env)).To(Equal(tracing.TraceID(tracedLogger)))
+	})
 })
@@ -0,0 +1,128 @@
+// This file was generated by counterfeiter
+package kawasakifakes
+
+import (
+	"code.cloudfoundry.org/garden"
+	"code.cloudfoundry.org/guardian/kawasaki"
+	"code.cloudfoundry.org/lager"
+	"sync"
+)
+
+type FakeBandwidthLimiter struct {
+	ApplyStub        func(log lager.Logger, intf string, limits garden.BandwidthLimits) error
+	applyMutex       sync.RWMutex
+	applyArgsForCall []struct {
+		log    lager.Logger
+		intf   string
+		limits garden.BandwidthLimits
+	}
+	applyReturns struct {
+		result1 error
+	}
+	LimitsStub        func(log lager.Logger, intf string) (garden.BandwidthLimits, error)
+	limitsMutex       sync.RWMutex
+	limitsArgsForCall []struct {
+		log  lager.Logger
+		intf string
+	}
+	limitsReturns struct {
+		result1 garden.BandwidthLimits
+		result2 error
+	}
+	invocations      map[string][][]interface{}
+	invocationsMutex sync.RWMutex
+}
+
+func (fake *FakeBandwidthLimiter) Apply(log lager.Logger, intf string, limits garden.BandwidthLimits) error {
+	fake.applyMutex.Lock()
+	fake.applyArgsForCall = append(fake.applyArgsForCall, struct {
+		log    lager.Logger
+		intf   string
+		limits garden.BandwidthLimits
+	}{log, intf, limits})
+	fake.recordInvocation("Apply", []interface{}{log, intf, limits})
+	fake.applyMutex.Unlock()
+	if fake.ApplyStub != nil {
+		return fake.ApplyStub(log, intf, limits)
+	} else {
+		return fake.applyReturns.result1
+	}
+}
+
+func (fake *FakeBandwidthLimiter) ApplyCallCount() int {
+	fake.applyMutex.RLock()
+	defer fake.applyMutex.RUnlock()
+	return len(fake.applyArgsForCall)
+}
+
+func (fake *FakeBandwidthLimiter) ApplyArgsForCall(i int) (lager.Logger, string, garden.BandwidthLimits) {
+	fake.applyMutex.RLock()
+	defer fake.applyMutex.RUnlock()
+	return fake.applyArgsForCall[i].log, fake.applyArgsForCall[i].intf, fake.applyArgsForCall[i].limits
+}
+
+func (fake *FakeBandwidthLimiter) ApplyReturns(result1 error) {
+	fake.ApplyStub = nil
+	fake.applyReturns = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *FakeBandwidthLimiter) Limits(log lager.Logger, intf string) (garden.BandwidthLimits, error) {
+	fake.limitsMutex.Lock()
+	fake.limitsArgsForCall = append(fake.limitsArgsForCall, struct {
+		log  lager.Logger
+		intf string
+	}{log, intf})
+	fake.recordInvocation("Limits", []interface{}{log, intf})
+	fake.limitsMutex.Unlock()
+	if fake.LimitsStub != nil {
+		return fake.LimitsStub(log, intf)
+	} else {
+		return fake.limitsReturns.result1, fake.limitsReturns.result2
+	}
+}
+
+func (fake *FakeBandwidthLimiter) LimitsCallCount() int {
+	fake.limitsMutex.RLock()
+	defer fake.limitsMutex.RUnlock()
+	return len(fake.limitsArgsForCall)
+}
+
+func (fake *FakeBandwidthLimiter) LimitsArgsForCall(i int) (lager.Logger, string) {
+	fake.limitsMutex.RLock()
+	defer fake.limitsMutex.RUnlock()
+	return fake.limitsArgsForCall[i].log, fake.limitsArgsForCall[i].intf
+}
+
+func (fake *FakeBandwidthLimiter) LimitsReturns(result1 garden.BandwidthLimits, result2 error) {
+	fake.LimitsStub = nil
+	fake.limitsReturns = struct {
+		result1 garden.BandwidthLimits
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeBandwidthLimiter) Invocations() map[string][][]interface{} {
+	fake.invocationsMutex.RLock()
+	defer fake.invocationsMutex.RUnlock()
+	fake.applyMutex.RLock()
+	defer fake.applyMutex.RUnlock()
+	fake.limitsMutex.RLock()
+	defer fake.limitsMutex.RUnlock()
+	return fake.invocations
+}
+
+func (fake *FakeBandwidthLimiter) recordInvocation(key string, args []interface{}) {
+	fake.invocationsMutex.Lock()
+	defer fake.invocationsMutex.Unlock()
+	if fake.invocations == nil {
+		fake.invocations = map[string][][]interface{}{}
+	}
+	if fake.invocations[key] == nil {
+		fake.invocations[key] = [][]interface{}{}
+	}
+	fake.invocations[key] = append(fake.invocations[key], args)
+}
+
+var _ kawasaki.BandwidthLimiter = new(FakeBandwidthLimiter)
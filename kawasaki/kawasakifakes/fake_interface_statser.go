@@ -0,0 +1,78 @@
+// This file was generated by counterfeiter
+package kawasakifakes
+
+import (
+	"code.cloudfoundry.org/guardian/kawasaki"
+	"code.cloudfoundry.org/guardian/kawasaki/devices"
+	"sync"
+)
+
+type FakeInterfaceStatser struct {
+	StatisticsStub        func(intf string) (devices.Statistics, error)
+	statisticsMutex       sync.RWMutex
+	statisticsArgsForCall []struct {
+		intf string
+	}
+	statisticsReturns struct {
+		result1 devices.Statistics
+		result2 error
+	}
+	invocations      map[string][][]interface{}
+	invocationsMutex sync.RWMutex
+}
+
+func (fake *FakeInterfaceStatser) Statistics(intf string) (devices.Statistics, error) {
+	fake.statisticsMutex.Lock()
+	fake.statisticsArgsForCall = append(fake.statisticsArgsForCall, struct {
+		intf string
+	}{intf})
+	fake.recordInvocation("Statistics", []interface{}{intf})
+	fake.statisticsMutex.Unlock()
+	if fake.StatisticsStub != nil {
+		return fake.StatisticsStub(intf)
+	} else {
+		return fake.statisticsReturns.result1, fake.statisticsReturns.result2
+	}
+}
+
+func (fake *FakeInterfaceStatser) StatisticsCallCount() int {
+	fake.statisticsMutex.RLock()
+	defer fake.statisticsMutex.RUnlock()
+	return len(fake.statisticsArgsForCall)
+}
+
+func (fake *FakeInterfaceStatser) StatisticsArgsForCall(i int) string {
+	fake.statisticsMutex.RLock()
+	defer fake.statisticsMutex.RUnlock()
+	return fake.statisticsArgsForCall[i].intf
+}
+
+func (fake *FakeInterfaceStatser) StatisticsReturns(result1 devices.Statistics, result2 error) {
+	fake.StatisticsStub = nil
+	fake.statisticsReturns = struct {
+		result1 devices.Statistics
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeInterfaceStatser) Invocations() map[string][][]interface{} {
+	fake.invocationsMutex.RLock()
+	defer fake.invocationsMutex.RUnlock()
+	fake.statisticsMutex.RLock()
+	defer fake.statisticsMutex.RUnlock()
+	return fake.invocations
+}
+
+func (fake *FakeInterfaceStatser) recordInvocation(key string, args []interface{}) {
+	fake.invocationsMutex.Lock()
+	defer fake.invocationsMutex.Unlock()
+	if fake.invocations == nil {
+		fake.invocations = map[string][][]interface{}{}
+	}
+	if fake.invocations[key] == nil {
+		fake.invocations[key] = [][]interface{}{}
+	}
+	fake.invocations[key] = append(fake.invocations[key], args)
+}
+
+var _ kawasaki.InterfaceStatser = new(FakeInterfaceStatser)
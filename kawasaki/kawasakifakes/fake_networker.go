@@ -5,6 +5,7 @@ import (
 	"sync"
 
 	"code.cloudfoundry.org/garden"
+	"code.cloudfoundry.org/guardian/gardener"
 	"code.cloudfoundry.org/guardian/kawasaki"
 	"code.cloudfoundry.org/lager"
 )
@@ -77,6 +78,36 @@ type FakeNetworker struct {
 	restoreReturns struct {
 		result1 error
 	}
+	LimitBandwidthStub        func(log lager.Logger, handle string, limits garden.BandwidthLimits) error
+	limitBandwidthMutex       sync.RWMutex
+	limitBandwidthArgsForCall []struct {
+		log    lager.Logger
+		handle string
+		limits garden.BandwidthLimits
+	}
+	limitBandwidthReturns struct {
+		result1 error
+	}
+	BandwidthLimitsStub        func(log lager.Logger, handle string) (garden.BandwidthLimits, error)
+	bandwidthLimitsMutex       sync.RWMutex
+	bandwidthLimitsArgsForCall []struct {
+		log    lager.Logger
+		handle string
+	}
+	bandwidthLimitsReturns struct {
+		result1 garden.BandwidthLimits
+		result2 error
+	}
+	StatsStub        func(log lager.Logger, handle string) (gardener.ContainerNetworkStat, error)
+	statsMutex       sync.RWMutex
+	statsArgsForCall []struct {
+		log    lager.Logger
+		handle string
+	}
+	statsReturns struct {
+		result1 gardener.ContainerNetworkStat
+		result2 error
+	}
 	invocations      map[string][][]interface{}
 	invocationsMutex sync.RWMutex
 }
@@ -322,6 +353,111 @@ func (fake *FakeNetworker) RestoreReturns(result1 error) {
 	}{result1}
 }
 
+func (fake *FakeNetworker) LimitBandwidth(log lager.Logger, handle string, limits garden.BandwidthLimits) error {
+	fake.limitBandwidthMutex.Lock()
+	fake.limitBandwidthArgsForCall = append(fake.limitBandwidthArgsForCall, struct {
+		log    lager.Logger
+		handle string
+		limits garden.BandwidthLimits
+	}{log, handle, limits})
+	fake.recordInvocation("LimitBandwidth", []interface{}{log, handle, limits})
+	fake.limitBandwidthMutex.Unlock()
+	if fake.LimitBandwidthStub != nil {
+		return fake.LimitBandwidthStub(log, handle, limits)
+	} else {
+		return fake.limitBandwidthReturns.result1
+	}
+}
+
+func (fake *FakeNetworker) LimitBandwidthCallCount() int {
+	fake.limitBandwidthMutex.RLock()
+	defer fake.limitBandwidthMutex.RUnlock()
+	return len(fake.limitBandwidthArgsForCall)
+}
+
+func (fake *FakeNetworker) LimitBandwidthArgsForCall(i int) (lager.Logger, string, garden.BandwidthLimits) {
+	fake.limitBandwidthMutex.RLock()
+	defer fake.limitBandwidthMutex.RUnlock()
+	return fake.limitBandwidthArgsForCall[i].log, fake.limitBandwidthArgsForCall[i].handle, fake.limitBandwidthArgsForCall[i].limits
+}
+
+func (fake *FakeNetworker) LimitBandwidthReturns(result1 error) {
+	fake.LimitBandwidthStub = nil
+	fake.limitBandwidthReturns = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *FakeNetworker) BandwidthLimits(log lager.Logger, handle string) (garden.BandwidthLimits, error) {
+	fake.bandwidthLimitsMutex.Lock()
+	fake.bandwidthLimitsArgsForCall = append(fake.bandwidthLimitsArgsForCall, struct {
+		log    lager.Logger
+		handle string
+	}{log, handle})
+	fake.recordInvocation("BandwidthLimits", []interface{}{log, handle})
+	fake.bandwidthLimitsMutex.Unlock()
+	if fake.BandwidthLimitsStub != nil {
+		return fake.BandwidthLimitsStub(log, handle)
+	} else {
+		return fake.bandwidthLimitsReturns.result1, fake.bandwidthLimitsReturns.result2
+	}
+}
+
+func (fake *FakeNetworker) BandwidthLimitsCallCount() int {
+	fake.bandwidthLimitsMutex.RLock()
+	defer fake.bandwidthLimitsMutex.RUnlock()
+	return len(fake.bandwidthLimitsArgsForCall)
+}
+
+func (fake *FakeNetworker) BandwidthLimitsArgsForCall(i int) (lager.Logger, string) {
+	fake.bandwidthLimitsMutex.RLock()
+	defer fake.bandwidthLimitsMutex.RUnlock()
+	return fake.bandwidthLimitsArgsForCall[i].log, fake.bandwidthLimitsArgsForCall[i].handle
+}
+
+func (fake *FakeNetworker) BandwidthLimitsReturns(result1 garden.BandwidthLimits, result2 error) {
+	fake.BandwidthLimitsStub = nil
+	fake.bandwidthLimitsReturns = struct {
+		result1 garden.BandwidthLimits
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeNetworker) Stats(log lager.Logger, handle string) (gardener.ContainerNetworkStat, error) {
+	fake.statsMutex.Lock()
+	fake.statsArgsForCall = append(fake.statsArgsForCall, struct {
+		log    lager.Logger
+		handle string
+	}{log, handle})
+	fake.recordInvocation("Stats", []interface{}{log, handle})
+	fake.statsMutex.Unlock()
+	if fake.StatsStub != nil {
+		return fake.StatsStub(log, handle)
+	} else {
+		return fake.statsReturns.result1, fake.statsReturns.result2
+	}
+}
+
+func (fake *FakeNetworker) StatsCallCount() int {
+	fake.statsMutex.RLock()
+	defer fake.statsMutex.RUnlock()
+	return len(fake.statsArgsForCall)
+}
+
+func (fake *FakeNetworker) StatsArgsForCall(i int) (lager.Logger, string) {
+	fake.statsMutex.RLock()
+	defer fake.statsMutex.RUnlock()
+	return fake.statsArgsForCall[i].log, fake.statsArgsForCall[i].handle
+}
+
+func (fake *FakeNetworker) StatsReturns(result1 gardener.ContainerNetworkStat, result2 error) {
+	fake.StatsStub = nil
+	fake.statsReturns = struct {
+		result1 gardener.ContainerNetworkStat
+		result2 error
+	}{result1, result2}
+}
+
 func (fake *FakeNetworker) Invocations() map[string][][]interface{} {
 	fake.invocationsMutex.RLock()
 	defer fake.invocationsMutex.RUnlock()
@@ -339,6 +475,12 @@ func (fake *FakeNetworker) Invocations() map[string][][]interface{} {
 	defer fake.bulkNetOutMutex.RUnlock()
 	fake.restoreMutex.RLock()
 	defer fake.restoreMutex.RUnlock()
+	fake.limitBandwidthMutex.RLock()
+	defer fake.limitBandwidthMutex.RUnlock()
+	fake.bandwidthLimitsMutex.RLock()
+	defer fake.bandwidthLimitsMutex.RUnlock()
+	fake.statsMutex.RLock()
+	defer fake.statsMutex.RUnlock()
 	return fake.invocations
 }
 
@@ -2,15 +2,16 @@
 package kawasakifakes
 
 import (
-	"sync"
-
 	"code.cloudfoundry.org/guardian/kawasaki"
+	"code.cloudfoundry.org/lager"
+	"sync"
 )
 
 type FakePortForwarder struct {
-	ForwardStub        func(spec kawasaki.PortForwarderSpec) error
+	ForwardStub        func(log lager.Logger, spec kawasaki.PortForwarderSpec) error
 	forwardMutex       sync.RWMutex
 	forwardArgsForCall []struct {
+		log  lager.Logger
 		spec kawasaki.PortForwarderSpec
 	}
 	forwardReturns struct {
@@ -20,15 +21,16 @@ type FakePortForwarder struct {
 	invocationsMutex sync.RWMutex
 }
 
-func (fake *FakePortForwarder) Forward(spec kawasaki.PortForwarderSpec) error {
+func (fake *FakePortForwarder) Forward(log lager.Logger, spec kawasaki.PortForwarderSpec) error {
 	fake.forwardMutex.Lock()
 	fake.forwardArgsForCall = append(fake.forwardArgsForCall, struct {
+		log  lager.Logger
 		spec kawasaki.PortForwarderSpec
-	}{spec})
-	fake.recordInvocation("Forward", []interface{}{spec})
+	}{log, spec})
+	fake.recordInvocation("Forward", []interface{}{log, spec})
 	fake.forwardMutex.Unlock()
 	if fake.ForwardStub != nil {
-		return fake.ForwardStub(spec)
+		return fake.ForwardStub(log, spec)
 	} else {
 		return fake.forwardReturns.result1
 	}
@@ -40,10 +42,10 @@ func (fake *FakePortForwarder) ForwardCallCount() int {
 	return len(fake.forwardArgsForCall)
 }
 
-func (fake *FakePortForwarder) ForwardArgsForCall(i int) kawasaki.PortForwarderSpec {
+func (fake *FakePortForwarder) ForwardArgsForCall(i int) (lager.Logger, kawasaki.PortForwarderSpec) {
 	fake.forwardMutex.RLock()
 	defer fake.forwardMutex.RUnlock()
-	return fake.forwardArgsForCall[i].spec
+	return fake.forwardArgsForCall[i].log, fake.forwardArgsForCall[i].spec
 }
 
 func (fake *FakePortForwarder) ForwardReturns(result1 error) {
@@ -9,7 +9,9 @@ import (
 
 	"code.cloudfoundry.org/garden"
 	"code.cloudfoundry.org/guardian/gardener"
+	"code.cloudfoundry.org/guardian/kawasaki/devices"
 	"code.cloudfoundry.org/guardian/kawasaki/subnets"
+	"code.cloudfoundry.org/guardian/tracing"
 	"code.cloudfoundry.org/lager"
 )
 
@@ -27,6 +29,8 @@ const iptablePrefixKey = "kawasaki.iptable-prefix"
 const iptableInstanceKey = "kawasaki.iptable-inst"
 const mtuKey = "kawasaki.mtu"
 const dnsServerKey = "kawasaki.dns-servers"
+const bandwidthRateKey = "kawasaki.bandwidth-rate"
+const bandwidthBurstKey = "kawasaki.bandwidth-burst"
 
 //go:generate counterfeiter . SpecParser
 
@@ -66,7 +70,7 @@ type PortPool interface {
 //go:generate counterfeiter . PortForwarder
 
 type PortForwarder interface {
-	Forward(spec PortForwarderSpec) error
+	Forward(log lager.Logger, spec PortForwarderSpec) error
 }
 
 type PortForwarderSpec struct {
@@ -84,6 +88,19 @@ type FirewallOpener interface {
 	BulkOpen(log lager.Logger, instance string, rule []garden.NetOutRule) error
 }
 
+//go:generate counterfeiter . BandwidthLimiter
+
+type BandwidthLimiter interface {
+	Apply(log lager.Logger, intf string, limits garden.BandwidthLimits) error
+	Limits(log lager.Logger, intf string) (garden.BandwidthLimits, error)
+}
+
+//go:generate counterfeiter . InterfaceStatser
+
+type InterfaceStatser interface {
+	Statistics(intf string) (devices.Statistics, error)
+}
+
 //go:generate counterfeiter . Networker
 
 type Networker interface {
@@ -93,6 +110,9 @@ type Networker interface {
 	NetIn(log lager.Logger, handle string, externalPort, containerPort uint32) (uint32, uint32, error)
 	NetOut(log lager.Logger, handle string, rule garden.NetOutRule) error
 	BulkNetOut(log lager.Logger, handle string, rules []garden.NetOutRule) error
+	LimitBandwidth(log lager.Logger, handle string, limits garden.BandwidthLimits) error
+	BandwidthLimits(log lager.Logger, handle string) (garden.BandwidthLimits, error)
+	Stats(log lager.Logger, handle string) (gardener.ContainerNetworkStat, error)
 	Restore(log lager.Logger, handle string) error
 }
 
@@ -107,6 +127,9 @@ type networker struct {
 	portPool       PortPool
 	firewallOpener FirewallOpener
 	configurer     Configurer
+
+	bandwidthLimiter BandwidthLimiter
+	interfaceStatser InterfaceStatser
 }
 
 func New(
@@ -119,6 +142,8 @@ func New(
 	portPool PortPool,
 	portForwarder PortForwarder,
 	firewallOpener FirewallOpener,
+	bandwidthLimiter BandwidthLimiter,
+	interfaceStatser InterfaceStatser,
 ) *networker {
 	return &networker{
 		iptablesBin: iptablesBin,
@@ -133,14 +158,18 @@ func New(
 		portPool:      portPool,
 
 		firewallOpener: firewallOpener,
+
+		bandwidthLimiter: bandwidthLimiter,
+		interfaceStatser: interfaceStatser,
 	}
 }
 
-func (n *networker) Network(log lager.Logger, containerSpec garden.ContainerSpec, pid int) error {
-	log = log.Session("network", lager.Data{
+func (n *networker) Network(log lager.Logger, containerSpec garden.ContainerSpec, pid int) (err error) {
+	log, span := tracing.StartSpan(log.Session("network", lager.Data{
 		"handle": containerSpec.Handle,
 		"spec":   containerSpec.Network,
-	})
+	}), "network")
+	defer func() { span.End(err) }()
 
 	log.Info("started")
 	defer log.Info("finished")
@@ -196,7 +225,7 @@ func (n *networker) NetIn(log lager.Logger, handle string, externalPort, contain
 		containerPort = externalPort
 	}
 
-	err = n.portForwarder.Forward(PortForwarderSpec{
+	err = n.portForwarder.Forward(log, PortForwarderSpec{
 		InstanceID:  cfg.IPTableInstance,
 		FromPort:    externalPort,
 		ToPort:      containerPort,
@@ -236,6 +265,61 @@ func (n *networker) BulkNetOut(log lager.Logger, handle string, rules []garden.N
 	return n.firewallOpener.BulkOpen(log, cfg.IPTableInstance, rules)
 }
 
+// LimitBandwidth shapes the traffic of the container's host interface and
+// stores the limits so that they can be re-applied on restore
+func (n *networker) LimitBandwidth(log lager.Logger, handle string, limits garden.BandwidthLimits) error {
+	log = log.Session("limit-bandwidth", lager.Data{"handle": handle, "limits": limits})
+
+	cfg, err := load(n.configStore, handle)
+	if err != nil {
+		log.Error("load-config-failed", err)
+		return err
+	}
+
+	if err := n.bandwidthLimiter.Apply(log, cfg.HostIntf, limits); err != nil {
+		return err
+	}
+
+	n.configStore.Set(handle, bandwidthRateKey, strconv.FormatUint(limits.RateInBytesPerSecond, 10))
+	n.configStore.Set(handle, bandwidthBurstKey, strconv.FormatUint(limits.BurstRateInBytesPerSecond, 10))
+
+	return nil
+}
+
+// BandwidthLimits returns the limits currently enforced on the container's
+// host interface
+func (n *networker) BandwidthLimits(log lager.Logger, handle string) (garden.BandwidthLimits, error) {
+	cfg, err := load(n.configStore, handle)
+	if err != nil {
+		return garden.BandwidthLimits{}, err
+	}
+
+	return n.bandwidthLimiter.Limits(log, cfg.HostIntf)
+}
+
+// Stats returns the traffic of the container. It is counted on the host
+// side of the veth pair, so what the host interface receives is what the
+// container sent.
+func (n *networker) Stats(log lager.Logger, handle string) (gardener.ContainerNetworkStat, error) {
+	cfg, err := load(n.configStore, handle)
+	if err != nil {
+		return gardener.ContainerNetworkStat{}, err
+	}
+
+	stats, err := n.interfaceStatser.Statistics(cfg.HostIntf)
+	if err != nil {
+		log.Error("interface-statistics-failed", err, lager.Data{"handle": handle, "interface": cfg.HostIntf})
+		return gardener.ContainerNetworkStat{}, fmt.Errorf("reading statistics of %s: %s", cfg.HostIntf, err)
+	}
+
+	return gardener.ContainerNetworkStat{
+		RxBytes:   stats.TxBytes,
+		TxBytes:   stats.RxBytes,
+		RxPackets: stats.TxPackets,
+		TxPackets: stats.RxPackets,
+	}, nil
+}
+
 func (n *networker) Destroy(log lager.Logger, handle string) error {
 	cfg, err := load(n.configStore, handle)
 	if err != nil {
@@ -281,6 +365,14 @@ func (n *networker) Restore(log lager.Logger, handle string) error {
 		return fmt.Errorf("subnet pool removing %s: %v", handle, err)
 	}
 
+	if limits, ok, err := loadBandwidthLimits(n.configStore, handle); err != nil {
+		return fmt.Errorf("loading bandwidth limits %s: %v", handle, err)
+	} else if ok {
+		if err := n.bandwidthLimiter.Apply(log, networkConfig.HostIntf, limits); err != nil {
+			return fmt.Errorf("applying bandwidth limits %s: %v", handle, err)
+		}
+	}
+
 	currentMappingsJson, ok := n.configStore.Get(handle, gardener.MappedPortsKey)
 	if !ok {
 		return nil
@@ -402,6 +494,33 @@ func load(config ConfigStore, handle string) (NetworkConfig, error) {
 	}, nil
 }
 
+func loadBandwidthLimits(config ConfigStore, handle string) (garden.BandwidthLimits, bool, error) {
+	rate, ok := config.Get(handle, bandwidthRateKey)
+	if !ok {
+		return garden.BandwidthLimits{}, false, nil
+	}
+
+	burst, ok := config.Get(handle, bandwidthBurstKey)
+	if !ok {
+		return garden.BandwidthLimits{}, false, fmt.Errorf("property not found: %s", bandwidthBurstKey)
+	}
+
+	parsedRate, err := strconv.ParseUint(rate, 10, 64)
+	if err != nil {
+		return garden.BandwidthLimits{}, false, err
+	}
+
+	parsedBurst, err := strconv.ParseUint(burst, 10, 64)
+	if err != nil {
+		return garden.BandwidthLimits{}, false, err
+	}
+
+	return garden.BandwidthLimits{
+		RateInBytesPerSecond:      parsedRate,
+		BurstRateInBytesPerSecond: parsedBurst,
+	}, true, nil
+}
+
 type portMappingList []garden.PortMapping
 
 func (l portMappingList) toJson() string {
@@ -10,6 +10,7 @@ import (
 	"code.cloudfoundry.org/garden"
 	"code.cloudfoundry.org/guardian/gardener"
 	"code.cloudfoundry.org/guardian/kawasaki"
+	"code.cloudfoundry.org/guardian/kawasaki/devices"
 	fakes "code.cloudfoundry.org/guardian/kawasaki/kawasakifakes"
 	"code.cloudfoundry.org/guardian/kawasaki/subnets"
 	"code.cloudfoundry.org/guardian/kawasaki/subnets/fake_subnet_pool"
@@ -29,6 +30,8 @@ var _ = Describe("Networker", func() {
 		fakePortPool       *fakes.FakePortPool
 		fakeFirewallOpener *fakes.FakeFirewallOpener
 		fakeConfigurer     *fakes.FakeConfigurer
+		fakeBandwidth      *fakes.FakeBandwidthLimiter
+		fakeStatser        *fakes.FakeInterfaceStatser
 		containerSpec      garden.ContainerSpec
 		networker          kawasaki.Networker
 		logger             lager.Logger
@@ -45,6 +48,8 @@ var _ = Describe("Networker", func() {
 		fakePortPool = new(fakes.FakePortPool)
 		fakeFirewallOpener = new(fakes.FakeFirewallOpener)
 		fakeConfigurer = new(fakes.FakeConfigurer)
+		fakeBandwidth = new(fakes.FakeBandwidthLimiter)
+		fakeStatser = new(fakes.FakeInterfaceStatser)
 
 		containerSpec = garden.ContainerSpec{
 			Handle:  "some-handle",
@@ -62,6 +67,8 @@ var _ = Describe("Networker", func() {
 			fakePortPool,
 			fakePortForwarder,
 			fakeFirewallOpener,
+			fakeBandwidth,
+			fakeStatser,
 		)
 
 		ip, subnet, err := net.ParseCIDR("123.123.123.12/24")
@@ -377,7 +384,7 @@ var _ = Describe("Networker", func() {
 			Expect(err).NotTo(HaveOccurred())
 			Expect(fakePortForwarder.ForwardCallCount()).To(Equal(1))
 
-			actualSpec := fakePortForwarder.ForwardArgsForCall(0)
+			_, actualSpec := fakePortForwarder.ForwardArgsForCall(0)
 			Expect(actualSpec.InstanceID).To(Equal(networkConfig.IPTableInstance))
 			Expect(actualSpec.ContainerIP).To(Equal(networkConfig.ContainerIP))
 			Expect(actualSpec.ExternalIP).To(Equal(networkConfig.ExternalIP))
@@ -399,7 +406,7 @@ var _ = Describe("Networker", func() {
 
 				Expect(fakePortPool.AcquireCallCount()).To(Equal(1))
 				Expect(fakePortForwarder.ForwardCallCount()).To(Equal(1))
-				spec := fakePortForwarder.ForwardArgsForCall(0)
+				_, spec := fakePortForwarder.ForwardArgsForCall(0)
 
 				Expect(spec.FromPort).To(Equal(externalPort))
 				Expect(spec.ToPort).To(Equal(containerPort))
@@ -493,6 +500,116 @@ var _ = Describe("Networker", func() {
 		})
 	})
 
+	Describe("LimitBandwidth", func() {
+		var limits garden.BandwidthLimits
+
+		BeforeEach(func() {
+			limits = garden.BandwidthLimits{
+				RateInBytesPerSecond:      1000,
+				BurstRateInBytesPerSecond: 2000,
+			}
+		})
+
+		It("applies the limits to the host interface", func() {
+			Expect(networker.LimitBandwidth(logger, "some-handle", limits)).To(Succeed())
+
+			Expect(fakeBandwidth.ApplyCallCount()).To(Equal(1))
+			_, intf, appliedLimits := fakeBandwidth.ApplyArgsForCall(0)
+			Expect(intf).To(Equal("banana-iface"))
+			Expect(appliedLimits).To(Equal(limits))
+		})
+
+		It("stores the limits in the config store", func() {
+			Expect(networker.LimitBandwidth(logger, "some-handle", limits)).To(Succeed())
+
+			Expect(fakeConfigStore.SetCallCount()).To(Equal(2))
+			handle, name, value := fakeConfigStore.SetArgsForCall(0)
+			Expect(handle).To(Equal("some-handle"))
+			Expect(name).To(Equal("kawasaki.bandwidth-rate"))
+			Expect(value).To(Equal("1000"))
+
+			handle, name, value = fakeConfigStore.SetArgsForCall(1)
+			Expect(handle).To(Equal("some-handle"))
+			Expect(name).To(Equal("kawasaki.bandwidth-burst"))
+			Expect(value).To(Equal("2000"))
+		})
+
+		Context("when applying the limits fails", func() {
+			BeforeEach(func() {
+				fakeBandwidth.ApplyReturns(errors.New("tc-failed"))
+			})
+
+			It("returns the error", func() {
+				Expect(networker.LimitBandwidth(logger, "some-handle", limits)).To(MatchError("tc-failed"))
+			})
+
+			It("does not store the limits", func() {
+				networker.LimitBandwidth(logger, "some-handle", limits)
+				Expect(fakeConfigStore.SetCallCount()).To(Equal(0))
+			})
+		})
+
+		Context("when the config cannot be loaded", func() {
+			It("returns the error", func() {
+				delete(config, "kawasaki.host-interface")
+				Expect(networker.LimitBandwidth(logger, "some-handle", limits)).To(MatchError("property not found: kawasaki.host-interface"))
+				Expect(fakeBandwidth.ApplyCallCount()).To(Equal(0))
+			})
+		})
+	})
+
+	Describe("BandwidthLimits", func() {
+		It("returns the limits reported for the host interface", func() {
+			fakeBandwidth.LimitsReturns(garden.BandwidthLimits{RateInBytesPerSecond: 42}, nil)
+
+			Expect(networker.BandwidthLimits(logger, "some-handle")).To(Equal(garden.BandwidthLimits{RateInBytesPerSecond: 42}))
+			_, intf := fakeBandwidth.LimitsArgsForCall(0)
+			Expect(intf).To(Equal("banana-iface"))
+		})
+
+		Context("when reading the limits fails", func() {
+			It("returns the error", func() {
+				fakeBandwidth.LimitsReturns(garden.BandwidthLimits{}, errors.New("tc-failed"))
+
+				_, err := networker.BandwidthLimits(logger, "some-handle")
+				Expect(err).To(MatchError("tc-failed"))
+			})
+		})
+	})
+
+	Describe("Stats", func() {
+		It("returns the counters of the host interface from the container's point of view", func() {
+			fakeStatser.StatisticsReturns(devices.Statistics{RxBytes: 1, TxBytes: 2, RxPackets: 3, TxPackets: 4}, nil)
+
+			Expect(networker.Stats(logger, "some-handle")).To(Equal(gardener.ContainerNetworkStat{
+				RxBytes:   2,
+				TxBytes:   1,
+				RxPackets: 4,
+				TxPackets: 3,
+			}))
+			Expect(fakeStatser.StatisticsArgsForCall(0)).To(Equal("banana-iface"))
+		})
+
+		Context("when the container has no network config", func() {
+			It("returns an error", func() {
+				config = nil
+
+				_, err := networker.Stats(logger, "some-handle")
+				Expect(err).To(HaveOccurred())
+				Expect(fakeStatser.StatisticsCallCount()).To(Equal(0))
+			})
+		})
+
+		Context("when reading the counters fails", func() {
+			It("returns the error", func() {
+				fakeStatser.StatisticsReturns(devices.Statistics{}, errors.New("no-such-interface"))
+
+				_, err := networker.Stats(logger, "some-handle")
+				Expect(err).To(MatchError("reading statistics of banana-iface: no-such-interface"))
+			})
+		})
+	})
+
 	Describe("Restore", func() {
 		It("removes the subnet from the the subnet pool", func() {
 			Expect(networker.Restore(logger, "some-handle")).To(Succeed())
@@ -509,6 +626,39 @@ var _ = Describe("Networker", func() {
 			Expect(calledPort).To(BeEquivalentTo(60000))
 		})
 
+		Context("when bandwidth limits were stored", func() {
+			BeforeEach(func() {
+				config["kawasaki.bandwidth-rate"] = "1000"
+				config["kawasaki.bandwidth-burst"] = "2000"
+			})
+
+			It("applies them to the host interface again", func() {
+				Expect(networker.Restore(logger, "some-handle")).To(Succeed())
+
+				Expect(fakeBandwidth.ApplyCallCount()).To(Equal(1))
+				_, intf, limits := fakeBandwidth.ApplyArgsForCall(0)
+				Expect(intf).To(Equal("banana-iface"))
+				Expect(limits).To(Equal(garden.BandwidthLimits{
+					RateInBytesPerSecond:      1000,
+					BurstRateInBytesPerSecond: 2000,
+				}))
+			})
+
+			Context("when applying the limits fails", func() {
+				It("returns the error", func() {
+					fakeBandwidth.ApplyReturns(errors.New("tc-failed"))
+					Expect(networker.Restore(logger, "some-handle")).To(MatchError(ContainSubstring("tc-failed")))
+				})
+			})
+		})
+
+		Context("when no bandwidth limits were stored", func() {
+			It("does not apply any limits", func() {
+				Expect(networker.Restore(logger, "some-handle")).To(Succeed())
+				Expect(fakeBandwidth.ApplyCallCount()).To(Equal(0))
+			})
+		})
+
 		Context("when the config couldn't be loaded", func() {
 			It("returns an appropriate error", func() {
 				config = nil
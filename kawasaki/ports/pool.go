@@ -114,6 +114,19 @@ func (p *PortPool) Release(port uint32) {
 	p.pool = append(p.pool, port)
 }
 
+// Capacity returns the number of ports in the pool's range
+func (p *PortPool) Capacity() int {
+	return int(p.size)
+}
+
+// Available returns the number of ports which can still be acquired
+func (p *PortPool) Available() int {
+	p.poolMutex.Lock()
+	defer p.poolMutex.Unlock()
+
+	return len(p.pool)
+}
+
 func (p *PortPool) RefreshState() State {
 	if len(p.pool) == 0 {
 		p.state.Offset = 0
@@ -23,6 +23,24 @@ var _ = Describe("Port pool", func() {
 		})
 	})
 
+	Describe("usage", func() {
+		It("reports the size of the range and the ports which are left", func() {
+			pool, err := ports.NewPool(10000, 5, initialState)
+			Expect(err).ToNot(HaveOccurred())
+
+			Expect(pool.Capacity()).To(Equal(5))
+			Expect(pool.Available()).To(Equal(5))
+
+			port, err := pool.Acquire()
+			Expect(err).ToNot(HaveOccurred())
+			Expect(pool.Available()).To(Equal(4))
+
+			pool.Release(port)
+			Expect(pool.Available()).To(Equal(5))
+			Expect(pool.Capacity()).To(Equal(5))
+		})
+	})
+
 	Describe("acquiring", func() {
 		It("returns the next available port from the pool", func() {
 			pool, err := ports.NewPool(10000, 5, initialState)
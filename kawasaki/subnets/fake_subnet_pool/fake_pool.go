@@ -55,6 +55,12 @@ type FakePool struct {
 	runIfFreeReturns struct {
 		result1 error
 	}
+	AllocatedStub        func() int
+	allocatedMutex       sync.RWMutex
+	allocatedArgsForCall []struct{}
+	allocatedReturns     struct {
+		result1 int
+	}
 	invocations      map[string][][]interface{}
 	invocationsMutex sync.RWMutex
 }
@@ -223,6 +229,31 @@ func (fake *FakePool) RunIfFreeReturns(result1 error) {
 	}{result1}
 }
 
+func (fake *FakePool) Allocated() int {
+	fake.allocatedMutex.Lock()
+	fake.allocatedArgsForCall = append(fake.allocatedArgsForCall, struct{}{})
+	fake.recordInvocation("Allocated", []interface{}{})
+	fake.allocatedMutex.Unlock()
+	if fake.AllocatedStub != nil {
+		return fake.AllocatedStub()
+	} else {
+		return fake.allocatedReturns.result1
+	}
+}
+
+func (fake *FakePool) AllocatedCallCount() int {
+	fake.allocatedMutex.RLock()
+	defer fake.allocatedMutex.RUnlock()
+	return len(fake.allocatedArgsForCall)
+}
+
+func (fake *FakePool) AllocatedReturns(result1 int) {
+	fake.AllocatedStub = nil
+	fake.allocatedReturns = struct {
+		result1 int
+	}{result1}
+}
+
 func (fake *FakePool) Invocations() map[string][][]interface{} {
 	fake.invocationsMutex.RLock()
 	defer fake.invocationsMutex.RUnlock()
@@ -236,6 +267,8 @@ func (fake *FakePool) Invocations() map[string][][]interface{} {
 	defer fake.capacityMutex.RUnlock()
 	fake.runIfFreeMutex.RLock()
 	defer fake.runIfFreeMutex.RUnlock()
+	fake.allocatedMutex.RLock()
+	defer fake.allocatedMutex.RUnlock()
 	return fake.invocations
 }
 
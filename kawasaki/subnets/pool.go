@@ -29,6 +29,9 @@ type Pool interface {
 	// Returns the number of /30 subnets which can be Acquired by a DynamicSubnetSelector.
 	Capacity() int
 
+	// Returns the number of subnets which have at least one IP address allocated.
+	Allocated() int
+
 	// Run the provided callback if the given subnet is not in use
 	RunIfFree(*net.IPNet, func() error) error
 }
@@ -128,6 +131,15 @@ func (m *pool) Capacity() int {
 	return int(math.Pow(2, float64(total-masked)) / 4)
 }
 
+// Allocated returns the number of subnets, static or dynamic, which are in
+// use by at least one container.
+func (p *pool) Allocated() int {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	return len(p.allocated)
+}
+
 func (p *pool) RunIfFree(subnet *net.IPNet, cb func() error) error {
 	p.mu.Lock()
 	defer p.mu.Unlock()
@@ -63,6 +63,31 @@ var _ = Describe("Subnet Pool", func() {
 		})
 	})
 
+	Describe("Allocated", func() {
+		BeforeEach(func() {
+			defaultSubnetPool = subnetPool("10.2.3.0/27")
+		})
+
+		It("returns zero when nothing has been acquired", func() {
+			Expect(subnetpool.Allocated()).To(Equal(0))
+		})
+
+		It("counts each subnet in use once", func() {
+			static := subnetPool("11.0.0.0/29")
+
+			_, _, err := subnetpool.Acquire(logger, subnets.StaticSubnetSelector{IPNet: static}, subnets.DynamicIPSelector)
+			Expect(err).ToNot(HaveOccurred())
+
+			_, _, err = subnetpool.Acquire(logger, subnets.StaticSubnetSelector{IPNet: static}, subnets.DynamicIPSelector)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(subnetpool.Allocated()).To(Equal(1))
+
+			_, _, err = subnetpool.Acquire(logger, subnets.DynamicSubnetSelector, subnets.DynamicIPSelector)
+			Expect(err).ToNot(HaveOccurred())
+			Expect(subnetpool.Allocated()).To(Equal(2))
+		})
+	})
+
 	Describe("Allocating and Releasing", func() {
 		Describe("Static Subnet Allocation", func() {
 			Context("when the requested subnet is within the dynamic allocation range", func() {
@@ -0,0 +1,218 @@
+package tc
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"os/exec"
+	"strconv"
+	"strings"
+
+	"code.cloudfoundry.org/garden"
+	"code.cloudfoundry.org/lager"
+	"github.com/cloudfoundry/gunk/command_runner"
+)
+
+// BandwidthLimiter shapes the traffic of a host interface using 'tc'. Traffic
+// leaving the host interface (i.e. going in to the container) is shaped with a
+// token bucket filter and traffic arriving on it (i.e. leaving the container)
+// is policed on the ingress qdisc.
+type BandwidthLimiter struct {
+	binPath string
+	runner  command_runner.CommandRunner
+}
+
+func NewBandwidthLimiter(binPath string, runner command_runner.CommandRunner) *BandwidthLimiter {
+	return &BandwidthLimiter{
+		binPath: binPath,
+		runner:  runner,
+	}
+}
+
+func (b *BandwidthLimiter) Apply(log lager.Logger, intf string, limits garden.BandwidthLimits) error {
+	log = log.Session("apply-bandwidth-limits", lager.Data{"interface": intf, "limits": limits})
+
+	log.Info("started")
+	defer log.Info("finished")
+
+	if limits.RateInBytesPerSecond == 0 {
+		return errors.New("invalid bandwidth limits: rate must be greater than 0")
+	}
+
+	if limits.BurstRateInBytesPerSecond == 0 {
+		return errors.New("invalid bandwidth limits: burst rate must be greater than 0")
+	}
+
+	rate := fmt.Sprintf("%dbit", limits.RateInBytesPerSecond*8)
+	burst := strconv.FormatUint(limits.BurstRateInBytesPerSecond, 10)
+
+	if err := b.run("replace-egress-qdisc", exec.Command(
+		b.binPath, "qdisc", "replace", "dev", intf, "root", "tbf", "rate", rate, "burst", burst, "latency", "25ms",
+	)); err != nil {
+		log.Error("replace-egress-qdisc-failed", err)
+		return err
+	}
+
+	// the interface has no ingress qdisc the first time limits are applied,
+	// so failing to delete it is expected
+	if err := b.run("delete-ingress-qdisc", exec.Command(
+		b.binPath, "qdisc", "del", "dev", intf, "ingress",
+	)); err != nil {
+		log.Debug("delete-ingress-qdisc-failed", lager.Data{"error": err.Error()})
+	}
+
+	if err := b.run("add-ingress-qdisc", exec.Command(
+		b.binPath, "qdisc", "add", "dev", intf, "handle", "ffff:", "ingress",
+	)); err != nil {
+		log.Error("add-ingress-qdisc-failed", err)
+		return err
+	}
+
+	if err := b.run("add-ingress-filter", exec.Command(
+		b.binPath, "filter", "add", "dev", intf, "parent", "ffff:", "protocol", "all", "u32", "match", "u32", "0", "0",
+		"police", "rate", rate, "burst", burst, "drop", "flowid", ":1",
+	)); err != nil {
+		log.Error("add-ingress-filter-failed", err)
+		return err
+	}
+
+	return nil
+}
+
+// Limits returns the bandwidth limits currently applied to the interface, as
+// reported by the kernel. An interface without a token bucket filter has no
+// limits. An interface with one but without the ingress police filter only
+// has half of its limits applied, which is an error.
+func (b *BandwidthLimiter) Limits(log lager.Logger, intf string) (garden.BandwidthLimits, error) {
+	log = log.Session("get-bandwidth-limits", lager.Data{"interface": intf})
+
+	stdout := new(bytes.Buffer)
+	cmd := exec.Command(b.binPath, "qdisc", "show", "dev", intf)
+	cmd.Stdout = stdout
+	if err := b.run("show-qdisc", cmd); err != nil {
+		log.Error("show-qdisc-failed", err)
+		return garden.BandwidthLimits{}, err
+	}
+
+	var tbf, ingress []string
+	for _, line := range strings.Split(stdout.String(), "\n") {
+		fields := strings.Fields(line)
+		if len(fields) < 2 || fields[0] != "qdisc" {
+			continue
+		}
+
+		switch fields[1] {
+		case "tbf":
+			tbf = fields
+		case "ingress":
+			ingress = fields
+		}
+	}
+
+	if tbf == nil {
+		return garden.BandwidthLimits{}, nil
+	}
+
+	limits, err := parseTbf(tbf)
+	if err != nil {
+		return garden.BandwidthLimits{}, err
+	}
+
+	policed := false
+	if ingress != nil {
+		policed, err = b.ingressPoliced(intf)
+		if err != nil {
+			log.Error("show-ingress-filter-failed", err)
+			return garden.BandwidthLimits{}, err
+		}
+	}
+
+	if !policed {
+		err := fmt.Errorf("bandwidth limits of %s are only partly applied: no ingress police filter", intf)
+		log.Error("ingress-not-policed", err)
+		return garden.BandwidthLimits{}, err
+	}
+
+	return limits, nil
+}
+
+// ingressPoliced returns true if the ingress qdisc of the interface has a
+// police filter
+func (b *BandwidthLimiter) ingressPoliced(intf string) (bool, error) {
+	stdout := new(bytes.Buffer)
+	cmd := exec.Command(b.binPath, "filter", "show", "dev", intf, "parent", "ffff:")
+	cmd.Stdout = stdout
+	if err := b.run("show-ingress-filter", cmd); err != nil {
+		return false, err
+	}
+
+	for _, line := range strings.Split(stdout.String(), "\n") {
+		fields := strings.Fields(line)
+		if len(fields) > 0 && fields[0] == "police" {
+			return true, nil
+		}
+	}
+
+	return false, nil
+}
+
+func (b *BandwidthLimiter) run(action string, cmd *exec.Cmd) error {
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+
+	if err := b.runner.Run(cmd); err != nil {
+		return fmt.Errorf("%s %s: %s: %s", b.binPath, action, err, strings.TrimSpace(stderr.String()))
+	}
+
+	return nil
+}
+
+// parseTbf parses a line such as
+// 'qdisc tbf 8001: root refcnt 2 rate 8Mbit burst 1Mb lat 25.0ms'
+func parseTbf(fields []string) (garden.BandwidthLimits, error) {
+	var limits garden.BandwidthLimits
+
+	for i := 0; i < len(fields)-1; i++ {
+		switch fields[i] {
+		case "rate":
+			bits, err := parseSize(fields[i+1], "bit", 1000)
+			if err != nil {
+				return garden.BandwidthLimits{}, fmt.Errorf("parse tbf rate: %s", err)
+			}
+			limits.RateInBytesPerSecond = bits / 8
+		case "burst":
+			burst, err := parseSize(fields[i+1], "b", 1024)
+			if err != nil {
+				return garden.BandwidthLimits{}, fmt.Errorf("parse tbf burst: %s", err)
+			}
+			limits.BurstRateInBytesPerSecond = burst
+		}
+	}
+
+	return limits, nil
+}
+
+func parseSize(value, unit string, base uint64) (uint64, error) {
+	if !strings.HasSuffix(value, unit) {
+		return 0, fmt.Errorf("invalid size '%s'", value)
+	}
+
+	number := strings.TrimSuffix(value, unit)
+	multiplier := uint64(1)
+	switch {
+	case strings.HasSuffix(number, "K"):
+		multiplier = base
+	case strings.HasSuffix(number, "M"):
+		multiplier = base * base
+	case strings.HasSuffix(number, "G"):
+		multiplier = base * base * base
+	}
+	number = strings.TrimRight(number, "KMG")
+
+	parsed, err := strconv.ParseFloat(number, 64)
+	if err != nil {
+		return 0, fmt.Errorf("invalid size '%s': %s", value, err)
+	}
+
+	return uint64(parsed * float64(multiplier)), nil
+}
@@ -0,0 +1,212 @@
+package tc_test
+
+import (
+	"errors"
+	"os/exec"
+
+	"code.cloudfoundry.org/garden"
+	"code.cloudfoundry.org/guardian/kawasaki/tc"
+	"code.cloudfoundry.org/lager/lagertest"
+	"github.com/cloudfoundry/gunk/command_runner/fake_command_runner"
+	. "github.com/cloudfoundry/gunk/command_runner/fake_command_runner/matchers"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("BandwidthLimiter", func() {
+	var (
+		fakeRunner *fake_command_runner.FakeCommandRunner
+		logger     *lagertest.TestLogger
+		limiter    *tc.BandwidthLimiter
+	)
+
+	BeforeEach(func() {
+		fakeRunner = fake_command_runner.New()
+		logger = lagertest.NewTestLogger("test")
+		limiter = tc.NewBandwidthLimiter("/sbin/tc", fakeRunner)
+	})
+
+	Describe("Apply", func() {
+		It("shapes egress and polices ingress on the interface", func() {
+			Expect(limiter.Apply(logger, "w-host-1", garden.BandwidthLimits{
+				RateInBytesPerSecond:      1000,
+				BurstRateInBytesPerSecond: 2048,
+			})).To(Succeed())
+
+			Expect(fakeRunner).To(HaveExecutedSerially(
+				fake_command_runner.CommandSpec{
+					Path: "/sbin/tc",
+					Args: []string{"qdisc", "replace", "dev", "w-host-1", "root", "tbf", "rate", "8000bit", "burst", "2048", "latency", "25ms"},
+				},
+				fake_command_runner.CommandSpec{
+					Path: "/sbin/tc",
+					Args: []string{"qdisc", "del", "dev", "w-host-1", "ingress"},
+				},
+				fake_command_runner.CommandSpec{
+					Path: "/sbin/tc",
+					Args: []string{"qdisc", "add", "dev", "w-host-1", "handle", "ffff:", "ingress"},
+				},
+				fake_command_runner.CommandSpec{
+					Path: "/sbin/tc",
+					Args: []string{
+						"filter", "add", "dev", "w-host-1", "parent", "ffff:", "protocol", "all", "u32", "match", "u32", "0", "0",
+						"police", "rate", "8000bit", "burst", "2048", "drop", "flowid", ":1",
+					},
+				},
+			))
+		})
+
+		Context("when the interface has no ingress qdisc to delete", func() {
+			BeforeEach(func() {
+				fakeRunner.WhenRunning(fake_command_runner.CommandSpec{
+					Path: "/sbin/tc",
+					Args: []string{"qdisc", "del", "dev", "w-host-1", "ingress"},
+				}, func(cmd *exec.Cmd) error {
+					cmd.Stderr.Write([]byte("RTNETLINK answers: Invalid argument"))
+					return errors.New("exit status 2")
+				})
+			})
+
+			It("still adds the ingress qdisc and filter", func() {
+				Expect(limiter.Apply(logger, "w-host-1", garden.BandwidthLimits{
+					RateInBytesPerSecond:      1000,
+					BurstRateInBytesPerSecond: 2048,
+				})).To(Succeed())
+
+				Expect(fakeRunner).To(HaveExecutedSerially(
+					fake_command_runner.CommandSpec{
+						Path: "/sbin/tc",
+						Args: []string{"qdisc", "add", "dev", "w-host-1", "handle", "ffff:", "ingress"},
+					},
+				))
+			})
+		})
+
+		Context("when the rate is zero", func() {
+			It("returns an error without running tc", func() {
+				Expect(limiter.Apply(logger, "w-host-1", garden.BandwidthLimits{
+					BurstRateInBytesPerSecond: 2048,
+				})).To(MatchError(ContainSubstring("rate must be greater than 0")))
+				Expect(fakeRunner.ExecutedCommands()).To(BeEmpty())
+			})
+		})
+
+		Context("when the burst rate is zero", func() {
+			It("returns an error without running tc", func() {
+				Expect(limiter.Apply(logger, "w-host-1", garden.BandwidthLimits{
+					RateInBytesPerSecond: 1000,
+				})).To(MatchError(ContainSubstring("burst rate must be greater than 0")))
+				Expect(fakeRunner.ExecutedCommands()).To(BeEmpty())
+			})
+		})
+
+		Context("when a tc command fails", func() {
+			BeforeEach(func() {
+				fakeRunner.WhenRunning(fake_command_runner.CommandSpec{
+					Path: "/sbin/tc",
+					Args: []string{"qdisc", "add", "dev", "w-host-1", "handle", "ffff:", "ingress"},
+				}, func(cmd *exec.Cmd) error {
+					cmd.Stderr.Write([]byte("RTNETLINK answers: File exists"))
+					return errors.New("exit status 2")
+				})
+			})
+
+			It("returns an error including the output of tc", func() {
+				err := limiter.Apply(logger, "w-host-1", garden.BandwidthLimits{
+					RateInBytesPerSecond:      1000,
+					BurstRateInBytesPerSecond: 2048,
+				})
+				Expect(err).To(MatchError(ContainSubstring("add-ingress-qdisc")))
+				Expect(err).To(MatchError(ContainSubstring("RTNETLINK answers: File exists")))
+			})
+		})
+	})
+
+	Describe("Limits", func() {
+		var qdiscs, filters string
+
+		BeforeEach(func() {
+			filters = "filter parent ffff: protocol all pref 49152 u32 chain 0 fh 800::800 order 2048 key ht 800 bkt 0 flowid :1\n" +
+				"  match 00000000/00000000 at 0\n" +
+				"\tpolice 0x1 rate 8Mbit burst 2Kb mtu 2Kb action drop overhead 0b\n"
+
+			fakeRunner.WhenRunning(fake_command_runner.CommandSpec{
+				Path: "/sbin/tc",
+				Args: []string{"qdisc", "show", "dev", "w-host-1"},
+			}, func(cmd *exec.Cmd) error {
+				cmd.Stdout.Write([]byte(qdiscs))
+				return nil
+			})
+
+			fakeRunner.WhenRunning(fake_command_runner.CommandSpec{
+				Path: "/sbin/tc",
+				Args: []string{"filter", "show", "dev", "w-host-1", "parent", "ffff:"},
+			}, func(cmd *exec.Cmd) error {
+				cmd.Stdout.Write([]byte(filters))
+				return nil
+			})
+		})
+
+		Context("when the interface has a token bucket filter", func() {
+			BeforeEach(func() {
+				qdiscs = "qdisc tbf 8001: root refcnt 2 rate 8Mbit burst 2Kb lat 25.0ms \nqdisc ingress ffff: parent ffff:fff1 ----------------\n"
+			})
+
+			It("returns the rate and burst reported by the kernel", func() {
+				Expect(limiter.Limits(logger, "w-host-1")).To(Equal(garden.BandwidthLimits{
+					RateInBytesPerSecond:      1000000,
+					BurstRateInBytesPerSecond: 2048,
+				}))
+			})
+
+			Context("but the ingress qdisc has no police filter", func() {
+				BeforeEach(func() {
+					filters = ""
+				})
+
+				It("returns an error", func() {
+					_, err := limiter.Limits(logger, "w-host-1")
+					Expect(err).To(MatchError(ContainSubstring("no ingress police filter")))
+				})
+			})
+
+			Context("but there is no ingress qdisc", func() {
+				BeforeEach(func() {
+					qdiscs = "qdisc tbf 8001: root refcnt 2 rate 8Mbit burst 2Kb lat 25.0ms \n"
+				})
+
+				It("returns an error without looking for the filter", func() {
+					_, err := limiter.Limits(logger, "w-host-1")
+					Expect(err).To(MatchError(ContainSubstring("no ingress police filter")))
+
+					Expect(fakeRunner).NotTo(HaveExecutedSerially(fake_command_runner.CommandSpec{
+						Path: "/sbin/tc",
+						Args: []string{"filter", "show", "dev", "w-host-1", "parent", "ffff:"},
+					}))
+				})
+			})
+		})
+
+		Context("when the interface has no token bucket filter", func() {
+			BeforeEach(func() {
+				qdiscs = "qdisc noqueue 0: root refcnt 2\n"
+			})
+
+			It("returns empty limits", func() {
+				Expect(limiter.Limits(logger, "w-host-1")).To(Equal(garden.BandwidthLimits{}))
+			})
+		})
+
+		Context("when the rate cannot be parsed", func() {
+			BeforeEach(func() {
+				qdiscs = "qdisc tbf 8001: root refcnt 2 rate potato burst 2Kb lat 25.0ms\n"
+			})
+
+			It("returns an error", func() {
+				_, err := limiter.Limits(logger, "w-host-1")
+				Expect(err).To(MatchError(ContainSubstring("parse tbf rate")))
+			})
+		})
+	})
+})
@@ -7,6 +7,7 @@ import (
 	"syscall"
 	"time"
 
+	"code.cloudfoundry.org/guardian/tracing"
 	"code.cloudfoundry.org/lager"
 	"github.com/cloudfoundry/gunk/command_runner"
 )
@@ -33,9 +34,17 @@ func (runner *Runner) Run(cmd *exec.Cmd) error {
 		cmd.Stderr = io.MultiWriter(cmd.Stderr, stderr)
 	}
 
-	rLog := runner.Logger.Session("command", lager.Data{
+	sessionData := lager.Data{
 		"argv": cmd.Args,
-	})
+	}
+
+	// the runner is shared, so commands run on behalf of a traced operation
+	// carry its trace id in their environment
+	if traceID := tracing.FromEnv(cmd.Env); traceID != "" {
+		sessionData[tracing.TraceIDKey] = traceID
+	}
+
+	rLog := runner.Logger.Session("command", sessionData)
 
 	started := time.Now()
 
@@ -6,6 +6,7 @@ import (
 	"time"
 
 	"code.cloudfoundry.org/guardian/logging"
+	"code.cloudfoundry.org/guardian/tracing"
 	"code.cloudfoundry.org/lager"
 	"code.cloudfoundry.org/lager/lagertest"
 	"github.com/cloudfoundry/gunk/command_runner"
@@ -51,6 +52,17 @@ var _ = Describe("Logging Runner", func() {
 		Expect(duration).To(BeNumerically(">=", 1*time.Second))
 	})
 
+	It("logs the trace id passed to the command", func() {
+		cmd := exec.Command("true")
+		cmd.Env = []string{tracing.EnvVar + "=some-trace"}
+
+		Expect(runner.Run(cmd)).To(Succeed())
+
+		for _, log := range logger.TestSink.Logs() {
+			Expect(log.Data).To(HaveKeyWithValue(tracing.TraceIDKey, "some-trace"))
+		}
+	})
+
 	It("logs the command's argv", func() {
 		err := runner.Run(exec.Command("bash", "-c", "echo sup"))
 		Expect(err).ToNot(HaveOccurred())
@@ -0,0 +1,229 @@
+package metrics
+
+import (
+	"time"
+
+	"code.cloudfoundry.org/garden"
+	"code.cloudfoundry.org/guardian/gardener"
+	"code.cloudfoundry.org/lager"
+	dropsonde_metrics "github.com/cloudfoundry/dropsonde/metrics"
+	"github.com/pivotal-golang/clock"
+)
+
+// HandleTag is the tag which every container metric carries
+const HandleTag = "handle"
+
+const (
+	containerCPUUsage     = "ContainerCPUUsage"
+	containerCPUPercent   = "ContainerCPUPercent"
+	containerMemoryUsage  = "ContainerMemoryUsage"
+	containerMemoryRSS    = "ContainerMemoryRSS"
+	containerDiskUsage    = "ContainerDiskUsage"
+	containerDiskInodes   = "ContainerDiskInodes"
+	containerRxBytes      = "ContainerNetworkRxBytes"
+	containerTxBytes      = "ContainerNetworkTxBytes"
+	containerRxPackets    = "ContainerNetworkRxPackets"
+	containerTxPackets    = "ContainerNetworkTxPackets"
+	containerPids         = "ContainerPids"
+	containerPidsLimit    = "ContainerPidsLimit"
+	containerThrottled    = "ContainerCPUThrottledPeriods"
+	containerThrottledFor = "ContainerCPUThrottledTime"
+	containerBlkioRead    = "ContainerBlockIOReadBytes"
+	containerBlkioWrite   = "ContainerBlockIOWriteBytes"
+	containerHugetlbUsage = "ContainerHugetlbUsage"
+	containerMetricsCount = Metric("ContainerMetricsReported")
+
+	containerMetricsReportingDuration = Duration("ContainerMetricsReporting")
+)
+
+//go:generate counterfeiter . ContainerMetricsSource
+
+type ContainerMetricsSource interface {
+	Containers(props garden.Properties) ([]garden.Container, error)
+	BulkExtendedMetrics(handles []string) (map[string]gardener.ExtendedMetricsEntry, error)
+}
+
+//go:generate counterfeiter . TaggedMetricSender
+
+type TaggedMetricSender interface {
+	SendValue(name string, value float64, unit string, tags map[string]string) error
+}
+
+// DropsondeSender sends tagged value metrics through the dropsonde emitter
+type DropsondeSender struct{}
+
+func (DropsondeSender) SendValue(name string, value float64, unit string, tags map[string]string) error {
+	chainer := dropsonde_metrics.Value(name, value, unit)
+	for key, value := range tags {
+		chainer = chainer.SetTag(key, value)
+	}
+
+	return chainer.Send()
+}
+
+// PeriodicContainerMetronNotifier sends the metrics of every container,
+// tagged with its handle and the values of TagProperties
+type PeriodicContainerMetronNotifier struct {
+	Interval      time.Duration
+	Logger        lager.Logger
+	Clock         clock.Clock
+	TagProperties []string
+
+	source  ContainerMetricsSource
+	sender  TaggedMetricSender
+	stopped chan struct{}
+}
+
+func NewPeriodicContainerMetronNotifier(
+	logger lager.Logger,
+	source ContainerMetricsSource,
+	sender TaggedMetricSender,
+	tagProperties []string,
+	interval time.Duration,
+	clock clock.Clock,
+) *PeriodicContainerMetronNotifier {
+	return &PeriodicContainerMetronNotifier{
+		Interval:      interval,
+		Logger:        logger,
+		Clock:         clock,
+		TagProperties: tagProperties,
+
+		source:  source,
+		sender:  sender,
+		stopped: make(chan struct{}),
+	}
+}
+
+func (notifier PeriodicContainerMetronNotifier) Start() {
+	logger := notifier.Logger.Session("container-metrics-notifier", lager.Data{"interval": notifier.Interval.String()})
+	logger.Info("starting")
+	ticker := notifier.Clock.NewTicker(notifier.Interval)
+
+	go func() {
+		defer ticker.Stop()
+
+		logger.Info("started", lager.Data{"time": notifier.Clock.Now()})
+		defer logger.Info("finished")
+
+		for {
+			select {
+			case <-ticker.C():
+				startedAt := notifier.Clock.Now()
+
+				containerMetricsCount.Send(notifier.emit(logger))
+
+				finishedAt := notifier.Clock.Now()
+				containerMetricsReportingDuration.Send(finishedAt.Sub(startedAt))
+			case <-notifier.stopped:
+				return
+			}
+		}
+	}()
+}
+
+func (notifier PeriodicContainerMetronNotifier) Stop() {
+	close(notifier.stopped)
+}
+
+// emit sends the metrics of each container and returns how many containers
+// were reported
+func (notifier PeriodicContainerMetronNotifier) emit(logger lager.Logger) int {
+	containers, err := notifier.source.Containers(nil)
+	if err != nil {
+		logger.Error("list-containers-failed", err)
+		return 0
+	}
+
+	tags := make(map[string]map[string]string, len(containers))
+	handles := make([]string, 0, len(containers))
+	for _, container := range containers {
+		handles = append(handles, container.Handle())
+		tags[container.Handle()] = notifier.tags(container)
+	}
+
+	entries, err := notifier.source.BulkExtendedMetrics(handles)
+	if err != nil {
+		logger.Error("bulk-metrics-failed", err)
+		return 0
+	}
+
+	reported := 0
+	for _, handle := range handles {
+		entry, ok := entries[handle]
+		if !ok {
+			continue
+		}
+
+		if entry.Err != nil {
+			logger.Info("skipping-container", lager.Data{"handle": handle, "error": entry.Err.Error()})
+			continue
+		}
+
+		notifier.send(logger, entry.Metrics, tags[handle])
+		reported++
+	}
+
+	return reported
+}
+
+func (notifier PeriodicContainerMetronNotifier) tags(container garden.Container) map[string]string {
+	tags := map[string]string{HandleTag: container.Handle()}
+	for _, name := range notifier.TagProperties {
+		if value, err := container.Property(name); err == nil {
+			tags[name] = value
+		}
+	}
+
+	return tags
+}
+
+type containerValue struct {
+	name  string
+	value float64
+	unit  string
+}
+
+func (notifier PeriodicContainerMetronNotifier) send(logger lager.Logger, metrics gardener.ExtendedMetrics, tags map[string]string) {
+	// block IO and huge pages are sent as totals over every device and page
+	// size; the Prometheus endpoint breaks them down
+	var readBytes, writeBytes, hugetlbUsage uint64
+	for _, stat := range metrics.BlockIOStats {
+		readBytes += stat.ReadBytes
+		writeBytes += stat.WriteBytes
+	}
+	for _, stat := range metrics.HugetlbStats {
+		hugetlbUsage += stat.Usage
+	}
+
+	values := []containerValue{
+		{containerCPUUsage, float64(metrics.CPUStat.Usage), "nanos"},
+		{containerCPUPercent, metrics.CPURateStat.Percent, "percentage"},
+		{containerMemoryUsage, float64(metrics.MemoryStat.TotalUsageTowardLimit), "bytes"},
+		{containerMemoryRSS, float64(metrics.MemoryStat.TotalRss), "bytes"},
+		{containerDiskUsage, float64(metrics.DiskStat.TotalBytesUsed), "bytes"},
+		{containerDiskInodes, float64(metrics.DiskStat.TotalInodesUsed), "Metric"},
+		{containerPids, float64(metrics.PidStat.Current), "Metric"},
+		{containerPidsLimit, float64(metrics.PidStat.Max), "Metric"},
+		{containerThrottled, float64(metrics.ThrottlingStat.ThrottledPeriods), "Metric"},
+		{containerThrottledFor, float64(metrics.ThrottlingStat.ThrottledTime), "nanos"},
+		{containerBlkioRead, float64(readBytes), "bytes"},
+		{containerBlkioWrite, float64(writeBytes), "bytes"},
+		{containerHugetlbUsage, float64(hugetlbUsage), "bytes"},
+	}
+
+	// unavailable network traffic is left out rather than sent as zero
+	if traffic := metrics.NetworkTraffic; traffic != nil {
+		values = append(values,
+			containerValue{containerRxBytes, float64(traffic.RxBytes), "bytes"},
+			containerValue{containerTxBytes, float64(traffic.TxBytes), "bytes"},
+			containerValue{containerRxPackets, float64(traffic.RxPackets), "Metric"},
+			containerValue{containerTxPackets, float64(traffic.TxPackets), "Metric"},
+		)
+	}
+
+	for _, v := range values {
+		if err := notifier.sender.SendValue(v.name, v.value, v.unit, tags); err != nil {
+			logger.Error("send-failed", err, lager.Data{"metric": v.name, "handle": tags[HandleTag]})
+		}
+	}
+}
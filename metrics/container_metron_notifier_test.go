@@ -0,0 +1,209 @@
+package metrics_test
+
+import (
+	"errors"
+	"time"
+
+	"code.cloudfoundry.org/garden"
+	"code.cloudfoundry.org/garden/gardenfakes"
+	"code.cloudfoundry.org/guardian/gardener"
+	"code.cloudfoundry.org/guardian/metrics"
+	fakes "code.cloudfoundry.org/guardian/metrics/metricsfakes"
+	"code.cloudfoundry.org/lager/lagertest"
+	"github.com/cloudfoundry/dropsonde/metric_sender/fake"
+	dropsonde_metrics "github.com/cloudfoundry/dropsonde/metrics"
+	"github.com/pivotal-golang/clock/fakeclock"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("PeriodicContainerMetronNotifier", func() {
+	type sent struct {
+		value float64
+		unit  string
+		tags  map[string]string
+	}
+
+	var (
+		source         *fakes.FakeContainerMetricsSource
+		sender         *fakes.FakeTaggedMetricSender
+		reportInterval time.Duration
+		fakeClock      *fakeclock.FakeClock
+
+		pcmn *metrics.PeriodicContainerMetronNotifier
+	)
+
+	container := func(handle string, props map[string]string) garden.Container {
+		c := new(gardenfakes.FakeContainer)
+		c.HandleReturns(handle)
+		c.PropertyStub = func(name string) (string, error) {
+			if value, ok := props[name]; ok {
+				return value, nil
+			}
+
+			return "", errors.New("no such property")
+		}
+		return c
+	}
+
+	sentValues := func() map[string]map[string]sent {
+		values := map[string]map[string]sent{}
+		for i := 0; i < sender.SendValueCallCount(); i++ {
+			name, value, unit, tags := sender.SendValueArgsForCall(i)
+			if values[tags[metrics.HandleTag]] == nil {
+				values[tags[metrics.HandleTag]] = map[string]sent{}
+			}
+			values[tags[metrics.HandleTag]][name] = sent{value, unit, tags}
+		}
+		return values
+	}
+
+	BeforeEach(func() {
+		reportInterval = 100 * time.Millisecond
+
+		source = new(fakes.FakeContainerMetricsSource)
+		source.ContainersReturns([]garden.Container{
+			container("banana", map[string]string{"app_id": "some-app", "other": "x"}),
+			container("potato", nil),
+		}, nil)
+
+		source.BulkExtendedMetricsReturns(map[string]gardener.ExtendedMetricsEntry{
+			"banana": {
+				Metrics: gardener.ExtendedMetrics{
+					Metrics: garden.Metrics{
+						CPUStat:    garden.ContainerCPUStat{Usage: 100},
+						MemoryStat: garden.ContainerMemoryStat{TotalUsageTowardLimit: 2048, TotalRss: 1024},
+						DiskStat:   garden.ContainerDiskStat{TotalBytesUsed: 4096, TotalInodesUsed: 12},
+					},
+					CPURateStat:    gardener.ContainerCPURateStat{Interval: time.Second, Percent: 12.5},
+					NetworkTraffic: &gardener.ContainerNetworkStat{RxBytes: 1, TxBytes: 2, RxPackets: 3, TxPackets: 4},
+					PidStat:        gardener.ContainerPidStat{Current: 5, Max: 50},
+					ThrottlingStat: gardener.ContainerCPUThrottlingStat{Periods: 10, ThrottledPeriods: 6, ThrottledTime: 700},
+					BlockIOStats: []gardener.ContainerBlockIOStat{
+						{Major: 8, Minor: 0, ReadBytes: 100, WriteBytes: 200},
+						{Major: 8, Minor: 16, ReadBytes: 1, WriteBytes: 2},
+					},
+					HugetlbStats: map[string]gardener.ContainerHugetlbStat{
+						"2MB": {Usage: 2048},
+						"1GB": {Usage: 1024},
+					},
+				},
+			},
+			"potato": {Err: garden.NewError("potato-error")},
+		}, nil)
+
+		sender = new(fakes.FakeTaggedMetricSender)
+		fakeClock = fakeclock.NewFakeClock(time.Unix(123, 456))
+
+		dropsonde_metrics.Initialize(fake.NewFakeMetricSender(), nil)
+	})
+
+	JustBeforeEach(func() {
+		pcmn = metrics.NewPeriodicContainerMetronNotifier(
+			lagertest.NewTestLogger("test"),
+			source,
+			sender,
+			[]string{"app_id"},
+			reportInterval,
+			fakeClock,
+		)
+		pcmn.Start()
+	})
+
+	AfterEach(func() {
+		pcmn.Stop()
+	})
+
+	It("does not emit anything before the report interval elapses", func() {
+		Consistently(sender.SendValueCallCount).Should(Equal(0))
+	})
+
+	Context("when the report interval elapses", func() {
+		var valuesSent int
+
+		BeforeEach(func() {
+			valuesSent = 17
+		})
+
+		JustBeforeEach(func() {
+			fakeClock.Increment(reportInterval)
+			Eventually(sender.SendValueCallCount).Should(Equal(valuesSent))
+		})
+
+		It("asks for the metrics of every container", func() {
+			Expect(source.ContainersArgsForCall(0)).To(BeNil())
+			Expect(source.BulkExtendedMetricsArgsForCall(0)).To(Equal([]string{"banana", "potato"}))
+		})
+
+		It("emits the metrics of each container", func() {
+			values := sentValues()["banana"]
+			Expect(values).To(HaveLen(17))
+			Expect(values["ContainerCPUUsage"].value).To(Equal(100.0))
+			Expect(values["ContainerCPUUsage"].unit).To(Equal("nanos"))
+			Expect(values["ContainerCPUPercent"].value).To(Equal(12.5))
+			Expect(values["ContainerMemoryUsage"].value).To(Equal(2048.0))
+			Expect(values["ContainerMemoryUsage"].unit).To(Equal("bytes"))
+			Expect(values["ContainerMemoryRSS"].value).To(Equal(1024.0))
+			Expect(values["ContainerDiskUsage"].value).To(Equal(4096.0))
+			Expect(values["ContainerDiskInodes"].value).To(Equal(12.0))
+			Expect(values["ContainerNetworkRxBytes"].value).To(Equal(1.0))
+			Expect(values["ContainerNetworkTxBytes"].value).To(Equal(2.0))
+			Expect(values["ContainerNetworkRxPackets"].value).To(Equal(3.0))
+			Expect(values["ContainerNetworkTxPackets"].value).To(Equal(4.0))
+		})
+
+		It("emits the pids, CPU throttling, block IO and huge pages of each container", func() {
+			values := sentValues()["banana"]
+			Expect(values["ContainerPids"].value).To(Equal(5.0))
+			Expect(values["ContainerPidsLimit"].value).To(Equal(50.0))
+			Expect(values["ContainerCPUThrottledPeriods"].value).To(Equal(6.0))
+			Expect(values["ContainerCPUThrottledTime"].value).To(Equal(700.0))
+			Expect(values["ContainerCPUThrottledTime"].unit).To(Equal("nanos"))
+			Expect(values["ContainerBlockIOReadBytes"].value).To(Equal(101.0))
+			Expect(values["ContainerBlockIOWriteBytes"].value).To(Equal(202.0))
+			Expect(values["ContainerHugetlbUsage"].value).To(Equal(3072.0))
+		})
+
+		It("tags the metrics with the handle and the selected properties", func() {
+			for _, value := range sentValues()["banana"] {
+				Expect(value.tags).To(Equal(map[string]string{
+					"handle": "banana",
+					"app_id": "some-app",
+				}))
+			}
+		})
+
+		Context("when the network traffic of a container is unavailable", func() {
+			BeforeEach(func() {
+				source.BulkExtendedMetricsReturns(map[string]gardener.ExtendedMetricsEntry{
+					"banana": {Metrics: gardener.ExtendedMetrics{PidStat: gardener.ContainerPidStat{Current: 5}}},
+				}, nil)
+				valuesSent = 13
+			})
+
+			It("emits the other metrics without any network traffic", func() {
+				values := sentValues()["banana"]
+				Expect(values).To(HaveLen(13))
+				Expect(values["ContainerPids"].value).To(Equal(5.0))
+				Expect(values).NotTo(HaveKey("ContainerNetworkRxBytes"))
+			})
+		})
+
+		It("skips containers whose metrics could not be read", func() {
+			Expect(sentValues()).NotTo(HaveKey("potato"))
+		})
+	})
+
+	Context("when the containers cannot be listed", func() {
+		BeforeEach(func() {
+			source.ContainersReturns(nil, errors.New("boom"))
+		})
+
+		It("emits nothing", func() {
+			fakeClock.Increment(reportInterval)
+			Consistently(sender.SendValueCallCount).Should(Equal(0))
+			Expect(source.BulkExtendedMetricsCallCount()).To(Equal(0))
+		})
+	})
+})
@@ -12,7 +12,16 @@ import (
 	"github.com/tedsuo/ifrit/http_server"
 )
 
-func StartDebugServer(address string, sink *lager.ReconfigurableSink, metrics Metrics) (ifrit.Process, error) {
+//go:generate counterfeiter . DrainState
+
+type DrainState interface {
+	Draining() bool
+	InFlightOperations() int
+}
+
+// StartDebugServer serves expvars and pprof, and the metrics in Prometheus
+// format on /metrics when prometheus is not nil
+func StartDebugServer(address string, sink *lager.ReconfigurableSink, metrics Metrics, drainState DrainState, prometheus http.Handler) (ifrit.Process, error) {
 	expvar.Publish("numCPUS", expvar.Func(func() interface{} {
 		return metrics.NumCPU()
 	}))
@@ -33,7 +42,15 @@ func StartDebugServer(address string, sink *lager.ReconfigurableSink, metrics Me
 		return metrics.DepotDirs()
 	}))
 
-	server := http_server.New(address, handler(sink))
+	expvar.Publish("draining", expvar.Func(func() interface{} {
+		return drainState.Draining()
+	}))
+
+	expvar.Publish("inFlightOperations", expvar.Func(func() interface{} {
+		return drainState.InFlightOperations()
+	}))
+
+	server := http_server.New(address, handler(sink, prometheus))
 	p := ifrit.Invoke(server)
 	select {
 	case <-p.Ready():
@@ -43,9 +60,14 @@ func StartDebugServer(address string, sink *lager.ReconfigurableSink, metrics Me
 	return p, nil
 }
 
-func handler(sink *lager.ReconfigurableSink) http.Handler {
+func handler(sink *lager.ReconfigurableSink, prometheus http.Handler) http.Handler {
 	pprofHandler := debugserver.Handler(sink)
 	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/metrics" && prometheus != nil {
+			prometheus.ServeHTTP(w, r)
+			return
+		}
+
 		if strings.HasPrefix(r.URL.Path, "/debug/vars") {
 			http.DefaultServeMux.ServeHTTP(w, r)
 			return
@@ -2,6 +2,7 @@ package metrics_test
 
 import (
 	"expvar"
+	"io/ioutil"
 	"net/http"
 	"os"
 
@@ -16,8 +17,9 @@ import (
 
 var _ = Describe("Debug", func() {
 	var (
-		serverProc  ifrit.Process
-		fakeMetrics *fakes.FakeMetrics
+		serverProc     ifrit.Process
+		fakeMetrics    *fakes.FakeMetrics
+		fakeDrainState *fakes.FakeDrainState
 	)
 
 	BeforeEach(func() {
@@ -30,8 +32,16 @@ var _ = Describe("Debug", func() {
 		fakeMetrics.BackingStoresReturns(12)
 		fakeMetrics.DepotDirsReturns(3)
 
+		fakeDrainState = new(fakes.FakeDrainState)
+		fakeDrainState.DrainingReturns(true)
+		fakeDrainState.InFlightOperationsReturns(2)
+
 		sink := lager.NewReconfigurableSink(lager.NewWriterSink(GinkgoWriter, lager.DEBUG), lager.DEBUG)
-		serverProc, err = metrics.StartDebugServer("127.0.0.1:5123", sink, fakeMetrics)
+		prometheus := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.Write([]byte("some_metric 1\n"))
+		})
+
+		serverProc, err = metrics.StartDebugServer("127.0.0.1:5123", sink, fakeMetrics, fakeDrainState, prometheus)
 		Expect(err).ToNot(HaveOccurred())
 	})
 
@@ -39,7 +49,7 @@ var _ = Describe("Debug", func() {
 		serverProc.Signal(os.Kill)
 	})
 
-	It("should report the number of loop devices, backing store files and depotDirs", func() {
+	It("should report the number of loop devices, backing store files and depotDirs, and the drain state, and serve /metrics", func() {
 		resp, err := http.Get("http://127.0.0.1:5123/debug/vars")
 		Expect(err).ToNot(HaveOccurred())
 
@@ -51,5 +61,15 @@ var _ = Describe("Debug", func() {
 		Expect(expvar.Get("depotDirs").String()).To(Equal("3"))
 		Expect(expvar.Get("numCPUS").String()).To(Equal("11"))
 		Expect(expvar.Get("numGoRoutines").String()).To(Equal("888"))
+		Expect(expvar.Get("draining").String()).To(Equal("true"))
+		Expect(expvar.Get("inFlightOperations").String()).To(Equal("2"))
+
+		resp, err = http.Get("http://127.0.0.1:5123/metrics")
+		Expect(err).ToNot(HaveOccurred())
+
+		defer resp.Body.Close()
+		body, err := ioutil.ReadAll(resp.Body)
+		Expect(err).ToNot(HaveOccurred())
+		Expect(string(body)).To(Equal("some_metric 1\n"))
 	})
 })
@@ -0,0 +1,130 @@
+// This file was generated by counterfeiter
+package metricsfakes
+
+import (
+	"sync"
+
+	"code.cloudfoundry.org/garden"
+	"code.cloudfoundry.org/guardian/gardener"
+	"code.cloudfoundry.org/guardian/metrics"
+)
+
+type FakeContainerMetricsSource struct {
+	ContainersStub        func(props garden.Properties) ([]garden.Container, error)
+	containersMutex       sync.RWMutex
+	containersArgsForCall []struct {
+		props garden.Properties
+	}
+	containersReturns struct {
+		result1 []garden.Container
+		result2 error
+	}
+	BulkExtendedMetricsStub        func(handles []string) (map[string]gardener.ExtendedMetricsEntry, error)
+	bulkExtendedMetricsMutex       sync.RWMutex
+	bulkExtendedMetricsArgsForCall []struct {
+		handles []string
+	}
+	bulkExtendedMetricsReturns struct {
+		result1 map[string]gardener.ExtendedMetricsEntry
+		result2 error
+	}
+	invocations      map[string][][]interface{}
+	invocationsMutex sync.RWMutex
+}
+
+func (fake *FakeContainerMetricsSource) Containers(props garden.Properties) ([]garden.Container, error) {
+	fake.containersMutex.Lock()
+	fake.containersArgsForCall = append(fake.containersArgsForCall, struct {
+		props garden.Properties
+	}{props})
+	fake.recordInvocation("Containers", []interface{}{props})
+	fake.containersMutex.Unlock()
+	if fake.ContainersStub != nil {
+		return fake.ContainersStub(props)
+	} else {
+		return fake.containersReturns.result1, fake.containersReturns.result2
+	}
+}
+
+func (fake *FakeContainerMetricsSource) ContainersCallCount() int {
+	fake.containersMutex.RLock()
+	defer fake.containersMutex.RUnlock()
+	return len(fake.containersArgsForCall)
+}
+
+func (fake *FakeContainerMetricsSource) ContainersArgsForCall(i int) garden.Properties {
+	fake.containersMutex.RLock()
+	defer fake.containersMutex.RUnlock()
+	return fake.containersArgsForCall[i].props
+}
+
+func (fake *FakeContainerMetricsSource) ContainersReturns(result1 []garden.Container, result2 error) {
+	fake.ContainersStub = nil
+	fake.containersReturns = struct {
+		result1 []garden.Container
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeContainerMetricsSource) BulkExtendedMetrics(handles []string) (map[string]gardener.ExtendedMetricsEntry, error) {
+	var handlesCopy []string
+	if handles != nil {
+		handlesCopy = make([]string, len(handles))
+		copy(handlesCopy, handles)
+	}
+	fake.bulkExtendedMetricsMutex.Lock()
+	fake.bulkExtendedMetricsArgsForCall = append(fake.bulkExtendedMetricsArgsForCall, struct {
+		handles []string
+	}{handlesCopy})
+	fake.recordInvocation("BulkExtendedMetrics", []interface{}{handlesCopy})
+	fake.bulkExtendedMetricsMutex.Unlock()
+	if fake.BulkExtendedMetricsStub != nil {
+		return fake.BulkExtendedMetricsStub(handles)
+	} else {
+		return fake.bulkExtendedMetricsReturns.result1, fake.bulkExtendedMetricsReturns.result2
+	}
+}
+
+func (fake *FakeContainerMetricsSource) BulkExtendedMetricsCallCount() int {
+	fake.bulkExtendedMetricsMutex.RLock()
+	defer fake.bulkExtendedMetricsMutex.RUnlock()
+	return len(fake.bulkExtendedMetricsArgsForCall)
+}
+
+func (fake *FakeContainerMetricsSource) BulkExtendedMetricsArgsForCall(i int) []string {
+	fake.bulkExtendedMetricsMutex.RLock()
+	defer fake.bulkExtendedMetricsMutex.RUnlock()
+	return fake.bulkExtendedMetricsArgsForCall[i].handles
+}
+
+func (fake *FakeContainerMetricsSource) BulkExtendedMetricsReturns(result1 map[string]gardener.ExtendedMetricsEntry, result2 error) {
+	fake.BulkExtendedMetricsStub = nil
+	fake.bulkExtendedMetricsReturns = struct {
+		result1 map[string]gardener.ExtendedMetricsEntry
+		result2 error
+	}{result1, result2}
+}
+
+func (fake *FakeContainerMetricsSource) Invocations() map[string][][]interface{} {
+	fake.invocationsMutex.RLock()
+	defer fake.invocationsMutex.RUnlock()
+	fake.containersMutex.RLock()
+	defer fake.containersMutex.RUnlock()
+	fake.bulkExtendedMetricsMutex.RLock()
+	defer fake.bulkExtendedMetricsMutex.RUnlock()
+	return fake.invocations
+}
+
+func (fake *FakeContainerMetricsSource) recordInvocation(key string, args []interface{}) {
+	fake.invocationsMutex.Lock()
+	defer fake.invocationsMutex.Unlock()
+	if fake.invocations == nil {
+		fake.invocations = map[string][][]interface{}{}
+	}
+	if fake.invocations[key] == nil {
+		fake.invocations[key] = [][]interface{}{}
+	}
+	fake.invocations[key] = append(fake.invocations[key], args)
+}
+
+var _ metrics.ContainerMetricsSource = new(FakeContainerMetricsSource)
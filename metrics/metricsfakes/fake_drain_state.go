@@ -0,0 +1,99 @@
+// This file was generated by counterfeiter
+package metricsfakes
+
+import (
+	"sync"
+
+	"code.cloudfoundry.org/guardian/metrics"
+)
+
+type FakeDrainState struct {
+	DrainingStub        func() bool
+	drainingMutex       sync.RWMutex
+	drainingArgsForCall []struct{}
+	drainingReturns     struct {
+		result1 bool
+	}
+	InFlightOperationsStub        func() int
+	inFlightOperationsMutex       sync.RWMutex
+	inFlightOperationsArgsForCall []struct{}
+	inFlightOperationsReturns     struct {
+		result1 int
+	}
+	invocations      map[string][][]interface{}
+	invocationsMutex sync.RWMutex
+}
+
+func (fake *FakeDrainState) Draining() bool {
+	fake.drainingMutex.Lock()
+	fake.drainingArgsForCall = append(fake.drainingArgsForCall, struct{}{})
+	fake.recordInvocation("Draining", []interface{}{})
+	fake.drainingMutex.Unlock()
+	if fake.DrainingStub != nil {
+		return fake.DrainingStub()
+	} else {
+		return fake.drainingReturns.result1
+	}
+}
+
+func (fake *FakeDrainState) DrainingCallCount() int {
+	fake.drainingMutex.RLock()
+	defer fake.drainingMutex.RUnlock()
+	return len(fake.drainingArgsForCall)
+}
+
+func (fake *FakeDrainState) DrainingReturns(result1 bool) {
+	fake.DrainingStub = nil
+	fake.drainingReturns = struct {
+		result1 bool
+	}{result1}
+}
+
+func (fake *FakeDrainState) InFlightOperations() int {
+	fake.inFlightOperationsMutex.Lock()
+	fake.inFlightOperationsArgsForCall = append(fake.inFlightOperationsArgsForCall, struct{}{})
+	fake.recordInvocation("InFlightOperations", []interface{}{})
+	fake.inFlightOperationsMutex.Unlock()
+	if fake.InFlightOperationsStub != nil {
+		return fake.InFlightOperationsStub()
+	} else {
+		return fake.inFlightOperationsReturns.result1
+	}
+}
+
+func (fake *FakeDrainState) InFlightOperationsCallCount() int {
+	fake.inFlightOperationsMutex.RLock()
+	defer fake.inFlightOperationsMutex.RUnlock()
+	return len(fake.inFlightOperationsArgsForCall)
+}
+
+func (fake *FakeDrainState) InFlightOperationsReturns(result1 int) {
+	fake.InFlightOperationsStub = nil
+	fake.inFlightOperationsReturns = struct {
+		result1 int
+	}{result1}
+}
+
+func (fake *FakeDrainState) Invocations() map[string][][]interface{} {
+	fake.invocationsMutex.RLock()
+	defer fake.invocationsMutex.RUnlock()
+	fake.drainingMutex.RLock()
+	defer fake.drainingMutex.RUnlock()
+	fake.inFlightOperationsMutex.RLock()
+	defer fake.inFlightOperationsMutex.RUnlock()
+	return fake.invocations
+}
+
+func (fake *FakeDrainState) recordInvocation(key string, args []interface{}) {
+	fake.invocationsMutex.Lock()
+	defer fake.invocationsMutex.Unlock()
+	if fake.invocations == nil {
+		fake.invocations = map[string][][]interface{}{}
+	}
+	if fake.invocations[key] == nil {
+		fake.invocations[key] = [][]interface{}{}
+	}
+	fake.invocations[key] = append(fake.invocations[key], args)
+}
+
+var _ metrics.DrainState = new(FakeDrainState)
@@ -0,0 +1,99 @@
+// This file was generated by counterfeiter
+package metricsfakes
+
+import (
+	"sync"
+
+	"code.cloudfoundry.org/guardian/metrics"
+)
+
+type FakePortPool struct {
+	CapacityStub        func() int
+	capacityMutex       sync.RWMutex
+	capacityArgsForCall []struct{}
+	capacityReturns     struct {
+		result1 int
+	}
+	AvailableStub        func() int
+	availableMutex       sync.RWMutex
+	availableArgsForCall []struct{}
+	availableReturns     struct {
+		result1 int
+	}
+	invocations      map[string][][]interface{}
+	invocationsMutex sync.RWMutex
+}
+
+func (fake *FakePortPool) Capacity() int {
+	fake.capacityMutex.Lock()
+	fake.capacityArgsForCall = append(fake.capacityArgsForCall, struct{}{})
+	fake.recordInvocation("Capacity", []interface{}{})
+	fake.capacityMutex.Unlock()
+	if fake.CapacityStub != nil {
+		return fake.CapacityStub()
+	} else {
+		return fake.capacityReturns.result1
+	}
+}
+
+func (fake *FakePortPool) CapacityCallCount() int {
+	fake.capacityMutex.RLock()
+	defer fake.capacityMutex.RUnlock()
+	return len(fake.capacityArgsForCall)
+}
+
+func (fake *FakePortPool) CapacityReturns(result1 int) {
+	fake.CapacityStub = nil
+	fake.capacityReturns = struct {
+		result1 int
+	}{result1}
+}
+
+func (fake *FakePortPool) Available() int {
+	fake.availableMutex.Lock()
+	fake.availableArgsForCall = append(fake.availableArgsForCall, struct{}{})
+	fake.recordInvocation("Available", []interface{}{})
+	fake.availableMutex.Unlock()
+	if fake.AvailableStub != nil {
+		return fake.AvailableStub()
+	} else {
+		return fake.availableReturns.result1
+	}
+}
+
+func (fake *FakePortPool) AvailableCallCount() int {
+	fake.availableMutex.RLock()
+	defer fake.availableMutex.RUnlock()
+	return len(fake.availableArgsForCall)
+}
+
+func (fake *FakePortPool) AvailableReturns(result1 int) {
+	fake.AvailableStub = nil
+	fake.availableReturns = struct {
+		result1 int
+	}{result1}
+}
+
+func (fake *FakePortPool) Invocations() map[string][][]interface{} {
+	fake.invocationsMutex.RLock()
+	defer fake.invocationsMutex.RUnlock()
+	fake.capacityMutex.RLock()
+	defer fake.capacityMutex.RUnlock()
+	fake.availableMutex.RLock()
+	defer fake.availableMutex.RUnlock()
+	return fake.invocations
+}
+
+func (fake *FakePortPool) recordInvocation(key string, args []interface{}) {
+	fake.invocationsMutex.Lock()
+	defer fake.invocationsMutex.Unlock()
+	if fake.invocations == nil {
+		fake.invocations = map[string][][]interface{}{}
+	}
+	if fake.invocations[key] == nil {
+		fake.invocations[key] = [][]interface{}{}
+	}
+	fake.invocations[key] = append(fake.invocations[key], args)
+}
+
+var _ metrics.PortPool = new(FakePortPool)
@@ -0,0 +1,99 @@
+// This file was generated by counterfeiter
+package metricsfakes
+
+import (
+	"sync"
+
+	"code.cloudfoundry.org/guardian/metrics"
+)
+
+type FakeSubnetPool struct {
+	CapacityStub        func() int
+	capacityMutex       sync.RWMutex
+	capacityArgsForCall []struct{}
+	capacityReturns     struct {
+		result1 int
+	}
+	AllocatedStub        func() int
+	allocatedMutex       sync.RWMutex
+	allocatedArgsForCall []struct{}
+	allocatedReturns     struct {
+		result1 int
+	}
+	invocations      map[string][][]interface{}
+	invocationsMutex sync.RWMutex
+}
+
+func (fake *FakeSubnetPool) Capacity() int {
+	fake.capacityMutex.Lock()
+	fake.capacityArgsForCall = append(fake.capacityArgsForCall, struct{}{})
+	fake.recordInvocation("Capacity", []interface{}{})
+	fake.capacityMutex.Unlock()
+	if fake.CapacityStub != nil {
+		return fake.CapacityStub()
+	} else {
+		return fake.capacityReturns.result1
+	}
+}
+
+func (fake *FakeSubnetPool) CapacityCallCount() int {
+	fake.capacityMutex.RLock()
+	defer fake.capacityMutex.RUnlock()
+	return len(fake.capacityArgsForCall)
+}
+
+func (fake *FakeSubnetPool) CapacityReturns(result1 int) {
+	fake.CapacityStub = nil
+	fake.capacityReturns = struct {
+		result1 int
+	}{result1}
+}
+
+func (fake *FakeSubnetPool) Allocated() int {
+	fake.allocatedMutex.Lock()
+	fake.allocatedArgsForCall = append(fake.allocatedArgsForCall, struct{}{})
+	fake.recordInvocation("Allocated", []interface{}{})
+	fake.allocatedMutex.Unlock()
+	if fake.AllocatedStub != nil {
+		return fake.AllocatedStub()
+	} else {
+		return fake.allocatedReturns.result1
+	}
+}
+
+func (fake *FakeSubnetPool) AllocatedCallCount() int {
+	fake.allocatedMutex.RLock()
+	defer fake.allocatedMutex.RUnlock()
+	return len(fake.allocatedArgsForCall)
+}
+
+func (fake *FakeSubnetPool) AllocatedReturns(result1 int) {
+	fake.AllocatedStub = nil
+	fake.allocatedReturns = struct {
+		result1 int
+	}{result1}
+}
+
+func (fake *FakeSubnetPool) Invocations() map[string][][]interface{} {
+	fake.invocationsMutex.RLock()
+	defer fake.invocationsMutex.RUnlock()
+	fake.capacityMutex.RLock()
+	defer fake.capacityMutex.RUnlock()
+	fake.allocatedMutex.RLock()
+	defer fake.allocatedMutex.RUnlock()
+	return fake.invocations
+}
+
+func (fake *FakeSubnetPool) recordInvocation(key string, args []interface{}) {
+	fake.invocationsMutex.Lock()
+	defer fake.invocationsMutex.Unlock()
+	if fake.invocations == nil {
+		fake.invocations = map[string][][]interface{}{}
+	}
+	if fake.invocations[key] == nil {
+		fake.invocations[key] = [][]interface{}{}
+	}
+	fake.invocations[key] = append(fake.invocations[key], args)
+}
+
+var _ metrics.SubnetPool = new(FakeSubnetPool)
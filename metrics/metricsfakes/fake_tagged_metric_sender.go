@@ -0,0 +1,82 @@
+// This file was generated by counterfeiter
+package metricsfakes
+
+import (
+	"sync"
+
+	"code.cloudfoundry.org/guardian/metrics"
+)
+
+type FakeTaggedMetricSender struct {
+	SendValueStub        func(name string, value float64, unit string, tags map[string]string) error
+	sendValueMutex       sync.RWMutex
+	sendValueArgsForCall []struct {
+		name  string
+		value float64
+		unit  string
+		tags  map[string]string
+	}
+	sendValueReturns struct {
+		result1 error
+	}
+	invocations      map[string][][]interface{}
+	invocationsMutex sync.RWMutex
+}
+
+func (fake *FakeTaggedMetricSender) SendValue(name string, value float64, unit string, tags map[string]string) error {
+	fake.sendValueMutex.Lock()
+	fake.sendValueArgsForCall = append(fake.sendValueArgsForCall, struct {
+		name  string
+		value float64
+		unit  string
+		tags  map[string]string
+	}{name, value, unit, tags})
+	fake.recordInvocation("SendValue", []interface{}{name, value, unit, tags})
+	fake.sendValueMutex.Unlock()
+	if fake.SendValueStub != nil {
+		return fake.SendValueStub(name, value, unit, tags)
+	} else {
+		return fake.sendValueReturns.result1
+	}
+}
+
+func (fake *FakeTaggedMetricSender) SendValueCallCount() int {
+	fake.sendValueMutex.RLock()
+	defer fake.sendValueMutex.RUnlock()
+	return len(fake.sendValueArgsForCall)
+}
+
+func (fake *FakeTaggedMetricSender) SendValueArgsForCall(i int) (string, float64, string, map[string]string) {
+	fake.sendValueMutex.RLock()
+	defer fake.sendValueMutex.RUnlock()
+	return fake.sendValueArgsForCall[i].name, fake.sendValueArgsForCall[i].value, fake.sendValueArgsForCall[i].unit, fake.sendValueArgsForCall[i].tags
+}
+
+func (fake *FakeTaggedMetricSender) SendValueReturns(result1 error) {
+	fake.SendValueStub = nil
+	fake.sendValueReturns = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *FakeTaggedMetricSender) Invocations() map[string][][]interface{} {
+	fake.invocationsMutex.RLock()
+	defer fake.invocationsMutex.RUnlock()
+	fake.sendValueMutex.RLock()
+	defer fake.sendValueMutex.RUnlock()
+	return fake.invocations
+}
+
+func (fake *FakeTaggedMetricSender) recordInvocation(key string, args []interface{}) {
+	fake.invocationsMutex.Lock()
+	defer fake.invocationsMutex.Unlock()
+	if fake.invocations == nil {
+		fake.invocations = map[string][][]interface{}{}
+	}
+	if fake.invocations[key] == nil {
+		fake.invocations[key] = [][]interface{}{}
+	}
+	fake.invocations[key] = append(fake.invocations[key], args)
+}
+
+var _ metrics.TaggedMetricSender = new(FakeTaggedMetricSender)
@@ -0,0 +1,321 @@
+package metrics
+
+import (
+	"io"
+	"sort"
+	"sync"
+	"time"
+
+	"code.cloudfoundry.org/garden"
+	"code.cloudfoundry.org/guardian/gardener"
+)
+
+const (
+	ResultSuccess = "success"
+	ResultFailure = "failure"
+)
+
+// OperationCount is how many times an API operation finished with a result
+type OperationCount struct {
+	Operation string
+	Result    string
+	Count     uint64
+}
+
+// OperationCounters counts the API operations served, by outcome
+type OperationCounters struct {
+	mu     sync.Mutex
+	counts map[OperationCount]uint64
+}
+
+func NewOperationCounters() *OperationCounters {
+	return &OperationCounters{counts: make(map[OperationCount]uint64)}
+}
+
+func (o *OperationCounters) Record(operation string, err error) {
+	result := ResultSuccess
+	if err != nil {
+		result = ResultFailure
+	}
+
+	o.mu.Lock()
+	defer o.mu.Unlock()
+
+	o.counts[OperationCount{Operation: operation, Result: result}]++
+}
+
+// Counts returns every count, ordered by operation and then result
+func (o *OperationCounters) Counts() []OperationCount {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+
+	counts := make(byOperation, 0, len(o.counts))
+	for key, count := range o.counts {
+		key.Count = count
+		counts = append(counts, key)
+	}
+
+	sort.Sort(counts)
+	return counts
+}
+
+type byOperation []OperationCount
+
+func (b byOperation) Len() int      { return len(b) }
+func (b byOperation) Swap(i, j int) { b[i], b[j] = b[j], b[i] }
+func (b byOperation) Less(i, j int) bool {
+	if b[i].Operation != b[j].Operation {
+		return b[i].Operation < b[j].Operation
+	}
+
+	return b[i].Result < b[j].Result
+}
+
+// NewInstrumentedBackend counts the operations served by backend, and by the
+// containers it returns, in counters
+func NewInstrumentedBackend(backend garden.Backend, counters *OperationCounters) garden.Backend {
+	return &instrumentedBackend{Backend: backend, counters: counters}
+}
+
+type instrumentedBackend struct {
+	garden.Backend
+	counters *OperationCounters
+}
+
+func (b *instrumentedBackend) wrap(container garden.Container) garden.Container {
+	if container == nil {
+		return nil
+	}
+
+	instrumented := &instrumentedContainer{Container: container, counters: b.counters}
+
+	// wrapping a container must not hide that it can be paused
+	if pausable, ok := container.(gardener.Pausable); ok {
+		return &instrumentedPausableContainer{instrumentedContainer: instrumented, pausable: pausable}
+	}
+
+	return instrumented
+}
+
+func (b *instrumentedBackend) Ping() error {
+	err := b.Backend.Ping()
+	b.counters.Record("ping", err)
+	return err
+}
+
+func (b *instrumentedBackend) Capacity() (garden.Capacity, error) {
+	capacity, err := b.Backend.Capacity()
+	b.counters.Record("capacity", err)
+	return capacity, err
+}
+
+func (b *instrumentedBackend) Create(spec garden.ContainerSpec) (garden.Container, error) {
+	container, err := b.Backend.Create(spec)
+	b.counters.Record("create", err)
+	return b.wrap(container), err
+}
+
+func (b *instrumentedBackend) Destroy(handle string) error {
+	err := b.Backend.Destroy(handle)
+	b.counters.Record("destroy", err)
+	return err
+}
+
+func (b *instrumentedBackend) Containers(props garden.Properties) ([]garden.Container, error) {
+	containers, err := b.Backend.Containers(props)
+	b.counters.Record("containers", err)
+
+	for i, container := range containers {
+		containers[i] = b.wrap(container)
+	}
+
+	return containers, err
+}
+
+func (b *instrumentedBackend) BulkInfo(handles []string) (map[string]garden.ContainerInfoEntry, error) {
+	infos, err := b.Backend.BulkInfo(handles)
+	b.counters.Record("bulk_info", err)
+	return infos, err
+}
+
+func (b *instrumentedBackend) BulkMetrics(handles []string) (map[string]garden.ContainerMetricsEntry, error) {
+	metrics, err := b.Backend.BulkMetrics(handles)
+	b.counters.Record("bulk_metrics", err)
+	return metrics, err
+}
+
+func (b *instrumentedBackend) Lookup(handle string) (garden.Container, error) {
+	container, err := b.Backend.Lookup(handle)
+	b.counters.Record("lookup", err)
+	return b.wrap(container), err
+}
+
+// GraceTime is asked by the server rather than by a client, so it is passed
+// the unwrapped container and not counted
+func (b *instrumentedBackend) GraceTime(container garden.Container) time.Duration {
+	if instrumented, ok := container.(*instrumentedContainer); ok {
+		container = instrumented.Container
+	}
+
+	return b.Backend.GraceTime(container)
+}
+
+type instrumentedContainer struct {
+	garden.Container
+	counters *OperationCounters
+}
+
+func (c *instrumentedContainer) Stop(kill bool) error {
+	err := c.Container.Stop(kill)
+	c.counters.Record("stop", err)
+	return err
+}
+
+func (c *instrumentedContainer) Info() (garden.ContainerInfo, error) {
+	info, err := c.Container.Info()
+	c.counters.Record("info", err)
+	return info, err
+}
+
+func (c *instrumentedContainer) StreamIn(spec garden.StreamInSpec) error {
+	err := c.Container.StreamIn(spec)
+	c.counters.Record("stream_in", err)
+	return err
+}
+
+func (c *instrumentedContainer) StreamOut(spec garden.StreamOutSpec) (io.ReadCloser, error) {
+	stream, err := c.Container.StreamOut(spec)
+	c.counters.Record("stream_out", err)
+	return stream, err
+}
+
+func (c *instrumentedContainer) LimitBandwidth(limits garden.BandwidthLimits) error {
+	err := c.Container.LimitBandwidth(limits)
+	c.counters.Record("limit_bandwidth", err)
+	return err
+}
+
+func (c *instrumentedContainer) LimitCPU(limits garden.CPULimits) error {
+	err := c.Container.LimitCPU(limits)
+	c.counters.Record("limit_cpu", err)
+	return err
+}
+
+func (c *instrumentedContainer) LimitDisk(limits garden.DiskLimits) error {
+	err := c.Container.LimitDisk(limits)
+	c.counters.Record("limit_disk", err)
+	return err
+}
+
+func (c *instrumentedContainer) LimitMemory(limits garden.MemoryLimits) error {
+	err := c.Container.LimitMemory(limits)
+	c.counters.Record("limit_memory", err)
+	return err
+}
+
+func (c *instrumentedContainer) CurrentBandwidthLimits() (garden.BandwidthLimits, error) {
+	limits, err := c.Container.CurrentBandwidthLimits()
+	c.counters.Record("current_bandwidth_limits", err)
+	return limits, err
+}
+
+func (c *instrumentedContainer) CurrentCPULimits() (garden.CPULimits, error) {
+	limits, err := c.Container.CurrentCPULimits()
+	c.counters.Record("current_cpu_limits", err)
+	return limits, err
+}
+
+func (c *instrumentedContainer) CurrentDiskLimits() (garden.DiskLimits, error) {
+	limits, err := c.Container.CurrentDiskLimits()
+	c.counters.Record("current_disk_limits", err)
+	return limits, err
+}
+
+func (c *instrumentedContainer) CurrentMemoryLimits() (garden.MemoryLimits, error) {
+	limits, err := c.Container.CurrentMemoryLimits()
+	c.counters.Record("current_memory_limits", err)
+	return limits, err
+}
+
+func (c *instrumentedContainer) NetIn(hostPort, containerPort uint32) (uint32, uint32, error) {
+	actualHostPort, actualContainerPort, err := c.Container.NetIn(hostPort, containerPort)
+	c.counters.Record("net_in", err)
+	return actualHostPort, actualContainerPort, err
+}
+
+func (c *instrumentedContainer) NetOut(netOutRule garden.NetOutRule) error {
+	err := c.Container.NetOut(netOutRule)
+	c.counters.Record("net_out", err)
+	return err
+}
+
+func (c *instrumentedContainer) BulkNetOut(netOutRules []garden.NetOutRule) error {
+	err := c.Container.BulkNetOut(netOutRules)
+	c.counters.Record("bulk_net_out", err)
+	return err
+}
+
+func (c *instrumentedContainer) Run(spec garden.ProcessSpec, io garden.ProcessIO) (garden.Process, error) {
+	process, err := c.Container.Run(spec, io)
+	c.counters.Record("run", err)
+	return process, err
+}
+
+func (c *instrumentedContainer) Attach(processID string, io garden.ProcessIO) (garden.Process, error) {
+	process, err := c.Container.Attach(processID, io)
+	c.counters.Record("attach", err)
+	return process, err
+}
+
+func (c *instrumentedContainer) Metrics() (garden.Metrics, error) {
+	metrics, err := c.Container.Metrics()
+	c.counters.Record("metrics", err)
+	return metrics, err
+}
+
+func (c *instrumentedContainer) SetGraceTime(graceTime time.Duration) error {
+	err := c.Container.SetGraceTime(graceTime)
+	c.counters.Record("set_grace_time", err)
+	return err
+}
+
+func (c *instrumentedContainer) Properties() (garden.Properties, error) {
+	properties, err := c.Container.Properties()
+	c.counters.Record("properties", err)
+	return properties, err
+}
+
+func (c *instrumentedContainer) Property(name string) (string, error) {
+	value, err := c.Container.Property(name)
+	c.counters.Record("property", err)
+	return value, err
+}
+
+func (c *instrumentedContainer) SetProperty(name string, value string) error {
+	err := c.Container.SetProperty(name, value)
+	c.counters.Record("set_property", err)
+	return err
+}
+
+func (c *instrumentedContainer) RemoveProperty(name string) error {
+	err := c.Container.RemoveProperty(name)
+	c.counters.Record("remove_property", err)
+	return err
+}
+
+type instrumentedPausableContainer struct {
+	*instrumentedContainer
+	pausable gardener.Pausable
+}
+
+func (c *instrumentedPausableContainer) Pause() error {
+	err := c.pausable.Pause()
+	c.counters.Record("pause", err)
+	return err
+}
+
+func (c *instrumentedPausableContainer) Resume() error {
+	err := c.pausable.Resume()
+	c.counters.Record("resume", err)
+	return err
+}
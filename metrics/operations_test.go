@@ -0,0 +1,161 @@
+package metrics_test
+
+import (
+	"errors"
+	"time"
+
+	"code.cloudfoundry.org/garden"
+	"code.cloudfoundry.org/garden/gardenfakes"
+	"code.cloudfoundry.org/guardian/gardener"
+	"code.cloudfoundry.org/guardian/metrics"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Instrumented backend", func() {
+	var (
+		backend       *gardenfakes.FakeBackend
+		fakeContainer *gardenfakes.FakeContainer
+		counters      *metrics.OperationCounters
+		instrumented  garden.Backend
+	)
+
+	BeforeEach(func() {
+		backend = new(gardenfakes.FakeBackend)
+		fakeContainer = new(gardenfakes.FakeContainer)
+		fakeContainer.HandleReturns("banana")
+		backend.CreateReturns(fakeContainer, nil)
+		backend.LookupReturns(fakeContainer, nil)
+		backend.ContainersReturns([]garden.Container{fakeContainer}, nil)
+
+		counters = metrics.NewOperationCounters()
+		instrumented = metrics.NewInstrumentedBackend(backend, counters)
+	})
+
+	It("counts successful and failed backend operations", func() {
+		_, err := instrumented.Create(garden.ContainerSpec{Handle: "banana"})
+		Expect(err).NotTo(HaveOccurred())
+
+		backend.DestroyReturns(errors.New("boom"))
+		Expect(instrumented.Destroy("banana")).To(MatchError("boom"))
+
+		Expect(counters.Counts()).To(Equal([]metrics.OperationCount{
+			{Operation: "create", Result: metrics.ResultSuccess, Count: 1},
+			{Operation: "destroy", Result: metrics.ResultFailure, Count: 1},
+		}))
+	})
+
+	It("counts the operations of the containers it returns", func() {
+		container, err := instrumented.Lookup("banana")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(container.Handle()).To(Equal("banana"))
+
+		fakeContainer.StopReturns(errors.New("boom"))
+		Expect(container.Stop(true)).To(MatchError("boom"))
+		Expect(fakeContainer.StopArgsForCall(0)).To(BeTrue())
+
+		containers, err := instrumented.Containers(nil)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(containers[0].SetProperty("foo", "bar")).To(Succeed())
+
+		Expect(counters.Counts()).To(Equal([]metrics.OperationCount{
+			{Operation: "containers", Result: metrics.ResultSuccess, Count: 1},
+			{Operation: "lookup", Result: metrics.ResultSuccess, Count: 1},
+			{Operation: "set_property", Result: metrics.ResultSuccess, Count: 1},
+			{Operation: "stop", Result: metrics.ResultFailure, Count: 1},
+		}))
+	})
+
+	It("counts changes to the limits of the containers it returns", func() {
+		container, err := instrumented.Lookup("banana")
+		Expect(err).NotTo(HaveOccurred())
+
+		fakeContainer.LimitMemoryReturns(errors.New("too-small"))
+		Expect(container.LimitBandwidth(garden.BandwidthLimits{})).To(Succeed())
+		Expect(container.LimitCPU(garden.CPULimits{})).To(Succeed())
+		Expect(container.LimitDisk(garden.DiskLimits{})).To(Succeed())
+		Expect(container.LimitMemory(garden.MemoryLimits{})).To(MatchError("too-small"))
+		Expect(fakeContainer.LimitMemoryCallCount()).To(Equal(1))
+
+		Expect(counters.Counts()).To(Equal([]metrics.OperationCount{
+			{Operation: "limit_bandwidth", Result: metrics.ResultSuccess, Count: 1},
+			{Operation: "limit_cpu", Result: metrics.ResultSuccess, Count: 1},
+			{Operation: "limit_disk", Result: metrics.ResultSuccess, Count: 1},
+			{Operation: "limit_memory", Result: metrics.ResultFailure, Count: 1},
+			{Operation: "lookup", Result: metrics.ResultSuccess, Count: 1},
+		}))
+	})
+
+	It("does not make containers which cannot be paused pausable", func() {
+		container, err := instrumented.Lookup("banana")
+		Expect(err).NotTo(HaveOccurred())
+
+		_, ok := container.(gardener.Pausable)
+		Expect(ok).To(BeFalse())
+	})
+
+	Context("when the container can be paused", func() {
+		var pausable *pausableContainer
+
+		BeforeEach(func() {
+			pausable = &pausableContainer{FakeContainer: fakeContainer, resumeErr: errors.New("still-frozen")}
+			backend.LookupReturns(pausable, nil)
+		})
+
+		It("counts pauses and resumes", func() {
+			container, err := instrumented.Lookup("banana")
+			Expect(err).NotTo(HaveOccurred())
+
+			paused, ok := container.(gardener.Pausable)
+			Expect(ok).To(BeTrue())
+
+			Expect(paused.Pause()).To(Succeed())
+			Expect(paused.Resume()).To(MatchError("still-frozen"))
+			Expect(pausable.pauses).To(Equal(1))
+			Expect(pausable.resumes).To(Equal(1))
+
+			Expect(counters.Counts()).To(Equal([]metrics.OperationCount{
+				{Operation: "lookup", Result: metrics.ResultSuccess, Count: 1},
+				{Operation: "pause", Result: metrics.ResultSuccess, Count: 1},
+				{Operation: "resume", Result: metrics.ResultFailure, Count: 1},
+			}))
+		})
+	})
+
+	It("does not wrap a container which was not found", func() {
+		backend.LookupReturns(nil, errors.New("not found"))
+
+		container, err := instrumented.Lookup("potato")
+		Expect(err).To(MatchError("not found"))
+		Expect(container).To(BeNil())
+	})
+
+	It("passes the original container to GraceTime without counting it", func() {
+		backend.GraceTimeReturns(time.Minute)
+
+		container, err := instrumented.Lookup("banana")
+		Expect(err).NotTo(HaveOccurred())
+
+		Expect(instrumented.GraceTime(container)).To(Equal(time.Minute))
+		Expect(backend.GraceTimeArgsForCall(0)).To(Equal(fakeContainer))
+		Expect(counters.Counts()).To(HaveLen(1))
+	})
+})
+
+type pausableContainer struct {
+	*gardenfakes.FakeContainer
+
+	pauses, resumes int
+	resumeErr       error
+}
+
+func (c *pausableContainer) Pause() error {
+	c.pauses++
+	return nil
+}
+
+func (c *pausableContainer) Resume() error {
+	c.resumes++
+	return c.resumeErr
+}
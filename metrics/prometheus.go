@@ -0,0 +1,311 @@
+package metrics
+
+import (
+	"bufio"
+	"fmt"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"code.cloudfoundry.org/guardian/gardener"
+	"code.cloudfoundry.org/lager"
+)
+
+//go:generate counterfeiter . SubnetPool
+
+type SubnetPool interface {
+	Capacity() int
+	Allocated() int
+}
+
+//go:generate counterfeiter . PortPool
+
+type PortPool interface {
+	Capacity() int
+	Available() int
+}
+
+// Prometheus serves the metrics of guardian and its containers in the
+// Prometheus text exposition format. Sources which are nil are left out.
+type Prometheus struct {
+	Logger     lager.Logger
+	Metrics    Metrics
+	DrainState DrainState
+	Containers ContainerMetricsSource
+	SubnetPool SubnetPool
+	PortPool   PortPool
+	Operations *OperationCounters
+	Stages     *StageHistograms
+}
+
+func (p *Prometheus) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "text/plain; version=0.0.4")
+
+	buf := bufio.NewWriter(w)
+	defer buf.Flush()
+
+	e := &exposition{w: buf}
+
+	if p.Metrics != nil {
+		e.gauge("guardian_cpus", "Number of CPUs on the host.", float64(p.Metrics.NumCPU()))
+		e.gauge("guardian_goroutines", "Number of goroutines in guardian.", float64(p.Metrics.NumGoroutine()))
+		e.gauge("guardian_loop_devices", "Number of loop devices in use.", float64(p.Metrics.LoopDevices()))
+		e.gauge("guardian_backing_stores", "Number of backing store files.", float64(p.Metrics.BackingStores()))
+		e.gauge("guardian_depot_dirs", "Number of container bundles in the depot.", float64(p.Metrics.DepotDirs()))
+	}
+
+	if p.DrainState != nil {
+		draining := 0.0
+		if p.DrainState.Draining() {
+			draining = 1
+		}
+
+		e.gauge("guardian_draining", "Whether guardian is draining, 1 if so.", draining)
+		e.gauge("guardian_in_flight_operations", "Number of container operations in progress.", float64(p.DrainState.InFlightOperations()))
+	}
+
+	if p.SubnetPool != nil {
+		e.gauge("guardian_subnet_pool_capacity", "Number of subnets which can be allocated dynamically.", float64(p.SubnetPool.Capacity()))
+		e.gauge("guardian_subnet_pool_allocated", "Number of subnets in use.", float64(p.SubnetPool.Allocated()))
+	}
+
+	if p.PortPool != nil {
+		e.gauge("guardian_port_pool_capacity", "Number of ports in the port pool.", float64(p.PortPool.Capacity()))
+		e.gauge("guardian_port_pool_available", "Number of ports which can still be mapped.", float64(p.PortPool.Available()))
+	}
+
+	if p.Operations != nil {
+		p.writeOperations(e)
+	}
+
+	if p.Stages != nil {
+		p.writeStages(e)
+	}
+
+	if p.Containers != nil {
+		p.writeContainers(e)
+	}
+}
+
+func (p *Prometheus) writeOperations(e *exposition) {
+	e.family("guardian_api_operations_total", "Number of API operations served, by result.", "counter")
+	for _, count := range p.Operations.Counts() {
+		e.sample("guardian_api_operations_total", []string{"operation", count.Operation, "result", count.Result}, float64(count.Count))
+	}
+}
+
+func (p *Prometheus) writeStages(e *exposition) {
+	histograms := p.Stages.Histograms()
+	bounds := p.Stages.Bounds()
+
+	e.family("guardian_stage_duration_seconds", "Time taken by each stage of an operation.", "histogram")
+	for _, h := range histograms {
+		for i, bound := range bounds {
+			e.sample("guardian_stage_duration_seconds_bucket", []string{"operation", h.Operation, "stage", h.Stage, "le", strconv.FormatFloat(bound, 'g', -1, 64)}, float64(h.Buckets[i]))
+		}
+		e.sample("guardian_stage_duration_seconds_bucket", []string{"operation", h.Operation, "stage", h.Stage, "le", "+Inf"}, float64(h.Count))
+		e.sample("guardian_stage_duration_seconds_sum", []string{"operation", h.Operation, "stage", h.Stage}, h.Sum)
+		e.sample("guardian_stage_duration_seconds_count", []string{"operation", h.Operation, "stage", h.Stage}, float64(h.Count))
+	}
+
+	e.family("guardian_stage_errors_total", "Number of times each stage of an operation failed.", "counter")
+	for _, h := range histograms {
+		e.sample("guardian_stage_errors_total", []string{"operation", h.Operation, "stage", h.Stage}, float64(h.Errors))
+	}
+}
+
+type containerFamily struct {
+	name  string
+	help  string
+	kind  string
+	value func(gardener.ExtendedMetrics) float64
+}
+
+var containerFamilies = []containerFamily{
+	{"guardian_container_cpu_usage_seconds_total", "CPU time used by the container.", "counter",
+		func(m gardener.ExtendedMetrics) float64 { return float64(m.CPUStat.Usage) / float64(time.Second) }},
+	{"guardian_container_cpu_percent", "CPU used by the container over the last sample interval, where 100 is one core.", "gauge",
+		func(m gardener.ExtendedMetrics) float64 { return m.CPURateStat.Percent }},
+	{"guardian_container_memory_usage_bytes", "Memory counted toward the container's limit.", "gauge",
+		func(m gardener.ExtendedMetrics) float64 { return float64(m.MemoryStat.TotalUsageTowardLimit) }},
+	{"guardian_container_memory_rss_bytes", "Resident memory of the container.", "gauge",
+		func(m gardener.ExtendedMetrics) float64 { return float64(m.MemoryStat.TotalRss) }},
+	{"guardian_container_disk_usage_bytes", "Disk used by the container.", "gauge",
+		func(m gardener.ExtendedMetrics) float64 { return float64(m.DiskStat.TotalBytesUsed) }},
+	{"guardian_container_pids", "Number of processes and threads in the container.", "gauge",
+		func(m gardener.ExtendedMetrics) float64 { return float64(m.PidStat.Current) }},
+	{"guardian_container_pids_limit", "Limit on the processes and threads of the container, 0 if there is none.", "gauge",
+		func(m gardener.ExtendedMetrics) float64 { return float64(m.PidStat.Max) }},
+	{"guardian_container_cpu_periods_total", "CPU scheduling periods the container was runnable in.", "counter",
+		func(m gardener.ExtendedMetrics) float64 { return float64(m.ThrottlingStat.Periods) }},
+	{"guardian_container_cpu_throttled_periods_total", "CPU scheduling periods in which the container was throttled.", "counter",
+		func(m gardener.ExtendedMetrics) float64 { return float64(m.ThrottlingStat.ThrottledPeriods) }},
+	{"guardian_container_cpu_throttled_seconds_total", "Time for which the container was throttled.", "counter",
+		func(m gardener.ExtendedMetrics) float64 {
+			return float64(m.ThrottlingStat.ThrottledTime) / float64(time.Second)
+		}},
+}
+
+// labelledValue is one sample of a family with a sample per block device or
+// huge page size of each container, or with a sample only when it is available
+type labelledValue struct {
+	labels []string
+	value  float64
+}
+
+type labelledContainerFamily struct {
+	name   string
+	help   string
+	kind   string
+	values func(gardener.ExtendedMetrics) []labelledValue
+}
+
+var labelledContainerFamilies = []labelledContainerFamily{
+	{"guardian_container_network_receive_bytes_total", "Bytes received by the container.", "counter",
+		networkValue(func(s gardener.ContainerNetworkStat) uint64 { return s.RxBytes })},
+	{"guardian_container_network_transmit_bytes_total", "Bytes sent by the container.", "counter",
+		networkValue(func(s gardener.ContainerNetworkStat) uint64 { return s.TxBytes })},
+	{"guardian_container_network_receive_packets_total", "Packets received by the container.", "counter",
+		networkValue(func(s gardener.ContainerNetworkStat) uint64 { return s.RxPackets })},
+	{"guardian_container_network_transmit_packets_total", "Packets sent by the container.", "counter",
+		networkValue(func(s gardener.ContainerNetworkStat) uint64 { return s.TxPackets })},
+	{"guardian_container_blkio_read_bytes_total", "Bytes read by the container from each block device.", "counter",
+		blockIOValues(func(s gardener.ContainerBlockIOStat) uint64 { return s.ReadBytes })},
+	{"guardian_container_blkio_write_bytes_total", "Bytes written by the container to each block device.", "counter",
+		blockIOValues(func(s gardener.ContainerBlockIOStat) uint64 { return s.WriteBytes })},
+	{"guardian_container_blkio_reads_total", "Reads by the container from each block device.", "counter",
+		blockIOValues(func(s gardener.ContainerBlockIOStat) uint64 { return s.ReadOps })},
+	{"guardian_container_blkio_writes_total", "Writes by the container to each block device.", "counter",
+		blockIOValues(func(s gardener.ContainerBlockIOStat) uint64 { return s.WriteOps })},
+	{"guardian_container_hugetlb_usage_bytes", "Huge pages used by the container, by page size.", "gauge",
+		hugetlbValues(func(s gardener.ContainerHugetlbStat) uint64 { return s.Usage })},
+	{"guardian_container_hugetlb_max_usage_bytes", "Most huge pages used by the container, by page size.", "gauge",
+		hugetlbValues(func(s gardener.ContainerHugetlbStat) uint64 { return s.MaxUsage })},
+	{"guardian_container_hugetlb_failures_total", "Huge page allocations of the container which hit its limit, by page size.", "counter",
+		hugetlbValues(func(s gardener.ContainerHugetlbStat) uint64 { return s.Failcnt })},
+}
+
+// networkValue leaves out the containers whose network traffic is
+// unavailable, rather than report it as zero
+func networkValue(value func(gardener.ContainerNetworkStat) uint64) func(gardener.ExtendedMetrics) []labelledValue {
+	return func(m gardener.ExtendedMetrics) []labelledValue {
+		if m.NetworkTraffic == nil {
+			return nil
+		}
+
+		return []labelledValue{{nil, float64(value(*m.NetworkTraffic))}}
+	}
+}
+
+func blockIOValues(value func(gardener.ContainerBlockIOStat) uint64) func(gardener.ExtendedMetrics) []labelledValue {
+	return func(m gardener.ExtendedMetrics) []labelledValue {
+		values := make([]labelledValue, 0, len(m.BlockIOStats))
+		for _, stat := range m.BlockIOStats {
+			device := fmt.Sprintf("%d:%d", stat.Major, stat.Minor)
+			values = append(values, labelledValue{[]string{"device", device}, float64(value(stat))})
+		}
+
+		return values
+	}
+}
+
+func hugetlbValues(value func(gardener.ContainerHugetlbStat) uint64) func(gardener.ExtendedMetrics) []labelledValue {
+	return func(m gardener.ExtendedMetrics) []labelledValue {
+		pageSizes := make([]string, 0, len(m.HugetlbStats))
+		for pageSize := range m.HugetlbStats {
+			pageSizes = append(pageSizes, pageSize)
+		}
+		sort.Strings(pageSizes)
+
+		values := make([]labelledValue, 0, len(pageSizes))
+		for _, pageSize := range pageSizes {
+			values = append(values, labelledValue{[]string{"page_size", pageSize}, float64(value(m.HugetlbStats[pageSize]))})
+		}
+
+		return values
+	}
+}
+
+func (p *Prometheus) writeContainers(e *exposition) {
+	containers, err := p.Containers.Containers(nil)
+	if err != nil {
+		p.Logger.Error("prometheus-list-containers-failed", err)
+		return
+	}
+
+	handles := make([]string, 0, len(containers))
+	for _, container := range containers {
+		handles = append(handles, container.Handle())
+	}
+
+	entries, err := p.Containers.BulkExtendedMetrics(handles)
+	if err != nil {
+		p.Logger.Error("prometheus-bulk-metrics-failed", err)
+		return
+	}
+
+	for _, family := range containerFamilies {
+		e.family(family.name, family.help, family.kind)
+		for _, handle := range handles {
+			entry, ok := entries[handle]
+			if !ok || entry.Err != nil {
+				continue
+			}
+
+			e.sample(family.name, []string{"handle", handle}, family.value(entry.Metrics))
+		}
+	}
+
+	for _, family := range labelledContainerFamilies {
+		e.family(family.name, family.help, family.kind)
+		for _, handle := range handles {
+			entry, ok := entries[handle]
+			if !ok || entry.Err != nil {
+				continue
+			}
+
+			for _, v := range family.values(entry.Metrics) {
+				e.sample(family.name, append([]string{"handle", handle}, v.labels...), v.value)
+			}
+		}
+	}
+}
+
+// exposition writes metric families in the Prometheus text format
+type exposition struct {
+	w *bufio.Writer
+}
+
+func (e *exposition) gauge(name, help string, value float64) {
+	e.family(name, help, "gauge")
+	e.sample(name, nil, value)
+}
+
+func (e *exposition) family(name, help, kind string) {
+	fmt.Fprintf(e.w, "# HELP %s %s\n", name, help)
+	fmt.Fprintf(e.w, "# TYPE %s %s\n", name, kind)
+}
+
+// sample writes one value, where labels alternate between names and values
+func (e *exposition) sample(name string, labels []string, value float64) {
+	e.w.WriteString(name)
+
+	if len(labels) > 0 {
+		pairs := make([]string, 0, len(labels)/2)
+		for i := 0; i+1 < len(labels); i += 2 {
+			pairs = append(pairs, fmt.Sprintf(`%s="%s"`, labels[i], escapeLabelValue(labels[i+1])))
+		}
+
+		e.w.WriteString("{" + strings.Join(pairs, ",") + "}")
+	}
+
+	e.w.WriteString(" " + strconv.FormatFloat(value, 'g', -1, 64) + "\n")
+}
+
+var labelValueEscaper = strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`)
+
+func escapeLabelValue(value string) string {
+	return labelValueEscaper.Replace(value)
+}
@@ -0,0 +1,213 @@
+package metrics_test
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"time"
+
+	"code.cloudfoundry.org/garden"
+	"code.cloudfoundry.org/garden/gardenfakes"
+	"code.cloudfoundry.org/guardian/gardener"
+	"code.cloudfoundry.org/guardian/metrics"
+	fakes "code.cloudfoundry.org/guardian/metrics/metricsfakes"
+	"code.cloudfoundry.org/lager/lagertest"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Prometheus", func() {
+	var (
+		prometheus *metrics.Prometheus
+		recorder   *httptest.ResponseRecorder
+	)
+
+	scrape := func() string {
+		recorder = httptest.NewRecorder()
+		request, err := http.NewRequest("GET", "/metrics", nil)
+		Expect(err).NotTo(HaveOccurred())
+
+		prometheus.ServeHTTP(recorder, request)
+		return recorder.Body.String()
+	}
+
+	BeforeEach(func() {
+		prometheus = &metrics.Prometheus{Logger: lagertest.NewTestLogger("test")}
+	})
+
+	It("serves the text format", func() {
+		scrape()
+		Expect(recorder.Code).To(Equal(http.StatusOK))
+		Expect(recorder.Header().Get("Content-Type")).To(Equal("text/plain; version=0.0.4"))
+	})
+
+	It("leaves out the sources which are not configured", func() {
+		Expect(scrape()).To(BeEmpty())
+	})
+
+	It("reports the host metrics and the drain state as gauges", func() {
+		fakeMetrics := new(fakes.FakeMetrics)
+		fakeMetrics.NumCPUReturns(11)
+		fakeMetrics.LoopDevicesReturns(33)
+		fakeMetrics.DepotDirsReturns(3)
+		prometheus.Metrics = fakeMetrics
+
+		fakeDrainState := new(fakes.FakeDrainState)
+		fakeDrainState.DrainingReturns(true)
+		fakeDrainState.InFlightOperationsReturns(2)
+		prometheus.DrainState = fakeDrainState
+
+		body := scrape()
+		Expect(body).To(ContainSubstring("# TYPE guardian_cpus gauge\nguardian_cpus 11\n"))
+		Expect(body).To(ContainSubstring("\nguardian_loop_devices 33\n"))
+		Expect(body).To(ContainSubstring("\nguardian_depot_dirs 3\n"))
+		Expect(body).To(ContainSubstring("\nguardian_draining 1\n"))
+		Expect(body).To(ContainSubstring("\nguardian_in_flight_operations 2\n"))
+	})
+
+	It("reports the usage of the subnet and port pools", func() {
+		subnetPool := new(fakes.FakeSubnetPool)
+		subnetPool.CapacityReturns(64)
+		subnetPool.AllocatedReturns(5)
+		prometheus.SubnetPool = subnetPool
+
+		portPool := new(fakes.FakePortPool)
+		portPool.CapacityReturns(5000)
+		portPool.AvailableReturns(4990)
+		prometheus.PortPool = portPool
+
+		body := scrape()
+		Expect(body).To(ContainSubstring("\nguardian_subnet_pool_capacity 64\n"))
+		Expect(body).To(ContainSubstring("\nguardian_subnet_pool_allocated 5\n"))
+		Expect(body).To(ContainSubstring("\nguardian_port_pool_capacity 5000\n"))
+		Expect(body).To(ContainSubstring("\nguardian_port_pool_available 4990\n"))
+	})
+
+	It("counts the API operations by result", func() {
+		operations := metrics.NewOperationCounters()
+		operations.Record("create", nil)
+		operations.Record("create", nil)
+		operations.Record("create", errors.New("boom"))
+		operations.Record("destroy", nil)
+		prometheus.Operations = operations
+
+		Expect(scrape()).To(ContainSubstring(`# TYPE guardian_api_operations_total counter
+guardian_api_operations_total{operation="create",result="failure"} 1
+guardian_api_operations_total{operation="create",result="success"} 2
+guardian_api_operations_total{operation="destroy",result="success"} 1
+`))
+	})
+
+	It("reports the duration of each stage as a histogram, and its errors", func() {
+		stages := metrics.NewStageHistograms([]float64{0.5, 1})
+		stages.RecordStage("create", "network", 250*time.Millisecond, nil)
+		stages.RecordStage("create", "network", 2*time.Second, errors.New("boom"))
+		prometheus.Stages = stages
+
+		body := scrape()
+		Expect(body).To(ContainSubstring(`# TYPE guardian_stage_duration_seconds histogram
+guardian_stage_duration_seconds_bucket{operation="create",stage="network",le="0.5"} 1
+guardian_stage_duration_seconds_bucket{operation="create",stage="network",le="1"} 1
+guardian_stage_duration_seconds_bucket{operation="create",stage="network",le="+Inf"} 2
+guardian_stage_duration_seconds_sum{operation="create",stage="network"} 2.25
+guardian_stage_duration_seconds_count{operation="create",stage="network"} 2
+`))
+		Expect(body).To(ContainSubstring(`guardian_stage_errors_total{operation="create",stage="network"} 1`))
+	})
+
+	Describe("container metrics", func() {
+		var source *fakes.FakeContainerMetricsSource
+
+		BeforeEach(func() {
+			container := func(handle string) garden.Container {
+				c := new(gardenfakes.FakeContainer)
+				c.HandleReturns(handle)
+				return c
+			}
+
+			source = new(fakes.FakeContainerMetricsSource)
+			source.ContainersReturns([]garden.Container{container("banana"), container(`bad"handle`), container("broken")}, nil)
+			source.BulkExtendedMetricsReturns(map[string]gardener.ExtendedMetricsEntry{
+				"banana": {
+					Metrics: gardener.ExtendedMetrics{
+						Metrics: garden.Metrics{
+							CPUStat:    garden.ContainerCPUStat{Usage: 1500000000},
+							MemoryStat: garden.ContainerMemoryStat{TotalUsageTowardLimit: 2048},
+							DiskStat:   garden.ContainerDiskStat{TotalBytesUsed: 4096},
+						},
+						CPURateStat:    gardener.ContainerCPURateStat{Percent: 12.5},
+						NetworkTraffic: &gardener.ContainerNetworkStat{RxBytes: 7},
+						PidStat:        gardener.ContainerPidStat{Current: 3, Max: 100},
+						ThrottlingStat: gardener.ContainerCPUThrottlingStat{Periods: 10, ThrottledPeriods: 4, ThrottledTime: 500000000},
+						BlockIOStats: []gardener.ContainerBlockIOStat{
+							{Major: 8, Minor: 0, ReadBytes: 512, WriteBytes: 1024, ReadOps: 1, WriteOps: 2},
+						},
+						HugetlbStats: map[string]gardener.ContainerHugetlbStat{
+							"2MB": {Usage: 2097152, MaxUsage: 4194304, Failcnt: 1},
+						},
+					},
+				},
+				`bad"handle`: {},
+				"broken":     {Err: garden.NewError("potato")},
+			}, nil)
+
+			prometheus.Containers = source
+		})
+
+		It("reports the usage of each container, labelled with its handle", func() {
+			body := scrape()
+			Expect(body).To(ContainSubstring("# TYPE guardian_container_cpu_usage_seconds_total counter\nguardian_container_cpu_usage_seconds_total{handle=\"banana\"} 1.5\n"))
+			Expect(body).To(ContainSubstring("\nguardian_container_cpu_percent{handle=\"banana\"} 12.5\n"))
+			Expect(body).To(ContainSubstring("\nguardian_container_memory_usage_bytes{handle=\"banana\"} 2048\n"))
+			Expect(body).To(ContainSubstring("\nguardian_container_disk_usage_bytes{handle=\"banana\"} 4096\n"))
+			Expect(body).To(ContainSubstring("\nguardian_container_network_receive_bytes_total{handle=\"banana\"} 7\n"))
+		})
+
+		It("reports the pids and CPU throttling of each container", func() {
+			body := scrape()
+			Expect(body).To(ContainSubstring("\nguardian_container_pids{handle=\"banana\"} 3\n"))
+			Expect(body).To(ContainSubstring("\nguardian_container_pids_limit{handle=\"banana\"} 100\n"))
+			Expect(body).To(ContainSubstring("\nguardian_container_cpu_periods_total{handle=\"banana\"} 10\n"))
+			Expect(body).To(ContainSubstring("\nguardian_container_cpu_throttled_periods_total{handle=\"banana\"} 4\n"))
+			Expect(body).To(ContainSubstring("\nguardian_container_cpu_throttled_seconds_total{handle=\"banana\"} 0.5\n"))
+		})
+
+		It("reports the block IO of each container by device, and its huge pages by page size", func() {
+			body := scrape()
+			Expect(body).To(ContainSubstring("\nguardian_container_blkio_read_bytes_total{handle=\"banana\",device=\"8:0\"} 512\n"))
+			Expect(body).To(ContainSubstring("\nguardian_container_blkio_write_bytes_total{handle=\"banana\",device=\"8:0\"} 1024\n"))
+			Expect(body).To(ContainSubstring("\nguardian_container_blkio_reads_total{handle=\"banana\",device=\"8:0\"} 1\n"))
+			Expect(body).To(ContainSubstring("\nguardian_container_blkio_writes_total{handle=\"banana\",device=\"8:0\"} 2\n"))
+			Expect(body).To(ContainSubstring("\nguardian_container_hugetlb_usage_bytes{handle=\"banana\",page_size=\"2MB\"} 2.097152e+06\n"))
+			Expect(body).To(ContainSubstring("\nguardian_container_hugetlb_failures_total{handle=\"banana\",page_size=\"2MB\"} 1\n"))
+		})
+
+		It("escapes label values", func() {
+			Expect(scrape()).To(ContainSubstring(`guardian_container_memory_usage_bytes{handle="bad\"handle"} 0`))
+		})
+
+		It("leaves out the network traffic of containers for which it is unavailable", func() {
+			Expect(scrape()).NotTo(ContainSubstring(`guardian_container_network_receive_bytes_total{handle="bad\"handle"}`))
+		})
+
+		It("leaves out containers whose metrics could not be read", func() {
+			Expect(scrape()).NotTo(ContainSubstring("broken"))
+		})
+
+		Context("when the containers cannot be listed", func() {
+			BeforeEach(func() {
+				source.ContainersReturns(nil, errors.New("boom"))
+			})
+
+			It("reports the other metrics", func() {
+				prometheus.Operations = metrics.NewOperationCounters()
+				prometheus.Operations.Record("ping", nil)
+
+				body := scrape()
+				Expect(body).To(ContainSubstring("guardian_api_operations_total"))
+				Expect(body).NotTo(ContainSubstring("guardian_container_"))
+			})
+		})
+	})
+})
@@ -0,0 +1,104 @@
+package metrics
+
+import (
+	"sort"
+	"sync"
+	"time"
+)
+
+// DefaultStageBuckets are the upper bounds, in seconds, of the buckets of
+// stage duration histograms
+var DefaultStageBuckets = []float64{0.005, 0.01, 0.025, 0.05, 0.1, 0.25, 0.5, 1, 2.5, 5, 10, 30, 60}
+
+// StageKey identifies a stage of an operation
+type StageKey struct {
+	Operation string
+	Stage     string
+}
+
+// StageHistogram is the distribution of the durations of one stage
+type StageHistogram struct {
+	StageKey
+
+	// Buckets holds the cumulative count of durations up to each upper bound
+	Buckets []uint64
+	Count   uint64
+	Sum     float64
+	Errors  uint64
+}
+
+// StageHistograms records the duration and outcome of the stages of
+// operations as histograms. It implements gardener.StageRecorder.
+type StageHistograms struct {
+	bounds []float64
+
+	mu         sync.Mutex
+	histograms map[StageKey]*StageHistogram
+}
+
+func NewStageHistograms(bounds []float64) *StageHistograms {
+	return &StageHistograms{
+		bounds:     bounds,
+		histograms: make(map[StageKey]*StageHistogram),
+	}
+}
+
+// Bounds returns the upper bounds of the buckets
+func (s *StageHistograms) Bounds() []float64 {
+	return s.bounds
+}
+
+func (s *StageHistograms) RecordStage(operation, stage string, took time.Duration, err error) {
+	seconds := took.Seconds()
+	key := StageKey{Operation: operation, Stage: stage}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	histogram, ok := s.histograms[key]
+	if !ok {
+		histogram = &StageHistogram{StageKey: key, Buckets: make([]uint64, len(s.bounds))}
+		s.histograms[key] = histogram
+	}
+
+	for i, bound := range s.bounds {
+		if seconds <= bound {
+			histogram.Buckets[i]++
+		}
+	}
+
+	histogram.Count++
+	histogram.Sum += seconds
+	if err != nil {
+		histogram.Errors++
+	}
+}
+
+// Histograms returns a copy of every histogram, ordered by operation and then
+// stage
+func (s *StageHistograms) Histograms() []StageHistogram {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	histograms := make(byStage, 0, len(s.histograms))
+	for _, histogram := range s.histograms {
+		copied := *histogram
+		copied.Buckets = append([]uint64(nil), histogram.Buckets...)
+		histograms = append(histograms, copied)
+	}
+
+	sort.Sort(histograms)
+	return histograms
+}
+
+type byStage []StageHistogram
+
+func (b byStage) Len() int      { return len(b) }
+func (b byStage) Swap(i, j int) { b[i], b[j] = b[j], b[i] }
+func (b byStage) Less(i, j int) bool {
+	if b[i].Operation != b[j].Operation {
+		return b[i].Operation < b[j].Operation
+	}
+
+	return b[i].Stage < b[j].Stage
+}
@@ -0,0 +1,58 @@
+package metrics_test
+
+import (
+	"errors"
+	"time"
+
+	"code.cloudfoundry.org/guardian/metrics"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("StageHistograms", func() {
+	var histograms *metrics.StageHistograms
+
+	BeforeEach(func() {
+		histograms = metrics.NewStageHistograms([]float64{0.1, 1})
+	})
+
+	It("counts each duration in every bucket it fits in", func() {
+		histograms.RecordStage("create", "network", 50*time.Millisecond, nil)
+		histograms.RecordStage("create", "network", 500*time.Millisecond, nil)
+		histograms.RecordStage("create", "network", 5*time.Second, errors.New("boom"))
+
+		recorded := histograms.Histograms()
+		Expect(recorded).To(HaveLen(1))
+		Expect(recorded[0].StageKey).To(Equal(metrics.StageKey{Operation: "create", Stage: "network"}))
+		Expect(recorded[0].Buckets).To(Equal([]uint64{1, 2}))
+		Expect(recorded[0].Count).To(BeEquivalentTo(3))
+		Expect(recorded[0].Sum).To(BeNumerically("~", 5.55, 0.001))
+		Expect(recorded[0].Errors).To(BeEquivalentTo(1))
+	})
+
+	It("keeps a histogram per operation and stage, in order", func() {
+		histograms.RecordStage("destroy", "total", time.Second, nil)
+		histograms.RecordStage("create", "volume", time.Second, nil)
+		histograms.RecordStage("create", "network", time.Second, nil)
+
+		keys := []metrics.StageKey{}
+		for _, histogram := range histograms.Histograms() {
+			keys = append(keys, histogram.StageKey)
+		}
+
+		Expect(keys).To(Equal([]metrics.StageKey{
+			{Operation: "create", Stage: "network"},
+			{Operation: "create", Stage: "volume"},
+			{Operation: "destroy", Stage: "total"},
+		}))
+	})
+
+	It("returns copies of the histograms", func() {
+		histograms.RecordStage("create", "network", time.Millisecond, nil)
+		copied := histograms.Histograms()
+
+		histograms.RecordStage("create", "network", time.Millisecond, nil)
+		Expect(copied[0].Buckets).To(Equal([]uint64{1, 1}))
+	})
+})
@@ -13,6 +13,7 @@ import (
 	"code.cloudfoundry.org/garden"
 	"code.cloudfoundry.org/guardian/gardener"
 	"code.cloudfoundry.org/guardian/kawasaki"
+	"code.cloudfoundry.org/guardian/tracing"
 	"code.cloudfoundry.org/lager"
 	"github.com/cloudfoundry/gunk/command_runner"
 )
@@ -27,8 +28,11 @@ type externalBinaryNetworker struct {
 	resolvConfigurer kawasaki.DnsResolvConfigurer
 	path             string
 	extraArg         []string
+	supportsStats    bool
 }
 
+// New returns a networker which runs the plugin at path. Plugins are only
+// asked for stats if supportsStats is set, as the stats action is optional.
 func New(
 	commandRunner command_runner.CommandRunner,
 	configStore kawasaki.ConfigStore,
@@ -37,6 +41,7 @@ func New(
 	resolvConfigurer kawasaki.DnsResolvConfigurer,
 	path string,
 	extraArg []string,
+	supportsStats bool,
 ) ExternalNetworker {
 	return &externalBinaryNetworker{
 		commandRunner:    commandRunner,
@@ -46,6 +51,7 @@ func New(
 		resolvConfigurer: resolvConfigurer,
 		path:             path,
 		extraArg:         extraArg,
+		supportsStats:    supportsStats,
 	}
 }
 
@@ -208,8 +214,50 @@ func (p *externalBinaryNetworker) BulkNetOut(log lager.Logger, handle string, ru
 	return nil
 }
 
+var ErrBandwidthLimitsNotSupported = errors.New("bandwidth limits are not supported by the network plugin")
+
+func (p *externalBinaryNetworker) LimitBandwidth(log lager.Logger, handle string, limits garden.BandwidthLimits) error {
+	return ErrBandwidthLimitsNotSupported
+}
+
+func (p *externalBinaryNetworker) BandwidthLimits(log lager.Logger, handle string) (garden.BandwidthLimits, error) {
+	return garden.BandwidthLimits{}, nil
+}
+
+// StatsOutputs are the counters of the container's traffic, which the plugin
+// reports from the container's point of view
+type StatsOutputs struct {
+	RxBytes   uint64 `json:"rx_bytes"`
+	TxBytes   uint64 `json:"tx_bytes"`
+	RxPackets uint64 `json:"rx_packets"`
+	TxPackets uint64 `json:"tx_packets"`
+}
+
+// Stats returns gardener.ErrNetworkStatsUnavailable, without running the
+// plugin, unless the plugin supports the stats action
+func (p *externalBinaryNetworker) Stats(log lager.Logger, handle string) (gardener.ContainerNetworkStat, error) {
+	if !p.supportsStats {
+		return gardener.ContainerNetworkStat{}, gardener.ErrNetworkStatsUnavailable
+	}
+
+	outputs := StatsOutputs{}
+	if err := p.exec(log, "stats", handle, nil, &outputs); err != nil {
+		return gardener.ContainerNetworkStat{}, err
+	}
+
+	return gardener.ContainerNetworkStat{
+		RxBytes:   outputs.RxBytes,
+		TxBytes:   outputs.TxBytes,
+		RxPackets: outputs.RxPackets,
+		TxPackets: outputs.TxPackets,
+	}, nil
+}
+
 func (p *externalBinaryNetworker) exec(log lager.Logger, action, handle string,
-	inputData interface{}, outputData interface{}) error {
+	inputData interface{}, outputData interface{}) (err error) {
+
+	log, span := tracing.StartSpan(log, "netplugin-"+action)
+	defer func() { span.End(err) }()
 
 	stdinBytes, err := json.Marshal(inputData)
 	if err != nil {
@@ -223,6 +271,7 @@ func (p *externalBinaryNetworker) exec(log lager.Logger, action, handle string,
 	stderr := &bytes.Buffer{}
 	cmd.Stderr = stderr
 	cmd.Stdin = bytes.NewReader(stdinBytes)
+	tracing.SetEnv(log, cmd)
 
 	err = p.commandRunner.Run(cmd)
 
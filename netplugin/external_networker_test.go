@@ -13,6 +13,7 @@ import (
 	"code.cloudfoundry.org/guardian/kawasaki/kawasakifakes"
 	"code.cloudfoundry.org/guardian/netplugin"
 	"code.cloudfoundry.org/guardian/properties"
+	"code.cloudfoundry.org/guardian/tracing"
 	"code.cloudfoundry.org/lager/lagertest"
 	"github.com/cloudfoundry/gunk/command_runner/fake_command_runner"
 
@@ -67,6 +68,7 @@ var _ = Describe("ExternalNetworker", func() {
 			resolvConfigurer,
 			"some/path",
 			[]string{"arg1", "arg2", "arg3"},
+			false,
 		)
 
 		pluginErr = nil
@@ -240,6 +242,14 @@ var _ = Describe("ExternalNetworker", func() {
 			}))
 		})
 
+		It("passes the trace id of the logger to the external plugin", func() {
+			tracedLogger, _ := new(tracing.Tracer).Start(logger, "destroy")
+			Expect(plugin.Destroy(tracedLogger, "my-handle")).To(Succeed())
+
+			cmd := fakeCommandRunner.ExecutedCommands()[0]
+			Expect(tracing.FromEnv(cmd.Env)).To(Equal(tracing.TraceID(tracedLogger)))
+		})
+
 		Context("when the external plugin errors", func() {
 			BeforeEach(func() {
 				pluginErr = errors.New("boom")
@@ -399,6 +409,95 @@ var _ = Describe("ExternalNetworker", func() {
 		})
 	})
 
+	Describe("LimitBandwidth", func() {
+		It("returns an error without calling the plugin", func() {
+			Expect(plugin.LimitBandwidth(logger, handle, garden.BandwidthLimits{})).To(MatchError(netplugin.ErrBandwidthLimitsNotSupported))
+			Expect(fakeCommandRunner.ExecutedCommands()).To(BeEmpty())
+		})
+	})
+
+	Describe("BandwidthLimits", func() {
+		It("returns empty limits", func() {
+			Expect(plugin.BandwidthLimits(logger, handle)).To(Equal(garden.BandwidthLimits{}))
+		})
+	})
+
+	Describe("Stats", func() {
+		It("reports the stats as unavailable without running the plugin", func() {
+			_, err := plugin.Stats(logger, handle)
+			Expect(err).To(Equal(gardener.ErrNetworkStatsUnavailable))
+			Expect(fakeCommandRunner.ExecutedCommands()).To(BeEmpty())
+		})
+	})
+
+	Describe("Stats, when the plugin supports them", func() {
+		BeforeEach(func() {
+			plugin = netplugin.New(
+				fakeCommandRunner,
+				configStore,
+				net.ParseIP("1.2.3.4"),
+				nil,
+				resolvConfigurer,
+				"some/path",
+				[]string{"arg1", "arg2", "arg3"},
+				true,
+			)
+
+			pluginOutput = `{
+					"rx_bytes": 1,
+					"tx_bytes": 2,
+					"rx_packets": 3,
+					"tx_packets": 4
+				}`
+		})
+
+		It("executes the external plugin with the correct args", func() {
+			_, err := plugin.Stats(logger, handle)
+			Expect(err).NotTo(HaveOccurred())
+
+			cmd := fakeCommandRunner.ExecutedCommands()[0]
+			Expect(cmd.Args).To(Equal([]string{
+				"some/path",
+				"arg1",
+				"arg2",
+				"arg3",
+				"--action", "stats",
+				"--handle", "some-handle",
+			}))
+		})
+
+		It("returns the counters output by the plugin", func() {
+			Expect(plugin.Stats(logger, handle)).To(Equal(gardener.ContainerNetworkStat{
+				RxBytes:   1,
+				TxBytes:   2,
+				RxPackets: 3,
+				TxPackets: 4,
+			}))
+		})
+
+		Context("when the external plugin errors", func() {
+			BeforeEach(func() {
+				pluginErr = errors.New("boom")
+			})
+
+			It("returns the error", func() {
+				_, err := plugin.Stats(logger, handle)
+				Expect(err).To(MatchError("external networker stats: boom"))
+			})
+		})
+
+		Context("when the plugin output is not valid JSON", func() {
+			BeforeEach(func() {
+				pluginOutput = "potato"
+			})
+
+			It("returns an error", func() {
+				_, err := plugin.Stats(logger, handle)
+				Expect(err).To(MatchError(ContainSubstring("unmarshaling result from external networker")))
+			})
+		})
+	})
+
 	Context("when the external plugin errors", func() {
 		var rule garden.NetOutRule
 
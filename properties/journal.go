@@ -0,0 +1,296 @@
+package properties
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sync"
+	"sync/atomic"
+
+	"code.cloudfoundry.org/lager"
+)
+
+// SnapshotEvery is how many changes are appended to the log before it is
+// compacted into a new snapshot
+const SnapshotEvery = 1000
+
+const (
+	opSet     = "set"
+	opRemove  = "remove"
+	opDestroy = "destroy"
+)
+
+// entry is one change, written to the log as a line of JSON
+type entry struct {
+	Op     string `json:"op"`
+	Handle string `json:"handle"`
+	Name   string `json:"name,omitempty"`
+	Value  string `json:"value,omitempty"`
+}
+
+// journal makes a Manager durable. The properties are kept in a snapshot,
+// in the same JSON format as Save, and every change since the snapshot was
+// taken is appended and synced to a log next to it.
+//
+// Changes are appended under the manager's write lock, so that the log is in
+// the same order as the changes, but synced after it has been released. One
+// sync then covers every change appended while the previous one was running.
+type journal struct {
+	logger       lager.Logger
+	snapshotPath string
+	log          *os.File
+	entries      int
+
+	// written is the number of entries appended to the log so far
+	written uint64
+
+	// syncMu serialises syncs; synced is the number of entries known to be
+	// on disk
+	syncMu sync.Mutex
+	synced uint64
+}
+
+// Open loads the snapshot at path, replays the log of changes made since it
+// was taken and records every further change. A JSON file written by Save is
+// a valid snapshot, so existing properties are picked up on first start.
+func Open(logger lager.Logger, path string) (*Manager, error) {
+	log := logger.Session("open-properties", lager.Data{"path": path})
+
+	mgr, err := Load(path)
+	if err != nil {
+		return nil, fmt.Errorf("load properties snapshot: %s", err)
+	}
+
+	logFile, err := os.OpenFile(logPath(path), os.O_RDWR|os.O_CREATE|os.O_APPEND, 0600)
+	if err != nil {
+		return nil, fmt.Errorf("open properties log: %s", err)
+	}
+
+	replayed, err := replay(log, logFile, mgr)
+	if err != nil {
+		logFile.Close()
+		return nil, err
+	}
+
+	log.Info("replayed", lager.Data{"entries": replayed})
+
+	mgr.journal = &journal{
+		logger:       logger.Session("properties-journal"),
+		snapshotPath: path,
+		log:          logFile,
+	}
+
+	// start from a single snapshot and an empty log, so that a log which was
+	// cut short by a crash is not appended to
+	if err := mgr.journal.snapshot(mgr.prop); err != nil {
+		logFile.Close()
+		return nil, err
+	}
+
+	return mgr, nil
+}
+
+// Close compacts the log into a final snapshot and stops recording changes
+func (m *Manager) Close() error {
+	m.propMutex.Lock()
+	defer m.propMutex.Unlock()
+
+	if m.journal == nil {
+		return nil
+	}
+
+	j := m.journal
+	m.journal = nil
+
+	if err := j.snapshot(m.prop); err != nil {
+		j.log.Close()
+		return err
+	}
+
+	return j.log.Close()
+}
+
+// record appends a change which has been applied under the write lock. The
+// returned function waits for the change to reach the disk, and must be
+// called once the lock has been released.
+func (m *Manager) record(e entry) func() {
+	if m.journal == nil {
+		return func() {}
+	}
+
+	j := m.journal
+	data := lager.Data{"op": e.Op, "handle": e.Handle, "name": e.Name}
+
+	seq, err := j.append(e, m.prop)
+	if err != nil {
+		j.logger.Error("append-failed", err, data)
+		return func() {}
+	}
+
+	return func() {
+		if err := j.sync(seq); err != nil {
+			j.logger.Error("sync-failed", err, data)
+		}
+	}
+}
+
+func (j *journal) append(e entry, state map[string]map[string]string) (uint64, error) {
+	line, err := json.Marshal(e)
+	if err != nil {
+		return 0, err
+	}
+
+	if _, err := j.log.Write(append(line, '\n')); err != nil {
+		return 0, err
+	}
+
+	seq := atomic.AddUint64(&j.written, 1)
+
+	j.entries++
+	if j.entries >= SnapshotEvery {
+		return seq, j.snapshot(state)
+	}
+
+	return seq, nil
+}
+
+// sync returns once the first seq entries are on disk, syncing the log unless
+// another sync or a snapshot has already covered them
+func (j *journal) sync(seq uint64) error {
+	j.syncMu.Lock()
+	defer j.syncMu.Unlock()
+
+	if j.synced >= seq {
+		return nil
+	}
+
+	written := atomic.LoadUint64(&j.written)
+	if err := j.log.Sync(); err != nil {
+		return err
+	}
+
+	j.synced = written
+	return nil
+}
+
+// snapshot atomically replaces the snapshot and then empties the log. If we
+// crash in between, replaying the log over the new snapshot is harmless as
+// every entry only sets the final state of the keys it names.
+func (j *journal) snapshot(state map[string]map[string]string) error {
+	data, err := json.Marshal(state)
+	if err != nil {
+		return err
+	}
+
+	if err := writeAtomically(j.snapshotPath, data); err != nil {
+		return fmt.Errorf("write properties snapshot: %s", err)
+	}
+
+	if err := j.log.Truncate(0); err != nil {
+		return fmt.Errorf("truncate properties log: %s", err)
+	}
+
+	if err := j.log.Sync(); err != nil {
+		return fmt.Errorf("sync properties log: %s", err)
+	}
+
+	// every entry written so far is in the snapshot
+	j.syncMu.Lock()
+	j.synced = atomic.LoadUint64(&j.written)
+	j.syncMu.Unlock()
+
+	j.entries = 0
+	return nil
+}
+
+// replay applies the logged changes to mgr. A last entry which is incomplete
+// was being written when we crashed, and is dropped.
+func replay(log lager.Logger, logFile *os.File, mgr *Manager) (int, error) {
+	reader := bufio.NewReader(logFile)
+
+	var (
+		replayed int
+		offset   int64
+	)
+
+	for {
+		line, err := reader.ReadBytes('\n')
+		if err == io.EOF {
+			if len(bytes.TrimSpace(line)) > 0 {
+				log.Info("dropping-incomplete-entry", lager.Data{"offset": offset})
+			}
+
+			return replayed, nil
+		}
+
+		if err != nil {
+			return replayed, fmt.Errorf("read properties log: %s", err)
+		}
+
+		var e entry
+		if err := json.Unmarshal(line, &e); err != nil {
+			return replayed, fmt.Errorf("corrupt properties log entry at offset %d: %s", offset, err)
+		}
+
+		mgr.apply(e)
+		replayed++
+		offset += int64(len(line))
+	}
+}
+
+func (m *Manager) apply(e entry) {
+	switch e.Op {
+	case opSet:
+		m.Set(e.Handle, e.Name, e.Value)
+	case opRemove:
+		m.Remove(e.Handle, e.Name)
+	case opDestroy:
+		m.DestroyKeySpace(e.Handle)
+	}
+}
+
+func logPath(snapshotPath string) string {
+	return snapshotPath + ".log"
+}
+
+// writeAtomically writes data to a temporary file and renames it over path,
+// syncing both so that path holds either the old or the new data after a
+// crash
+func writeAtomically(path string, data []byte) error {
+	tmp := path + ".tmp"
+
+	f, err := os.OpenFile(tmp, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, 0600)
+	if err != nil {
+		return err
+	}
+
+	if _, err := f.Write(data); err != nil {
+		f.Close()
+		return err
+	}
+
+	if err := f.Sync(); err != nil {
+		f.Close()
+		return err
+	}
+
+	if err := f.Close(); err != nil {
+		return err
+	}
+
+	if err := os.Rename(tmp, path); err != nil {
+		return err
+	}
+
+	dir, err := os.Open(filepath.Dir(path))
+	if err != nil {
+		return err
+	}
+	defer dir.Close()
+
+	return dir.Sync()
+}
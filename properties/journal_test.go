@@ -0,0 +1,181 @@
+package properties_test
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+
+	"code.cloudfoundry.org/garden"
+	"code.cloudfoundry.org/guardian/properties"
+	"code.cloudfoundry.org/lager/lagertest"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Open", func() {
+	var (
+		logger   *lagertest.TestLogger
+		dir      string
+		propPath string
+	)
+
+	BeforeEach(func() {
+		var err error
+		dir, err = ioutil.TempDir("", "properties-journal")
+		Expect(err).NotTo(HaveOccurred())
+
+		logger = lagertest.NewTestLogger("test")
+		propPath = filepath.Join(dir, "props.json")
+	})
+
+	AfterEach(func() {
+		Expect(os.RemoveAll(dir)).To(Succeed())
+	})
+
+	// reopen simulates a crash: the manager is abandoned without being closed
+	reopen := func() *properties.Manager {
+		mgr, err := properties.Open(logger, propPath)
+		Expect(err).NotTo(HaveOccurred())
+		return mgr
+	}
+
+	get := func(mgr *properties.Manager, handle, name string) string {
+		value, ok := mgr.Get(handle, name)
+		Expect(ok).To(BeTrue())
+		return value
+	}
+
+	It("starts empty when there is nothing on disk", func() {
+		mgr := reopen()
+
+		props, err := mgr.All("handle")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(props).To(BeEmpty())
+	})
+
+	It("keeps changes which were made before a crash", func() {
+		mgr := reopen()
+		mgr.Set("handle", "ip", "10.0.0.2")
+		mgr.Set("handle", "port", "8080")
+		mgr.Set("other", "ip", "10.0.0.6")
+		Expect(mgr.Remove("handle", "port")).To(Succeed())
+		Expect(mgr.DestroyKeySpace("other")).To(Succeed())
+
+		recovered := reopen()
+
+		Expect(recovered.All("handle")).To(Equal(garden.Properties{"ip": "10.0.0.2"}))
+		_, ok := recovered.Get("other", "ip")
+		Expect(ok).To(BeFalse())
+	})
+
+	It("keeps changes which were made concurrently", func() {
+		mgr := reopen()
+
+		var wg sync.WaitGroup
+		for i := 0; i < 20; i++ {
+			wg.Add(1)
+			go func(i int) {
+				defer GinkgoRecover()
+				defer wg.Done()
+
+				mgr.Set(fmt.Sprintf("handle-%d", i), "ip", fmt.Sprintf("10.0.0.%d", i))
+			}(i)
+		}
+		wg.Wait()
+
+		recovered := reopen()
+		for i := 0; i < 20; i++ {
+			Expect(get(recovered, fmt.Sprintf("handle-%d", i), "ip")).To(Equal(fmt.Sprintf("10.0.0.%d", i)))
+		}
+	})
+
+	It("compacts the log into the snapshot when it is closed", func() {
+		mgr := reopen()
+		mgr.Set("handle", "ip", "10.0.0.2")
+		Expect(mgr.Close()).To(Succeed())
+
+		log, err := ioutil.ReadFile(propPath + ".log")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(log).To(BeEmpty())
+
+		loaded, err := properties.Load(propPath)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(get(loaded, "handle", "ip")).To(Equal("10.0.0.2"))
+	})
+
+	It("compacts the log once it grows large", func() {
+		mgr := reopen()
+		for i := 0; i < properties.SnapshotEvery; i++ {
+			mgr.Set("handle", "counter", strings.Repeat("x", i%10))
+		}
+
+		log, err := ioutil.ReadFile(propPath + ".log")
+		Expect(err).NotTo(HaveOccurred())
+		Expect(log).To(BeEmpty())
+
+		Expect(get(reopen(), "handle", "counter")).To(Equal(strings.Repeat("x", (properties.SnapshotEvery-1)%10)))
+	})
+
+	It("leaves no temporary files behind", func() {
+		mgr := reopen()
+		mgr.Set("handle", "ip", "10.0.0.2")
+		Expect(mgr.Close()).To(Succeed())
+
+		_, err := os.Stat(propPath + ".tmp")
+		Expect(os.IsNotExist(err)).To(BeTrue())
+	})
+
+	Context("when there is a properties file from a previous version", func() {
+		BeforeEach(func() {
+			Expect(ioutil.WriteFile(propPath, []byte(`{"handle":{"ip":"10.0.0.2"}}`), 0600)).To(Succeed())
+		})
+
+		It("migrates its properties", func() {
+			mgr := reopen()
+			Expect(get(mgr, "handle", "ip")).To(Equal("10.0.0.2"))
+
+			mgr.Set("handle", "port", "8080")
+			Expect(reopen().All("handle")).To(Equal(garden.Properties{"ip": "10.0.0.2", "port": "8080"}))
+		})
+	})
+
+	Context("when the last entry of the log was cut short", func() {
+		BeforeEach(func() {
+			Expect(ioutil.WriteFile(propPath+".log", []byte(
+				`{"op":"set","handle":"handle","name":"ip","value":"10.0.0.2"}`+"\n"+
+					`{"op":"set","handle":"handle","na`,
+			), 0600)).To(Succeed())
+		})
+
+		It("replays the complete entries and drops the rest", func() {
+			mgr := reopen()
+			Expect(mgr.All("handle")).To(Equal(garden.Properties{"ip": "10.0.0.2"}))
+		})
+	})
+
+	Context("when an entry in the middle of the log is corrupt", func() {
+		BeforeEach(func() {
+			Expect(ioutil.WriteFile(propPath+".log", []byte(
+				"garbage\n"+
+					`{"op":"set","handle":"handle","name":"ip","value":"10.0.0.2"}`+"\n",
+			), 0600)).To(Succeed())
+		})
+
+		It("returns an error", func() {
+			_, err := properties.Open(logger, propPath)
+			Expect(err).To(MatchError(ContainSubstring("corrupt properties log entry at offset 0")))
+		})
+	})
+
+	Context("when the snapshot is corrupt", func() {
+		It("returns an error", func() {
+			Expect(ioutil.WriteFile(propPath, []byte("{teest: banana"), 0600)).To(Succeed())
+
+			_, err := properties.Open(logger, propPath)
+			Expect(err).To(MatchError(ContainSubstring("load properties snapshot")))
+		})
+	})
+})
@@ -3,6 +3,7 @@ package properties
 import (
 	"encoding/json"
 	"fmt"
+	"sort"
 	"sync"
 
 	"code.cloudfoundry.org/garden"
@@ -11,47 +12,103 @@ import (
 type Manager struct {
 	propMutex sync.RWMutex
 	prop      map[string]map[string]string
+
+	// index holds the handles which have each value of each property
+	index map[string]map[string]map[string]struct{}
+
+	// journal, if the manager was opened with Open, records every change
+	journal *journal
 }
 
 func NewManager() *Manager {
 	return &Manager{
-		prop: make(map[string]map[string]string),
+		prop:  make(map[string]map[string]string),
+		index: make(map[string]map[string]map[string]struct{}),
 	}
 }
 
 func (m *Manager) DestroyKeySpace(handle string) error {
 	m.propMutex.Lock()
-	defer m.propMutex.Unlock()
+
+	for name, value := range m.prop[handle] {
+		m.unindex(handle, name, value)
+	}
 
 	delete(m.prop, handle)
+	synced := m.record(entry{Op: opDestroy, Handle: handle})
+
+	m.propMutex.Unlock()
+	synced()
 
 	return nil
 }
 
+func (m *Manager) HasKeySpace(handle string) bool {
+	m.propMutex.RLock()
+	defer m.propMutex.RUnlock()
+
+	_, ok := m.prop[handle]
+	return ok
+}
+
 func (m *Manager) MarshalJSON() ([]byte, error) {
+	m.propMutex.RLock()
+	defer m.propMutex.RUnlock()
+
 	return json.Marshal(m.prop)
 }
 
 func (m *Manager) UnmarshalJSON(data []byte) error {
-	return json.Unmarshal(data, &(m.prop))
+	if err := json.Unmarshal(data, &(m.prop)); err != nil {
+		return err
+	}
+
+	m.index = make(map[string]map[string]map[string]struct{})
+	for handle, props := range m.prop {
+		for name, value := range props {
+			m.addToIndex(handle, name, value)
+		}
+	}
+
+	return nil
 }
 
 func (m *Manager) Set(handle string, name string, value string) {
 	m.propMutex.Lock()
-	defer m.propMutex.Unlock()
 
 	if _, ok := m.prop[handle]; !ok {
 		m.prop[handle] = make(map[string]string)
 	}
 
+	if old, ok := m.prop[handle][name]; ok {
+		m.unindex(handle, name, old)
+	}
+
 	m.prop[handle][name] = value
+	m.addToIndex(handle, name, value)
+	synced := m.record(entry{Op: opSet, Handle: handle, Name: name, Value: value})
+
+	m.propMutex.Unlock()
+	synced()
 }
 
+// All returns a copy of the properties of handle, which callers may read
+// while the properties change
 func (m *Manager) All(handle string) (garden.Properties, error) {
 	m.propMutex.RLock()
 	defer m.propMutex.RUnlock()
 
-	return m.prop[handle], nil
+	props, ok := m.prop[handle]
+	if !ok {
+		return nil, nil
+	}
+
+	all := make(garden.Properties, len(props))
+	for name, value := range props {
+		all[name] = value
+	}
+
+	return all, nil
 }
 
 func (m *Manager) Get(handle string, name string) (string, bool) {
@@ -69,15 +126,21 @@ func (m *Manager) Get(handle string, name string) (string, bool) {
 
 func (m *Manager) Remove(handle string, name string) error {
 	m.propMutex.Lock()
-	defer m.propMutex.Unlock()
 
-	if _, exists := m.prop[handle][name]; !exists {
+	value, exists := m.prop[handle][name]
+	if !exists {
+		m.propMutex.Unlock()
 		return NoSuchPropertyError{
 			Message: fmt.Sprintf("cannot Remove %s:%s", handle, name),
 		}
 	}
 
 	delete(m.prop[handle], name)
+	m.unindex(handle, name, value)
+	synced := m.record(entry{Op: opRemove, Handle: handle, Name: name})
+
+	m.propMutex.Unlock()
+	synced()
 
 	return nil
 }
@@ -95,6 +158,130 @@ func (m *Manager) MatchesAll(handle string, props garden.Properties) bool {
 	return true
 }
 
+// MatchingHandles returns the handles whose properties satisfy the selector
+// built from props by SelectorFromProperties
+func (m *Manager) MatchingHandles(props garden.Properties) ([]string, error) {
+	selector, err := SelectorFromProperties(props)
+	if err != nil {
+		return nil, err
+	}
+
+	return m.Select(selector), nil
+}
+
+// Select returns the handles, in order, whose properties satisfy the
+// selector. Equality, set and existence requirements are answered from the
+// index so that only the handles which could match are checked.
+func (m *Manager) Select(selector Selector) []string {
+	m.propMutex.RLock()
+	defer m.propMutex.RUnlock()
+
+	var handles []string
+	for handle := range m.candidates(selector) {
+		if selector.Matches(m.prop[handle]) {
+			handles = append(handles, handle)
+		}
+	}
+
+	sort.Strings(handles)
+	return handles
+}
+
+// candidates picks the smallest set of handles which the index says could
+// satisfy one of the requirements, or every handle if none can use the index
+func (m *Manager) candidates(selector Selector) map[string]struct{} {
+	var (
+		best     []map[string]struct{}
+		bestSize = -1
+	)
+
+	for _, requirement := range selector {
+		sets, ok := m.indexed(requirement)
+		if !ok {
+			continue
+		}
+
+		size := 0
+		for _, set := range sets {
+			size += len(set)
+		}
+
+		if bestSize < 0 || size < bestSize {
+			best, bestSize = sets, size
+		}
+	}
+
+	if bestSize < 0 {
+		all := make(map[string]struct{}, len(m.prop))
+		for handle := range m.prop {
+			all[handle] = struct{}{}
+		}
+
+		return all
+	}
+
+	candidates := make(map[string]struct{}, bestSize)
+	for _, set := range best {
+		for handle := range set {
+			candidates[handle] = struct{}{}
+		}
+	}
+
+	return candidates
+}
+
+// indexed returns the sets of handles which between them hold every handle
+// that could satisfy the requirement, if the index can answer it
+func (m *Manager) indexed(requirement Requirement) ([]map[string]struct{}, bool) {
+	values := m.index[requirement.Key]
+
+	switch requirement.Operator {
+	case Equals, In:
+		var sets []map[string]struct{}
+		for _, value := range requirement.Values {
+			sets = append(sets, values[value])
+		}
+		return sets, true
+	case Exists, HasPrefix, GreaterThan, GreaterEqual, LessThan, LessEqual:
+		var sets []map[string]struct{}
+		for _, set := range values {
+			sets = append(sets, set)
+		}
+		return sets, true
+	}
+
+	return nil, false
+}
+
+func (m *Manager) addToIndex(handle, name, value string) {
+	if m.index == nil {
+		m.index = make(map[string]map[string]map[string]struct{})
+	}
+
+	if m.index[name] == nil {
+		m.index[name] = make(map[string]map[string]struct{})
+	}
+
+	if m.index[name][value] == nil {
+		m.index[name][value] = make(map[string]struct{})
+	}
+
+	m.index[name][value][handle] = struct{}{}
+}
+
+func (m *Manager) unindex(handle, name, value string) {
+	handles := m.index[name][value]
+	delete(handles, handle)
+
+	if len(handles) == 0 {
+		delete(m.index[name], value)
+	}
+
+	if len(m.index[name]) == 0 {
+		delete(m.index, name)
+	}
+}
+
 type NoSuchPropertyError struct {
 	Message string
 }
@@ -34,6 +34,21 @@ var _ = Describe("Properties", func() {
 		})
 	})
 
+	Describe("HasKeySpace", func() {
+		It("returns true when the handle has properties", func() {
+			Expect(propertyManager.HasKeySpace("handle")).To(BeTrue())
+		})
+
+		It("returns false when the handle is unknown", func() {
+			Expect(propertyManager.HasKeySpace("some-handle-that-doesnt-exist")).To(BeFalse())
+		})
+
+		It("returns false once the key space has been destroyed", func() {
+			Expect(propertyManager.DestroyKeySpace("handle")).To(Succeed())
+			Expect(propertyManager.HasKeySpace("handle")).To(BeFalse())
+		})
+	})
+
 	Describe("All", func() {
 		It("returns the properties", func() {
 			props, err := propertyManager.All("handle")
@@ -42,6 +57,16 @@ var _ = Describe("Properties", func() {
 			Expect(props).To(HaveLen(1))
 			Expect(props).To(HaveKeyWithValue("name", "value"))
 		})
+
+		It("returns a copy which later changes do not affect", func() {
+			props, err := propertyManager.All("handle")
+			Expect(err).NotTo(HaveOccurred())
+
+			propertyManager.Set("handle", "name", "some-other-value")
+			propertyManager.Set("handle", "other-name", "other-value")
+
+			Expect(props).To(Equal(garden.Properties{"name": "value"}))
+		})
 	})
 
 	Describe("Get", func() {
@@ -150,6 +175,90 @@ var _ = Describe("Properties", func() {
 		})
 	})
 
+	Describe("MatchingHandles", func() {
+		BeforeEach(func() {
+			propertyManager.Set("fred", "family", "flintstone")
+			propertyManager.Set("fred", "age", "35")
+			propertyManager.Set("wilma", "family", "flintstone")
+			propertyManager.Set("wilma", "age", "33")
+			propertyManager.Set("barney", "family", "rubble")
+		})
+
+		It("returns the handles matching every property, in order", func() {
+			handles, err := propertyManager.MatchingHandles(garden.Properties{"family": "flintstone"})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(handles).To(Equal([]string{"fred", "wilma"}))
+		})
+
+		It("applies the selector property", func() {
+			handles, err := propertyManager.MatchingHandles(garden.Properties{
+				"family":               "flintstone",
+				properties.SelectorKey: "age>34",
+			})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(handles).To(Equal([]string{"fred"}))
+		})
+
+		It("matches handles which lack a property", func() {
+			handles, err := propertyManager.MatchingHandles(garden.Properties{properties.SelectorKey: "!age"})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(handles).To(Equal([]string{"barney", "handle"}))
+		})
+
+		It("returns an error when the selector is invalid", func() {
+			_, err := propertyManager.MatchingHandles(garden.Properties{properties.SelectorKey: "age>old"})
+			Expect(err).To(BeAssignableToTypeOf(properties.InvalidSelectorError{}))
+		})
+
+		Context("when a property changes", func() {
+			It("matches on the new value only", func() {
+				propertyManager.Set("barney", "family", "flintstone")
+
+				handles, err := propertyManager.MatchingHandles(garden.Properties{"family": "rubble"})
+				Expect(err).NotTo(HaveOccurred())
+				Expect(handles).To(BeEmpty())
+
+				handles, err = propertyManager.MatchingHandles(garden.Properties{"family": "flintstone"})
+				Expect(err).NotTo(HaveOccurred())
+				Expect(handles).To(ContainElement("barney"))
+			})
+		})
+
+		Context("when a property is removed", func() {
+			It("no longer matches it", func() {
+				Expect(propertyManager.Remove("fred", "family")).To(Succeed())
+
+				handles, err := propertyManager.MatchingHandles(garden.Properties{"family": "flintstone"})
+				Expect(err).NotTo(HaveOccurred())
+				Expect(handles).To(Equal([]string{"wilma"}))
+			})
+		})
+
+		Context("when a key space is destroyed", func() {
+			It("no longer matches it", func() {
+				Expect(propertyManager.DestroyKeySpace("wilma")).To(Succeed())
+
+				handles, err := propertyManager.MatchingHandles(garden.Properties{properties.SelectorKey: "family"})
+				Expect(err).NotTo(HaveOccurred())
+				Expect(handles).To(Equal([]string{"barney", "fred"}))
+			})
+		})
+
+		Context("when the manager is restored from JSON", func() {
+			It("rebuilds the index", func() {
+				data, err := json.Marshal(propertyManager)
+				Expect(err).NotTo(HaveOccurred())
+
+				var roundtripped properties.Manager
+				Expect(json.Unmarshal(data, &roundtripped)).To(Succeed())
+
+				handles, err := roundtripped.MatchingHandles(garden.Properties{"family": "rubble"})
+				Expect(err).NotTo(HaveOccurred())
+				Expect(handles).To(Equal([]string{"barney"}))
+			})
+		})
+	})
+
 	Describe("MarshalJSON", func() {
 		It("can be saved and restored from JSON", func() {
 			mgr := properties.NewManager()
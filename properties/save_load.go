@@ -10,6 +10,7 @@ func Load(path string) (*Manager, error) {
 	if err != nil {
 		return NewManager(), nil
 	}
+	defer f.Close()
 
 	var mgr Manager
 	if err := json.NewDecoder(f).Decode(&mgr); err != nil {
@@ -19,11 +20,13 @@ func Load(path string) (*Manager, error) {
 	return &mgr, nil
 }
 
+// Save writes the properties to path atomically, so that a crash part way
+// through leaves the previous contents in place
 func Save(path string, mgr *Manager) error {
-	f, err := os.Create(path)
+	data, err := json.Marshal(mgr)
 	if err != nil {
 		return err
 	}
 
-	return json.NewEncoder(f).Encode(mgr)
+	return writeAtomically(path, data)
 }
@@ -0,0 +1,300 @@
+package properties
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"code.cloudfoundry.org/garden"
+)
+
+// SelectorKey is the property which, when passed to Containers, holds a
+// selector expression rather than a value to match exactly
+const SelectorKey = "garden.selector"
+
+type Operator string
+
+const (
+	Exists       Operator = "exists"
+	DoesNotExist Operator = "!"
+	Equals       Operator = "="
+	NotEquals    Operator = "!="
+	In           Operator = "in"
+	NotIn        Operator = "notin"
+	HasPrefix    Operator = "^="
+	GreaterThan  Operator = ">"
+	GreaterEqual Operator = ">="
+	LessThan     Operator = "<"
+	LessEqual    Operator = "<="
+)
+
+// valueOperators are ordered so that the longest operator at a position wins
+var valueOperators = []Operator{"==", NotEquals, HasPrefix, GreaterEqual, LessEqual, Equals, GreaterThan, LessThan}
+
+// Requirement is a condition on the value of a single property
+type Requirement struct {
+	Key      string
+	Operator Operator
+	Values   []string
+}
+
+func (r Requirement) Matches(value string, exists bool) bool {
+	switch r.Operator {
+	case Exists:
+		return exists
+	case DoesNotExist:
+		return !exists
+	case Equals:
+		return exists && value == r.Values[0]
+	case NotEquals:
+		return !exists || value != r.Values[0]
+	case In:
+		return exists && contains(r.Values, value)
+	case NotIn:
+		return !exists || !contains(r.Values, value)
+	case HasPrefix:
+		return exists && strings.HasPrefix(value, r.Values[0])
+	case GreaterThan, GreaterEqual, LessThan, LessEqual:
+		if !exists {
+			return false
+		}
+
+		return r.compare(value)
+	}
+
+	return false
+}
+
+func (r Requirement) compare(value string) bool {
+	actual, err := strconv.ParseFloat(value, 64)
+	if err != nil {
+		return false
+	}
+
+	// the bound was checked when the requirement was parsed
+	bound, _ := strconv.ParseFloat(r.Values[0], 64)
+
+	switch r.Operator {
+	case GreaterThan:
+		return actual > bound
+	case GreaterEqual:
+		return actual >= bound
+	case LessThan:
+		return actual < bound
+	default:
+		return actual <= bound
+	}
+}
+
+// Selector matches a set of properties which satisfy all of its requirements
+type Selector []Requirement
+
+func (s Selector) Matches(props garden.Properties) bool {
+	for _, requirement := range s {
+		value, exists := props[requirement.Key]
+		if !requirement.Matches(value, exists) {
+			return false
+		}
+	}
+
+	return true
+}
+
+// InvalidSelectorError is returned when a selector expression cannot be parsed
+type InvalidSelectorError struct {
+	Selector string
+	Reason   string
+}
+
+func (e InvalidSelectorError) Error() string {
+	return fmt.Sprintf("invalid selector '%s': %s", e.Selector, e.Reason)
+}
+
+// SelectorFromProperties turns the properties passed to Containers into a
+// selector. Every property must match exactly, except SelectorKey whose value
+// is parsed with ParseSelector.
+func SelectorFromProperties(props garden.Properties) (Selector, error) {
+	var selector Selector
+	for key, value := range props {
+		if key == SelectorKey {
+			continue
+		}
+
+		selector = append(selector, Requirement{Key: key, Operator: Equals, Values: []string{value}})
+	}
+
+	if expression, ok := props[SelectorKey]; ok {
+		parsed, err := ParseSelector(expression)
+		if err != nil {
+			return nil, err
+		}
+
+		selector = append(selector, parsed...)
+	}
+
+	return selector, nil
+}
+
+// ParseSelector parses a comma separated list of requirements, each one of
+//
+//	key             the property is set
+//	!key            the property is not set
+//	key=value       the property is set to value (== is also accepted)
+//	key!=value      the property is not set to value, or is not set at all
+//	key in (a,b)    the property is set to one of the values
+//	key notin (a,b) the property is not set to any of the values
+//	key^=prefix     the property is set to a value starting with prefix
+//	key>n, key>=n, key<n, key<=n
+//	                the property is set to a number which compares with n
+func ParseSelector(expression string) (Selector, error) {
+	terms, err := splitTerms(expression)
+	if err != nil {
+		return nil, InvalidSelectorError{Selector: expression, Reason: err.Error()}
+	}
+
+	var selector Selector
+	for _, term := range terms {
+		requirement, err := parseRequirement(term)
+		if err != nil {
+			return nil, InvalidSelectorError{Selector: expression, Reason: err.Error()}
+		}
+
+		selector = append(selector, requirement)
+	}
+
+	return selector, nil
+}
+
+// splitTerms splits on the commas which are not inside a set of values
+func splitTerms(expression string) ([]string, error) {
+	var (
+		terms []string
+		depth int
+		start int
+	)
+
+	for i, c := range expression {
+		switch c {
+		case '(':
+			depth++
+		case ')':
+			depth--
+			if depth < 0 {
+				return nil, fmt.Errorf("unexpected ')'")
+			}
+		case ',':
+			if depth == 0 {
+				terms = append(terms, expression[start:i])
+				start = i + 1
+			}
+		}
+	}
+
+	if depth != 0 {
+		return nil, fmt.Errorf("unterminated '('")
+	}
+
+	terms = append(terms, expression[start:])
+
+	var nonEmpty []string
+	for _, term := range terms {
+		if term = strings.TrimSpace(term); term != "" {
+			nonEmpty = append(nonEmpty, term)
+		}
+	}
+
+	return nonEmpty, nil
+}
+
+func parseRequirement(term string) (Requirement, error) {
+	if fields := strings.Fields(term); len(fields) >= 2 && (fields[1] == string(In) || fields[1] == string(NotIn)) {
+		return parseSet(term, fields[0], Operator(fields[1]))
+	}
+
+	i := strings.IndexAny(term, "!=^<>")
+	if i < 0 {
+		return Requirement{Key: term, Operator: Exists}, checkKey(term)
+	}
+
+	if i == 0 && term[0] == '!' {
+		key := strings.TrimSpace(term[1:])
+		return Requirement{Key: key, Operator: DoesNotExist}, checkKey(key)
+	}
+
+	key := strings.TrimSpace(term[:i])
+	if err := checkKey(key); err != nil {
+		return Requirement{}, err
+	}
+
+	op := operatorAt(term[i:])
+	if op == "" {
+		return Requirement{}, fmt.Errorf("unknown operator in '%s'", term)
+	}
+
+	value := strings.TrimSpace(term[i+len(op):])
+	if op == "==" {
+		op = Equals
+	}
+
+	switch op {
+	case GreaterThan, GreaterEqual, LessThan, LessEqual:
+		if _, err := strconv.ParseFloat(value, 64); err != nil {
+			return Requirement{}, fmt.Errorf("'%s' is not a number", value)
+		}
+	}
+
+	return Requirement{Key: key, Operator: op, Values: []string{value}}, nil
+}
+
+func operatorAt(s string) Operator {
+	for _, op := range valueOperators {
+		if strings.HasPrefix(s, string(op)) {
+			return op
+		}
+	}
+
+	return ""
+}
+
+func parseSet(term, key string, op Operator) (Requirement, error) {
+	if err := checkKey(key); err != nil {
+		return Requirement{}, err
+	}
+
+	rest := strings.TrimSpace(term[len(key):])
+	rest = strings.TrimSpace(rest[len(op):])
+	if !strings.HasPrefix(rest, "(") || !strings.HasSuffix(rest, ")") {
+		return Requirement{}, fmt.Errorf("values of '%s' must be in parentheses", key)
+	}
+
+	var values []string
+	for _, value := range strings.Split(rest[1:len(rest)-1], ",") {
+		if value = strings.TrimSpace(value); value != "" {
+			values = append(values, value)
+		}
+	}
+
+	if len(values) == 0 {
+		return Requirement{}, fmt.Errorf("'%s %s' needs at least one value", key, op)
+	}
+
+	return Requirement{Key: key, Operator: op, Values: values}, nil
+}
+
+func checkKey(key string) error {
+	if key == "" || strings.ContainsAny(key, " !=<>()^") {
+		return fmt.Errorf("invalid key '%s'", key)
+	}
+
+	return nil
+}
+
+func contains(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+
+	return false
+}
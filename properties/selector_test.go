@@ -0,0 +1,88 @@
+package properties_test
+
+import (
+	"code.cloudfoundry.org/garden"
+	"code.cloudfoundry.org/guardian/properties"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/ginkgo/extensions/table"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Selector", func() {
+	props := garden.Properties{
+		"owner":   "alice",
+		"tier":    "web",
+		"version": "12",
+		"name":    "web-frontend",
+	}
+
+	DescribeTable("matching properties",
+		func(expression string, matches bool) {
+			selector, err := properties.ParseSelector(expression)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(selector.Matches(props)).To(Equal(matches))
+		},
+		Entry("exists", "owner", true),
+		Entry("exists, when absent", "missing", false),
+		Entry("absent", "!missing", true),
+		Entry("absent, when set", "!owner", false),
+		Entry("equals", "owner=alice", true),
+		Entry("double equals", "owner==alice", true),
+		Entry("equals, with another value", "owner=bob", false),
+		Entry("not equals", "owner!=bob", true),
+		Entry("not equals, with the same value", "owner!=alice", false),
+		Entry("not equals, when absent", "missing!=bob", true),
+		Entry("in", "tier in (db, web)", true),
+		Entry("in, without the value", "tier in (db,cache)", false),
+		Entry("notin", "tier notin (db,cache)", true),
+		Entry("notin, with the value", "tier notin (web)", false),
+		Entry("prefix", "name^=web-", true),
+		Entry("prefix, not matching", "name^=db-", false),
+		Entry("greater than", "version>11", true),
+		Entry("greater than or equal", "version>=12", true),
+		Entry("less than", "version<12", false),
+		Entry("less than or equal", "version<=12.5", true),
+		Entry("numeric comparison with a non-numeric value", "owner>1", false),
+		Entry("numeric comparison when absent", "missing<1", false),
+		Entry("all of several requirements", "owner=alice, tier in (web,db), version>10", true),
+		Entry("one of several requirements failing", "owner=alice,!tier", false),
+	)
+
+	DescribeTable("invalid expressions",
+		func(expression string) {
+			_, err := properties.ParseSelector(expression)
+			Expect(err).To(BeAssignableToTypeOf(properties.InvalidSelectorError{}))
+		},
+		Entry("unterminated set", "tier in (web"),
+		Entry("set without parentheses", "tier in web"),
+		Entry("empty set", "tier in ()"),
+		Entry("numeric comparison with a non-number", "version>ten"),
+		Entry("missing key", "=alice"),
+		Entry("unknown operator", "owner!alice"),
+	)
+
+	Describe("SelectorFromProperties", func() {
+		It("requires every property to match exactly", func() {
+			selector, err := properties.SelectorFromProperties(garden.Properties{"owner": "alice"})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(selector).To(ConsistOf(properties.Requirement{Key: "owner", Operator: properties.Equals, Values: []string{"alice"}}))
+		})
+
+		It("parses the selector property", func() {
+			selector, err := properties.SelectorFromProperties(garden.Properties{
+				"owner":                "alice",
+				properties.SelectorKey: "!deleted",
+			})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(selector).To(ConsistOf(
+				properties.Requirement{Key: "owner", Operator: properties.Equals, Values: []string{"alice"}},
+				properties.Requirement{Key: "deleted", Operator: properties.DoesNotExist},
+			))
+		})
+
+		It("returns an error when the selector property is invalid", func() {
+			_, err := properties.SelectorFromProperties(garden.Properties{properties.SelectorKey: "a in ("})
+			Expect(err).To(MatchError(ContainSubstring("invalid selector 'a in ('")))
+		})
+	})
+})
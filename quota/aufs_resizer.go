@@ -0,0 +1,107 @@
+package quota
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+
+	"code.cloudfoundry.org/garden"
+	"code.cloudfoundry.org/garden-shed/layercake"
+	"code.cloudfoundry.org/lager"
+	"github.com/cloudfoundry/gunk/command_runner"
+)
+
+var ErrNoQuota = errors.New("container does not have a disk quota")
+
+//go:generate counterfeiter . BaseSizer
+
+type BaseSizer interface {
+	BaseSize(logger lager.Logger, containerRootFSPath string) (uint64, error)
+}
+
+// AUFSResizer grows the loop-mounted backing store which holds the quota of
+// a container's read-write layer in an AUFS graph
+type AUFSResizer struct {
+	BackingStoresPath string
+	BaseSizer         BaseSizer
+	Runner            command_runner.CommandRunner
+}
+
+func (r *AUFSResizer) Resize(log lager.Logger, handle, rootFSPath string, limits garden.DiskLimits) error {
+	log = log.Session("aufs-resize", lager.Data{"handle": handle, "limits": limits})
+
+	log.Info("started")
+	defer log.Info("finished")
+
+	backingStore := filepath.Join(r.BackingStoresPath, layercake.ContainerID(handle).GraphID())
+	info, err := os.Stat(backingStore)
+	if os.IsNotExist(err) {
+		return ErrNoQuota
+	}
+	if err != nil {
+		return fmt.Errorf("stat backing store: %s", err)
+	}
+
+	size := limits.ByteHard
+	if limits.Scope == garden.DiskLimitScopeTotal {
+		baseSize, err := r.BaseSizer.BaseSize(log, rootFSPath)
+		if err != nil {
+			log.Error("base-size-failed", err)
+			return fmt.Errorf("base size: %s", err)
+		}
+
+		if size <= baseSize {
+			return fmt.Errorf("disk limit %d must be larger than the image size %d", size, baseSize)
+		}
+		size -= baseSize
+	}
+
+	if int64(size) < info.Size() {
+		return fmt.Errorf("shrinking the disk quota from %d to %d bytes is not supported", info.Size(), size)
+	}
+
+	if err := os.Truncate(backingStore, int64(size)); err != nil {
+		log.Error("truncate-failed", err)
+		return fmt.Errorf("grow backing store: %s", err)
+	}
+
+	loopDevice, err := r.loopDevice(backingStore)
+	if err != nil {
+		log.Error("find-loop-device-failed", err)
+		return err
+	}
+
+	if err := r.Runner.Run(exec.Command("losetup", "-c", loopDevice)); err != nil {
+		log.Error("losetup-failed", err, lager.Data{"device": loopDevice})
+		return fmt.Errorf("refresh loop device capacity: %s", err)
+	}
+
+	if err := r.Runner.Run(exec.Command("resize2fs", loopDevice)); err != nil {
+		log.Error("resize2fs-failed", err, lager.Data{"device": loopDevice})
+		return fmt.Errorf("resize filesystem: %s", err)
+	}
+
+	return nil
+}
+
+func (r *AUFSResizer) loopDevice(backingStore string) (string, error) {
+	stdout := new(bytes.Buffer)
+	cmd := exec.Command("losetup", "-j", backingStore)
+	cmd.Stdout = stdout
+
+	if err := r.Runner.Run(cmd); err != nil {
+		return "", fmt.Errorf("find loop device: %s", err)
+	}
+
+	// e.g. '/dev/loop0: [2049]:1234 (/var/vcap/data/garden/aufs_graph/backing_stores/abc)'
+	device := strings.SplitN(strings.TrimSpace(stdout.String()), ":", 2)[0]
+	if device == "" {
+		return "", fmt.Errorf("find loop device: no loop device is attached to %s", backingStore)
+	}
+
+	return device, nil
+}
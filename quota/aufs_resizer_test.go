@@ -0,0 +1,147 @@
+package quota_test
+
+import (
+	"errors"
+	"io/ioutil"
+	"os"
+	"os/exec"
+	"path/filepath"
+
+	"code.cloudfoundry.org/garden"
+	"code.cloudfoundry.org/garden-shed/layercake"
+	"code.cloudfoundry.org/guardian/quota"
+	fakes "code.cloudfoundry.org/guardian/quota/quotafakes"
+	"code.cloudfoundry.org/lager/lagertest"
+	"github.com/cloudfoundry/gunk/command_runner/fake_command_runner"
+	. "github.com/cloudfoundry/gunk/command_runner/fake_command_runner/matchers"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("AUFSResizer", func() {
+	var (
+		backingStoresPath string
+		backingStore      string
+		fakeRunner        *fake_command_runner.FakeCommandRunner
+		fakeBaseSizer     *fakes.FakeBaseSizer
+		logger            *lagertest.TestLogger
+		resizer           *quota.AUFSResizer
+	)
+
+	BeforeEach(func() {
+		var err error
+		backingStoresPath, err = ioutil.TempDir("", "backing-stores")
+		Expect(err).NotTo(HaveOccurred())
+
+		backingStore = filepath.Join(backingStoresPath, layercake.ContainerID("some-handle").GraphID())
+		Expect(ioutil.WriteFile(backingStore, make([]byte, 1024), 0600)).To(Succeed())
+
+		fakeRunner = fake_command_runner.New()
+		fakeRunner.WhenRunning(fake_command_runner.CommandSpec{
+			Path: "losetup",
+			Args: []string{"-j", backingStore},
+		}, func(cmd *exec.Cmd) error {
+			cmd.Stdout.Write([]byte("/dev/loop3: [2049]:1234 (" + backingStore + ")\n"))
+			return nil
+		})
+
+		fakeBaseSizer = new(fakes.FakeBaseSizer)
+		logger = lagertest.NewTestLogger("test")
+
+		resizer = &quota.AUFSResizer{
+			BackingStoresPath: backingStoresPath,
+			BaseSizer:         fakeBaseSizer,
+			Runner:            fakeRunner,
+		}
+	})
+
+	AfterEach(func() {
+		Expect(os.RemoveAll(backingStoresPath)).To(Succeed())
+	})
+
+	It("grows the backing store of the container's layer", func() {
+		Expect(resizer.Resize(logger, "some-handle", "/rootfs", garden.DiskLimits{ByteHard: 4096})).To(Succeed())
+
+		info, err := os.Stat(backingStore)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(info.Size()).To(BeEquivalentTo(4096))
+	})
+
+	It("refreshes the loop device and resizes the filesystem", func() {
+		Expect(resizer.Resize(logger, "some-handle", "/rootfs", garden.DiskLimits{ByteHard: 4096})).To(Succeed())
+
+		Expect(fakeRunner).To(HaveExecutedSerially(
+			fake_command_runner.CommandSpec{Path: "losetup", Args: []string{"-j", backingStore}},
+			fake_command_runner.CommandSpec{Path: "losetup", Args: []string{"-c", "/dev/loop3"}},
+			fake_command_runner.CommandSpec{Path: "resize2fs", Args: []string{"/dev/loop3"}},
+		))
+	})
+
+	Context("when the scope is total", func() {
+		BeforeEach(func() {
+			fakeBaseSizer.BaseSizeReturns(1000, nil)
+		})
+
+		It("excludes the size of the image from the quota", func() {
+			Expect(resizer.Resize(logger, "some-handle", "/rootfs", garden.DiskLimits{
+				ByteHard: 5096,
+				Scope:    garden.DiskLimitScopeTotal,
+			})).To(Succeed())
+
+			_, rootFSPath := fakeBaseSizer.BaseSizeArgsForCall(0)
+			Expect(rootFSPath).To(Equal("/rootfs"))
+
+			info, err := os.Stat(backingStore)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(info.Size()).To(BeEquivalentTo(4096))
+		})
+
+		Context("when the limit is smaller than the image", func() {
+			It("returns an error", func() {
+				Expect(resizer.Resize(logger, "some-handle", "/rootfs", garden.DiskLimits{
+					ByteHard: 999,
+					Scope:    garden.DiskLimitScopeTotal,
+				})).To(MatchError(ContainSubstring("must be larger than the image size")))
+			})
+		})
+
+		Context("when getting the base size fails", func() {
+			It("returns an error", func() {
+				fakeBaseSizer.BaseSizeReturns(0, errors.New("boom"))
+				Expect(resizer.Resize(logger, "some-handle", "/rootfs", garden.DiskLimits{
+					ByteHard: 5096,
+					Scope:    garden.DiskLimitScopeTotal,
+				})).To(MatchError("base size: boom"))
+			})
+		})
+	})
+
+	Context("when the new limit is smaller than the current quota", func() {
+		It("returns an error and does not touch the backing store", func() {
+			Expect(resizer.Resize(logger, "some-handle", "/rootfs", garden.DiskLimits{ByteHard: 512})).To(MatchError(ContainSubstring("not supported")))
+
+			info, err := os.Stat(backingStore)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(info.Size()).To(BeEquivalentTo(1024))
+		})
+	})
+
+	Context("when the container has no backing store", func() {
+		It("returns ErrNoQuota", func() {
+			Expect(resizer.Resize(logger, "other-handle", "/rootfs", garden.DiskLimits{ByteHard: 4096})).To(MatchError(quota.ErrNoQuota))
+		})
+	})
+
+	Context("when resize2fs fails", func() {
+		It("returns an error", func() {
+			fakeRunner.WhenRunning(fake_command_runner.CommandSpec{
+				Path: "resize2fs",
+			}, func(cmd *exec.Cmd) error {
+				return errors.New("exit status 1")
+			})
+
+			Expect(resizer.Resize(logger, "some-handle", "/rootfs", garden.DiskLimits{ByteHard: 4096})).To(MatchError("resize filesystem: exit status 1"))
+		})
+	})
+})
@@ -0,0 +1,33 @@
+package quota
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+
+	"code.cloudfoundry.org/guardian/gardener"
+)
+
+// LoadOwnerQuotas reads per-owner quotas from a JSON file of the form
+//
+//	{
+//	  "default": {"max_containers": 10},
+//	  "unowned": {"max_containers": 100},
+//	  "owners": {
+//	    "tenant-a": {"max_containers": 50, "memory_in_bytes": 8589934592, "disk_in_bytes": 107374182400}
+//	  }
+//	}
+func LoadOwnerQuotas(path string) (gardener.OwnerQuotas, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return gardener.OwnerQuotas{}, fmt.Errorf("open quota config: %s", err)
+	}
+	defer file.Close()
+
+	var quotas gardener.OwnerQuotas
+	if err := json.NewDecoder(file).Decode(&quotas); err != nil {
+		return gardener.OwnerQuotas{}, fmt.Errorf("parse quota config: %s", err)
+	}
+
+	return quotas, nil
+}
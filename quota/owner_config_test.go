@@ -0,0 +1,62 @@
+package quota_test
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+
+	"code.cloudfoundry.org/guardian/gardener"
+	"code.cloudfoundry.org/guardian/quota"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("LoadOwnerQuotas", func() {
+	var (
+		dir  string
+		path string
+	)
+
+	BeforeEach(func() {
+		var err error
+		dir, err = ioutil.TempDir("", "quota-config")
+		Expect(err).NotTo(HaveOccurred())
+
+		path = filepath.Join(dir, "quotas.json")
+	})
+
+	AfterEach(func() {
+		Expect(os.RemoveAll(dir)).To(Succeed())
+	})
+
+	It("loads the default, unowned and per-owner quotas", func() {
+		Expect(ioutil.WriteFile(path, []byte(`{
+			"default": {"max_containers": 10},
+			"unowned": {"max_containers": 20},
+			"owners": {
+				"alice": {"max_containers": 50, "memory_in_bytes": 1024, "disk_in_bytes": 2048}
+			}
+		}`), 0600)).To(Succeed())
+
+		quotas, err := quota.LoadOwnerQuotas(path)
+		Expect(err).NotTo(HaveOccurred())
+
+		Expect(quotas.Default).To(Equal(&gardener.Quota{MaxContainers: 10}))
+		Expect(quotas.Unowned).To(Equal(&gardener.Quota{MaxContainers: 20}))
+		Expect(quotas.Owners).To(Equal(map[string]gardener.Quota{
+			"alice": {MaxContainers: 50, MemoryInBytes: 1024, DiskInBytes: 2048},
+		}))
+	})
+
+	It("returns an error when the file does not exist", func() {
+		_, err := quota.LoadOwnerQuotas(filepath.Join(dir, "nope.json"))
+		Expect(err).To(MatchError(ContainSubstring("open quota config")))
+	})
+
+	It("returns an error when the file is not valid JSON", func() {
+		Expect(ioutil.WriteFile(path, []byte("max_containers: 10"), 0600)).To(Succeed())
+
+		_, err := quota.LoadOwnerQuotas(path)
+		Expect(err).To(MatchError(ContainSubstring("parse quota config")))
+	})
+})
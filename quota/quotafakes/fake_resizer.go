@@ -0,0 +1,83 @@
+// This file was generated by counterfeiter
+package quotafakes
+
+import (
+	"code.cloudfoundry.org/garden"
+	"code.cloudfoundry.org/guardian/quota"
+	"code.cloudfoundry.org/lager"
+	"sync"
+)
+
+type FakeResizer struct {
+	ResizeStub        func(log lager.Logger, handle, rootFSPath string, limits garden.DiskLimits) error
+	resizeMutex       sync.RWMutex
+	resizeArgsForCall []struct {
+		log        lager.Logger
+		handle     string
+		rootFSPath string
+		limits     garden.DiskLimits
+	}
+	resizeReturns struct {
+		result1 error
+	}
+	invocations      map[string][][]interface{}
+	invocationsMutex sync.RWMutex
+}
+
+func (fake *FakeResizer) Resize(log lager.Logger, handle string, rootFSPath string, limits garden.DiskLimits) error {
+	fake.resizeMutex.Lock()
+	fake.resizeArgsForCall = append(fake.resizeArgsForCall, struct {
+		log        lager.Logger
+		handle     string
+		rootFSPath string
+		limits     garden.DiskLimits
+	}{log, handle, rootFSPath, limits})
+	fake.recordInvocation("Resize", []interface{}{log, handle, rootFSPath, limits})
+	fake.resizeMutex.Unlock()
+	if fake.ResizeStub != nil {
+		return fake.ResizeStub(log, handle, rootFSPath, limits)
+	} else {
+		return fake.resizeReturns.result1
+	}
+}
+
+func (fake *FakeResizer) ResizeCallCount() int {
+	fake.resizeMutex.RLock()
+	defer fake.resizeMutex.RUnlock()
+	return len(fake.resizeArgsForCall)
+}
+
+func (fake *FakeResizer) ResizeArgsForCall(i int) (lager.Logger, string, string, garden.DiskLimits) {
+	fake.resizeMutex.RLock()
+	defer fake.resizeMutex.RUnlock()
+	return fake.resizeArgsForCall[i].log, fake.resizeArgsForCall[i].handle, fake.resizeArgsForCall[i].rootFSPath, fake.resizeArgsForCall[i].limits
+}
+
+func (fake *FakeResizer) ResizeReturns(result1 error) {
+	fake.ResizeStub = nil
+	fake.resizeReturns = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *FakeResizer) Invocations() map[string][][]interface{} {
+	fake.invocationsMutex.RLock()
+	defer fake.invocationsMutex.RUnlock()
+	fake.resizeMutex.RLock()
+	defer fake.resizeMutex.RUnlock()
+	return fake.invocations
+}
+
+func (fake *FakeResizer) recordInvocation(key string, args []interface{}) {
+	fake.invocationsMutex.Lock()
+	defer fake.invocationsMutex.Unlock()
+	if fake.invocations == nil {
+		fake.invocations = map[string][][]interface{}{}
+	}
+	if fake.invocations[key] == nil {
+		fake.invocations[key] = [][]interface{}{}
+	}
+	fake.invocations[key] = append(fake.invocations[key], args)
+}
+
+var _ quota.Resizer = new(FakeResizer)
@@ -0,0 +1,31 @@
+package quota
+
+import (
+	"code.cloudfoundry.org/garden"
+	"code.cloudfoundry.org/garden-shed/rootfs_provider"
+	"code.cloudfoundry.org/lager"
+)
+
+//go:generate counterfeiter . Resizer
+
+type Resizer interface {
+	Resize(log lager.Logger, handle, rootFSPath string, limits garden.DiskLimits) error
+}
+
+type VolumeProvider interface {
+	Create(log lager.Logger, handle string, spec rootfs_provider.Spec) (string, []string, error)
+	Destroy(log lager.Logger, handle, rootFSPath string) error
+	Metrics(log lager.Logger, handle string) (garden.ContainerDiskStat, error)
+	GC(log lager.Logger) error
+}
+
+// ResizableVolumeCreator adds disk quota resizing to a volume provider, such
+// as garden-shed's CakeOrdinator, which can only set a quota on creation
+type ResizableVolumeCreator struct {
+	VolumeProvider
+	Resizer Resizer
+}
+
+func (v *ResizableVolumeCreator) Resize(log lager.Logger, handle, rootFSPath string, limits garden.DiskLimits) error {
+	return v.Resizer.Resize(log, handle, rootFSPath, limits)
+}
@@ -0,0 +1,50 @@
+package quota_test
+
+import (
+	"errors"
+
+	"code.cloudfoundry.org/garden"
+	"code.cloudfoundry.org/guardian/gardener"
+	"code.cloudfoundry.org/guardian/quota"
+	fakes "code.cloudfoundry.org/guardian/quota/quotafakes"
+	"code.cloudfoundry.org/lager/lagertest"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("ResizableVolumeCreator", func() {
+	var (
+		fakeResizer   *fakes.FakeResizer
+		volumeCreator *quota.ResizableVolumeCreator
+		logger        *lagertest.TestLogger
+	)
+
+	BeforeEach(func() {
+		fakeResizer = new(fakes.FakeResizer)
+		logger = lagertest.NewTestLogger("test")
+
+		volumeCreator = &quota.ResizableVolumeCreator{
+			VolumeProvider: gardener.NoopVolumeCreator{},
+			Resizer:        fakeResizer,
+		}
+	})
+
+	It("delegates resizing to the resizer", func() {
+		limits := garden.DiskLimits{ByteHard: 1024}
+		Expect(volumeCreator.Resize(logger, "some-handle", "/rootfs", limits)).To(Succeed())
+
+		Expect(fakeResizer.ResizeCallCount()).To(Equal(1))
+		_, handle, rootFSPath, resizedLimits := fakeResizer.ResizeArgsForCall(0)
+		Expect(handle).To(Equal("some-handle"))
+		Expect(rootFSPath).To(Equal("/rootfs"))
+		Expect(resizedLimits).To(Equal(limits))
+	})
+
+	Context("when the resizer fails", func() {
+		It("returns the error", func() {
+			fakeResizer.ResizeReturns(errors.New("banana"))
+			Expect(volumeCreator.Resize(logger, "some-handle", "/rootfs", garden.DiskLimits{})).To(MatchError("banana"))
+		})
+	})
+})
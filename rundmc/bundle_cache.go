@@ -0,0 +1,163 @@
+package rundmc
+
+import (
+	"sort"
+	"sync"
+
+	"code.cloudfoundry.org/guardian/rundmc/goci"
+	"code.cloudfoundry.org/lager"
+)
+
+// bundleCache holds the handles in the depot and the bundles loaded from it,
+// so that listing containers and getting their info does not go to disk
+// every time. Anything which changes the depot must invalidate it.
+type bundleCache struct {
+	mu sync.RWMutex
+
+	// handles is nil until the depot has been listed
+	handles map[string]struct{}
+	bundles map[string]cachedBundle
+
+	// version changes whenever the cache is invalidated, so that a load
+	// which raced with a change to the depot is not stored
+	version uint64
+}
+
+type cachedBundle struct {
+	path   string
+	bundle goci.Bndl
+}
+
+func (c *bundleCache) bundle(handle string) (cachedBundle, uint64, bool) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	cached, ok := c.bundles[handle]
+	return cached, c.version, ok
+}
+
+func (c *bundleCache) storeBundle(handle string, version uint64, cached cachedBundle) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if version != c.version {
+		return
+	}
+
+	if c.bundles == nil {
+		c.bundles = make(map[string]cachedBundle)
+	}
+
+	c.bundles[handle] = cached
+}
+
+func (c *bundleCache) handleList() ([]string, uint64, bool) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	if c.handles == nil {
+		return nil, c.version, false
+	}
+
+	handles := make([]string, 0, len(c.handles))
+	for handle := range c.handles {
+		handles = append(handles, handle)
+	}
+	sort.Strings(handles)
+
+	return handles, c.version, true
+}
+
+func (c *bundleCache) storeHandles(version uint64, handles []string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if version != c.version {
+		return
+	}
+
+	c.handles = make(map[string]struct{}, len(handles))
+	for _, handle := range handles {
+		c.handles[handle] = struct{}{}
+	}
+}
+
+// added records that a bundle has been created in the depot
+func (c *bundleCache) added(handle string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.version++
+	delete(c.bundles, handle)
+	if c.handles != nil {
+		c.handles[handle] = struct{}{}
+	}
+}
+
+// updated records that the bundle of a container has changed
+func (c *bundleCache) updated(handle string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.version++
+	delete(c.bundles, handle)
+}
+
+// removed records that a bundle has been removed from the depot
+func (c *bundleCache) removed(handle string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.version++
+	delete(c.bundles, handle)
+	if c.handles != nil {
+		delete(c.handles, handle)
+	}
+}
+
+// loadBundle returns the path and bundle of a container, from the cache if
+// it has been loaded before. The bundle is shared by every request, so it must
+// not be changed in place: callers derive changed bundles through the With
+// methods, which copy what they change.
+func (c *Containerizer) loadBundle(log lager.Logger, handle string) (string, goci.Bndl, error) {
+	cached, version, ok := c.cache.bundle(handle)
+	if ok {
+		return cached.path, cached.bundle, nil
+	}
+
+	path, err := c.depot.Lookup(log, handle)
+	if err != nil {
+		return "", goci.Bndl{}, err
+	}
+
+	bundle, err := c.loader.Load(path)
+	if err != nil {
+		return "", goci.Bndl{}, err
+	}
+
+	c.cache.storeBundle(handle, version, cachedBundle{path: path, bundle: bundle})
+	return path, bundle, nil
+}
+
+// RebuildCache lists the depot and loads every bundle in it, so that the
+// first requests after a restart are served from memory
+func (c *Containerizer) RebuildCache(log lager.Logger) error {
+	log = log.Session("rebuild-bundle-cache")
+
+	log.Info("started")
+	defer log.Info("finished")
+
+	handles, err := c.Handles()
+	if err != nil {
+		log.Error("handles-failed", err)
+		return err
+	}
+
+	for _, handle := range handles {
+		if _, _, err := c.loadBundle(log, handle); err != nil {
+			log.Error("load-bundle-failed", err, lager.Data{"handle": handle})
+		}
+	}
+
+	return nil
+}
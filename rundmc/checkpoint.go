@@ -0,0 +1,525 @@
+package rundmc
+
+import (
+	"archive/tar"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"syscall"
+
+	"code.cloudfoundry.org/garden"
+	"code.cloudfoundry.org/guardian/gardener"
+	"code.cloudfoundry.org/guardian/rundmc/goci"
+	"code.cloudfoundry.org/lager"
+)
+
+// The layout of a checkpoint archive
+const (
+	checkpointImageDir       = "checkpoint"
+	checkpointBundleDir      = "bundle"
+	checkpointRootFSDir      = "rootfs"
+	checkpointPropertiesFile = "properties.json"
+)
+
+// Checkpoint dumps the running container, its bundle, its root filesystem
+// and the given properties to archivePath, which is written as a tar archive
+// if it ends in '.tar' and as a directory otherwise. The container is left
+// stopped.
+func (c *Containerizer) Checkpoint(log lager.Logger, handle, archivePath string, properties garden.Properties) error {
+	log = log.Session("checkpoint", lager.Data{"handle": handle, "archive": archivePath})
+
+	log.Info("started")
+	defer log.Info("finished")
+
+	bundlePath, err := c.depot.Lookup(log, handle)
+	if err != nil {
+		log.Error("lookup-failed", err)
+		return err
+	}
+
+	bundle, err := c.loader.Load(bundlePath)
+	if err != nil {
+		log.Error("load-failed", err)
+		return err
+	}
+
+	dir := archivePath
+	if isTarArchive(archivePath) {
+		dir, err = ioutil.TempDir("", "checkpoint")
+		if err != nil {
+			return err
+		}
+		defer os.RemoveAll(dir)
+	}
+
+	if err := os.MkdirAll(filepath.Join(dir, checkpointBundleDir), 0700); err != nil {
+		return fmt.Errorf("checkpoint: %s", err)
+	}
+
+	if err := bundle.Save(filepath.Join(dir, checkpointBundleDir)); err != nil {
+		log.Error("save-bundle-failed", err)
+		return fmt.Errorf("checkpoint: %s", err)
+	}
+
+	if err := writeProperties(filepath.Join(dir, checkpointPropertiesFile), properties); err != nil {
+		log.Error("save-properties-failed", err)
+		return fmt.Errorf("checkpoint: %s", err)
+	}
+
+	if err := c.runtime.Checkpoint(log, handle, filepath.Join(dir, checkpointImageDir)); err != nil {
+		log.Error("runtime-checkpoint-failed", err)
+		return err
+	}
+	c.states.StoreStopped(handle)
+
+	// the root filesystem is copied once the processes are stopped, so that
+	// it matches the files they had open
+	if err := copyTree(bundle.RootFS(), filepath.Join(dir, checkpointRootFSDir)); err != nil {
+		log.Error("copy-rootfs-failed", err)
+		return fmt.Errorf("checkpoint: %s", err)
+	}
+
+	if isTarArchive(archivePath) {
+		if err := tarDir(dir, archivePath); err != nil {
+			log.Error("tar-failed", err)
+			return fmt.Errorf("checkpoint: %s", err)
+		}
+	}
+
+	return nil
+}
+
+// Restore creates a container with the given handle from a checkpoint
+// archive, and returns the properties stored in the archive. The container is
+// restored on to the root filesystem returned by prepare, which is given the
+// root filesystem stored in the archive to create it from.
+func (c *Containerizer) Restore(log lager.Logger, handle, archivePath string, prepare gardener.RestorePreparer) (garden.Properties, error) {
+	log = log.Session("restore", lager.Data{"handle": handle, "archive": archivePath})
+
+	log.Info("started")
+	defer log.Info("finished")
+
+	dir := archivePath
+	if isTarArchive(archivePath) {
+		var err error
+		dir, err = ioutil.TempDir("", "restore")
+		if err != nil {
+			return nil, err
+		}
+		defer os.RemoveAll(dir)
+
+		if err := untar(archivePath, dir); err != nil {
+			log.Error("untar-failed", err)
+			return nil, fmt.Errorf("restore: %s", err)
+		}
+	}
+
+	bundle, err := c.loader.Load(filepath.Join(dir, checkpointBundleDir))
+	if err != nil {
+		log.Error("load-failed", err)
+		return nil, err
+	}
+
+	properties, err := readProperties(filepath.Join(dir, checkpointPropertiesFile))
+	if err != nil {
+		log.Error("read-properties-failed", err)
+		return nil, fmt.Errorf("restore: %s", err)
+	}
+
+	rootFSPath, err := prepare(gardener.RestoreSpec{
+		Properties:         properties,
+		Limits:             bundleLimits(bundle),
+		ArchivedRootFSPath: filepath.Join(dir, checkpointRootFSDir),
+	})
+	if err != nil {
+		log.Error("prepare-failed", err)
+		return nil, err
+	}
+	bundle = bundle.WithRootFS(rootFSPath)
+
+	if err := c.depot.Create(log, handle, bundle); err != nil {
+		log.Error("depot-create-failed", err)
+		return nil, err
+	}
+	c.cache.added(handle)
+
+	bundlePath, err := c.depot.Lookup(log, handle)
+	if err != nil {
+		log.Error("lookup-failed", err)
+		return nil, err
+	}
+
+	if err := c.runtime.Restore(log, handle, bundlePath, filepath.Join(dir, checkpointImageDir)); err != nil {
+		log.Error("runtime-restore-failed", err)
+		return nil, err
+	}
+
+	c.watchEvents(log, handle)
+
+	return properties, nil
+}
+
+func isTarArchive(path string) bool {
+	return strings.HasSuffix(path, ".tar")
+}
+
+func writeProperties(path string, properties garden.Properties) error {
+	contents, err := json.Marshal(properties)
+	if err != nil {
+		return err
+	}
+
+	return ioutil.WriteFile(path, contents, 0600)
+}
+
+func readProperties(path string) (garden.Properties, error) {
+	contents, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	properties := garden.Properties{}
+	if err := json.Unmarshal(contents, &properties); err != nil {
+		return nil, err
+	}
+
+	return properties, nil
+}
+
+func tarDir(dir, tarPath string) error {
+	file, err := os.Create(tarPath)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	tw := tar.NewWriter(file)
+	links := hardlinks{}
+	if err := filepath.Walk(dir, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+
+		name, err := filepath.Rel(dir, path)
+		if err != nil || name == "." {
+			return err
+		}
+
+		var link string
+		if info.Mode()&os.ModeSymlink != 0 {
+			if link, err = os.Readlink(path); err != nil {
+				return err
+			}
+		} else if !info.IsDir() && !info.Mode().IsRegular() {
+			// devices, sockets and pipes are provided by the runtime rather
+			// than the root filesystem
+			return nil
+		}
+
+		header, err := tar.FileInfoHeader(info, link)
+		if err != nil {
+			return err
+		}
+		header.Name = name
+
+		if stat, ok := info.Sys().(*syscall.Stat_t); ok && info.Mode().IsRegular() {
+			if first, ok := links.seen(stat, name); ok {
+				header.Typeflag = tar.TypeLink
+				header.Linkname = first
+				header.Size = 0
+				return tw.WriteHeader(header)
+			}
+		}
+
+		if err := tw.WriteHeader(header); err != nil {
+			return err
+		}
+
+		if !info.Mode().IsRegular() {
+			return nil
+		}
+
+		f, err := os.Open(path)
+		if err != nil {
+			return err
+		}
+		defer f.Close()
+
+		_, err = io.Copy(tw, f)
+		return err
+	}); err != nil {
+		return err
+	}
+
+	return tw.Close()
+}
+
+func untar(tarPath, dir string) error {
+	file, err := os.Open(tarPath)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	var dirs dirAttributes
+	tr := tar.NewReader(file)
+	for {
+		header, err := tr.Next()
+		if err == io.EOF {
+			return dirs.apply()
+		}
+		if err != nil {
+			return err
+		}
+
+		path := filepath.Join(dir, header.Name)
+		if !strings.HasPrefix(path, filepath.Clean(dir)+string(os.PathSeparator)) {
+			return fmt.Errorf("invalid path in archive: %s", header.Name)
+		}
+
+		// the archive may contain symlinks to anywhere, which must never be
+		// followed when writing the entries after them
+		if err := checkNoSymlinks(dir, path); err != nil {
+			return err
+		}
+
+		mode := header.FileInfo().Mode()
+		switch header.Typeflag {
+		case tar.TypeDir:
+			if err := os.MkdirAll(path, 0700); err != nil {
+				return err
+			}
+			dirs.add(path, header.Uid, header.Gid, mode)
+			continue
+		case tar.TypeReg, tar.TypeRegA:
+			if err := os.MkdirAll(filepath.Dir(path), 0700); err != nil {
+				return err
+			}
+
+			if err := writeFile(path, tr, mode); err != nil {
+				return err
+			}
+		case tar.TypeSymlink:
+			if err := os.MkdirAll(filepath.Dir(path), 0700); err != nil {
+				return err
+			}
+
+			if err := os.Symlink(header.Linkname, path); err != nil {
+				return err
+			}
+		case tar.TypeLink:
+			target := filepath.Join(dir, header.Linkname)
+			if !strings.HasPrefix(target, filepath.Clean(dir)+string(os.PathSeparator)) {
+				return fmt.Errorf("invalid link in archive: %s", header.Linkname)
+			}
+
+			if err := checkNoSymlinks(dir, target); err != nil {
+				return err
+			}
+
+			if err := os.MkdirAll(filepath.Dir(path), 0700); err != nil {
+				return err
+			}
+
+			// the link shares the owner and mode of its target
+			if err := os.Link(target, path); err != nil {
+				return err
+			}
+			continue
+		default:
+			// checkpoints never contain devices, sockets or pipes, so
+			// anything else did not come from Checkpoint
+			return fmt.Errorf("unsupported entry in archive: %s", header.Name)
+		}
+
+		if err := setOwnerAndMode(path, header.Uid, header.Gid, mode); err != nil {
+			return err
+		}
+	}
+}
+
+// copyTree copies the directories, regular files and symlinks under src to
+// dst, keeping their ownership and modes, and which files are hardlinked
+func copyTree(src, dst string) error {
+	var dirs dirAttributes
+	links := hardlinks{}
+	if err := filepath.Walk(src, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+
+		name, err := filepath.Rel(src, path)
+		if err != nil {
+			return err
+		}
+		target := filepath.Join(dst, name)
+
+		stat, ok := info.Sys().(*syscall.Stat_t)
+		if !ok {
+			return fmt.Errorf("cannot get the owner of %s", path)
+		}
+		uid, gid := int(stat.Uid), int(stat.Gid)
+
+		mode := info.Mode()
+		switch {
+		case mode.IsDir():
+			if err := os.MkdirAll(target, 0700); err != nil {
+				return err
+			}
+			dirs.add(target, uid, gid, mode)
+			return nil
+		case mode.IsRegular():
+			if first, ok := links.seen(stat, target); ok {
+				return os.Link(first, target)
+			}
+
+			f, err := os.Open(path)
+			if err != nil {
+				return err
+			}
+			defer f.Close()
+
+			if err := writeFile(target, f, mode); err != nil {
+				return err
+			}
+		case mode&os.ModeSymlink != 0:
+			link, err := os.Readlink(path)
+			if err != nil {
+				return err
+			}
+
+			if err := os.Symlink(link, target); err != nil {
+				return err
+			}
+		default:
+			// devices, sockets and pipes are provided by the runtime rather
+			// than the root filesystem
+			return nil
+		}
+
+		return setOwnerAndMode(target, uid, gid, mode)
+	}); err != nil {
+		return err
+	}
+
+	return dirs.apply()
+}
+
+// hardlinks remembers the first path written for each file with more than
+// one link, so that the others can be written as links to it
+type hardlinks map[hardlinkKey]string
+
+type hardlinkKey struct {
+	dev, ino uint64
+}
+
+// seen returns the first path written for the file, or records path as that
+// path if this is the first time the file has been seen
+func (h hardlinks) seen(stat *syscall.Stat_t, path string) (string, bool) {
+	if stat.Nlink < 2 {
+		return "", false
+	}
+
+	key := hardlinkKey{dev: uint64(stat.Dev), ino: uint64(stat.Ino)}
+	if first, ok := h[key]; ok {
+		return first, true
+	}
+
+	h[key] = path
+	return "", false
+}
+
+// dirAttributes holds the owners and modes of directories until everything
+// has been written in to them, as they may not be writable once they are set
+type dirAttributes []dirAttribute
+
+type dirAttribute struct {
+	path     string
+	uid, gid int
+	mode     os.FileMode
+}
+
+func (d *dirAttributes) add(path string, uid, gid int, mode os.FileMode) {
+	*d = append(*d, dirAttribute{path: path, uid: uid, gid: gid, mode: mode})
+}
+
+func (d dirAttributes) apply() error {
+	// children first, so that a parent is never made unwritable too early
+	for i := len(d) - 1; i >= 0; i-- {
+		if err := setOwnerAndMode(d[i].path, d[i].uid, d[i].gid, d[i].mode); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func setOwnerAndMode(path string, uid, gid int, mode os.FileMode) error {
+	if err := os.Lchown(path, uid, gid); err != nil {
+		return err
+	}
+
+	// symlinks have no mode of their own
+	if mode&os.ModeSymlink != 0 {
+		return nil
+	}
+
+	// the mode is set after the owner, as changing the owner clears the
+	// setuid and setgid bits
+	return os.Chmod(path, mode)
+}
+
+// checkNoSymlinks returns an error if path, or any directory between it and
+// dir, is a symlink
+func checkNoSymlinks(dir, path string) error {
+	dir = filepath.Clean(dir)
+	for p := filepath.Clean(path); p != dir && strings.HasPrefix(p, dir); p = filepath.Dir(p) {
+		info, err := os.Lstat(p)
+		if os.IsNotExist(err) {
+			continue
+		}
+		if err != nil {
+			return err
+		}
+
+		if info.Mode()&os.ModeSymlink != 0 {
+			return fmt.Errorf("invalid path in archive: %s is a symlink", p)
+		}
+	}
+
+	return nil
+}
+
+func bundleLimits(bundle goci.Bndl) garden.Limits {
+	var limits garden.Limits
+
+	resources := bundle.Resources()
+	if resources == nil {
+		return limits
+	}
+
+	if resources.CPU != nil && resources.CPU.Shares != nil {
+		limits.CPU.LimitInShares = *resources.CPU.Shares
+	}
+
+	if resources.Memory != nil && resources.Memory.Limit != nil {
+		limits.Memory.LimitInBytes = *resources.Memory.Limit
+	}
+
+	return limits
+}
+
+func writeFile(path string, r io.Reader, mode os.FileMode) error {
+	f, err := os.OpenFile(path, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, mode)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	_, err = io.Copy(f, r)
+	return err
+}
@@ -0,0 +1,281 @@
+package rundmc_test
+
+import (
+	"archive/tar"
+	"errors"
+	"io/ioutil"
+	"os"
+	"os/exec"
+	"path/filepath"
+
+	"code.cloudfoundry.org/garden"
+	"code.cloudfoundry.org/guardian/gardener"
+	"code.cloudfoundry.org/guardian/rundmc"
+	"code.cloudfoundry.org/guardian/rundmc/depot"
+	"code.cloudfoundry.org/guardian/rundmc/goci"
+	fakes "code.cloudfoundry.org/guardian/rundmc/rundmcfakes"
+	"code.cloudfoundry.org/lager"
+	"code.cloudfoundry.org/lager/lagertest"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("Checkpoint and Restore", func() {
+	var (
+		fakeDepot      *fakes.FakeDepot
+		fakeOCIRuntime *fakes.FakeOCIRuntime
+		fakeStateStore *fakes.FakeStateStore
+		fakeEventStore *fakes.FakeEventStore
+
+		workDir       string
+		logger        lager.Logger
+		containerizer *rundmc.Containerizer
+		properties    garden.Properties
+	)
+
+	BeforeEach(func() {
+		var err error
+		workDir, err = ioutil.TempDir("", "checkpoint-test")
+		Expect(err).NotTo(HaveOccurred())
+
+		fakeDepot = new(fakes.FakeDepot)
+		fakeOCIRuntime = new(fakes.FakeOCIRuntime)
+		fakeStateStore = new(fakes.FakeStateStore)
+		fakeEventStore = new(fakes.FakeEventStore)
+		logger = lagertest.NewTestLogger("test")
+
+		rootFSPath := filepath.Join(workDir, "rootfs")
+		Expect(os.MkdirAll(filepath.Join(rootFSPath, "etc"), 0755)).To(Succeed())
+		Expect(ioutil.WriteFile(filepath.Join(rootFSPath, "etc", "motd"), []byte("hello"), 0640)).To(Succeed())
+		Expect(os.Symlink("etc/motd", filepath.Join(rootFSPath, "motd"))).To(Succeed())
+		Expect(os.Link(filepath.Join(rootFSPath, "etc", "motd"), filepath.Join(rootFSPath, "etc", "issue"))).To(Succeed())
+
+		bundlePath := filepath.Join(workDir, "depot", "some-handle")
+		Expect(os.MkdirAll(bundlePath, 0700)).To(Succeed())
+		Expect(goci.Bundle().WithHostname("some-handle").WithRootFS(rootFSPath).Save(bundlePath)).To(Succeed())
+
+		fakeDepot.LookupStub = func(_ lager.Logger, handle string) (string, error) {
+			return filepath.Join(workDir, "depot", handle), nil
+		}
+
+		fakeOCIRuntime.CheckpointStub = func(_ lager.Logger, _, imagePath string) error {
+			Expect(os.MkdirAll(imagePath, 0700)).To(Succeed())
+			return ioutil.WriteFile(filepath.Join(imagePath, "pages-1.img"), []byte("memory"), 0600)
+		}
+
+		properties = garden.Properties{"foo": "bar", "kawasaki.subnet": "10.0.0.0/30"}
+
+		containerizer = rundmc.New(fakeDepot, new(fakes.FakeBundleGenerator), fakeOCIRuntime, &goci.BndlLoader{}, new(fakes.FakeNstarRunner), new(fakes.FakeStopper), fakeEventStore, fakeStateStore, new(fakes.FakeStatsStore), nil)
+	})
+
+	AfterEach(func() {
+		Expect(os.RemoveAll(workDir)).To(Succeed())
+	})
+
+	prepareRootFS := func(gardener.RestoreSpec) (string, error) {
+		return "/path/to/restored/rootfs", nil
+	}
+
+	itRoundTrips := func(archiveName string) {
+		var archivePath string
+
+		BeforeEach(func() {
+			archivePath = filepath.Join(workDir, archiveName)
+			Expect(containerizer.Checkpoint(logger, "some-handle", archivePath, properties)).To(Succeed())
+		})
+
+		It("asks the runtime to checkpoint the container", func() {
+			Expect(fakeOCIRuntime.CheckpointCallCount()).To(Equal(1))
+			_, id, _ := fakeOCIRuntime.CheckpointArgsForCall(0)
+			Expect(id).To(Equal("some-handle"))
+		})
+
+		It("marks the container as stopped", func() {
+			Expect(fakeStateStore.StoreStoppedCallCount()).To(Equal(1))
+			Expect(fakeStateStore.StoreStoppedArgsForCall(0)).To(Equal("some-handle"))
+		})
+
+		Describe("restoring the archive", func() {
+			var (
+				restoredProperties garden.Properties
+				restoredImage      []byte
+				restoreSpec        gardener.RestoreSpec
+				archivedMotd       []byte
+				archivedLink       string
+				archivedHardlinked bool
+			)
+
+			BeforeEach(func() {
+				fakeOCIRuntime.RestoreStub = func(_ lager.Logger, _, _, imagePath string) error {
+					var err error
+					restoredImage, err = ioutil.ReadFile(filepath.Join(imagePath, "pages-1.img"))
+					return err
+				}
+
+				var err error
+				restoredProperties, err = containerizer.Restore(logger, "new-handle", archivePath, func(spec gardener.RestoreSpec) (string, error) {
+					restoreSpec = spec
+
+					var err error
+					archivedMotd, err = ioutil.ReadFile(filepath.Join(spec.ArchivedRootFSPath, "etc", "motd"))
+					Expect(err).NotTo(HaveOccurred())
+					archivedLink, err = os.Readlink(filepath.Join(spec.ArchivedRootFSPath, "motd"))
+					Expect(err).NotTo(HaveOccurred())
+
+					motd, err := os.Stat(filepath.Join(spec.ArchivedRootFSPath, "etc", "motd"))
+					Expect(err).NotTo(HaveOccurred())
+					issue, err := os.Stat(filepath.Join(spec.ArchivedRootFSPath, "etc", "issue"))
+					Expect(err).NotTo(HaveOccurred())
+					archivedHardlinked = os.SameFile(motd, issue)
+
+					return "/path/to/restored/rootfs", nil
+				})
+				Expect(err).NotTo(HaveOccurred())
+			})
+
+			It("creates the checkpointed bundle in the depot under the new handle", func() {
+				Expect(fakeDepot.CreateCallCount()).To(Equal(1))
+				_, handle, bundle := fakeDepot.CreateArgsForCall(0)
+				Expect(handle).To(Equal("new-handle"))
+				Expect(bundle.(goci.Bndl).Hostname()).To(Equal("some-handle"))
+			})
+
+			It("gives the root filesystem in the archive and the properties to prepare", func() {
+				Expect(archivedMotd).To(Equal([]byte("hello")))
+				Expect(archivedLink).To(Equal("etc/motd"))
+				Expect(archivedHardlinked).To(BeTrue())
+				Expect(restoreSpec.Properties).To(Equal(properties))
+			})
+
+			It("restores the container on to the root filesystem returned by prepare", func() {
+				_, _, bundle := fakeDepot.CreateArgsForCall(0)
+				Expect(bundle.(goci.Bndl).RootFS()).To(Equal("/path/to/restored/rootfs"))
+			})
+
+			It("asks the runtime to restore the checkpoint in to the new bundle", func() {
+				Expect(fakeOCIRuntime.RestoreCallCount()).To(Equal(1))
+				_, id, bundlePath, _ := fakeOCIRuntime.RestoreArgsForCall(0)
+				Expect(id).To(Equal("new-handle"))
+				Expect(bundlePath).To(Equal(filepath.Join(workDir, "depot", "new-handle")))
+				Expect(restoredImage).To(Equal([]byte("memory")))
+			})
+
+			It("returns the checkpointed properties", func() {
+				Expect(restoredProperties).To(Equal(properties))
+			})
+
+			It("watches the restored container for events", func() {
+				Eventually(fakeOCIRuntime.WatchEventsCallCount).Should(Equal(1))
+			})
+		})
+	}
+
+	Context("when checkpointing to a directory", func() {
+		itRoundTrips("archive-dir")
+	})
+
+	Context("when checkpointing to a tar archive", func() {
+		itRoundTrips("archive.tar")
+
+		It("produces a single archive file", func() {
+			info, err := os.Stat(filepath.Join(workDir, "archive.tar"))
+			Expect(err).NotTo(HaveOccurred())
+			Expect(info.Mode().IsRegular()).To(BeTrue())
+
+			cmd := exec.Command("tar", "-tf", filepath.Join(workDir, "archive.tar"))
+			Expect(cmd.Output()).To(SatisfyAll(
+				ContainSubstring("bundle/config.json"),
+				ContainSubstring("checkpoint/pages-1.img"),
+				ContainSubstring("properties.json"),
+				ContainSubstring("rootfs/etc/motd"),
+			))
+		})
+	})
+
+	Context("when the runtime fails to checkpoint", func() {
+		It("returns the error without marking the container as stopped", func() {
+			fakeOCIRuntime.CheckpointReturns(errors.New("criu-says-no"))
+
+			Expect(containerizer.Checkpoint(logger, "some-handle", filepath.Join(workDir, "archive"), properties)).To(MatchError("criu-says-no"))
+			Expect(fakeStateStore.StoreStoppedCallCount()).To(Equal(0))
+		})
+	})
+
+	Context("when the container does not exist", func() {
+		It("returns an error", func() {
+			fakeDepot.LookupReturns("", depot.ErrDoesNotExist)
+
+			Expect(containerizer.Checkpoint(logger, "missing-handle", filepath.Join(workDir, "archive"), properties)).To(MatchError(depot.ErrDoesNotExist))
+		})
+	})
+
+	Context("when restoring from an archive that does not exist", func() {
+		It("returns an error without creating the container", func() {
+			_, err := containerizer.Restore(logger, "new-handle", filepath.Join(workDir, "missing"), prepareRootFS)
+			Expect(err).To(HaveOccurred())
+			Expect(fakeDepot.CreateCallCount()).To(Equal(0))
+		})
+	})
+
+	Context("when preparing the restore fails", func() {
+		It("returns the error without creating the container", func() {
+			archivePath := filepath.Join(workDir, "archive")
+			Expect(containerizer.Checkpoint(logger, "some-handle", archivePath, properties)).To(Succeed())
+
+			_, err := containerizer.Restore(logger, "new-handle", archivePath, func(gardener.RestoreSpec) (string, error) {
+				return "", errors.New("not-admitted")
+			})
+			Expect(err).To(MatchError("not-admitted"))
+			Expect(fakeDepot.CreateCallCount()).To(Equal(0))
+			Expect(fakeOCIRuntime.RestoreCallCount()).To(Equal(0))
+		})
+	})
+
+	Context("when the runtime fails to restore", func() {
+		It("returns the error", func() {
+			archivePath := filepath.Join(workDir, "archive")
+			Expect(containerizer.Checkpoint(logger, "some-handle", archivePath, properties)).To(Succeed())
+
+			fakeOCIRuntime.RestoreReturns(errors.New("criu-is-sad"))
+			_, err := containerizer.Restore(logger, "new-handle", archivePath, prepareRootFS)
+			Expect(err).To(MatchError("criu-is-sad"))
+		})
+	})
+
+	Context("when a tar archive writes through a symlink", func() {
+		It("refuses to restore it", func() {
+			outside := filepath.Join(workDir, "outside")
+			Expect(os.Mkdir(outside, 0700)).To(Succeed())
+
+			archivePath := filepath.Join(workDir, "evil.tar")
+			f, err := os.Create(archivePath)
+			Expect(err).NotTo(HaveOccurred())
+			tw := tar.NewWriter(f)
+			Expect(tw.WriteHeader(&tar.Header{Name: "rootfs/escape", Typeflag: tar.TypeSymlink, Linkname: outside, Uid: os.Getuid(), Gid: os.Getgid()})).To(Succeed())
+			Expect(tw.WriteHeader(&tar.Header{Name: "rootfs/escape/pwned", Typeflag: tar.TypeReg, Mode: 0600, Size: 1, Uid: os.Getuid(), Gid: os.Getgid()})).To(Succeed())
+			_, err = tw.Write([]byte("x"))
+			Expect(err).NotTo(HaveOccurred())
+			Expect(tw.Close()).To(Succeed())
+			Expect(f.Close()).To(Succeed())
+
+			_, err = containerizer.Restore(logger, "new-handle", archivePath, prepareRootFS)
+			Expect(err).To(MatchError(ContainSubstring("is a symlink")))
+			Expect(filepath.Join(outside, "pwned")).NotTo(BeAnExistingFile())
+		})
+	})
+
+	Context("when a tar archive contains an entry which checkpoints never do", func() {
+		It("refuses to restore it", func() {
+			archivePath := filepath.Join(workDir, "fifo.tar")
+			f, err := os.Create(archivePath)
+			Expect(err).NotTo(HaveOccurred())
+			tw := tar.NewWriter(f)
+			Expect(tw.WriteHeader(&tar.Header{Name: "rootfs/fifo", Typeflag: tar.TypeFifo, Mode: 0600, Uid: os.Getuid(), Gid: os.Getgid()})).To(Succeed())
+			Expect(tw.Close()).To(Succeed())
+			Expect(f.Close()).To(Succeed())
+
+			_, err = containerizer.Restore(logger, "new-handle", archivePath, prepareRootFS)
+			Expect(err).To(MatchError(ContainSubstring("unsupported entry in archive: rootfs/fifo")))
+		})
+	})
+})
@@ -3,13 +3,18 @@ package rundmc
 import (
 	"fmt"
 	"io"
+	"strconv"
+	"strings"
+	"sync"
 
 	"code.cloudfoundry.org/garden"
 	"code.cloudfoundry.org/guardian/gardener"
 	"code.cloudfoundry.org/guardian/rundmc/depot"
 	"code.cloudfoundry.org/guardian/rundmc/goci"
 	"code.cloudfoundry.org/guardian/rundmc/runrunc"
+	"code.cloudfoundry.org/guardian/tracing"
 	"code.cloudfoundry.org/lager"
+	"github.com/opencontainers/runtime-spec/specs-go"
 )
 
 //go:generate counterfeiter . Depot
@@ -20,9 +25,11 @@ import (
 //go:generate counterfeiter . BundleLoader
 //go:generate counterfeiter . Stopper
 //go:generate counterfeiter . StateStore
+//go:generate counterfeiter . StatsStore
 
 type Depot interface {
 	Create(log lager.Logger, handle string, bundle depot.BundleSaver) error
+	Update(log lager.Logger, handle string, bundle depot.BundleSaver) error
 	Lookup(log lager.Logger, handle string) (path string, err error)
 	Destroy(log lager.Logger, handle string) error
 	Handles() ([]string, error)
@@ -42,9 +49,15 @@ type OCIRuntime interface {
 	Attach(log lager.Logger, id, bundlePath, processId string, io garden.ProcessIO) (garden.Process, error)
 	Kill(log lager.Logger, bundlePath string) error
 	Delete(log lager.Logger, bundlePath string) error
+	ForceDelete(log lager.Logger, id string) error
 	State(log lager.Logger, id string) (runrunc.State, error)
 	Stats(log lager.Logger, id string) (gardener.ActualContainerMetrics, error)
 	WatchEvents(log lager.Logger, id string, eventsNotifier runrunc.EventsNotifier) error
+	Update(log lager.Logger, id string, resources specs.Resources) error
+	Pause(log lager.Logger, id string) error
+	Resume(log lager.Logger, id string) error
+	Checkpoint(log lager.Logger, id, imagePath string) error
+	Restore(log lager.Logger, id, bundlePath, imagePath string) error
 }
 
 type NstarRunner interface {
@@ -57,13 +70,23 @@ type Stopper interface {
 }
 
 type EventStore interface {
-	OnEvent(id string, event string) error
-	Events(id string) []string
+	OnEvent(id string, event gardener.Event) error
+	Events(id string) []gardener.Event
+	Forget(id string)
 }
 
 type StateStore interface {
 	StoreStopped(handle string)
 	IsStopped(handle string) bool
+	StorePaused(handle string)
+	StoreRunning(handle string)
+	IsPaused(handle string) bool
+}
+
+type StatsStore interface {
+	Track(handle string)
+	Latest(handle string) (gardener.ActualContainerMetrics, bool)
+	Forget(handle string)
 }
 
 // Containerizer knows how to manage a depot of container bundles
@@ -76,9 +99,16 @@ type Containerizer struct {
 	nstar   NstarRunner
 	events  EventStore
 	states  StateStore
+	stats   StatsStore
+	stages  gardener.StageRecorder
+
+	cache *bundleCache
+
+	exitsMu sync.Mutex
+	exits   map[string]*processExit
 }
 
-func New(depot Depot, bundler BundleGenerator, runtime OCIRuntime, loader BundleLoader, nstarRunner NstarRunner, stopper Stopper, events EventStore, states StateStore) *Containerizer {
+func New(depot Depot, bundler BundleGenerator, runtime OCIRuntime, loader BundleLoader, nstarRunner NstarRunner, stopper Stopper, events EventStore, states StateStore, stats StatsStore, stages gardener.StageRecorder) *Containerizer {
 	return &Containerizer{
 		depot:   depot,
 		bundler: bundler,
@@ -88,55 +118,123 @@ func New(depot Depot, bundler BundleGenerator, runtime OCIRuntime, loader Bundle
 		stopper: stopper,
 		events:  events,
 		states:  states,
+		stats:   stats,
+		stages:  stages,
+		cache:   &bundleCache{},
+		exits:   make(map[string]*processExit),
 	}
 }
 
 // Create creates a bundle in the depot and starts its init process
-func (c *Containerizer) Create(log lager.Logger, spec gardener.DesiredContainerSpec) error {
-	log = log.Session("containerizer-create", lager.Data{"handle": spec.Handle})
+func (c *Containerizer) Create(log lager.Logger, spec gardener.DesiredContainerSpec) (err error) {
+	log, span := tracing.StartSpan(log.Session("containerizer-create", lager.Data{"handle": spec.Handle}), "containerizer-create")
 
 	log.Info("start")
-	defer log.Info("finished")
+	timer := gardener.NewStageTimer(c.stages, "containerizer-create")
+	defer func() {
+		log.Info("finished", timer.Finish(err))
+		span.End(err)
+	}()
 
-	if err := c.depot.Create(log, spec.Handle, c.bundler.Generate(spec)); err != nil {
+	if err := timer.Time("depot-create", func() error {
+		return c.depot.Create(log, spec.Handle, c.bundler.Generate(spec))
+	}); err != nil {
 		log.Error("depot-create-failed", err)
 		return err
 	}
+	c.cache.added(spec.Handle)
 
+	endStage := timer.Start("depot-lookup")
 	path, err := c.depot.Lookup(log, spec.Handle)
+	endStage(err)
 	if err != nil {
 		log.Error("lookup-failed", err)
 		return err
 	}
 
-	if err = c.runtime.Create(log, path, spec.Handle, garden.ProcessIO{}); err != nil {
+	if err = timer.Time("runtime-create", func() error {
+		return c.runtime.Create(log, path, spec.Handle, garden.ProcessIO{})
+	}); err != nil {
 		log.Error("runtime-create-failed", err)
 		return err
 	}
 
+	c.recordEvent(log, spec.Handle, gardener.EventCreated, nil)
+	c.watchEvents(log, spec.Handle)
+
+	return nil
+}
+
+// WatchExisting follows the events and stats of every container in the depot
+// which has not stopped. The watchers of the containers which were running
+// before a restart did not survive it.
+func (c *Containerizer) WatchExisting(log lager.Logger) error {
+	log = log.Session("watch-existing")
+
+	log.Info("started")
+	defer log.Info("finished")
+
+	handles, err := c.Handles()
+	if err != nil {
+		log.Error("handles-failed", err)
+		return err
+	}
+
+	for _, handle := range handles {
+		state, err := c.runtime.State(log, handle)
+		if err != nil {
+			log.Error("state-failed", err, lager.Data{"handle": handle})
+			continue
+		}
+
+		if state.Status == runrunc.StoppedStatus {
+			continue
+		}
+
+		c.watchEvents(log.Session("watch", lager.Data{"handle": handle}), handle)
+	}
+
+	return nil
+}
+
+// watchEvents follows the events and stats of a container in the background
+func (c *Containerizer) watchEvents(log lager.Logger, handle string) {
+	c.stats.Track(handle)
+
 	go func() {
-		if err := c.runtime.WatchEvents(log, spec.Handle, c.events); err != nil {
+		if err := c.runtime.WatchEvents(log, handle, c.events); err != nil {
 			log.Error("watch-failed", err)
 		}
 	}()
-
-	return nil
 }
 
 // Run runs a process inside a running container
-func (c *Containerizer) Run(log lager.Logger, handle string, spec garden.ProcessSpec, io garden.ProcessIO) (garden.Process, error) {
-	log = log.Session("run", lager.Data{"handle": handle, "path": spec.Path})
+func (c *Containerizer) Run(log lager.Logger, handle string, spec garden.ProcessSpec, io garden.ProcessIO) (_ garden.Process, err error) {
+	log, span := tracing.StartSpan(log.Session("run", lager.Data{"handle": handle, "path": spec.Path}), "containerizer-run")
 
 	log.Info("started")
-	defer log.Info("finished")
+	timer := gardener.NewStageTimer(c.stages, "run")
+	defer func() {
+		log.Info("finished", timer.Finish(err))
+		span.End(err)
+	}()
 
+	endStage := timer.Start("depot-lookup")
 	path, err := c.depot.Lookup(log, handle)
+	endStage(err)
 	if err != nil {
 		log.Error("lookup-failed", err)
 		return nil, err
 	}
 
-	return c.runtime.Exec(log, path, handle, spec, io)
+	endStage = timer.Start("runtime-exec")
+	process, err := c.runtime.Exec(log, path, handle, spec, io)
+	endStage(err)
+	if err != nil {
+		return nil, err
+	}
+
+	return c.watchExit(log, handle, process), nil
 }
 
 func (c *Containerizer) Attach(log lager.Logger, handle string, processID string, io garden.ProcessIO) (garden.Process, error) {
@@ -151,24 +249,40 @@ func (c *Containerizer) Attach(log lager.Logger, handle string, processID string
 		return nil, err
 	}
 
-	return c.runtime.Attach(log, path, handle, processID, io)
+	process, err := c.runtime.Attach(log, path, handle, processID, io)
+	if err != nil {
+		return nil, err
+	}
+
+	return c.watchExit(log, handle, process), nil
 }
 
 // StreamIn streams files in to the container
-func (c *Containerizer) StreamIn(log lager.Logger, handle string, spec garden.StreamInSpec) error {
+func (c *Containerizer) StreamIn(log lager.Logger, handle string, spec garden.StreamInSpec) (err error) {
 	log = log.Session("stream-in", lager.Data{"handle": handle})
 
 	log.Info("started")
-	defer log.Info("finished")
+	timer := gardener.NewStageTimer(c.stages, "containerizer-stream-in")
+	defer func() {
+		log.Info("finished", timer.Finish(err))
+	}()
 
+	endStage := timer.Start("runtime-state")
 	state, err := c.runtime.State(log, handle)
+	endStage(err)
 	if err != nil {
 		log.Error("check-pid-failed", err)
 		return fmt.Errorf("stream-in: pid not found for container")
 	}
 
-	if err := c.nstar.StreamIn(log, state.Pid, spec.Path, spec.User, spec.TarStream); err != nil {
+	if err := timer.Time("nstar-stream-in", func() error {
+		return c.nstar.StreamIn(log, state.Pid, spec.Path, spec.User, spec.TarStream)
+	}); err != nil {
 		log.Error("nstar-failed", err)
+		if strings.Contains(err.Error(), "quota exceeded") {
+			c.recordEvent(log, handle, gardener.EventQuotaExceeded, map[string]string{"resource": "disk"})
+		}
+
 		return fmt.Errorf("stream-in: nstar: %s", err)
 	}
 
@@ -176,19 +290,26 @@ func (c *Containerizer) StreamIn(log lager.Logger, handle string, spec garden.St
 }
 
 // StreamOut stream files from the container
-func (c *Containerizer) StreamOut(log lager.Logger, handle string, spec garden.StreamOutSpec) (io.ReadCloser, error) {
+func (c *Containerizer) StreamOut(log lager.Logger, handle string, spec garden.StreamOutSpec) (_ io.ReadCloser, err error) {
 	log = log.Session("stream-out", lager.Data{"handle": handle})
 
 	log.Info("started")
-	defer log.Info("finished")
+	timer := gardener.NewStageTimer(c.stages, "containerizer-stream-out")
+	defer func() {
+		log.Info("finished", timer.Finish(err))
+	}()
 
+	endStage := timer.Start("runtime-state")
 	state, err := c.runtime.State(log, handle)
+	endStage(err)
 	if err != nil {
 		log.Error("check-pid-failed", err)
 		return nil, fmt.Errorf("stream-out: pid not found for container")
 	}
 
+	endStage = timer.Start("nstar-stream-out")
 	stream, err := c.nstar.StreamOut(log, state.Pid, spec.Path, spec.User)
+	endStage(err)
 	if err != nil {
 		log.Error("nstar-failed", err)
 		return nil, fmt.Errorf("stream-out: nstar: %s", err)
@@ -216,6 +337,43 @@ func (c *Containerizer) Stop(log lager.Logger, handle string, kill bool) error {
 	}
 
 	c.states.StoreStopped(handle)
+	c.recordEvent(log, handle, gardener.EventStopped, nil)
+	return nil
+}
+
+// Pause freezes all the processes in the container, keeping their memory
+func (c *Containerizer) Pause(log lager.Logger, handle string) error {
+	log = log.Session("pause", lager.Data{"handle": handle})
+
+	log.Info("started")
+	defer log.Info("finished")
+
+	if c.states.IsStopped(handle) {
+		return fmt.Errorf("pause: container is stopped")
+	}
+
+	if err := c.runtime.Pause(log, handle); err != nil {
+		log.Error("runtime-pause-failed", err)
+		return err
+	}
+
+	c.states.StorePaused(handle)
+	return nil
+}
+
+// Resume thaws the processes of a paused container
+func (c *Containerizer) Resume(log lager.Logger, handle string) error {
+	log = log.Session("resume", lager.Data{"handle": handle})
+
+	log.Info("started")
+	defer log.Info("finished")
+
+	if err := c.runtime.Resume(log, handle); err != nil {
+		log.Error("runtime-resume-failed", err)
+		return err
+	}
+
+	c.states.StoreRunning(handle)
 	return nil
 }
 
@@ -236,28 +394,40 @@ func (c *Containerizer) Destroy(log lager.Logger, handle string) error {
 		"state": state,
 	})
 
-	if state.Status == runrunc.CreatedStatus || state.Status == runrunc.StoppedStatus {
+	switch state.Status {
+	case runrunc.CreatedStatus, runrunc.StoppedStatus:
 		if err := c.runtime.Delete(log, handle); err != nil {
 			log.Error("delete-failed", err)
 			return err
 		}
+	case runrunc.PausedStatus:
+		// frozen processes cannot act on being killed, so thaw them first; the
+		// forced delete kills them whether or not that worked
+		if err := c.runtime.Resume(log, handle); err != nil {
+			log.Error("resume-failed", err)
+		}
+
+		if err := c.runtime.ForceDelete(log, handle); err != nil {
+			log.Error("force-delete-failed", err)
+			return err
+		}
 	}
 
+	c.recordEvent(log, handle, gardener.EventDestroyed, nil)
 	return nil
 }
 
 func (c *Containerizer) RemoveBundle(log lager.Logger, handle string) error {
 	log = log.Session("depot", lager.Data{"handle": handle})
+
+	defer c.cache.removed(handle)
+	defer c.stats.Forget(handle)
+	defer c.events.Forget(handle)
 	return c.depot.Destroy(log, handle)
 }
 
 func (c *Containerizer) Info(log lager.Logger, handle string) (gardener.ActualContainerSpec, error) {
-	bundlePath, err := c.depot.Lookup(log, handle)
-	if err != nil {
-		return gardener.ActualContainerSpec{}, err
-	}
-
-	bundle, err := c.loader.Load(bundlePath)
+	bundlePath, bundle, err := c.loadBundle(log, handle)
 	if err != nil {
 		return gardener.ActualContainerSpec{}, err
 	}
@@ -268,24 +438,142 @@ func (c *Containerizer) Info(log lager.Logger, handle string) (gardener.ActualCo
 		Pid:        state.Pid,
 		BundlePath: bundlePath,
 		RootFSPath: bundle.RootFS(),
-		Events:     c.events.Events(handle),
+		Events:     c.eventDescriptions(handle),
 		Stopped:    c.states.IsStopped(handle),
-		Limits: garden.Limits{
-			CPU: garden.CPULimits{
-				LimitInShares: *bundle.Resources().CPU.Shares,
-			},
-			Memory: garden.MemoryLimits{
-				LimitInBytes: *bundle.Resources().Memory.Limit,
-			},
-		},
+		Paused:     c.states.IsPaused(handle),
+		Limits:     bundleLimits(bundle),
 	}, nil
 }
 
+// UpdateLimits applies the CPU and memory limits to the running container and
+// records them in its bundle
+func (c *Containerizer) UpdateLimits(log lager.Logger, handle string, limits garden.Limits) error {
+	log = log.Session("update-limits", lager.Data{"handle": handle, "limits": limits})
+
+	log.Info("started")
+	defer log.Info("finished")
+
+	_, bundle, err := c.loadBundle(log, handle)
+	if err != nil {
+		log.Error("load-failed", err)
+		return err
+	}
+
+	memoryLimit := uint64(limits.Memory.LimitInBytes)
+	cpuShares := uint64(limits.CPU.LimitInShares)
+	resources := specs.Resources{
+		Memory: &specs.Memory{Limit: &memoryLimit, Swap: &memoryLimit},
+		CPU:    &specs.CPU{Shares: &cpuShares},
+	}
+
+	if err := c.runtime.Update(log, handle, resources); err != nil {
+		log.Error("runtime-update-failed", err)
+		return err
+	}
+
+	// the With methods copy the resources, leaving the cached bundle as it is
+	bundle = bundle.WithMemoryLimit(*resources.Memory).WithCPUShares(*resources.CPU)
+
+	defer c.cache.updated(handle)
+	if err := c.depot.Update(log, handle, bundle); err != nil {
+		log.Error("depot-update-failed", err)
+		return err
+	}
+
+	return nil
+}
+
+// Metrics returns the most recent stats reported by runc events, and only
+// asks runc for stats when there are none
 func (c *Containerizer) Metrics(log lager.Logger, handle string) (gardener.ActualContainerMetrics, error) {
+	if metrics, ok := c.stats.Latest(handle); ok {
+		return metrics, nil
+	}
+
 	return c.runtime.Stats(log, handle)
 }
 
 // Handles returns a list of all container handles
 func (c *Containerizer) Handles() ([]string, error) {
-	return c.depot.Handles()
+	handles, version, ok := c.cache.handleList()
+	if ok {
+		return handles, nil
+	}
+
+	handles, err := c.depot.Handles()
+	if err != nil {
+		return nil, err
+	}
+
+	c.cache.storeHandles(version, handles)
+	return handles, nil
+}
+
+func (c *Containerizer) recordEvent(log lager.Logger, handle string, eventType gardener.EventType, data map[string]string) {
+	if err := c.events.OnEvent(handle, gardener.Event{Type: eventType, Data: data}); err != nil {
+		log.Error("record-event-failed", err, lager.Data{"type": eventType})
+	}
+}
+
+func (c *Containerizer) eventDescriptions(handle string) []string {
+	var descriptions []string
+	for _, event := range c.events.Events(handle) {
+		descriptions = append(descriptions, event.String())
+	}
+
+	return descriptions
+}
+
+// processExit is the exit status of a process, available once done is closed
+type processExit struct {
+	done   chan struct{}
+	status int
+	err    error
+}
+
+// watchExit waits in the background for the process to exit and records a
+// process-exited event when it does, whether or not anyone waits for it. The
+// returned process's Wait returns the exit status collected by the watch.
+// Attaching to a process which is already being watched shares the watch, so
+// the exit is collected and recorded once.
+func (c *Containerizer) watchExit(log lager.Logger, handle string, process garden.Process) garden.Process {
+	c.exitsMu.Lock()
+	defer c.exitsMu.Unlock()
+
+	exit, ok := c.exits[process.ID()]
+	if !ok {
+		exit = &processExit{done: make(chan struct{})}
+		c.exits[process.ID()] = exit
+
+		go c.collectExit(log, handle, process, exit)
+	}
+
+	return &exitWatchedProcess{Process: process, exit: exit}
+}
+
+func (c *Containerizer) collectExit(log lager.Logger, handle string, process garden.Process, exit *processExit) {
+	exit.status, exit.err = process.Wait()
+	if exit.err == nil {
+		c.recordEvent(log, handle, gardener.EventProcessExited, map[string]string{
+			"process_id":  process.ID(),
+			"exit_status": strconv.Itoa(exit.status),
+		})
+	}
+
+	c.exitsMu.Lock()
+	delete(c.exits, process.ID())
+	c.exitsMu.Unlock()
+
+	close(exit.done)
+}
+
+type exitWatchedProcess struct {
+	garden.Process
+
+	exit *processExit
+}
+
+func (p *exitWatchedProcess) Wait() (int, error) {
+	<-p.exit.done
+	return p.exit.status, p.exit.err
 }
@@ -6,8 +6,11 @@ import (
 	"time"
 
 	"code.cloudfoundry.org/garden"
+	"code.cloudfoundry.org/garden/gardenfakes"
 	"code.cloudfoundry.org/guardian/gardener"
+	"code.cloudfoundry.org/guardian/gardener/gardenerfakes"
 	"code.cloudfoundry.org/guardian/rundmc"
+	"code.cloudfoundry.org/guardian/rundmc/depot"
 	"code.cloudfoundry.org/guardian/rundmc/goci"
 	fakes "code.cloudfoundry.org/guardian/rundmc/rundmcfakes"
 	"code.cloudfoundry.org/guardian/rundmc/runrunc"
@@ -29,6 +32,8 @@ var _ = Describe("Rundmc", func() {
 		fakeStopper      *fakes.FakeStopper
 		fakeEventStore   *fakes.FakeEventStore
 		fakeStateStore   *fakes.FakeStateStore
+		fakeStatsStore   *fakes.FakeStatsStore
+		fakeStages       *gardenerfakes.FakeStageRecorder
 
 		logger        lager.Logger
 		containerizer *rundmc.Containerizer
@@ -43,13 +48,15 @@ var _ = Describe("Rundmc", func() {
 		fakeStopper = new(fakes.FakeStopper)
 		fakeEventStore = new(fakes.FakeEventStore)
 		fakeStateStore = new(fakes.FakeStateStore)
+		fakeStatsStore = new(fakes.FakeStatsStore)
+		fakeStages = new(gardenerfakes.FakeStageRecorder)
 		logger = lagertest.NewTestLogger("test")
 
 		fakeDepot.LookupStub = func(_ lager.Logger, handle string) (string, error) {
 			return "/path/to/" + handle, nil
 		}
 
-		containerizer = rundmc.New(fakeDepot, fakeBundler, fakeOCIRuntime, fakeBundleLoader, fakeNstarRunner, fakeStopper, fakeEventStore, fakeStateStore)
+		containerizer = rundmc.New(fakeDepot, fakeBundler, fakeOCIRuntime, fakeBundleLoader, fakeNstarRunner, fakeStopper, fakeEventStore, fakeStateStore, fakeStatsStore, fakeStages)
 	})
 
 	Describe("Create", func() {
@@ -91,6 +98,17 @@ var _ = Describe("Rundmc", func() {
 			Expect(id).To(Equal("exuberant!"))
 		})
 
+		It("records a created event", func() {
+			Expect(containerizer.Create(logger, gardener.DesiredContainerSpec{
+				Handle: "exuberant!",
+			})).To(Succeed())
+
+			Expect(fakeEventStore.OnEventCallCount()).To(Equal(1))
+			handle, event := fakeEventStore.OnEventArgsForCall(0)
+			Expect(handle).To(Equal("exuberant!"))
+			Expect(event.Type).To(Equal(gardener.EventCreated))
+		})
+
 		It("should prepare the root file system", func() {
 			Expect(containerizer.Create(logger, gardener.DesiredContainerSpec{
 				Handle: "exuberant!",
@@ -106,6 +124,11 @@ var _ = Describe("Rundmc", func() {
 			It("should return an error", func() {
 				Expect(containerizer.Create(logger, gardener.DesiredContainerSpec{})).NotTo(Succeed())
 			})
+
+			It("does not record a created event", func() {
+				containerizer.Create(logger, gardener.DesiredContainerSpec{})
+				Expect(fakeEventStore.OnEventCallCount()).To(Equal(0))
+			})
 		})
 
 		It("should watch for events in a goroutine", func() {
@@ -133,9 +156,87 @@ var _ = Describe("Rundmc", func() {
 			Expect(handle).To(Equal("some-container"))
 			Expect(eventsNotifier).To(Equal(fakeEventStore))
 		})
+
+		It("tracks the stats of the container", func() {
+			Expect(containerizer.Create(logger, gardener.DesiredContainerSpec{Handle: "some-container"})).To(Succeed())
+
+			Expect(fakeStatsStore.TrackCallCount()).To(Equal(1))
+			Expect(fakeStatsStore.TrackArgsForCall(0)).To(Equal("some-container"))
+		})
+	})
+
+	Describe("WatchExisting", func() {
+		BeforeEach(func() {
+			fakeDepot.HandlesReturns([]string{"running", "stopped", "paused"}, nil)
+			fakeOCIRuntime.StateStub = func(_ lager.Logger, handle string) (runrunc.State, error) {
+				return runrunc.State{Status: runrunc.Status(handle)}, nil
+			}
+		})
+
+		It("watches the events and tracks the stats of the containers which have not stopped", func() {
+			Expect(containerizer.WatchExisting(logger)).To(Succeed())
+
+			Eventually(fakeOCIRuntime.WatchEventsCallCount).Should(Equal(2))
+			var watched []string
+			for i := 0; i < 2; i++ {
+				_, handle, eventsNotifier := fakeOCIRuntime.WatchEventsArgsForCall(i)
+				Expect(eventsNotifier).To(Equal(fakeEventStore))
+				watched = append(watched, handle)
+			}
+			Expect(watched).To(ConsistOf("running", "paused"))
+
+			Expect(fakeStatsStore.TrackCallCount()).To(Equal(2))
+		})
+
+		It("skips containers whose state cannot be found", func() {
+			fakeOCIRuntime.StateStub = func(_ lager.Logger, handle string) (runrunc.State, error) {
+				if handle == "running" {
+					return runrunc.State{}, errors.New("boom")
+				}
+				return runrunc.State{Status: runrunc.Status(handle)}, nil
+			}
+
+			Expect(containerizer.WatchExisting(logger)).To(Succeed())
+
+			Eventually(fakeOCIRuntime.WatchEventsCallCount).Should(Equal(1))
+			_, handle, _ := fakeOCIRuntime.WatchEventsArgsForCall(0)
+			Expect(handle).To(Equal("paused"))
+		})
+
+		It("returns an error when the depot cannot be listed", func() {
+			fakeDepot.HandlesReturns(nil, errors.New("spiderman-error"))
+			Expect(containerizer.WatchExisting(logger)).To(MatchError("spiderman-error"))
+		})
 	})
 
 	Describe("Run", func() {
+		BeforeEach(func() {
+			fakeOCIRuntime.ExecReturns(new(gardenfakes.FakeProcess), nil)
+		})
+
+		It("records how long finding the bundle and execing the process took", func() {
+			fakeOCIRuntime.ExecReturns(nil, errors.New("exec failed"))
+
+			_, err := containerizer.Run(logger, "some-handle", garden.ProcessSpec{Path: "hello"}, garden.ProcessIO{})
+			Expect(err).To(MatchError("exec failed"))
+
+			Expect(fakeStages.RecordStageCallCount()).To(Equal(3))
+			operation, stage, _, err := fakeStages.RecordStageArgsForCall(0)
+			Expect(operation).To(Equal("run"))
+			Expect(stage).To(Equal("depot-lookup"))
+			Expect(err).NotTo(HaveOccurred())
+
+			_, stage, _, err = fakeStages.RecordStageArgsForCall(1)
+			Expect(stage).To(Equal("runtime-exec"))
+			Expect(err).To(MatchError("exec failed"))
+
+			_, stage, _, err = fakeStages.RecordStageArgsForCall(2)
+			Expect(stage).To(Equal(gardener.StageTotal))
+			Expect(err).To(MatchError("exec failed"))
+
+			Expect(logger).To(gbytes.Say(`test.run.finished.*"stages":{"depot-lookup":"[^"]+","runtime-exec":"[^"]+"},"took"`))
+		})
+
 		It("should ask the execer to exec a process in the container", func() {
 			containerizer.Run(logger, "some-handle", garden.ProcessSpec{Path: "hello"}, garden.ProcessIO{})
 			Expect(fakeOCIRuntime.ExecCallCount()).To(Equal(1))
@@ -146,6 +247,93 @@ var _ = Describe("Rundmc", func() {
 			Expect(spec.Path).To(Equal("hello"))
 		})
 
+		Describe("waiting for the process", func() {
+			var fakeProcess *gardenfakes.FakeProcess
+
+			BeforeEach(func() {
+				fakeProcess = new(gardenfakes.FakeProcess)
+				fakeProcess.IDReturns("some-process")
+				fakeProcess.WaitReturns(42, nil)
+				fakeOCIRuntime.ExecReturns(fakeProcess, nil)
+			})
+
+			It("returns the exit status of the process", func() {
+				process, err := containerizer.Run(logger, "some-handle", garden.ProcessSpec{}, garden.ProcessIO{})
+				Expect(err).NotTo(HaveOccurred())
+				Expect(process.ID()).To(Equal("some-process"))
+				Expect(process.Wait()).To(Equal(42))
+			})
+
+			It("records a process-exited event with the exit status when the process exits", func() {
+				_, err := containerizer.Run(logger, "some-handle", garden.ProcessSpec{}, garden.ProcessIO{})
+				Expect(err).NotTo(HaveOccurred())
+
+				Eventually(fakeEventStore.OnEventCallCount).Should(Equal(1))
+				handle, event := fakeEventStore.OnEventArgsForCall(0)
+				Expect(handle).To(Equal("some-handle"))
+				Expect(event.Type).To(Equal(gardener.EventProcessExited))
+				Expect(event.Data).To(Equal(map[string]string{
+					"process_id":  "some-process",
+					"exit_status": "42",
+				}))
+			})
+
+			It("collects the exit status once, however many times it is waited for", func() {
+				process, err := containerizer.Run(logger, "some-handle", garden.ProcessSpec{}, garden.ProcessIO{})
+				Expect(err).NotTo(HaveOccurred())
+
+				Expect(process.Wait()).To(Equal(42))
+				Expect(process.Wait()).To(Equal(42))
+
+				Expect(fakeProcess.WaitCallCount()).To(Equal(1))
+				Consistently(fakeEventStore.OnEventCallCount).Should(Equal(1))
+			})
+
+			It("shares the exit status with clients attaching to the process", func() {
+				exited := make(chan struct{})
+				fakeProcess.WaitStub = func() (int, error) {
+					<-exited
+					return 42, nil
+				}
+
+				_, err := containerizer.Run(logger, "some-handle", garden.ProcessSpec{}, garden.ProcessIO{})
+				Expect(err).NotTo(HaveOccurred())
+
+				attachedProcess := new(gardenfakes.FakeProcess)
+				attachedProcess.IDReturns("some-process")
+				fakeOCIRuntime.AttachReturns(attachedProcess, nil)
+
+				attached, err := containerizer.Attach(logger, "some-handle", "some-process", garden.ProcessIO{})
+				Expect(err).NotTo(HaveOccurred())
+
+				close(exited)
+				Expect(attached.Wait()).To(Equal(42))
+				Expect(attachedProcess.WaitCallCount()).To(Equal(0))
+				Consistently(fakeEventStore.OnEventCallCount).Should(Equal(1))
+			})
+
+			Context("when waiting fails", func() {
+				It("does not record an event", func() {
+					fakeProcess.WaitReturns(0, errors.New("connection lost"))
+
+					process, err := containerizer.Run(logger, "some-handle", garden.ProcessSpec{}, garden.ProcessIO{})
+					Expect(err).NotTo(HaveOccurred())
+
+					_, err = process.Wait()
+					Expect(err).To(MatchError("connection lost"))
+					Consistently(fakeEventStore.OnEventCallCount).Should(Equal(0))
+				})
+			})
+		})
+
+		Context("when exec fails", func() {
+			It("returns the error", func() {
+				fakeOCIRuntime.ExecReturns(nil, errors.New("exec failed"))
+				_, err := containerizer.Run(logger, "some-handle", garden.ProcessSpec{}, garden.ProcessIO{})
+				Expect(err).To(MatchError("exec failed"))
+			})
+		})
+
 		Context("when looking up the container fails", func() {
 			It("returns an error", func() {
 				fakeDepot.LookupReturns("", errors.New("blam"))
@@ -162,6 +350,34 @@ var _ = Describe("Rundmc", func() {
 	})
 
 	Describe("Attach", func() {
+		var fakeProcess *gardenfakes.FakeProcess
+
+		BeforeEach(func() {
+			fakeProcess = new(gardenfakes.FakeProcess)
+			fakeProcess.IDReturns("123")
+			fakeProcess.WaitReturns(3, nil)
+			fakeOCIRuntime.AttachReturns(fakeProcess, nil)
+		})
+
+		It("records a process-exited event when the attached process exits", func() {
+			process, err := containerizer.Attach(logger, "some-handle", "123", garden.ProcessIO{})
+			Expect(err).NotTo(HaveOccurred())
+			Expect(process.Wait()).To(Equal(3))
+
+			Eventually(fakeEventStore.OnEventCallCount).Should(Equal(1))
+			_, event := fakeEventStore.OnEventArgsForCall(0)
+			Expect(event.Type).To(Equal(gardener.EventProcessExited))
+			Expect(event.Data).To(HaveKeyWithValue("process_id", "123"))
+		})
+
+		Context("when attaching fails", func() {
+			It("returns the error", func() {
+				fakeOCIRuntime.AttachReturns(nil, errors.New("no such process"))
+				_, err := containerizer.Attach(logger, "some-handle", "123", garden.ProcessIO{})
+				Expect(err).To(MatchError("no such process"))
+			})
+		})
+
 		It("should ask the execer to attach a process in the container", func() {
 			containerizer.Attach(logger, "some-handle", "123", garden.ProcessIO{})
 			Expect(fakeOCIRuntime.AttachCallCount()).To(Equal(1))
@@ -212,9 +428,33 @@ var _ = Describe("Rundmc", func() {
 			Expect(containerizer.StreamIn(logger, "some-handle", garden.StreamInSpec{})).To(MatchError("stream-in: pid not found for container"))
 		})
 
+		It("records how long finding the PID and running nstar took", func() {
+			Expect(containerizer.StreamIn(logger, "some-handle", garden.StreamInSpec{})).To(Succeed())
+
+			stages := []string{}
+			for i := 0; i < fakeStages.RecordStageCallCount(); i++ {
+				operation, stage, _, _ := fakeStages.RecordStageArgsForCall(i)
+				Expect(operation).To(Equal("containerizer-stream-in"))
+				stages = append(stages, stage)
+			}
+			Expect(stages).To(Equal([]string{"runtime-state", "nstar-stream-in", gardener.StageTotal}))
+		})
+
 		It("returns the error if nstar fails", func() {
 			fakeNstarRunner.StreamInReturns(errors.New("failed"))
 			Expect(containerizer.StreamIn(logger, "some-handle", garden.StreamInSpec{})).To(MatchError("stream-in: nstar: failed"))
+			Expect(fakeEventStore.OnEventCallCount()).To(Equal(0))
+		})
+
+		It("records a quota-exceeded event if nstar runs out of disk quota", func() {
+			fakeNstarRunner.StreamInReturns(errors.New("write /foo: disk quota exceeded"))
+			Expect(containerizer.StreamIn(logger, "some-handle", garden.StreamInSpec{})).NotTo(Succeed())
+
+			Expect(fakeEventStore.OnEventCallCount()).To(Equal(1))
+			handle, event := fakeEventStore.OnEventArgsForCall(0)
+			Expect(handle).To(Equal("some-handle"))
+			Expect(event.Type).To(Equal(gardener.EventQuotaExceeded))
+			Expect(event.Data).To(HaveKeyWithValue("resource", "disk"))
 		})
 	})
 
@@ -290,6 +530,13 @@ var _ = Describe("Rundmc", func() {
 				handle := fakeStateStore.StoreStoppedArgsForCall(0)
 				Expect(handle).To(Equal("some-handle"))
 			})
+
+			It("records a stopped event", func() {
+				Expect(fakeEventStore.OnEventCallCount()).To(Equal(1))
+				handle, event := fakeEventStore.OnEventArgsForCall(0)
+				Expect(handle).To(Equal("some-handle"))
+				Expect(event.Type).To(Equal(gardener.EventStopped))
+			})
 		})
 
 		Context("when the stop fails", func() {
@@ -341,6 +588,14 @@ var _ = Describe("Rundmc", func() {
 					Expect(arg2(fakeOCIRuntime.DeleteArgsForCall(0))).To(Equal("some-handle"))
 				})
 
+				It("records a destroyed event", func() {
+					Expect(containerizer.Destroy(logger, "some-handle")).To(Succeed())
+					Expect(fakeEventStore.OnEventCallCount()).To(Equal(1))
+					handle, event := fakeEventStore.OnEventArgsForCall(0)
+					Expect(handle).To(Equal("some-handle"))
+					Expect(event.Type).To(Equal(gardener.EventDestroyed))
+				})
+
 				Context("when delete fails", func() {
 					It("does not destroy the depot directory", func() {
 						fakeOCIRuntime.DeleteReturns(errors.New("delete failed"))
@@ -385,6 +640,42 @@ var _ = Describe("Rundmc", func() {
 				Expect(fakeOCIRuntime.DeleteCallCount()).To(Equal(0))
 			})
 		})
+
+		Context("when the container is paused", func() {
+			BeforeEach(func() {
+				fakeOCIRuntime.StateReturns(runrunc.State{
+					Status: runrunc.PausedStatus,
+				}, nil)
+			})
+
+			It("resumes the container and then force deletes it", func() {
+				Expect(containerizer.Destroy(logger, "some-handle")).To(Succeed())
+
+				Expect(fakeOCIRuntime.ResumeCallCount()).To(Equal(1))
+				Expect(arg2(fakeOCIRuntime.ResumeArgsForCall(0))).To(Equal("some-handle"))
+
+				Expect(fakeOCIRuntime.ForceDeleteCallCount()).To(Equal(1))
+				Expect(arg2(fakeOCIRuntime.ForceDeleteArgsForCall(0))).To(Equal("some-handle"))
+				Expect(fakeOCIRuntime.DeleteCallCount()).To(Equal(0))
+			})
+
+			Context("when resuming fails", func() {
+				It("still force deletes the container", func() {
+					fakeOCIRuntime.ResumeReturns(errors.New("resume failed"))
+
+					Expect(containerizer.Destroy(logger, "some-handle")).To(Succeed())
+					Expect(fakeOCIRuntime.ForceDeleteCallCount()).To(Equal(1))
+				})
+			})
+
+			Context("when the forced delete fails", func() {
+				It("returns the error", func() {
+					fakeOCIRuntime.ForceDeleteReturns(errors.New("delete failed"))
+
+					Expect(containerizer.Destroy(logger, "some-handle")).To(MatchError("delete failed"))
+				})
+			})
+		})
 	})
 
 	Describe("RemoveBundle", func() {
@@ -395,6 +686,18 @@ var _ = Describe("Rundmc", func() {
 			Expect(handle).To(Equal("some-handle"))
 		})
 
+		It("forgets the stats of the container", func() {
+			Expect(containerizer.RemoveBundle(logger, "some-handle")).To(Succeed())
+			Expect(fakeStatsStore.ForgetCallCount()).To(Equal(1))
+			Expect(fakeStatsStore.ForgetArgsForCall(0)).To(Equal("some-handle"))
+		})
+
+		It("forgets the events of the container", func() {
+			Expect(containerizer.RemoveBundle(logger, "some-handle")).To(Succeed())
+			Expect(fakeEventStore.ForgetCallCount()).To(Equal(1))
+			Expect(fakeEventStore.ForgetArgsForCall(0)).To(Equal("some-handle"))
+		})
+
 		Context("when removing bundle from depot fails", func() {
 			BeforeEach(func() {
 				fakeDepot.DestroyReturns(errors.New("destroy failed"))
@@ -475,16 +778,16 @@ var _ = Describe("Rundmc", func() {
 		})
 
 		It("should return any events from the event store", func() {
-			fakeEventStore.EventsReturns([]string{
-				"potato",
-				"fire",
+			fakeEventStore.EventsReturns([]gardener.Event{
+				{Type: gardener.EventOutOfMemory},
+				{Type: gardener.EventProcessExited, Data: map[string]string{"process_id": "abc", "exit_status": "1"}},
 			})
 
 			actualSpec, err := containerizer.Info(logger, "some-handle")
 			Expect(err).NotTo(HaveOccurred())
 			Expect(actualSpec.Events).To(Equal([]string{
-				"potato",
-				"fire",
+				"Out of memory",
+				"Process abc exited with status 1",
 			}))
 		})
 
@@ -495,6 +798,161 @@ var _ = Describe("Rundmc", func() {
 			Expect(err).NotTo(HaveOccurred())
 			Expect(actualSpec.Stopped).To(Equal(true))
 		})
+
+		It("should return the paused state from the state store", func() {
+			fakeStateStore.IsPausedReturns(true)
+
+			actualSpec, err := containerizer.Info(logger, "some-handle")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(actualSpec.Paused).To(Equal(true))
+		})
+	})
+
+	Describe("Pause", func() {
+		It("asks the runtime to pause the container", func() {
+			Expect(containerizer.Pause(logger, "some-handle")).To(Succeed())
+
+			Expect(fakeOCIRuntime.PauseCallCount()).To(Equal(1))
+			_, id := fakeOCIRuntime.PauseArgsForCall(0)
+			Expect(id).To(Equal("some-handle"))
+		})
+
+		It("transitions the stored state", func() {
+			Expect(containerizer.Pause(logger, "some-handle")).To(Succeed())
+
+			Expect(fakeStateStore.StorePausedCallCount()).To(Equal(1))
+			Expect(fakeStateStore.StorePausedArgsForCall(0)).To(Equal("some-handle"))
+		})
+
+		Context("when the container is stopped", func() {
+			It("returns an error without pausing", func() {
+				fakeStateStore.IsStoppedReturns(true)
+
+				Expect(containerizer.Pause(logger, "some-handle")).To(MatchError("pause: container is stopped"))
+				Expect(fakeOCIRuntime.PauseCallCount()).To(Equal(0))
+			})
+		})
+
+		Context("when the runtime fails to pause", func() {
+			BeforeEach(func() {
+				fakeOCIRuntime.PauseReturns(errors.New("boom"))
+			})
+
+			It("returns the error", func() {
+				Expect(containerizer.Pause(logger, "some-handle")).To(MatchError("boom"))
+			})
+
+			It("does not transition to the paused state", func() {
+				containerizer.Pause(logger, "some-handle")
+				Expect(fakeStateStore.StorePausedCallCount()).To(Equal(0))
+			})
+		})
+	})
+
+	Describe("Resume", func() {
+		It("asks the runtime to resume the container", func() {
+			Expect(containerizer.Resume(logger, "some-handle")).To(Succeed())
+
+			Expect(fakeOCIRuntime.ResumeCallCount()).To(Equal(1))
+			_, id := fakeOCIRuntime.ResumeArgsForCall(0)
+			Expect(id).To(Equal("some-handle"))
+		})
+
+		It("transitions the stored state", func() {
+			Expect(containerizer.Resume(logger, "some-handle")).To(Succeed())
+
+			Expect(fakeStateStore.StoreRunningCallCount()).To(Equal(1))
+			Expect(fakeStateStore.StoreRunningArgsForCall(0)).To(Equal("some-handle"))
+		})
+
+		Context("when the runtime fails to resume", func() {
+			BeforeEach(func() {
+				fakeOCIRuntime.ResumeReturns(errors.New("boom"))
+			})
+
+			It("returns the error", func() {
+				Expect(containerizer.Resume(logger, "some-handle")).To(MatchError("boom"))
+			})
+
+			It("does not transition the stored state", func() {
+				containerizer.Resume(logger, "some-handle")
+				Expect(fakeStateStore.StoreRunningCallCount()).To(Equal(0))
+			})
+		})
+	})
+
+	Describe("UpdateLimits", func() {
+		var limits garden.Limits
+
+		BeforeEach(func() {
+			var limit uint64 = 10
+			var shares uint64 = 20
+			fakeBundleLoader.LoadReturns(goci.Bndl{
+				Spec: specs.Spec{
+					Linux: specs.Linux{
+						Resources: &specs.Resources{
+							Memory: &specs.Memory{Limit: &limit},
+							CPU:    &specs.CPU{Shares: &shares},
+						},
+					},
+				},
+			}, nil)
+
+			limits = garden.Limits{
+				Memory: garden.MemoryLimits{LimitInBytes: 2048},
+				CPU:    garden.CPULimits{LimitInShares: 512},
+			}
+		})
+
+		It("asks the runtime to update the resources of the container", func() {
+			Expect(containerizer.UpdateLimits(logger, "some-handle", limits)).To(Succeed())
+
+			Expect(fakeOCIRuntime.UpdateCallCount()).To(Equal(1))
+			_, id, resources := fakeOCIRuntime.UpdateArgsForCall(0)
+			Expect(id).To(Equal("some-handle"))
+			Expect(*resources.Memory.Limit).To(BeEquivalentTo(2048))
+			Expect(*resources.Memory.Swap).To(BeEquivalentTo(2048))
+			Expect(*resources.CPU.Shares).To(BeEquivalentTo(512))
+		})
+
+		It("saves the new limits in the bundle", func() {
+			Expect(containerizer.UpdateLimits(logger, "some-handle", limits)).To(Succeed())
+
+			Expect(fakeDepot.UpdateCallCount()).To(Equal(1))
+			_, handle, bundle := fakeDepot.UpdateArgsForCall(0)
+			Expect(handle).To(Equal("some-handle"))
+			Expect(*bundle.(goci.Bndl).Resources().Memory.Limit).To(BeEquivalentTo(2048))
+			Expect(*bundle.(goci.Bndl).Resources().CPU.Shares).To(BeEquivalentTo(512))
+		})
+
+		Context("when looking up the bundle path fails", func() {
+			It("returns the error", func() {
+				fakeDepot.LookupReturns("", errors.New("spiderman-error"))
+				Expect(containerizer.UpdateLimits(logger, "some-handle", limits)).To(MatchError("spiderman-error"))
+			})
+		})
+
+		Context("when the runtime fails to update the container", func() {
+			BeforeEach(func() {
+				fakeOCIRuntime.UpdateReturns(errors.New("batman-error"))
+			})
+
+			It("returns the error", func() {
+				Expect(containerizer.UpdateLimits(logger, "some-handle", limits)).To(MatchError("batman-error"))
+			})
+
+			It("does not update the bundle", func() {
+				containerizer.UpdateLimits(logger, "some-handle", limits)
+				Expect(fakeDepot.UpdateCallCount()).To(Equal(0))
+			})
+		})
+
+		Context("when saving the bundle fails", func() {
+			It("returns the error", func() {
+				fakeDepot.UpdateReturns(errors.New("robin-error"))
+				Expect(containerizer.UpdateLimits(logger, "some-handle", limits)).To(MatchError("robin-error"))
+			})
+		})
 	})
 
 	Describe("Metrics", func() {
@@ -521,6 +979,140 @@ var _ = Describe("Rundmc", func() {
 				Expect(err).To(MatchError("banana"))
 			})
 		})
+
+		Context("when there are recent stats from runc events", func() {
+			var metrics gardener.ActualContainerMetrics
+
+			BeforeEach(func() {
+				metrics = gardener.ActualContainerMetrics{
+					CPU:     garden.ContainerCPUStat{Usage: 4},
+					CPURate: gardener.ContainerCPURateStat{Interval: time.Second, Percent: 50},
+				}
+				fakeStatsStore.LatestReturns(metrics, true)
+			})
+
+			It("returns them without asking runc", func() {
+				Expect(containerizer.Metrics(logger, "foo")).To(Equal(metrics))
+				Expect(fakeStatsStore.LatestArgsForCall(0)).To(Equal("foo"))
+				Expect(fakeOCIRuntime.StatsCallCount()).To(Equal(0))
+			})
+		})
+	})
+
+	Describe("caching bundles and handles", func() {
+		BeforeEach(func() {
+			var limit uint64 = 10
+			var shares uint64 = 20
+			fakeBundleLoader.LoadReturns(goci.Bndl{
+				Spec: specs.Spec{
+					Linux: specs.Linux{
+						Resources: &specs.Resources{
+							Memory: &specs.Memory{Limit: &limit},
+							CPU:    &specs.CPU{Shares: &shares},
+						},
+					},
+				},
+			}, nil)
+
+			fakeDepot.HandlesReturns([]string{"banana", "some-handle"}, nil)
+		})
+
+		It("loads each bundle only once", func() {
+			_, err := containerizer.Info(logger, "some-handle")
+			Expect(err).NotTo(HaveOccurred())
+			_, err = containerizer.Info(logger, "some-handle")
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(fakeDepot.LookupCallCount()).To(Equal(1))
+			Expect(fakeBundleLoader.LoadCallCount()).To(Equal(1))
+		})
+
+		It("does not cache a bundle which failed to load", func() {
+			fakeBundleLoader.LoadReturns(goci.Bndl{}, errors.New("aquaman-error"))
+			_, err := containerizer.Info(logger, "some-handle")
+			Expect(err).To(HaveOccurred())
+
+			_, err = containerizer.Info(logger, "some-handle")
+			Expect(err).To(HaveOccurred())
+			Expect(fakeBundleLoader.LoadCallCount()).To(Equal(2))
+		})
+
+		It("lists the depot only once", func() {
+			Expect(containerizer.Handles()).To(ConsistOf("banana", "some-handle"))
+			Expect(containerizer.Handles()).To(ConsistOf("banana", "some-handle"))
+
+			Expect(fakeDepot.HandlesCallCount()).To(Equal(1))
+		})
+
+		It("adds created containers to the handles", func() {
+			Expect(containerizer.Handles()).To(HaveLen(2))
+			Expect(containerizer.Create(logger, gardener.DesiredContainerSpec{Handle: "new"})).To(Succeed())
+
+			Expect(containerizer.Handles()).To(ConsistOf("banana", "some-handle", "new"))
+			Expect(fakeDepot.HandlesCallCount()).To(Equal(1))
+		})
+
+		It("forgets containers whose bundles are removed", func() {
+			_, err := containerizer.Info(logger, "some-handle")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(containerizer.Handles()).To(HaveLen(2))
+
+			Expect(containerizer.RemoveBundle(logger, "some-handle")).To(Succeed())
+
+			Expect(containerizer.Handles()).To(ConsistOf("banana"))
+
+			_, err = containerizer.Info(logger, "some-handle")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(fakeBundleLoader.LoadCallCount()).To(Equal(2))
+		})
+
+		It("reloads a bundle after its limits are updated", func() {
+			_, err := containerizer.Info(logger, "some-handle")
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(containerizer.UpdateLimits(logger, "some-handle", garden.Limits{})).To(Succeed())
+			Expect(fakeBundleLoader.LoadCallCount()).To(Equal(1))
+
+			_, err = containerizer.Info(logger, "some-handle")
+			Expect(err).NotTo(HaveOccurred())
+			Expect(fakeBundleLoader.LoadCallCount()).To(Equal(2))
+		})
+
+		It("does not share the cached bundle with callers which change it", func() {
+			_, err := containerizer.Info(logger, "some-handle")
+			Expect(err).NotTo(HaveOccurred())
+
+			fakeDepot.UpdateStub = func(_ lager.Logger, _ string, _ depot.BundleSaver) error {
+				info, err := containerizer.Info(logger, "some-handle")
+				Expect(err).NotTo(HaveOccurred())
+				Expect(info.Limits.Memory.LimitInBytes).To(BeEquivalentTo(10))
+				return nil
+			}
+
+			Expect(containerizer.UpdateLimits(logger, "some-handle", garden.Limits{
+				Memory: garden.MemoryLimits{LimitInBytes: 50},
+			})).To(Succeed())
+			Expect(fakeDepot.UpdateCallCount()).To(Equal(1))
+		})
+
+		Describe("RebuildCache", func() {
+			It("loads every bundle in the depot", func() {
+				Expect(containerizer.RebuildCache(logger)).To(Succeed())
+				Expect(fakeBundleLoader.LoadCallCount()).To(Equal(2))
+
+				_, err := containerizer.Info(logger, "banana")
+				Expect(err).NotTo(HaveOccurred())
+				Expect(containerizer.Handles()).To(HaveLen(2))
+
+				Expect(fakeBundleLoader.LoadCallCount()).To(Equal(2))
+				Expect(fakeDepot.HandlesCallCount()).To(Equal(1))
+			})
+
+			It("returns an error when the depot cannot be listed", func() {
+				fakeDepot.HandlesReturns(nil, errors.New("spiderman-error"))
+				Expect(containerizer.RebuildCache(logger)).To(MatchError("spiderman-error"))
+			})
+		})
 	})
 
 	Describe("handles", func() {
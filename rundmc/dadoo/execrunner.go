@@ -17,6 +17,7 @@ import (
 
 	"code.cloudfoundry.org/garden"
 	"code.cloudfoundry.org/guardian/rundmc/runrunc"
+	"code.cloudfoundry.org/guardian/tracing"
 	"code.cloudfoundry.org/lager"
 	"github.com/cloudfoundry/gunk/command_runner"
 	"github.com/kr/logfmt"
@@ -90,6 +91,8 @@ func (d *ExecRunner) Run(log lager.Logger, spec *runrunc.PreparedSpec, processes
 		cmd = exec.Command(d.dadooPath, "exec", d.runcPath, processPath, handle)
 	}
 
+	tracing.SetEnv(log, cmd)
+
 	cmd.ExtraFiles = []*os.File{
 		fd3w,
 		logw,
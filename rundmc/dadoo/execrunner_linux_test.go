@@ -19,6 +19,7 @@ import (
 	dadoofakes "code.cloudfoundry.org/guardian/rundmc/dadoo/dadoofakes"
 	"code.cloudfoundry.org/guardian/rundmc/runrunc"
 	fakes "code.cloudfoundry.org/guardian/rundmc/runrunc/runruncfakes"
+	"code.cloudfoundry.org/guardian/tracing"
 	"code.cloudfoundry.org/lager"
 	"code.cloudfoundry.org/lager/lagertest"
 	"github.com/cloudfoundry/gunk/command_runner/fake_command_runner"
@@ -165,6 +166,14 @@ var _ = Describe("Dadoo ExecRunner", func() {
 			)
 		})
 
+		It("passes the trace id of the logger to dadoo", func() {
+			tracedLog, _ := new(tracing.Tracer).Start(log, "exec")
+			runner.Run(tracedLog, &runrunc.PreparedSpec{}, processPath, "some-handle", nil, garden.ProcessIO{})
+
+			cmd := fakeCommandRunner.StartedCommands()[0]
+			Expect(tracing.FromEnv(cmd.Env)).To(Equal(tracing.TraceID(tracedLog)))
+		})
+
 		Context("when TTY is requested", func() {
 			It("executed the dadoo binary with the correct arguments", func() {
 				runner.Run(log, &runrunc.PreparedSpec{
@@ -50,6 +50,26 @@ func (d *DirectoryDepot) Create(log lager.Logger, handle string, bundle BundleSa
 	return nil
 }
 
+// Update saves the bundle over the existing bundle of the given container
+func (d *DirectoryDepot) Update(log lager.Logger, handle string, bundle BundleSaver) error {
+	log = log.Session("depot-update", lager.Data{"handle": handle})
+
+	log.Info("started")
+	defer log.Info("finished")
+
+	path := d.toDir(handle)
+	if _, err := os.Stat(path); err != nil {
+		return ErrDoesNotExist
+	}
+
+	if err := bundle.Save(path); err != nil {
+		log.Error("update-failed", err, lager.Data{"path": path})
+		return err
+	}
+
+	return nil
+}
+
 func (d *DirectoryDepot) Lookup(log lager.Logger, handle string) (string, error) {
 	log = log.Session("lookup", lager.Data{"handle": handle})
 
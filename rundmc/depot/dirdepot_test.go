@@ -74,6 +74,33 @@ var _ = Describe("Depot", func() {
 		})
 	})
 
+	Describe("update", func() {
+		Context("when the container directory exists", func() {
+			BeforeEach(func() {
+				Expect(os.MkdirAll(filepath.Join(depotDir, "aardvaark"), 0755)).To(Succeed())
+			})
+
+			It("should serialize the container config over the existing directory", func() {
+				Expect(dirdepot.Update(logger, "aardvaark", fakeBundle)).To(Succeed())
+				Expect(fakeBundle.SaveCallCount()).To(Equal(1))
+				Expect(fakeBundle.SaveArgsForCall(0)).To(Equal(path.Join(depotDir, "aardvaark")))
+			})
+
+			It("does not destroy the container directory if saving fails", func() {
+				fakeBundle.SaveReturns(errors.New("didn't work"))
+				Expect(dirdepot.Update(logger, "aardvaark", fakeBundle)).To(MatchError("didn't work"))
+				Expect(filepath.Join(depotDir, "aardvaark")).To(BeADirectory())
+			})
+		})
+
+		Context("when the container directory does not exist", func() {
+			It("returns an ErrDoesNotExist", func() {
+				Expect(dirdepot.Update(logger, "potato", fakeBundle)).To(MatchError(depot.ErrDoesNotExist))
+				Expect(fakeBundle.SaveCallCount()).To(Equal(0))
+			})
+		})
+	})
+
 	Describe("destroy", func() {
 		It("should destroy the container directory", func() {
 			Expect(os.MkdirAll(filepath.Join(depotDir, "potato"), 0755)).To(Succeed())
@@ -0,0 +1,126 @@
+package depot
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"code.cloudfoundry.org/guardian/gardener"
+	"code.cloudfoundry.org/lager"
+)
+
+const journalExtension = ".json"
+
+// CreationJournal records the progress of container creation as a file per
+// container in its own directory, so that a create interrupted by a crash can
+// be rolled back or finished on the next start. It is kept outside of the
+// depot so that a journal is never mistaken for a half-created bundle.
+type CreationJournal struct {
+	dir string
+}
+
+func NewCreationJournal(dir string) *CreationJournal {
+	return &CreationJournal{
+		dir: dir,
+	}
+}
+
+func (j *CreationJournal) Write(log lager.Logger, entry gardener.CreationJournalEntry) error {
+	log = log.Session("journal-write", lager.Data{"handle": entry.Spec.Handle, "stages": entry.Stages})
+
+	log.Debug("started")
+	defer log.Debug("finished")
+
+	if err := os.MkdirAll(j.dir, 0700); err != nil {
+		log.Error("mkdir-failed", err, lager.Data{"path": j.dir})
+		return err
+	}
+
+	contents, err := json.Marshal(entry)
+	if err != nil {
+		return err
+	}
+
+	// write to a temporary file and rename it so that a crash never leaves a
+	// truncated journal behind
+	tmp, err := ioutil.TempFile(j.dir, "."+entry.Spec.Handle)
+	if err != nil {
+		log.Error("create-temp-file-failed", err)
+		return err
+	}
+	defer os.Remove(tmp.Name())
+
+	if _, err := tmp.Write(contents); err != nil {
+		tmp.Close()
+		log.Error("write-failed", err)
+		return err
+	}
+
+	if err := tmp.Sync(); err != nil {
+		tmp.Close()
+		log.Error("sync-failed", err)
+		return err
+	}
+
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+
+	return os.Rename(tmp.Name(), j.path(entry.Spec.Handle))
+}
+
+func (j *CreationJournal) Remove(log lager.Logger, handle string) error {
+	log = log.Session("journal-remove", lager.Data{"handle": handle})
+
+	log.Debug("started")
+	defer log.Debug("finished")
+
+	if err := os.Remove(j.path(handle)); err != nil && !os.IsNotExist(err) {
+		log.Error("remove-failed", err)
+		return err
+	}
+
+	return nil
+}
+
+// Entries returns the journal entries of all containers whose creation was
+// not completed
+func (j *CreationJournal) Entries(log lager.Logger) ([]gardener.CreationJournalEntry, error) {
+	log = log.Session("journal-entries")
+
+	log.Debug("started")
+	defer log.Debug("finished")
+
+	paths, err := filepath.Glob(filepath.Join(j.dir, "*"+journalExtension))
+	if err != nil {
+		return nil, err
+	}
+
+	entries := []gardener.CreationJournalEntry{}
+	for _, path := range paths {
+		contents, err := ioutil.ReadFile(path)
+		if err != nil {
+			return nil, fmt.Errorf("read journal %s: %s", path, err)
+		}
+
+		var entry gardener.CreationJournalEntry
+		if err := json.Unmarshal(contents, &entry); err != nil {
+			// an unreadable journal can only be rolled back, which needs nothing
+			// but the handle
+			log.Error("parse-failed", err, lager.Data{"path": path})
+			entry = gardener.CreationJournalEntry{}
+			entry.Spec.Handle = strings.TrimSuffix(filepath.Base(path), journalExtension)
+		}
+
+		entries = append(entries, entry)
+	}
+
+	return entries, nil
+}
+
+func (j *CreationJournal) path(handle string) string {
+	return filepath.Join(j.dir, handle+journalExtension)
+}
@@ -0,0 +1,129 @@
+package depot_test
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+
+	"code.cloudfoundry.org/garden"
+	"code.cloudfoundry.org/guardian/gardener"
+	"code.cloudfoundry.org/guardian/rundmc/depot"
+	"code.cloudfoundry.org/lager"
+	"code.cloudfoundry.org/lager/lagertest"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("CreationJournal", func() {
+	var (
+		journalDir string
+		journal    *depot.CreationJournal
+		logger     lager.Logger
+		entry      gardener.CreationJournalEntry
+	)
+
+	BeforeEach(func() {
+		var err error
+
+		journalDir, err = ioutil.TempDir("", "journal-test")
+		Expect(err).NotTo(HaveOccurred())
+
+		logger = lagertest.NewTestLogger("test")
+		journal = depot.NewCreationJournal(journalDir)
+
+		entry = gardener.CreationJournalEntry{
+			Spec: garden.ContainerSpec{
+				Handle:     "some-handle",
+				Properties: garden.Properties{"foo": "bar"},
+			},
+			RootFSPath: "/path/to/rootfs",
+			Stages:     []gardener.CreationStage{gardener.CreationStageVolume},
+		}
+	})
+
+	AfterEach(func() {
+		os.RemoveAll(journalDir)
+	})
+
+	Describe("Write", func() {
+		It("writes a file named after the container, not a directory which looks like a bundle", func() {
+			Expect(journal.Write(logger, entry)).To(Succeed())
+			Expect(filepath.Join(journalDir, "some-handle.json")).To(BeARegularFile())
+			Expect(filepath.Join(journalDir, "some-handle")).NotTo(BeAnExistingFile())
+		})
+
+		Context("when the journal directory does not exist yet", func() {
+			It("creates it", func() {
+				journal = depot.NewCreationJournal(filepath.Join(journalDir, "nested"))
+				Expect(journal.Write(logger, entry)).To(Succeed())
+				Expect(journal.Entries(logger)).To(ConsistOf(entry))
+			})
+		})
+
+		It("stores the entry so that it can be read back", func() {
+			Expect(journal.Write(logger, entry)).To(Succeed())
+			Expect(journal.Entries(logger)).To(ConsistOf(entry))
+		})
+
+		It("replaces any previous entry for the container", func() {
+			Expect(journal.Write(logger, entry)).To(Succeed())
+
+			entry.Stages = append(entry.Stages, gardener.CreationStageContainer)
+			Expect(journal.Write(logger, entry)).To(Succeed())
+
+			Expect(journal.Entries(logger)).To(ConsistOf(entry))
+		})
+
+		It("does not leave temporary files behind", func() {
+			Expect(journal.Write(logger, entry)).To(Succeed())
+
+			files, err := ioutil.ReadDir(journalDir)
+			Expect(err).NotTo(HaveOccurred())
+			Expect(files).To(HaveLen(1))
+		})
+	})
+
+	Describe("Remove", func() {
+		It("removes the entry", func() {
+			Expect(journal.Write(logger, entry)).To(Succeed())
+			Expect(journal.Remove(logger, "some-handle")).To(Succeed())
+
+			Expect(journal.Entries(logger)).To(BeEmpty())
+			Expect(filepath.Join(journalDir, "some-handle.json")).NotTo(BeAnExistingFile())
+		})
+
+		Context("when there is no entry", func() {
+			It("succeeds", func() {
+				Expect(journal.Remove(logger, "some-handle")).To(Succeed())
+			})
+		})
+	})
+
+	Describe("Entries", func() {
+		It("ignores files which are not journals", func() {
+			Expect(ioutil.WriteFile(filepath.Join(journalDir, ".some-handle123"), []byte("{"), 0600)).To(Succeed())
+			Expect(journal.Write(logger, entry)).To(Succeed())
+
+			Expect(journal.Entries(logger)).To(ConsistOf(entry))
+		})
+
+		Context("when the journal directory does not exist", func() {
+			It("returns no entries", func() {
+				journal = depot.NewCreationJournal(filepath.Join(journalDir, "missing"))
+				Expect(journal.Entries(logger)).To(BeEmpty())
+			})
+		})
+
+		Context("when a journal is corrupt", func() {
+			It("returns an entry with only the handle, so it can be rolled back", func() {
+				Expect(ioutil.WriteFile(filepath.Join(journalDir, "corrupt-handle.json"), []byte("{"), 0600)).To(Succeed())
+
+				entries, err := journal.Entries(logger)
+				Expect(err).NotTo(HaveOccurred())
+				Expect(entries).To(HaveLen(1))
+				Expect(entries[0].Spec.Handle).To(Equal("corrupt-handle"))
+				Expect(entries[0].Stages).To(BeEmpty())
+			})
+		})
+	})
+})
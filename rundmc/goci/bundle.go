@@ -64,30 +64,33 @@ func (b Bndl) Resources() *specs.Resources {
 	return b.Spec.Linux.Resources
 }
 
+// WithCPUShares returns a bundle with the given CPU resources. The original bundle is not modified.
 func (b Bndl) WithCPUShares(shares specs.CPU) Bndl {
-	resources := b.Resources()
-	if resources == nil {
-		resources = &specs.Resources{}
-	}
-
+	resources := b.copyResources()
 	resources.CPU = &shares
 	b.Spec.Linux.Resources = resources
 
 	return b
 }
 
+// WithMemoryLimit returns a bundle with the given memory resources. The original bundle is not modified.
 func (b Bndl) WithMemoryLimit(limit specs.Memory) Bndl {
-	resources := b.Resources()
-	if resources == nil {
-		resources = &specs.Resources{}
-	}
-
+	resources := b.copyResources()
 	resources.Memory = &limit
 	b.Spec.Linux.Resources = resources
 
 	return b
 }
 
+func (b Bndl) copyResources() *specs.Resources {
+	if b.Resources() == nil {
+		return &specs.Resources{}
+	}
+
+	resources := *b.Resources()
+	return &resources
+}
+
 // WithNamespace returns a bundle with the given namespace in the list of namespaces. The bundle is not modified, but any
 // existing namespace of this type will be replaced.
 func (b Bndl) WithNamespace(ns specs.Namespace) Bndl {
@@ -166,6 +166,36 @@ var _ = Describe("Bundle", func() {
 		It("returns a bundle with the cpu shares added to the runtime spec", func() {
 			Expect(returnedBundle.Resources().CPU).To(Equal(&specs.CPU{Shares: &shares}))
 		})
+
+		It("does not modify the resources of the original bundle", func() {
+			var limit uint64 = 20
+			original := initialBundle.WithMemoryLimit(specs.Memory{Limit: &limit})
+
+			returnedBundle = original.WithCPUShares(specs.CPU{Shares: &shares})
+			Expect(returnedBundle.Resources().Memory).To(Equal(&specs.Memory{Limit: &limit}))
+			Expect(original.Resources().CPU).To(BeNil())
+		})
+	})
+
+	Describe("WithMemoryLimit", func() {
+		var limit uint64 = 10
+
+		BeforeEach(func() {
+			returnedBundle = initialBundle.WithMemoryLimit(specs.Memory{Limit: &limit})
+		})
+
+		It("returns a bundle with the memory limit added to the runtime spec", func() {
+			Expect(returnedBundle.Resources().Memory).To(Equal(&specs.Memory{Limit: &limit}))
+		})
+
+		It("does not modify the resources of the original bundle", func() {
+			var shares uint64 = 20
+			original := initialBundle.WithCPUShares(specs.CPU{Shares: &shares})
+
+			returnedBundle = original.WithMemoryLimit(specs.Memory{Limit: &limit})
+			Expect(returnedBundle.Resources().CPU).To(Equal(&specs.CPU{Shares: &shares}))
+			Expect(original.Resources().Memory).To(BeNil())
+		})
 	})
 
 	Describe("WithNamespace", func() {
@@ -38,10 +38,40 @@ func DeleteCommand(id, logFile string) *exec.Cmd {
 	return DefaultRuncBinary.DeleteCommand(id, logFile)
 }
 
+// ForceDeleteCommand creates a command that kills and deletes a container using the default runc binary name.
+func ForceDeleteCommand(id, logFile string) *exec.Cmd {
+	return DefaultRuncBinary.ForceDeleteCommand(id, logFile)
+}
+
 func EventsCommand(id string) *exec.Cmd {
 	return DefaultRuncBinary.EventsCommand(id)
 }
 
+// UpdateCommand creates a command that updates the resources of a container using the default runc binary name.
+func UpdateCommand(id, logFile string) *exec.Cmd {
+	return DefaultRuncBinary.UpdateCommand(id, logFile)
+}
+
+// PauseCommand creates a command that freezes a container using the default runc binary name.
+func PauseCommand(id, logFile string) *exec.Cmd {
+	return DefaultRuncBinary.PauseCommand(id, logFile)
+}
+
+// ResumeCommand creates a command that thaws a container using the default runc binary name.
+func ResumeCommand(id, logFile string) *exec.Cmd {
+	return DefaultRuncBinary.ResumeCommand(id, logFile)
+}
+
+// CheckpointCommand creates a command that checkpoints a container using the default runc binary name.
+func CheckpointCommand(id, imagePath, logFile string) *exec.Cmd {
+	return DefaultRuncBinary.CheckpointCommand(id, imagePath, logFile)
+}
+
+// RestoreCommand creates a command that restores a container using the default runc binary name.
+func RestoreCommand(id, bundlePath, imagePath, logFile string) *exec.Cmd {
+	return DefaultRuncBinary.RestoreCommand(id, bundlePath, imagePath, logFile)
+}
+
 // StartCommand returns an *exec.Cmd that, when run, will execute a given bundle.
 func (runc RuncBinary) StartCommand(path, id string, detach bool, log string) *exec.Cmd {
 	args := []string{"--debug", "--log", log, "start"}
@@ -96,3 +126,39 @@ func (runc RuncBinary) StatsCommand(id, logFile string) *exec.Cmd {
 func (runc RuncBinary) DeleteCommand(id, logFile string) *exec.Cmd {
 	return exec.Command(string(runc), "--debug", "--log", logFile, "delete", id)
 }
+
+// ForceDeleteCommand returns an *exec.Cmd that, when run, will kill the
+// processes of the container, whatever state it is in, and delete it.
+func (runc RuncBinary) ForceDeleteCommand(id, logFile string) *exec.Cmd {
+	return exec.Command(string(runc), "--debug", "--log", logFile, "delete", "--force", id)
+}
+
+// UpdateCommand returns an *exec.Cmd that, when run, will update the cgroup
+// resources of the container with the resources JSON read from stdin.
+func (runc RuncBinary) UpdateCommand(id, logFile string) *exec.Cmd {
+	return exec.Command(string(runc), "--debug", "--log", logFile, "update", "-r", "-", id)
+}
+
+// PauseCommand returns an *exec.Cmd that, when run, will freeze all the
+// processes of the container using the freezer cgroup.
+func (runc RuncBinary) PauseCommand(id, logFile string) *exec.Cmd {
+	return exec.Command(string(runc), "--debug", "--log", logFile, "pause", id)
+}
+
+// ResumeCommand returns an *exec.Cmd that, when run, will thaw all the
+// processes of a paused container.
+func (runc RuncBinary) ResumeCommand(id, logFile string) *exec.Cmd {
+	return exec.Command(string(runc), "--debug", "--log", logFile, "resume", id)
+}
+
+// CheckpointCommand returns an *exec.Cmd that, when run, will dump the state
+// of the container's processes to the image path and stop the container.
+func (runc RuncBinary) CheckpointCommand(id, imagePath, logFile string) *exec.Cmd {
+	return exec.Command(string(runc), "--debug", "--log", logFile, "checkpoint", "--image-path", imagePath, id)
+}
+
+// RestoreCommand returns an *exec.Cmd that, when run, will restore a
+// container from the image path in the background.
+func (runc RuncBinary) RestoreCommand(id, bundlePath, imagePath, logFile string) *exec.Cmd {
+	return exec.Command(string(runc), "--debug", "--log", logFile, "restore", "--detach", "--image-path", imagePath, "--bundle", bundlePath, id)
+}
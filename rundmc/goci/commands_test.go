@@ -70,4 +70,46 @@ var _ = Describe("Commands", func() {
 			Expect(cmd.Args).To(Equal([]string{"funC", "--debug", "--log", "log.file", "delete", "my-bundle-id"}))
 		})
 	})
+
+	Describe("ForceDeleteCommand", func() {
+		It("creates an *exec.Cmd to kill and delete the bundle", func() {
+			cmd := goci.ForceDeleteCommand("my-bundle-id", "log.file")
+			Expect(cmd.Args).To(Equal([]string{"funC", "--debug", "--log", "log.file", "delete", "--force", "my-bundle-id"}))
+		})
+	})
+
+	Describe("UpdateCommand", func() {
+		It("creates an *exec.Cmd to update the resources of the bundle from stdin", func() {
+			cmd := goci.UpdateCommand("my-bundle-id", "log.file")
+			Expect(cmd.Args).To(Equal([]string{"funC", "--debug", "--log", "log.file", "update", "-r", "-", "my-bundle-id"}))
+		})
+	})
+
+	Describe("PauseCommand", func() {
+		It("creates an *exec.Cmd to pause the bundle", func() {
+			cmd := goci.PauseCommand("my-bundle-id", "log.file")
+			Expect(cmd.Args).To(Equal([]string{"funC", "--debug", "--log", "log.file", "pause", "my-bundle-id"}))
+		})
+	})
+
+	Describe("ResumeCommand", func() {
+		It("creates an *exec.Cmd to resume the bundle", func() {
+			cmd := goci.ResumeCommand("my-bundle-id", "log.file")
+			Expect(cmd.Args).To(Equal([]string{"funC", "--debug", "--log", "log.file", "resume", "my-bundle-id"}))
+		})
+	})
+
+	Describe("CheckpointCommand", func() {
+		It("creates an *exec.Cmd to checkpoint the bundle to the image path", func() {
+			cmd := goci.CheckpointCommand("my-bundle-id", "/image/path", "log.file")
+			Expect(cmd.Args).To(Equal([]string{"funC", "--debug", "--log", "log.file", "checkpoint", "--image-path", "/image/path", "my-bundle-id"}))
+		})
+	})
+
+	Describe("RestoreCommand", func() {
+		It("creates an *exec.Cmd to restore the bundle from the image path", func() {
+			cmd := goci.RestoreCommand("my-bundle-id", "/bundle/path", "/image/path", "log.file")
+			Expect(cmd.Args).To(Equal([]string{"funC", "--debug", "--log", "log.file", "restore", "--detach", "--image-path", "/image/path", "--bundle", "/bundle/path", "my-bundle-id"}))
+		})
+	})
 })
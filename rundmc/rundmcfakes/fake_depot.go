@@ -46,6 +46,16 @@ type FakeDepot struct {
 		result1 []string
 		result2 error
 	}
+	UpdateStub        func(log lager.Logger, handle string, bundle depot.BundleSaver) error
+	updateMutex       sync.RWMutex
+	updateArgsForCall []struct {
+		log    lager.Logger
+		handle string
+		bundle depot.BundleSaver
+	}
+	updateReturns struct {
+		result1 error
+	}
 	invocations      map[string][][]interface{}
 	invocationsMutex sync.RWMutex
 }
@@ -180,6 +190,41 @@ func (fake *FakeDepot) HandlesReturns(result1 []string, result2 error) {
 	}{result1, result2}
 }
 
+func (fake *FakeDepot) Update(log lager.Logger, handle string, bundle depot.BundleSaver) error {
+	fake.updateMutex.Lock()
+	fake.updateArgsForCall = append(fake.updateArgsForCall, struct {
+		log    lager.Logger
+		handle string
+		bundle depot.BundleSaver
+	}{log, handle, bundle})
+	fake.recordInvocation("Update", []interface{}{log, handle, bundle})
+	fake.updateMutex.Unlock()
+	if fake.UpdateStub != nil {
+		return fake.UpdateStub(log, handle, bundle)
+	} else {
+		return fake.updateReturns.result1
+	}
+}
+
+func (fake *FakeDepot) UpdateCallCount() int {
+	fake.updateMutex.RLock()
+	defer fake.updateMutex.RUnlock()
+	return len(fake.updateArgsForCall)
+}
+
+func (fake *FakeDepot) UpdateArgsForCall(i int) (lager.Logger, string, depot.BundleSaver) {
+	fake.updateMutex.RLock()
+	defer fake.updateMutex.RUnlock()
+	return fake.updateArgsForCall[i].log, fake.updateArgsForCall[i].handle, fake.updateArgsForCall[i].bundle
+}
+
+func (fake *FakeDepot) UpdateReturns(result1 error) {
+	fake.UpdateStub = nil
+	fake.updateReturns = struct {
+		result1 error
+	}{result1}
+}
+
 func (fake *FakeDepot) Invocations() map[string][][]interface{} {
 	fake.invocationsMutex.RLock()
 	defer fake.invocationsMutex.RUnlock()
@@ -191,6 +236,8 @@ func (fake *FakeDepot) Invocations() map[string][][]interface{} {
 	defer fake.destroyMutex.RUnlock()
 	fake.handlesMutex.RLock()
 	defer fake.handlesMutex.RUnlock()
+	fake.updateMutex.RLock()
+	defer fake.updateMutex.RUnlock()
 	return fake.invocations
 }
 
@@ -4,36 +4,42 @@ package rundmcfakes
 import (
 	"sync"
 
+	"code.cloudfoundry.org/guardian/gardener"
 	"code.cloudfoundry.org/guardian/rundmc"
 )
 
 type FakeEventStore struct {
-	OnEventStub        func(id string, event string) error
+	OnEventStub        func(id string, event gardener.Event) error
 	onEventMutex       sync.RWMutex
 	onEventArgsForCall []struct {
 		id    string
-		event string
+		event gardener.Event
 	}
 	onEventReturns struct {
 		result1 error
 	}
-	EventsStub        func(id string) []string
+	EventsStub        func(id string) []gardener.Event
 	eventsMutex       sync.RWMutex
 	eventsArgsForCall []struct {
 		id string
 	}
 	eventsReturns struct {
-		result1 []string
+		result1 []gardener.Event
+	}
+	ForgetStub        func(id string)
+	forgetMutex       sync.RWMutex
+	forgetArgsForCall []struct {
+		id string
 	}
 	invocations      map[string][][]interface{}
 	invocationsMutex sync.RWMutex
 }
 
-func (fake *FakeEventStore) OnEvent(id string, event string) error {
+func (fake *FakeEventStore) OnEvent(id string, event gardener.Event) error {
 	fake.onEventMutex.Lock()
 	fake.onEventArgsForCall = append(fake.onEventArgsForCall, struct {
 		id    string
-		event string
+		event gardener.Event
 	}{id, event})
 	fake.recordInvocation("OnEvent", []interface{}{id, event})
 	fake.onEventMutex.Unlock()
@@ -50,7 +56,7 @@ func (fake *FakeEventStore) OnEventCallCount() int {
 	return len(fake.onEventArgsForCall)
 }
 
-func (fake *FakeEventStore) OnEventArgsForCall(i int) (string, string) {
+func (fake *FakeEventStore) OnEventArgsForCall(i int) (string, gardener.Event) {
 	fake.onEventMutex.RLock()
 	defer fake.onEventMutex.RUnlock()
 	return fake.onEventArgsForCall[i].id, fake.onEventArgsForCall[i].event
@@ -63,7 +69,7 @@ func (fake *FakeEventStore) OnEventReturns(result1 error) {
 	}{result1}
 }
 
-func (fake *FakeEventStore) Events(id string) []string {
+func (fake *FakeEventStore) Events(id string) []gardener.Event {
 	fake.eventsMutex.Lock()
 	fake.eventsArgsForCall = append(fake.eventsArgsForCall, struct {
 		id string
@@ -89,13 +95,37 @@ func (fake *FakeEventStore) EventsArgsForCall(i int) string {
 	return fake.eventsArgsForCall[i].id
 }
 
-func (fake *FakeEventStore) EventsReturns(result1 []string) {
+func (fake *FakeEventStore) EventsReturns(result1 []gardener.Event) {
 	fake.EventsStub = nil
 	fake.eventsReturns = struct {
-		result1 []string
+		result1 []gardener.Event
 	}{result1}
 }
 
+func (fake *FakeEventStore) Forget(id string) {
+	fake.forgetMutex.Lock()
+	fake.forgetArgsForCall = append(fake.forgetArgsForCall, struct {
+		id string
+	}{id})
+	fake.recordInvocation("Forget", []interface{}{id})
+	fake.forgetMutex.Unlock()
+	if fake.ForgetStub != nil {
+		fake.ForgetStub(id)
+	}
+}
+
+func (fake *FakeEventStore) ForgetCallCount() int {
+	fake.forgetMutex.RLock()
+	defer fake.forgetMutex.RUnlock()
+	return len(fake.forgetArgsForCall)
+}
+
+func (fake *FakeEventStore) ForgetArgsForCall(i int) string {
+	fake.forgetMutex.RLock()
+	defer fake.forgetMutex.RUnlock()
+	return fake.forgetArgsForCall[i].id
+}
+
 func (fake *FakeEventStore) Invocations() map[string][][]interface{} {
 	fake.invocationsMutex.RLock()
 	defer fake.invocationsMutex.RUnlock()
@@ -103,6 +133,8 @@ func (fake *FakeEventStore) Invocations() map[string][][]interface{} {
 	defer fake.onEventMutex.RUnlock()
 	fake.eventsMutex.RLock()
 	defer fake.eventsMutex.RUnlock()
+	fake.forgetMutex.RLock()
+	defer fake.forgetMutex.RUnlock()
 	return fake.invocations
 }
 
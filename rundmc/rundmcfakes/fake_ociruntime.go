@@ -9,6 +9,7 @@ import (
 	"code.cloudfoundry.org/guardian/rundmc"
 	"code.cloudfoundry.org/guardian/rundmc/runrunc"
 	"code.cloudfoundry.org/lager"
+	"github.com/opencontainers/runtime-spec/specs-go"
 )
 
 type FakeOCIRuntime struct {
@@ -97,6 +98,64 @@ type FakeOCIRuntime struct {
 	watchEventsReturns struct {
 		result1 error
 	}
+	UpdateStub        func(log lager.Logger, id string, resources specs.Resources) error
+	updateMutex       sync.RWMutex
+	updateArgsForCall []struct {
+		log       lager.Logger
+		id        string
+		resources specs.Resources
+	}
+	updateReturns struct {
+		result1 error
+	}
+	PauseStub        func(log lager.Logger, id string) error
+	pauseMutex       sync.RWMutex
+	pauseArgsForCall []struct {
+		log lager.Logger
+		id  string
+	}
+	pauseReturns struct {
+		result1 error
+	}
+	ResumeStub        func(log lager.Logger, id string) error
+	resumeMutex       sync.RWMutex
+	resumeArgsForCall []struct {
+		log lager.Logger
+		id  string
+	}
+	resumeReturns struct {
+		result1 error
+	}
+	CheckpointStub        func(log lager.Logger, id, imagePath string) error
+	checkpointMutex       sync.RWMutex
+	checkpointArgsForCall []struct {
+		log       lager.Logger
+		id        string
+		imagePath string
+	}
+	checkpointReturns struct {
+		result1 error
+	}
+	RestoreStub        func(log lager.Logger, id, bundlePath, imagePath string) error
+	restoreMutex       sync.RWMutex
+	restoreArgsForCall []struct {
+		log        lager.Logger
+		id         string
+		bundlePath string
+		imagePath  string
+	}
+	restoreReturns struct {
+		result1 error
+	}
+	ForceDeleteStub        func(log lager.Logger, id string) error
+	forceDeleteMutex       sync.RWMutex
+	forceDeleteArgsForCall []struct {
+		log lager.Logger
+		id  string
+	}
+	forceDeleteReturns struct {
+		result1 error
+	}
 	invocations      map[string][][]interface{}
 	invocationsMutex sync.RWMutex
 }
@@ -386,6 +445,214 @@ func (fake *FakeOCIRuntime) WatchEventsReturns(result1 error) {
 	}{result1}
 }
 
+func (fake *FakeOCIRuntime) Update(log lager.Logger, id string, resources specs.Resources) error {
+	fake.updateMutex.Lock()
+	fake.updateArgsForCall = append(fake.updateArgsForCall, struct {
+		log       lager.Logger
+		id        string
+		resources specs.Resources
+	}{log, id, resources})
+	fake.recordInvocation("Update", []interface{}{log, id, resources})
+	fake.updateMutex.Unlock()
+	if fake.UpdateStub != nil {
+		return fake.UpdateStub(log, id, resources)
+	} else {
+		return fake.updateReturns.result1
+	}
+}
+
+func (fake *FakeOCIRuntime) UpdateCallCount() int {
+	fake.updateMutex.RLock()
+	defer fake.updateMutex.RUnlock()
+	return len(fake.updateArgsForCall)
+}
+
+func (fake *FakeOCIRuntime) UpdateArgsForCall(i int) (lager.Logger, string, specs.Resources) {
+	fake.updateMutex.RLock()
+	defer fake.updateMutex.RUnlock()
+	return fake.updateArgsForCall[i].log, fake.updateArgsForCall[i].id, fake.updateArgsForCall[i].resources
+}
+
+func (fake *FakeOCIRuntime) UpdateReturns(result1 error) {
+	fake.UpdateStub = nil
+	fake.updateReturns = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *FakeOCIRuntime) Pause(log lager.Logger, id string) error {
+	fake.pauseMutex.Lock()
+	fake.pauseArgsForCall = append(fake.pauseArgsForCall, struct {
+		log lager.Logger
+		id  string
+	}{log, id})
+	fake.recordInvocation("Pause", []interface{}{log, id})
+	fake.pauseMutex.Unlock()
+	if fake.PauseStub != nil {
+		return fake.PauseStub(log, id)
+	} else {
+		return fake.pauseReturns.result1
+	}
+}
+
+func (fake *FakeOCIRuntime) PauseCallCount() int {
+	fake.pauseMutex.RLock()
+	defer fake.pauseMutex.RUnlock()
+	return len(fake.pauseArgsForCall)
+}
+
+func (fake *FakeOCIRuntime) PauseArgsForCall(i int) (lager.Logger, string) {
+	fake.pauseMutex.RLock()
+	defer fake.pauseMutex.RUnlock()
+	return fake.pauseArgsForCall[i].log, fake.pauseArgsForCall[i].id
+}
+
+func (fake *FakeOCIRuntime) PauseReturns(result1 error) {
+	fake.PauseStub = nil
+	fake.pauseReturns = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *FakeOCIRuntime) Resume(log lager.Logger, id string) error {
+	fake.resumeMutex.Lock()
+	fake.resumeArgsForCall = append(fake.resumeArgsForCall, struct {
+		log lager.Logger
+		id  string
+	}{log, id})
+	fake.recordInvocation("Resume", []interface{}{log, id})
+	fake.resumeMutex.Unlock()
+	if fake.ResumeStub != nil {
+		return fake.ResumeStub(log, id)
+	} else {
+		return fake.resumeReturns.result1
+	}
+}
+
+func (fake *FakeOCIRuntime) ResumeCallCount() int {
+	fake.resumeMutex.RLock()
+	defer fake.resumeMutex.RUnlock()
+	return len(fake.resumeArgsForCall)
+}
+
+func (fake *FakeOCIRuntime) ResumeArgsForCall(i int) (lager.Logger, string) {
+	fake.resumeMutex.RLock()
+	defer fake.resumeMutex.RUnlock()
+	return fake.resumeArgsForCall[i].log, fake.resumeArgsForCall[i].id
+}
+
+func (fake *FakeOCIRuntime) ResumeReturns(result1 error) {
+	fake.ResumeStub = nil
+	fake.resumeReturns = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *FakeOCIRuntime) Checkpoint(log lager.Logger, id string, imagePath string) error {
+	fake.checkpointMutex.Lock()
+	fake.checkpointArgsForCall = append(fake.checkpointArgsForCall, struct {
+		log       lager.Logger
+		id        string
+		imagePath string
+	}{log, id, imagePath})
+	fake.recordInvocation("Checkpoint", []interface{}{log, id, imagePath})
+	fake.checkpointMutex.Unlock()
+	if fake.CheckpointStub != nil {
+		return fake.CheckpointStub(log, id, imagePath)
+	} else {
+		return fake.checkpointReturns.result1
+	}
+}
+
+func (fake *FakeOCIRuntime) CheckpointCallCount() int {
+	fake.checkpointMutex.RLock()
+	defer fake.checkpointMutex.RUnlock()
+	return len(fake.checkpointArgsForCall)
+}
+
+func (fake *FakeOCIRuntime) CheckpointArgsForCall(i int) (lager.Logger, string, string) {
+	fake.checkpointMutex.RLock()
+	defer fake.checkpointMutex.RUnlock()
+	return fake.checkpointArgsForCall[i].log, fake.checkpointArgsForCall[i].id, fake.checkpointArgsForCall[i].imagePath
+}
+
+func (fake *FakeOCIRuntime) CheckpointReturns(result1 error) {
+	fake.CheckpointStub = nil
+	fake.checkpointReturns = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *FakeOCIRuntime) Restore(log lager.Logger, id string, bundlePath string, imagePath string) error {
+	fake.restoreMutex.Lock()
+	fake.restoreArgsForCall = append(fake.restoreArgsForCall, struct {
+		log        lager.Logger
+		id         string
+		bundlePath string
+		imagePath  string
+	}{log, id, bundlePath, imagePath})
+	fake.recordInvocation("Restore", []interface{}{log, id, bundlePath, imagePath})
+	fake.restoreMutex.Unlock()
+	if fake.RestoreStub != nil {
+		return fake.RestoreStub(log, id, bundlePath, imagePath)
+	} else {
+		return fake.restoreReturns.result1
+	}
+}
+
+func (fake *FakeOCIRuntime) RestoreCallCount() int {
+	fake.restoreMutex.RLock()
+	defer fake.restoreMutex.RUnlock()
+	return len(fake.restoreArgsForCall)
+}
+
+func (fake *FakeOCIRuntime) RestoreArgsForCall(i int) (lager.Logger, string, string, string) {
+	fake.restoreMutex.RLock()
+	defer fake.restoreMutex.RUnlock()
+	return fake.restoreArgsForCall[i].log, fake.restoreArgsForCall[i].id, fake.restoreArgsForCall[i].bundlePath, fake.restoreArgsForCall[i].imagePath
+}
+
+func (fake *FakeOCIRuntime) RestoreReturns(result1 error) {
+	fake.RestoreStub = nil
+	fake.restoreReturns = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *FakeOCIRuntime) ForceDelete(log lager.Logger, id string) error {
+	fake.forceDeleteMutex.Lock()
+	fake.forceDeleteArgsForCall = append(fake.forceDeleteArgsForCall, struct {
+		log lager.Logger
+		id  string
+	}{log, id})
+	fake.recordInvocation("ForceDelete", []interface{}{log, id})
+	fake.forceDeleteMutex.Unlock()
+	if fake.ForceDeleteStub != nil {
+		return fake.ForceDeleteStub(log, id)
+	} else {
+		return fake.forceDeleteReturns.result1
+	}
+}
+
+func (fake *FakeOCIRuntime) ForceDeleteCallCount() int {
+	fake.forceDeleteMutex.RLock()
+	defer fake.forceDeleteMutex.RUnlock()
+	return len(fake.forceDeleteArgsForCall)
+}
+
+func (fake *FakeOCIRuntime) ForceDeleteArgsForCall(i int) (lager.Logger, string) {
+	fake.forceDeleteMutex.RLock()
+	defer fake.forceDeleteMutex.RUnlock()
+	return fake.forceDeleteArgsForCall[i].log, fake.forceDeleteArgsForCall[i].id
+}
+
+func (fake *FakeOCIRuntime) ForceDeleteReturns(result1 error) {
+	fake.ForceDeleteStub = nil
+	fake.forceDeleteReturns = struct {
+		result1 error
+	}{result1}
+}
+
 func (fake *FakeOCIRuntime) Invocations() map[string][][]interface{} {
 	fake.invocationsMutex.RLock()
 	defer fake.invocationsMutex.RUnlock()
@@ -405,6 +672,18 @@ func (fake *FakeOCIRuntime) Invocations() map[string][][]interface{} {
 	defer fake.statsMutex.RUnlock()
 	fake.watchEventsMutex.RLock()
 	defer fake.watchEventsMutex.RUnlock()
+	fake.updateMutex.RLock()
+	defer fake.updateMutex.RUnlock()
+	fake.pauseMutex.RLock()
+	defer fake.pauseMutex.RUnlock()
+	fake.resumeMutex.RLock()
+	defer fake.resumeMutex.RUnlock()
+	fake.checkpointMutex.RLock()
+	defer fake.checkpointMutex.RUnlock()
+	fake.restoreMutex.RLock()
+	defer fake.restoreMutex.RUnlock()
+	fake.forceDeleteMutex.RLock()
+	defer fake.forceDeleteMutex.RUnlock()
 	return fake.invocations
 }
 
@@ -25,6 +25,14 @@ type FakeProperties struct {
 		result1 string
 		result2 bool
 	}
+	HasKeySpaceStub        func(handle string) bool
+	hasKeySpaceMutex       sync.RWMutex
+	hasKeySpaceArgsForCall []struct {
+		handle string
+	}
+	hasKeySpaceReturns struct {
+		result1 bool
+	}
 	invocations      map[string][][]interface{}
 	invocationsMutex sync.RWMutex
 }
@@ -90,6 +98,39 @@ func (fake *FakeProperties) GetReturns(result1 string, result2 bool) {
 	}{result1, result2}
 }
 
+func (fake *FakeProperties) HasKeySpace(handle string) bool {
+	fake.hasKeySpaceMutex.Lock()
+	fake.hasKeySpaceArgsForCall = append(fake.hasKeySpaceArgsForCall, struct {
+		handle string
+	}{handle})
+	fake.recordInvocation("HasKeySpace", []interface{}{handle})
+	fake.hasKeySpaceMutex.Unlock()
+	if fake.HasKeySpaceStub != nil {
+		return fake.HasKeySpaceStub(handle)
+	} else {
+		return fake.hasKeySpaceReturns.result1
+	}
+}
+
+func (fake *FakeProperties) HasKeySpaceCallCount() int {
+	fake.hasKeySpaceMutex.RLock()
+	defer fake.hasKeySpaceMutex.RUnlock()
+	return len(fake.hasKeySpaceArgsForCall)
+}
+
+func (fake *FakeProperties) HasKeySpaceArgsForCall(i int) string {
+	fake.hasKeySpaceMutex.RLock()
+	defer fake.hasKeySpaceMutex.RUnlock()
+	return fake.hasKeySpaceArgsForCall[i].handle
+}
+
+func (fake *FakeProperties) HasKeySpaceReturns(result1 bool) {
+	fake.HasKeySpaceStub = nil
+	fake.hasKeySpaceReturns = struct {
+		result1 bool
+	}{result1}
+}
+
 func (fake *FakeProperties) Invocations() map[string][][]interface{} {
 	fake.invocationsMutex.RLock()
 	defer fake.invocationsMutex.RUnlock()
@@ -97,6 +138,8 @@ func (fake *FakeProperties) Invocations() map[string][][]interface{} {
 	defer fake.setMutex.RUnlock()
 	fake.getMutex.RLock()
 	defer fake.getMutex.RUnlock()
+	fake.hasKeySpaceMutex.RLock()
+	defer fake.hasKeySpaceMutex.RUnlock()
 	return fake.invocations
 }
 
@@ -0,0 +1,141 @@
+// This file was generated by counterfeiter
+package rundmcfakes
+
+import (
+	"sync"
+
+	"code.cloudfoundry.org/guardian/gardener"
+	"code.cloudfoundry.org/guardian/rundmc"
+)
+
+type FakeStatsStore struct {
+	LatestStub        func(handle string) (gardener.ActualContainerMetrics, bool)
+	latestMutex       sync.RWMutex
+	latestArgsForCall []struct {
+		handle string
+	}
+	latestReturns struct {
+		result1 gardener.ActualContainerMetrics
+		result2 bool
+	}
+	ForgetStub        func(handle string)
+	forgetMutex       sync.RWMutex
+	forgetArgsForCall []struct {
+		handle string
+	}
+	TrackStub        func(handle string)
+	trackMutex       sync.RWMutex
+	trackArgsForCall []struct {
+		handle string
+	}
+	invocations      map[string][][]interface{}
+	invocationsMutex sync.RWMutex
+}
+
+func (fake *FakeStatsStore) Latest(handle string) (gardener.ActualContainerMetrics, bool) {
+	fake.latestMutex.Lock()
+	fake.latestArgsForCall = append(fake.latestArgsForCall, struct {
+		handle string
+	}{handle})
+	fake.recordInvocation("Latest", []interface{}{handle})
+	fake.latestMutex.Unlock()
+	if fake.LatestStub != nil {
+		return fake.LatestStub(handle)
+	} else {
+		return fake.latestReturns.result1, fake.latestReturns.result2
+	}
+}
+
+func (fake *FakeStatsStore) LatestCallCount() int {
+	fake.latestMutex.RLock()
+	defer fake.latestMutex.RUnlock()
+	return len(fake.latestArgsForCall)
+}
+
+func (fake *FakeStatsStore) LatestArgsForCall(i int) string {
+	fake.latestMutex.RLock()
+	defer fake.latestMutex.RUnlock()
+	return fake.latestArgsForCall[i].handle
+}
+
+func (fake *FakeStatsStore) LatestReturns(result1 gardener.ActualContainerMetrics, result2 bool) {
+	fake.LatestStub = nil
+	fake.latestReturns = struct {
+		result1 gardener.ActualContainerMetrics
+		result2 bool
+	}{result1, result2}
+}
+
+func (fake *FakeStatsStore) Forget(handle string) {
+	fake.forgetMutex.Lock()
+	fake.forgetArgsForCall = append(fake.forgetArgsForCall, struct {
+		handle string
+	}{handle})
+	fake.recordInvocation("Forget", []interface{}{handle})
+	fake.forgetMutex.Unlock()
+	if fake.ForgetStub != nil {
+		fake.ForgetStub(handle)
+	}
+}
+
+func (fake *FakeStatsStore) ForgetCallCount() int {
+	fake.forgetMutex.RLock()
+	defer fake.forgetMutex.RUnlock()
+	return len(fake.forgetArgsForCall)
+}
+
+func (fake *FakeStatsStore) ForgetArgsForCall(i int) string {
+	fake.forgetMutex.RLock()
+	defer fake.forgetMutex.RUnlock()
+	return fake.forgetArgsForCall[i].handle
+}
+
+func (fake *FakeStatsStore) Track(handle string) {
+	fake.trackMutex.Lock()
+	fake.trackArgsForCall = append(fake.trackArgsForCall, struct {
+		handle string
+	}{handle})
+	fake.recordInvocation("Track", []interface{}{handle})
+	fake.trackMutex.Unlock()
+	if fake.TrackStub != nil {
+		fake.TrackStub(handle)
+	}
+}
+
+func (fake *FakeStatsStore) TrackCallCount() int {
+	fake.trackMutex.RLock()
+	defer fake.trackMutex.RUnlock()
+	return len(fake.trackArgsForCall)
+}
+
+func (fake *FakeStatsStore) TrackArgsForCall(i int) string {
+	fake.trackMutex.RLock()
+	defer fake.trackMutex.RUnlock()
+	return fake.trackArgsForCall[i].handle
+}
+
+func (fake *FakeStatsStore) Invocations() map[string][][]interface{} {
+	fake.invocationsMutex.RLock()
+	defer fake.invocationsMutex.RUnlock()
+	fake.latestMutex.RLock()
+	defer fake.latestMutex.RUnlock()
+	fake.forgetMutex.RLock()
+	defer fake.forgetMutex.RUnlock()
+	fake.trackMutex.RLock()
+	defer fake.trackMutex.RUnlock()
+	return fake.invocations
+}
+
+func (fake *FakeStatsStore) recordInvocation(key string, args []interface{}) {
+	fake.invocationsMutex.Lock()
+	defer fake.invocationsMutex.Unlock()
+	if fake.invocations == nil {
+		fake.invocations = map[string][][]interface{}{}
+	}
+	if fake.invocations[key] == nil {
+		fake.invocations[key] = [][]interface{}{}
+	}
+	fake.invocations[key] = append(fake.invocations[key], args)
+}
+
+var _ rundmc.StatsStore = new(FakeStatsStore)
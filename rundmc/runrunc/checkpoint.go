@@ -0,0 +1,54 @@
+package runrunc
+
+import (
+	"fmt"
+	"os/exec"
+
+	"code.cloudfoundry.org/lager"
+)
+
+type Checkpointer struct {
+	runner RuncCmdRunner
+	runc   RuncBinary
+}
+
+func NewCheckpointer(runner RuncCmdRunner, runc RuncBinary) *Checkpointer {
+	return &Checkpointer{
+		runner: runner,
+		runc:   runc,
+	}
+}
+
+// Checkpoint dumps the processes of the container to the image path using
+// 'runc checkpoint', which leaves the container stopped
+func (c *Checkpointer) Checkpoint(log lager.Logger, handle, imagePath string) error {
+	log = log.Session("checkpoint", lager.Data{"handle": handle, "image-path": imagePath})
+
+	log.Info("started")
+	defer log.Info("finished")
+
+	if err := c.runner.RunAndLog(log, func(logFile string) *exec.Cmd {
+		return c.runc.CheckpointCommand(handle, imagePath, logFile)
+	}); err != nil {
+		return fmt.Errorf("runc checkpoint: %s", err)
+	}
+
+	return nil
+}
+
+// Restore recreates the container from the bundle and the processes from the
+// image path using 'runc restore'
+func (c *Checkpointer) Restore(log lager.Logger, handle, bundlePath, imagePath string) error {
+	log = log.Session("restore", lager.Data{"handle": handle, "bundle": bundlePath, "image-path": imagePath})
+
+	log.Info("started")
+	defer log.Info("finished")
+
+	if err := c.runner.RunAndLog(log, func(logFile string) *exec.Cmd {
+		return c.runc.RestoreCommand(handle, bundlePath, imagePath, logFile)
+	}); err != nil {
+		return fmt.Errorf("runc restore: %s", err)
+	}
+
+	return nil
+}
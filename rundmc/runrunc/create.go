@@ -8,6 +8,7 @@ import (
 	"path/filepath"
 
 	"code.cloudfoundry.org/garden"
+	"code.cloudfoundry.org/guardian/tracing"
 	"code.cloudfoundry.org/lager"
 	"github.com/cloudfoundry/gunk/command_runner"
 )
@@ -32,6 +33,7 @@ func (c *Creator) Create(log lager.Logger, bundlePath, id string, _ garden.Proce
 	pidFilePath := filepath.Join(bundlePath, "pidfile")
 
 	cmd := exec.Command(c.runcPath, "--debug", "--log", logFilePath, "create", "--no-new-keyring", "--bundle", bundlePath, "--pid-file", pidFilePath, id)
+	tracing.SetEnv(log, cmd)
 
 	log.Info("creating", lager.Data{
 		"runc":        c.runcPath,
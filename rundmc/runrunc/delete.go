@@ -28,3 +28,16 @@ func (d *Deleter) Delete(log lager.Logger, handle string) error {
 		return d.runc.DeleteCommand(handle, logFile)
 	})
 }
+
+// ForceDelete kills the processes of the container, whatever state it is in,
+// and deletes it
+func (d *Deleter) ForceDelete(log lager.Logger, handle string) error {
+	log = log.Session("force-delete", lager.Data{"handle": handle})
+
+	log.Info("started")
+	defer log.Info("finished")
+
+	return d.runner.RunAndLog(log, func(logFile string) *exec.Cmd {
+		return d.runc.ForceDeleteCommand(handle, logFile)
+	})
+}
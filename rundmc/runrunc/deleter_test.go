@@ -48,4 +48,18 @@ var _ = Describe("Delete", func() {
 		}))
 	})
 
+	Describe("ForceDelete", func() {
+		It("runs 'runc delete --force' using the logging runner", func() {
+			runcBinary.ForceDeleteCommandStub = func(id, logFile string) *exec.Cmd {
+				return exec.Command("funC", "--log", logFile, "delete", "--force", id)
+			}
+
+			Expect(deleter.ForceDelete(logger, "some-container")).To(Succeed())
+			Expect(commandRunner).To(HaveExecutedSerially(fake_command_runner.CommandSpec{
+				Path: "funC",
+				Args: []string{"--log", "potato.log", "delete", "--force", "some-container"},
+			}))
+		})
+	})
+
 })
@@ -6,6 +6,7 @@ import (
 	"os"
 	"os/exec"
 
+	"code.cloudfoundry.org/guardian/tracing"
 	"code.cloudfoundry.org/lager"
 	"github.com/cloudfoundry/gunk/command_runner"
 )
@@ -36,7 +37,10 @@ func (l *logRunner) RunAndLog(log lager.Logger, loggingCmd LoggingCmd) (err erro
 	if err != nil {
 		return err
 	}
-	err = l.runner.Run(loggingCmd(logFile.Name()))
+	cmd := loggingCmd(logFile.Name())
+	tracing.SetEnv(log, cmd)
+
+	err = l.runner.Run(cmd)
 	return forwardLogs(log, logFile, err)
 }
 
@@ -7,6 +7,7 @@ import (
 	"os/exec"
 
 	"code.cloudfoundry.org/guardian/rundmc/runrunc"
+	"code.cloudfoundry.org/guardian/tracing"
 	"code.cloudfoundry.org/lager"
 	"code.cloudfoundry.org/lager/lagertest"
 	"github.com/cloudfoundry/gunk/command_runner/fake_command_runner"
@@ -51,6 +52,16 @@ var _ = Describe("RunAndLog", func() {
 		}))
 	})
 
+	It("passes the trace id of the logger to the command", func() {
+		log, _ := new(tracing.Tracer).Start(logger, "create")
+		Expect(logRunner.RunAndLog(log, func(logFile string) *exec.Cmd {
+			return exec.Command("something.exe")
+		})).To(Succeed())
+
+		cmd := commandRunner.ExecutedCommands()[0]
+		Expect(tracing.FromEnv(cmd.Env)).To(Equal(tracing.TraceID(log)))
+	})
+
 	It("forwards any logs coming from the log file", func() {
 		commandRunner.WhenRunning(fake_command_runner.CommandSpec{
 			Path: "something.exe",
@@ -21,6 +21,9 @@ type RunRunc struct {
 	*Stater
 	*Killer
 	*Deleter
+	*Updater
+	*Pauser
+	*Checkpointer
 }
 
 //go:generate counterfeiter . RuncBinary
@@ -31,17 +34,27 @@ type RuncBinary interface {
 	StatsCommand(id, logFile string) *exec.Cmd
 	KillCommand(id, signal, logFile string) *exec.Cmd
 	DeleteCommand(id, logFile string) *exec.Cmd
+	ForceDeleteCommand(id, logFile string) *exec.Cmd
+	UpdateCommand(id, logFile string) *exec.Cmd
+	PauseCommand(id, logFile string) *exec.Cmd
+	ResumeCommand(id, logFile string) *exec.Cmd
+	CheckpointCommand(id, imagePath, logFile string) *exec.Cmd
+	RestoreCommand(id, bundlePath, imagePath, logFile string) *exec.Cmd
 }
 
-func New(runner command_runner.CommandRunner, runcCmdRunner RuncCmdRunner, runc RuncBinary, dadooPath, runcPath string, execPreparer ExecPreparer, execRunner ExecRunner) *RunRunc {
+func New(runner command_runner.CommandRunner, runcCmdRunner RuncCmdRunner, runc RuncBinary, dadooPath, runcPath string, execPreparer ExecPreparer, execRunner ExecRunner, statsNotifier StatsNotifier) *RunRunc {
 	return &RunRunc{
 		Creator: NewCreator(runcPath, runner),
 		Execer:  NewExecer(execPreparer, execRunner),
 
-		OomWatcher: NewOomWatcher(runner, runc),
+		OomWatcher: NewOomWatcher(runner, runc, statsNotifier),
 		Statser:    NewStatser(runcCmdRunner, runc),
 		Stater:     NewStater(runcCmdRunner, runc),
 		Killer:     NewKiller(runcCmdRunner, runc),
 		Deleter:    NewDeleter(runcCmdRunner, runc),
+		Updater:    NewUpdater(runcCmdRunner, runc),
+		Pauser:     NewPauser(runcCmdRunner, runc),
+
+		Checkpointer: NewCheckpointer(runcCmdRunner, runc),
 	}
 }
@@ -4,15 +4,16 @@ package runruncfakes
 import (
 	"sync"
 
+	"code.cloudfoundry.org/guardian/gardener"
 	"code.cloudfoundry.org/guardian/rundmc/runrunc"
 )
 
 type FakeEventsNotifier struct {
-	OnEventStub        func(handle string, event string) error
+	OnEventStub        func(handle string, event gardener.Event) error
 	onEventMutex       sync.RWMutex
 	onEventArgsForCall []struct {
 		handle string
-		event  string
+		event  gardener.Event
 	}
 	onEventReturns struct {
 		result1 error
@@ -21,11 +22,11 @@ type FakeEventsNotifier struct {
 	invocationsMutex sync.RWMutex
 }
 
-func (fake *FakeEventsNotifier) OnEvent(handle string, event string) error {
+func (fake *FakeEventsNotifier) OnEvent(handle string, event gardener.Event) error {
 	fake.onEventMutex.Lock()
 	fake.onEventArgsForCall = append(fake.onEventArgsForCall, struct {
 		handle string
-		event  string
+		event  gardener.Event
 	}{handle, event})
 	fake.recordInvocation("OnEvent", []interface{}{handle, event})
 	fake.onEventMutex.Unlock()
@@ -42,7 +43,7 @@ func (fake *FakeEventsNotifier) OnEventCallCount() int {
 	return len(fake.onEventArgsForCall)
 }
 
-func (fake *FakeEventsNotifier) OnEventArgsForCall(i int) (string, string) {
+func (fake *FakeEventsNotifier) OnEventArgsForCall(i int) (string, gardener.Event) {
 	fake.onEventMutex.RLock()
 	defer fake.onEventMutex.RUnlock()
 	return fake.onEventArgsForCall[i].handle, fake.onEventArgsForCall[i].event
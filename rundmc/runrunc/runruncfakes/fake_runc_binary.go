@@ -64,6 +64,63 @@ type FakeRuncBinary struct {
 	deleteCommandReturns struct {
 		result1 *exec.Cmd
 	}
+	UpdateCommandStub        func(id, logFile string) *exec.Cmd
+	updateCommandMutex       sync.RWMutex
+	updateCommandArgsForCall []struct {
+		id      string
+		logFile string
+	}
+	updateCommandReturns struct {
+		result1 *exec.Cmd
+	}
+	PauseCommandStub        func(id, logFile string) *exec.Cmd
+	pauseCommandMutex       sync.RWMutex
+	pauseCommandArgsForCall []struct {
+		id      string
+		logFile string
+	}
+	pauseCommandReturns struct {
+		result1 *exec.Cmd
+	}
+	ResumeCommandStub        func(id, logFile string) *exec.Cmd
+	resumeCommandMutex       sync.RWMutex
+	resumeCommandArgsForCall []struct {
+		id      string
+		logFile string
+	}
+	resumeCommandReturns struct {
+		result1 *exec.Cmd
+	}
+	CheckpointCommandStub        func(id, imagePath, logFile string) *exec.Cmd
+	checkpointCommandMutex       sync.RWMutex
+	checkpointCommandArgsForCall []struct {
+		id        string
+		imagePath string
+		logFile   string
+	}
+	checkpointCommandReturns struct {
+		result1 *exec.Cmd
+	}
+	RestoreCommandStub        func(id, bundlePath, imagePath, logFile string) *exec.Cmd
+	restoreCommandMutex       sync.RWMutex
+	restoreCommandArgsForCall []struct {
+		id         string
+		bundlePath string
+		imagePath  string
+		logFile    string
+	}
+	restoreCommandReturns struct {
+		result1 *exec.Cmd
+	}
+	ForceDeleteCommandStub        func(id string, logFile string) *exec.Cmd
+	forceDeleteCommandMutex       sync.RWMutex
+	forceDeleteCommandArgsForCall []struct {
+		id      string
+		logFile string
+	}
+	forceDeleteCommandReturns struct {
+		result1 *exec.Cmd
+	}
 	invocations      map[string][][]interface{}
 	invocationsMutex sync.RWMutex
 }
@@ -273,6 +330,213 @@ func (fake *FakeRuncBinary) DeleteCommandReturns(result1 *exec.Cmd) {
 	}{result1}
 }
 
+func (fake *FakeRuncBinary) UpdateCommand(id string, logFile string) *exec.Cmd {
+	fake.updateCommandMutex.Lock()
+	fake.updateCommandArgsForCall = append(fake.updateCommandArgsForCall, struct {
+		id      string
+		logFile string
+	}{id, logFile})
+	fake.recordInvocation("UpdateCommand", []interface{}{id, logFile})
+	fake.updateCommandMutex.Unlock()
+	if fake.UpdateCommandStub != nil {
+		return fake.UpdateCommandStub(id, logFile)
+	} else {
+		return fake.updateCommandReturns.result1
+	}
+}
+
+func (fake *FakeRuncBinary) UpdateCommandCallCount() int {
+	fake.updateCommandMutex.RLock()
+	defer fake.updateCommandMutex.RUnlock()
+	return len(fake.updateCommandArgsForCall)
+}
+
+func (fake *FakeRuncBinary) UpdateCommandArgsForCall(i int) (string, string) {
+	fake.updateCommandMutex.RLock()
+	defer fake.updateCommandMutex.RUnlock()
+	return fake.updateCommandArgsForCall[i].id, fake.updateCommandArgsForCall[i].logFile
+}
+
+func (fake *FakeRuncBinary) UpdateCommandReturns(result1 *exec.Cmd) {
+	fake.UpdateCommandStub = nil
+	fake.updateCommandReturns = struct {
+		result1 *exec.Cmd
+	}{result1}
+}
+
+func (fake *FakeRuncBinary) PauseCommand(id string, logFile string) *exec.Cmd {
+	fake.pauseCommandMutex.Lock()
+	fake.pauseCommandArgsForCall = append(fake.pauseCommandArgsForCall, struct {
+		id      string
+		logFile string
+	}{id, logFile})
+	fake.recordInvocation("PauseCommand", []interface{}{id, logFile})
+	fake.pauseCommandMutex.Unlock()
+	if fake.PauseCommandStub != nil {
+		return fake.PauseCommandStub(id, logFile)
+	} else {
+		return fake.pauseCommandReturns.result1
+	}
+}
+
+func (fake *FakeRuncBinary) PauseCommandCallCount() int {
+	fake.pauseCommandMutex.RLock()
+	defer fake.pauseCommandMutex.RUnlock()
+	return len(fake.pauseCommandArgsForCall)
+}
+
+func (fake *FakeRuncBinary) PauseCommandArgsForCall(i int) (string, string) {
+	fake.pauseCommandMutex.RLock()
+	defer fake.pauseCommandMutex.RUnlock()
+	return fake.pauseCommandArgsForCall[i].id, fake.pauseCommandArgsForCall[i].logFile
+}
+
+func (fake *FakeRuncBinary) PauseCommandReturns(result1 *exec.Cmd) {
+	fake.PauseCommandStub = nil
+	fake.pauseCommandReturns = struct {
+		result1 *exec.Cmd
+	}{result1}
+}
+
+func (fake *FakeRuncBinary) ResumeCommand(id string, logFile string) *exec.Cmd {
+	fake.resumeCommandMutex.Lock()
+	fake.resumeCommandArgsForCall = append(fake.resumeCommandArgsForCall, struct {
+		id      string
+		logFile string
+	}{id, logFile})
+	fake.recordInvocation("ResumeCommand", []interface{}{id, logFile})
+	fake.resumeCommandMutex.Unlock()
+	if fake.ResumeCommandStub != nil {
+		return fake.ResumeCommandStub(id, logFile)
+	} else {
+		return fake.resumeCommandReturns.result1
+	}
+}
+
+func (fake *FakeRuncBinary) ResumeCommandCallCount() int {
+	fake.resumeCommandMutex.RLock()
+	defer fake.resumeCommandMutex.RUnlock()
+	return len(fake.resumeCommandArgsForCall)
+}
+
+func (fake *FakeRuncBinary) ResumeCommandArgsForCall(i int) (string, string) {
+	fake.resumeCommandMutex.RLock()
+	defer fake.resumeCommandMutex.RUnlock()
+	return fake.resumeCommandArgsForCall[i].id, fake.resumeCommandArgsForCall[i].logFile
+}
+
+func (fake *FakeRuncBinary) ResumeCommandReturns(result1 *exec.Cmd) {
+	fake.ResumeCommandStub = nil
+	fake.resumeCommandReturns = struct {
+		result1 *exec.Cmd
+	}{result1}
+}
+
+func (fake *FakeRuncBinary) CheckpointCommand(id string, imagePath string, logFile string) *exec.Cmd {
+	fake.checkpointCommandMutex.Lock()
+	fake.checkpointCommandArgsForCall = append(fake.checkpointCommandArgsForCall, struct {
+		id        string
+		imagePath string
+		logFile   string
+	}{id, imagePath, logFile})
+	fake.recordInvocation("CheckpointCommand", []interface{}{id, imagePath, logFile})
+	fake.checkpointCommandMutex.Unlock()
+	if fake.CheckpointCommandStub != nil {
+		return fake.CheckpointCommandStub(id, imagePath, logFile)
+	} else {
+		return fake.checkpointCommandReturns.result1
+	}
+}
+
+func (fake *FakeRuncBinary) CheckpointCommandCallCount() int {
+	fake.checkpointCommandMutex.RLock()
+	defer fake.checkpointCommandMutex.RUnlock()
+	return len(fake.checkpointCommandArgsForCall)
+}
+
+func (fake *FakeRuncBinary) CheckpointCommandArgsForCall(i int) (string, string, string) {
+	fake.checkpointCommandMutex.RLock()
+	defer fake.checkpointCommandMutex.RUnlock()
+	return fake.checkpointCommandArgsForCall[i].id, fake.checkpointCommandArgsForCall[i].imagePath, fake.checkpointCommandArgsForCall[i].logFile
+}
+
+func (fake *FakeRuncBinary) CheckpointCommandReturns(result1 *exec.Cmd) {
+	fake.CheckpointCommandStub = nil
+	fake.checkpointCommandReturns = struct {
+		result1 *exec.Cmd
+	}{result1}
+}
+
+func (fake *FakeRuncBinary) RestoreCommand(id string, bundlePath string, imagePath string, logFile string) *exec.Cmd {
+	fake.restoreCommandMutex.Lock()
+	fake.restoreCommandArgsForCall = append(fake.restoreCommandArgsForCall, struct {
+		id         string
+		bundlePath string
+		imagePath  string
+		logFile    string
+	}{id, bundlePath, imagePath, logFile})
+	fake.recordInvocation("RestoreCommand", []interface{}{id, bundlePath, imagePath, logFile})
+	fake.restoreCommandMutex.Unlock()
+	if fake.RestoreCommandStub != nil {
+		return fake.RestoreCommandStub(id, bundlePath, imagePath, logFile)
+	} else {
+		return fake.restoreCommandReturns.result1
+	}
+}
+
+func (fake *FakeRuncBinary) RestoreCommandCallCount() int {
+	fake.restoreCommandMutex.RLock()
+	defer fake.restoreCommandMutex.RUnlock()
+	return len(fake.restoreCommandArgsForCall)
+}
+
+func (fake *FakeRuncBinary) RestoreCommandArgsForCall(i int) (string, string, string, string) {
+	fake.restoreCommandMutex.RLock()
+	defer fake.restoreCommandMutex.RUnlock()
+	return fake.restoreCommandArgsForCall[i].id, fake.restoreCommandArgsForCall[i].bundlePath, fake.restoreCommandArgsForCall[i].imagePath, fake.restoreCommandArgsForCall[i].logFile
+}
+
+func (fake *FakeRuncBinary) RestoreCommandReturns(result1 *exec.Cmd) {
+	fake.RestoreCommandStub = nil
+	fake.restoreCommandReturns = struct {
+		result1 *exec.Cmd
+	}{result1}
+}
+
+func (fake *FakeRuncBinary) ForceDeleteCommand(id string, logFile string) *exec.Cmd {
+	fake.forceDeleteCommandMutex.Lock()
+	fake.forceDeleteCommandArgsForCall = append(fake.forceDeleteCommandArgsForCall, struct {
+		id      string
+		logFile string
+	}{id, logFile})
+	fake.recordInvocation("ForceDeleteCommand", []interface{}{id, logFile})
+	fake.forceDeleteCommandMutex.Unlock()
+	if fake.ForceDeleteCommandStub != nil {
+		return fake.ForceDeleteCommandStub(id, logFile)
+	} else {
+		return fake.forceDeleteCommandReturns.result1
+	}
+}
+
+func (fake *FakeRuncBinary) ForceDeleteCommandCallCount() int {
+	fake.forceDeleteCommandMutex.RLock()
+	defer fake.forceDeleteCommandMutex.RUnlock()
+	return len(fake.forceDeleteCommandArgsForCall)
+}
+
+func (fake *FakeRuncBinary) ForceDeleteCommandArgsForCall(i int) (string, string) {
+	fake.forceDeleteCommandMutex.RLock()
+	defer fake.forceDeleteCommandMutex.RUnlock()
+	return fake.forceDeleteCommandArgsForCall[i].id, fake.forceDeleteCommandArgsForCall[i].logFile
+}
+
+func (fake *FakeRuncBinary) ForceDeleteCommandReturns(result1 *exec.Cmd) {
+	fake.ForceDeleteCommandStub = nil
+	fake.forceDeleteCommandReturns = struct {
+		result1 *exec.Cmd
+	}{result1}
+}
+
 func (fake *FakeRuncBinary) Invocations() map[string][][]interface{} {
 	fake.invocationsMutex.RLock()
 	defer fake.invocationsMutex.RUnlock()
@@ -288,6 +552,18 @@ func (fake *FakeRuncBinary) Invocations() map[string][][]interface{} {
 	defer fake.killCommandMutex.RUnlock()
 	fake.deleteCommandMutex.RLock()
 	defer fake.deleteCommandMutex.RUnlock()
+	fake.updateCommandMutex.RLock()
+	defer fake.updateCommandMutex.RUnlock()
+	fake.pauseCommandMutex.RLock()
+	defer fake.pauseCommandMutex.RUnlock()
+	fake.resumeCommandMutex.RLock()
+	defer fake.resumeCommandMutex.RUnlock()
+	fake.checkpointCommandMutex.RLock()
+	defer fake.checkpointCommandMutex.RUnlock()
+	fake.restoreCommandMutex.RLock()
+	defer fake.restoreCommandMutex.RUnlock()
+	fake.forceDeleteCommandMutex.RLock()
+	defer fake.forceDeleteCommandMutex.RUnlock()
 	return fake.invocations
 }
 
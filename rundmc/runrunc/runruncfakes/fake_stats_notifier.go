@@ -4,33 +4,31 @@ package runruncfakes
 import (
 	"sync"
 
-	"code.cloudfoundry.org/garden"
+	"code.cloudfoundry.org/guardian/gardener"
 	"code.cloudfoundry.org/guardian/rundmc/runrunc"
 )
 
 type FakeStatsNotifier struct {
-	OnStatStub        func(handle string, cpuStat garden.ContainerCPUStat, memoryStat garden.ContainerMemoryStat)
+	OnStatStub        func(handle string, metrics gardener.ActualContainerMetrics)
 	onStatMutex       sync.RWMutex
 	onStatArgsForCall []struct {
-		handle     string
-		cpuStat    garden.ContainerCPUStat
-		memoryStat garden.ContainerMemoryStat
+		handle  string
+		metrics gardener.ActualContainerMetrics
 	}
 	invocations      map[string][][]interface{}
 	invocationsMutex sync.RWMutex
 }
 
-func (fake *FakeStatsNotifier) OnStat(handle string, cpuStat garden.ContainerCPUStat, memoryStat garden.ContainerMemoryStat) {
+func (fake *FakeStatsNotifier) OnStat(handle string, metrics gardener.ActualContainerMetrics) {
 	fake.onStatMutex.Lock()
 	fake.onStatArgsForCall = append(fake.onStatArgsForCall, struct {
-		handle     string
-		cpuStat    garden.ContainerCPUStat
-		memoryStat garden.ContainerMemoryStat
-	}{handle, cpuStat, memoryStat})
-	fake.recordInvocation("OnStat", []interface{}{handle, cpuStat, memoryStat})
+		handle  string
+		metrics gardener.ActualContainerMetrics
+	}{handle, metrics})
+	fake.recordInvocation("OnStat", []interface{}{handle, metrics})
 	fake.onStatMutex.Unlock()
 	if fake.OnStatStub != nil {
-		fake.OnStatStub(handle, cpuStat, memoryStat)
+		fake.OnStatStub(handle, metrics)
 	}
 }
 
@@ -40,10 +38,10 @@ func (fake *FakeStatsNotifier) OnStatCallCount() int {
 	return len(fake.onStatArgsForCall)
 }
 
-func (fake *FakeStatsNotifier) OnStatArgsForCall(i int) (string, garden.ContainerCPUStat, garden.ContainerMemoryStat) {
+func (fake *FakeStatsNotifier) OnStatArgsForCall(i int) (string, gardener.ActualContainerMetrics) {
 	fake.onStatMutex.RLock()
 	defer fake.onStatMutex.RUnlock()
-	return fake.onStatArgsForCall[i].handle, fake.onStatArgsForCall[i].cpuStat, fake.onStatArgsForCall[i].memoryStat
+	return fake.onStatArgsForCall[i].handle, fake.onStatArgsForCall[i].metrics
 }
 
 func (fake *FakeStatsNotifier) Invocations() map[string][][]interface{} {
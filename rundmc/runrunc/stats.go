@@ -5,6 +5,8 @@ import (
 	"encoding/json"
 	"fmt"
 	"os/exec"
+	"sort"
+	"strings"
 
 	"code.cloudfoundry.org/garden"
 	"code.cloudfoundry.org/guardian/gardener"
@@ -13,22 +15,52 @@ import (
 
 //go:generate counterfeiter . StatsNotifier
 type StatsNotifier interface {
-	OnStat(handle string, cpuStat garden.ContainerCPUStat, memoryStat garden.ContainerMemoryStat)
+	OnStat(handle string, metrics gardener.ActualContainerMetrics)
 }
 
 type runcStats struct {
-	Data struct {
-		CPUStats struct {
-			CPUUsage struct {
-				Usage  uint64 `json:"total"`
-				System uint64 `json:"kernel"`
-				User   uint64 `json:"user"`
-			} `json:"usage"`
-		} `json:"cpu"`
-		MemoryStats struct {
-			Stats garden.ContainerMemoryStat `json:"raw"`
-		} `json:"memory"`
-	}
+	Data runcStatsData
+}
+
+// runcStatsData is the output of `runc stats`, and of each stats event sent
+// by `runc events`
+type runcStatsData struct {
+	CPUStats struct {
+		CPUUsage struct {
+			Usage  uint64 `json:"total"`
+			System uint64 `json:"kernel"`
+			User   uint64 `json:"user"`
+		} `json:"usage"`
+		Throttling struct {
+			Periods          uint64 `json:"periods"`
+			ThrottledPeriods uint64 `json:"throttledPeriods"`
+			ThrottledTime    uint64 `json:"throttledTime"`
+		} `json:"throttling"`
+	} `json:"cpu"`
+	MemoryStats struct {
+		Stats garden.ContainerMemoryStat `json:"raw"`
+	} `json:"memory"`
+	PidsStats struct {
+		Current uint64 `json:"current"`
+		Limit   uint64 `json:"limit"`
+	} `json:"pids"`
+	BlkioStats struct {
+		ServiceBytes []blkioEntry `json:"ioServiceBytesRecursive"`
+		Serviced     []blkioEntry `json:"ioServicedRecursive"`
+	} `json:"blkio"`
+	HugetlbStats map[string]struct {
+		Usage    uint64 `json:"usage"`
+		MaxUsage uint64 `json:"max"`
+		Failcnt  uint64 `json:"failcnt"`
+	} `json:"hugetlb"`
+}
+
+// blkioEntry is one counter for one device, where Op is e.g. Read or Write
+type blkioEntry struct {
+	Major uint64 `json:"major"`
+	Minor uint64 `json:"minor"`
+	Op    string `json:"op"`
+	Value uint64 `json:"value"`
 }
 
 type Statser struct {
@@ -58,16 +90,94 @@ func (r *Statser) Stats(log lager.Logger, id string) (gardener.ActualContainerMe
 		return gardener.ActualContainerMetrics{}, fmt.Errorf("decode stats: %s", err)
 	}
 
+	return data.Data.metrics(), nil
+}
+
+func (data runcStatsData) metrics() gardener.ActualContainerMetrics {
 	stats := gardener.ActualContainerMetrics{
-		Memory: data.Data.MemoryStats.Stats,
+		Memory: data.MemoryStats.Stats,
 		CPU: garden.ContainerCPUStat{
-			Usage:  data.Data.CPUStats.CPUUsage.Usage,
-			System: data.Data.CPUStats.CPUUsage.System,
-			User:   data.Data.CPUStats.CPUUsage.User,
+			Usage:  data.CPUStats.CPUUsage.Usage,
+			System: data.CPUStats.CPUUsage.System,
+			User:   data.CPUStats.CPUUsage.User,
 		},
 	}
 
 	stats.Memory.TotalUsageTowardLimit = stats.Memory.TotalRss + (stats.Memory.TotalCache - stats.Memory.TotalInactiveFile)
 
-	return stats, nil
+	stats.Pid = gardener.ContainerPidStat{
+		Current: data.PidsStats.Current,
+		Max:     data.PidsStats.Limit,
+	}
+
+	stats.Throttling = gardener.ContainerCPUThrottlingStat{
+		Periods:          data.CPUStats.Throttling.Periods,
+		ThrottledPeriods: data.CPUStats.Throttling.ThrottledPeriods,
+		ThrottledTime:    data.CPUStats.Throttling.ThrottledTime,
+	}
+
+	stats.BlockIO = blockIOStats(data.BlkioStats.ServiceBytes, data.BlkioStats.Serviced)
+
+	if len(data.HugetlbStats) > 0 {
+		stats.Hugetlb = make(map[string]gardener.ContainerHugetlbStat, len(data.HugetlbStats))
+		for pageSize, hugetlb := range data.HugetlbStats {
+			stats.Hugetlb[pageSize] = gardener.ContainerHugetlbStat{
+				Usage:    hugetlb.Usage,
+				MaxUsage: hugetlb.MaxUsage,
+				Failcnt:  hugetlb.Failcnt,
+			}
+		}
+	}
+
+	return stats
+}
+
+// blockIOStats combines the byte and operation counters of each device,
+// ordered by device number
+func blockIOStats(serviceBytes, serviced []blkioEntry) []gardener.ContainerBlockIOStat {
+	var stats byDevice
+	devices := map[[2]uint64]int{}
+
+	device := func(entry blkioEntry) *gardener.ContainerBlockIOStat {
+		key := [2]uint64{entry.Major, entry.Minor}
+		if _, ok := devices[key]; !ok {
+			devices[key] = len(stats)
+			stats = append(stats, gardener.ContainerBlockIOStat{Major: entry.Major, Minor: entry.Minor})
+		}
+
+		return &stats[devices[key]]
+	}
+
+	for _, entry := range serviceBytes {
+		switch strings.ToLower(entry.Op) {
+		case "read":
+			device(entry).ReadBytes = entry.Value
+		case "write":
+			device(entry).WriteBytes = entry.Value
+		}
+	}
+
+	for _, entry := range serviced {
+		switch strings.ToLower(entry.Op) {
+		case "read":
+			device(entry).ReadOps = entry.Value
+		case "write":
+			device(entry).WriteOps = entry.Value
+		}
+	}
+
+	sort.Sort(stats)
+	return stats
+}
+
+type byDevice []gardener.ContainerBlockIOStat
+
+func (d byDevice) Len() int      { return len(d) }
+func (d byDevice) Swap(i, j int) { d[i], d[j] = d[j], d[i] }
+func (d byDevice) Less(i, j int) bool {
+	if d[i].Major != d[j].Major {
+		return d[i].Major < d[j].Major
+	}
+
+	return d[i].Minor < d[j].Minor
 }
@@ -10,6 +10,7 @@ import (
 	. "github.com/onsi/gomega"
 
 	"code.cloudfoundry.org/garden"
+	"code.cloudfoundry.org/guardian/gardener"
 	"code.cloudfoundry.org/guardian/rundmc/runrunc"
 	fakes "code.cloudfoundry.org/guardian/rundmc/runrunc/runruncfakes"
 	"github.com/cloudfoundry/gunk/command_runner/fake_command_runner"
@@ -160,6 +161,81 @@ var _ = Describe("Stats", func() {
 
 	})
 
+	Context("when runC reports extended stats", func() {
+		BeforeEach(func() {
+			commandRunner.WhenRunning(fake_command_runner.CommandSpec{
+				Path: "funC-stats",
+			}, func(cmd *exec.Cmd) error {
+				cmd.Stdout.Write([]byte(`{
+					"type": "stats",
+					"data": {
+						"cpu": {
+							"usage": {"total": 1, "kernel": 2, "user": 3},
+							"throttling": {"periods": 100, "throttledPeriods": 7, "throttledTime": 123456}
+						},
+						"memory": {"raw": {}},
+						"pids": {"current": 12, "limit": 1024},
+						"blkio": {
+							"ioServiceBytesRecursive": [
+								{"major": 8, "minor": 16, "op": "Read", "value": 4096},
+								{"major": 8, "minor": 0, "op": "Read", "value": 1024},
+								{"major": 8, "minor": 0, "op": "Write", "value": 2048},
+								{"major": 8, "minor": 0, "op": "Total", "value": 3072}
+							],
+							"ioServicedRecursive": [
+								{"major": 8, "minor": 0, "op": "Read", "value": 3},
+								{"major": 8, "minor": 0, "op": "Write", "value": 4},
+								{"major": 8, "minor": 16, "op": "Read", "value": 1}
+							]
+						},
+						"hugetlb": {
+							"2MB": {"usage": 2097152, "max": 4194304, "failcnt": 1}
+						}
+					}
+				}`))
+
+				return nil
+			})
+		})
+
+		It("parses the pids stats", func() {
+			stats, err := statser.Stats(logger, "some-handle")
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(stats.Pid).To(Equal(gardener.ContainerPidStat{Current: 12, Max: 1024}))
+		})
+
+		It("parses the CPU throttling stats", func() {
+			stats, err := statser.Stats(logger, "some-handle")
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(stats.Throttling).To(Equal(gardener.ContainerCPUThrottlingStat{
+				Periods:          100,
+				ThrottledPeriods: 7,
+				ThrottledTime:    123456,
+			}))
+		})
+
+		It("combines the block IO stats of each device, in device order", func() {
+			stats, err := statser.Stats(logger, "some-handle")
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(stats.BlockIO).To(Equal([]gardener.ContainerBlockIOStat{
+				{Major: 8, Minor: 0, ReadBytes: 1024, WriteBytes: 2048, ReadOps: 3, WriteOps: 4},
+				{Major: 8, Minor: 16, ReadBytes: 4096, ReadOps: 1},
+			}))
+		})
+
+		It("parses the hugetlb stats", func() {
+			stats, err := statser.Stats(logger, "some-handle")
+			Expect(err).NotTo(HaveOccurred())
+
+			Expect(stats.Hugetlb).To(Equal(map[string]gardener.ContainerHugetlbStat{
+				"2MB": {Usage: 2097152, MaxUsage: 4194304, Failcnt: 1},
+			}))
+		})
+	})
+
 	Context("when runC reports invalid JSON", func() {
 		BeforeEach(func() {
 			commandRunner.WhenRunning(fake_command_runner.CommandSpec{
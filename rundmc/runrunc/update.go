@@ -0,0 +1,46 @@
+package runrunc
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os/exec"
+
+	"code.cloudfoundry.org/lager"
+	"github.com/opencontainers/runtime-spec/specs-go"
+)
+
+type Updater struct {
+	runner RuncCmdRunner
+	runc   RuncBinary
+}
+
+func NewUpdater(runner RuncCmdRunner, runc RuncBinary) *Updater {
+	return &Updater{
+		runner: runner,
+		runc:   runc,
+	}
+}
+
+// Update applies the given resources to the cgroups of a running container using 'runc update'
+func (u *Updater) Update(log lager.Logger, handle string, resources specs.Resources) error {
+	log = log.Session("update", lager.Data{"handle": handle})
+
+	log.Info("started")
+	defer log.Info("finished")
+
+	resourcesJSON, err := json.Marshal(resources)
+	if err != nil {
+		return fmt.Errorf("encode resources: %s", err)
+	}
+
+	if err := u.runner.RunAndLog(log, func(logFile string) *exec.Cmd {
+		cmd := u.runc.UpdateCommand(handle, logFile)
+		cmd.Stdin = bytes.NewReader(resourcesJSON)
+		return cmd
+	}); err != nil {
+		return fmt.Errorf("runc update: %s", err)
+	}
+
+	return nil
+}
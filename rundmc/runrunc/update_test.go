@@ -0,0 +1,77 @@
+package runrunc_test
+
+import (
+	"errors"
+	"io/ioutil"
+	"os/exec"
+
+	"code.cloudfoundry.org/guardian/rundmc/runrunc"
+	fakes "code.cloudfoundry.org/guardian/rundmc/runrunc/runruncfakes"
+	"code.cloudfoundry.org/lager"
+	"code.cloudfoundry.org/lager/lagertest"
+	"github.com/cloudfoundry/gunk/command_runner/fake_command_runner"
+	. "github.com/cloudfoundry/gunk/command_runner/fake_command_runner/matchers"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"github.com/opencontainers/runtime-spec/specs-go"
+)
+
+var _ = Describe("Update", func() {
+	var (
+		commandRunner *fake_command_runner.FakeCommandRunner
+		runner        *fakes.FakeRuncCmdRunner
+		runcBinary    *fakes.FakeRuncBinary
+		logger        *lagertest.TestLogger
+
+		updater *runrunc.Updater
+	)
+
+	BeforeEach(func() {
+		runcBinary = new(fakes.FakeRuncBinary)
+		commandRunner = fake_command_runner.New()
+		runner = new(fakes.FakeRuncCmdRunner)
+		logger = lagertest.NewTestLogger("test")
+
+		updater = runrunc.NewUpdater(runner, runcBinary)
+
+		runcBinary.UpdateCommandStub = func(id, logFile string) *exec.Cmd {
+			return exec.Command("funC", "--log", logFile, "update", "-r", "-", id)
+		}
+
+		runner.RunAndLogStub = func(_ lager.Logger, fn runrunc.LoggingCmd) error {
+			return commandRunner.Run(fn("potato.log"))
+		}
+	})
+
+	It("runs 'runc update' with the resources on stdin using the logging runner", func() {
+		var stdin []byte
+		commandRunner.WhenRunning(fake_command_runner.CommandSpec{
+			Path: "funC",
+		}, func(cmd *exec.Cmd) error {
+			var err error
+			stdin, err = ioutil.ReadAll(cmd.Stdin)
+			return err
+		})
+
+		limit := uint64(1024)
+		Expect(updater.Update(logger, "some-container", specs.Resources{
+			Memory: &specs.Memory{Limit: &limit},
+		})).To(Succeed())
+
+		Expect(commandRunner).To(HaveExecutedSerially(fake_command_runner.CommandSpec{
+			Path: "funC",
+			Args: []string{"--log", "potato.log", "update", "-r", "-", "some-container"},
+		}))
+		Expect(stdin).To(MatchJSON(`{"memory": {"limit": 1024}}`))
+	})
+
+	Context("when runc update fails", func() {
+		BeforeEach(func() {
+			runner.RunAndLogReturns(errors.New("boom"))
+		})
+
+		It("returns the error", func() {
+			Expect(updater.Update(logger, "some-container", specs.Resources{})).To(MatchError("runc update: boom"))
+		})
+	})
+})
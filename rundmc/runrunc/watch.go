@@ -5,22 +5,28 @@ import (
 	"fmt"
 	"io"
 
+	"code.cloudfoundry.org/guardian/gardener"
+	"code.cloudfoundry.org/guardian/tracing"
 	"code.cloudfoundry.org/lager"
 	"github.com/cloudfoundry/gunk/command_runner"
 )
 
 //go:generate counterfeiter . EventsNotifier
 type EventsNotifier interface {
-	OnEvent(handle string, event string) error
+	OnEvent(handle string, event gardener.Event) error
 }
 
 type OomWatcher struct {
 	commandRunner command_runner.CommandRunner
 	runc          RuncBinary
+	statsNotifier StatsNotifier
 }
 
-func NewOomWatcher(runner command_runner.CommandRunner, runc RuncBinary) *OomWatcher {
-	return &OomWatcher{runner, runc}
+// NewOomWatcher returns a watcher which reports OOM events to the events
+// notifier passed to WatchEvents and the periodic stats events of runc to
+// statsNotifier, which may be nil
+func NewOomWatcher(runner command_runner.CommandRunner, runc RuncBinary, statsNotifier StatsNotifier) *OomWatcher {
+	return &OomWatcher{runner, runc, statsNotifier}
 }
 
 type runcEvent struct {
@@ -39,6 +45,8 @@ func (r *OomWatcher) WatchEvents(log lager.Logger, handle string, eventsNotifier
 	})
 	log.Info("watching")
 
+	tracing.SetEnv(log, cmd)
+
 	defer func() {
 		stdoutR.Close()
 		log.Info("done")
@@ -70,11 +78,28 @@ func (r *OomWatcher) WatchEvents(log lager.Logger, handle string, eventsNotifier
 		log.Debug("got-event", lager.Data{
 			"type": event.Type,
 		})
-		if event.Type == "oom" {
-			err := eventsNotifier.OnEvent(handle, "Out of memory")
+		switch event.Type {
+		case "oom":
+			err := eventsNotifier.OnEvent(handle, gardener.Event{Type: gardener.EventOutOfMemory})
 			if err != nil {
 				log.Debug("failed-to-notify-oom-event", lager.Data{"event": event.Data})
 			}
+		case "stats":
+			r.notifyStats(log, handle, event.Data)
 		}
 	}
 }
+
+func (r *OomWatcher) notifyStats(log lager.Logger, handle string, data json.RawMessage) {
+	if r.statsNotifier == nil {
+		return
+	}
+
+	var stats runcStatsData
+	if err := json.Unmarshal(data, &stats); err != nil {
+		log.Error("decode-stats-event", err)
+		return
+	}
+
+	r.statsNotifier.OnStat(handle, stats.metrics())
+}
@@ -9,8 +9,11 @@ import (
 	. "github.com/onsi/ginkgo"
 	. "github.com/onsi/gomega"
 
+	"code.cloudfoundry.org/garden"
+	"code.cloudfoundry.org/guardian/gardener"
 	"code.cloudfoundry.org/guardian/rundmc/runrunc"
 	fakes "code.cloudfoundry.org/guardian/rundmc/runrunc/runruncfakes"
+	"code.cloudfoundry.org/guardian/tracing"
 	"github.com/cloudfoundry/gunk/command_runner/fake_command_runner"
 )
 
@@ -19,6 +22,7 @@ var _ = Describe("Watching for Events", func() {
 		commandRunner *fake_command_runner.FakeCommandRunner
 		runcBinary    *fakes.FakeRuncBinary
 		logger        *lagertest.TestLogger
+		statsNotifier *fakes.FakeStatsNotifier
 
 		runner *runrunc.OomWatcher
 	)
@@ -27,8 +31,9 @@ var _ = Describe("Watching for Events", func() {
 		runcBinary = new(fakes.FakeRuncBinary)
 		commandRunner = fake_command_runner.New()
 		logger = lagertest.NewTestLogger("test")
+		statsNotifier = new(fakes.FakeStatsNotifier)
 
-		runner = runrunc.NewOomWatcher(commandRunner, runcBinary)
+		runner = runrunc.NewOomWatcher(commandRunner, runcBinary, statsNotifier)
 
 		runcBinary.EventsCommandStub = func(handle string) *exec.Cmd {
 			return exec.Command("funC-events", "events", handle)
@@ -45,6 +50,15 @@ var _ = Describe("Watching for Events", func() {
 		Expect(runner.WatchEvents(logger, "some-container", nil)).To(MatchError("start: boom"))
 	})
 
+	It("passes the trace id of the logger to runc events", func() {
+		tracedLogger, _ := new(tracing.Tracer).Start(logger, "watch")
+		Expect(runner.WatchEvents(tracedLogger, "some-container", nil)).To(Succeed())
+
+		Expect(commandRunner.StartedCommands()).To(HaveLen(1))
+		cmd := commandRunner.StartedCommands()[0]
+		Expect(tracing.FromEnv(cmd.Env)).To(Equal(tracing.TraceID(tracedLogger)))
+	})
+
 	Context("when runc events succeeds", func() {
 		var (
 			eventsCh chan string
@@ -92,13 +106,13 @@ var _ = Describe("Watching for Events", func() {
 			Eventually(eventsNotifier.OnEventCallCount).Should(Equal(1))
 			handle, event := eventsNotifier.OnEventArgsForCall(0)
 			Expect(handle).To(Equal("some-container"))
-			Expect(event).To(Equal("Out of memory"))
+			Expect(event.Type).To(Equal(gardener.EventOutOfMemory))
 
 			eventsCh <- `{"type":"oom"}`
 			Eventually(eventsNotifier.OnEventCallCount).Should(Equal(2))
 			handle, event = eventsNotifier.OnEventArgsForCall(1)
 			Expect(handle).To(Equal("some-container"))
-			Expect(event).To(Equal("Out of memory"))
+			Expect(event.Type).To(Equal(gardener.EventOutOfMemory))
 		})
 
 		It("does not report non-OOM events", func() {
@@ -110,6 +124,48 @@ var _ = Describe("Watching for Events", func() {
 			Consistently(eventsNotifier.OnEventCallCount).Should(Equal(0))
 		})
 
+		It("reports stats events to the stats notifier", func() {
+			defer close(eventsCh)
+
+			go runner.WatchEvents(logger, "some-container", eventsNotifier)
+
+			eventsCh <- `{"type":"stats","data":{"cpu":{"usage":{"total":10,"kernel":2,"user":8}},"memory":{"raw":{"rss":5}},"pids":{"current":3}}}`
+			Eventually(statsNotifier.OnStatCallCount).Should(Equal(1))
+
+			handle, metrics := statsNotifier.OnStatArgsForCall(0)
+			Expect(handle).To(Equal("some-container"))
+			Expect(metrics.CPU).To(Equal(garden.ContainerCPUStat{Usage: 10, System: 2, User: 8}))
+			Expect(metrics.Memory.Rss).To(BeEquivalentTo(5))
+			Expect(metrics.Pid.Current).To(BeEquivalentTo(3))
+			Expect(eventsNotifier.OnEventCallCount()).To(Equal(0))
+		})
+
+		It("skips stats events which cannot be decoded", func() {
+			defer close(eventsCh)
+
+			go runner.WatchEvents(logger, "some-container", eventsNotifier)
+
+			eventsCh <- `{"type":"stats","data":{"cpu":"potato"}}`
+			eventsCh <- `{"type":"stats","data":{"cpu":{"usage":{"total":10}}}}`
+			Eventually(statsNotifier.OnStatCallCount).Should(Equal(1))
+
+			_, metrics := statsNotifier.OnStatArgsForCall(0)
+			Expect(metrics.CPU.Usage).To(BeEquivalentTo(10))
+		})
+
+		Context("when there is no stats notifier", func() {
+			BeforeEach(func() {
+				runner = runrunc.NewOomWatcher(commandRunner, runcBinary, nil)
+			})
+
+			It("ignores stats events", func() {
+				eventsCh <- `{"type":"stats","data":{"cpu":{"usage":{"total":10}}}}`
+				close(eventsCh)
+
+				Expect(runner.WatchEvents(logger, "some-container", eventsNotifier)).To(Succeed())
+			})
+		})
+
 		It("waits on the process to avoid zombies", func() {
 			close(eventsCh)
 
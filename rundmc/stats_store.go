@@ -0,0 +1,140 @@
+package rundmc
+
+import (
+	"sync"
+	"time"
+
+	"code.cloudfoundry.org/guardian/gardener"
+	"github.com/pivotal-golang/clock"
+)
+
+// DefaultStatsWindow is the number of samples kept for each container. runc
+// sends a stats event every 5 seconds, so this is about a minute of samples.
+const DefaultStatsWindow = 12
+
+// DefaultStatsMaxAge is how old the newest sample may be before it is no
+// longer used in place of asking runc for fresh stats
+const DefaultStatsMaxAge = 15 * time.Second
+
+type StatsSample struct {
+	Time    time.Time
+	Metrics gardener.ActualContainerMetrics
+}
+
+type stats struct {
+	clock  clock.Clock
+	size   int
+	maxAge time.Duration
+
+	// samples has an entry for each tracked container, even before it has
+	// reported any
+	mu      sync.RWMutex
+	samples map[string][]StatsSample
+}
+
+// NewStatsStore returns a store which keeps the most recent size samples of
+// each container, as reported by the runc events watcher
+func NewStatsStore(clock clock.Clock, size int, maxAge time.Duration) *stats {
+	if size < 2 {
+		size = DefaultStatsWindow
+	}
+
+	if maxAge <= 0 {
+		maxAge = DefaultStatsMaxAge
+	}
+
+	return &stats{
+		clock:   clock,
+		size:    size,
+		maxAge:  maxAge,
+		samples: make(map[string][]StatsSample),
+	}
+}
+
+// Track starts keeping the samples of a container, before its stats are
+// watched
+func (s *stats) Track(handle string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, ok := s.samples[handle]; !ok {
+		s.samples[handle] = nil
+	}
+}
+
+// OnStat keeps a sample of a tracked container. Samples of other containers,
+// such as those which arrive after the container has been forgotten, are
+// dropped.
+func (s *stats) OnStat(handle string, metrics gardener.ActualContainerMetrics) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	existing, ok := s.samples[handle]
+	if !ok {
+		return
+	}
+
+	samples := append(existing, StatsSample{Time: s.clock.Now(), Metrics: metrics})
+	if len(samples) > s.size {
+		samples = samples[len(samples)-s.size:]
+	}
+
+	s.samples[handle] = samples
+}
+
+// Latest returns the newest sample of the container with the rate of CPU
+// use since the sample before it, or false if there is no recent sample
+func (s *stats) Latest(handle string) (gardener.ActualContainerMetrics, bool) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	samples := s.samples[handle]
+	if len(samples) == 0 {
+		return gardener.ActualContainerMetrics{}, false
+	}
+
+	latest := samples[len(samples)-1]
+	if s.clock.Now().Sub(latest.Time) > s.maxAge {
+		return gardener.ActualContainerMetrics{}, false
+	}
+
+	metrics := latest.Metrics
+	if len(samples) > 1 {
+		metrics.CPURate = cpuRate(samples[len(samples)-2], latest)
+	}
+
+	return metrics, true
+}
+
+// Samples returns the samples of the container which are in the window,
+// oldest first
+func (s *stats) Samples(handle string) []StatsSample {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	samples := make([]StatsSample, len(s.samples[handle]))
+	copy(samples, s.samples[handle])
+	return samples
+}
+
+func (s *stats) Forget(handle string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	delete(s.samples, handle)
+}
+
+func cpuRate(previous, latest StatsSample) gardener.ContainerCPURateStat {
+	interval := latest.Time.Sub(previous.Time)
+
+	// usage goes backwards if the container was restored from a checkpoint
+	if interval <= 0 || latest.Metrics.CPU.Usage < previous.Metrics.CPU.Usage {
+		return gardener.ContainerCPURateStat{Interval: interval}
+	}
+
+	used := latest.Metrics.CPU.Usage - previous.Metrics.CPU.Usage
+	return gardener.ContainerCPURateStat{
+		Interval: interval,
+		Percent:  float64(used) / float64(interval.Nanoseconds()) * 100,
+	}
+}
@@ -0,0 +1,127 @@
+package rundmc_test
+
+import (
+	"time"
+
+	"code.cloudfoundry.org/garden"
+	"code.cloudfoundry.org/guardian/gardener"
+	"code.cloudfoundry.org/guardian/rundmc"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"github.com/pivotal-golang/clock/fakeclock"
+)
+
+var _ = Describe("Stats Store", func() {
+	var (
+		fakeClock *fakeclock.FakeClock
+		store     interface {
+			OnStat(handle string, metrics gardener.ActualContainerMetrics)
+			Latest(handle string) (gardener.ActualContainerMetrics, bool)
+			Samples(handle string) []rundmc.StatsSample
+			Track(handle string)
+			Forget(handle string)
+		}
+	)
+
+	cpuUsage := func(usage uint64) gardener.ActualContainerMetrics {
+		return gardener.ActualContainerMetrics{CPU: garden.ContainerCPUStat{Usage: usage}}
+	}
+
+	BeforeEach(func() {
+		fakeClock = fakeclock.NewFakeClock(time.Unix(123, 0))
+		store = rundmc.NewStatsStore(fakeClock, 3, 10*time.Second)
+		store.Track("foo")
+		store.Track("bar")
+	})
+
+	It("has no stats for a container which has not reported any", func() {
+		_, ok := store.Latest("foo")
+		Expect(ok).To(BeFalse())
+	})
+
+	It("returns the latest sample without a rate when there is only one", func() {
+		store.OnStat("foo", cpuUsage(100))
+
+		metrics, ok := store.Latest("foo")
+		Expect(ok).To(BeTrue())
+		Expect(metrics.CPU.Usage).To(BeEquivalentTo(100))
+		Expect(metrics.CPURate).To(Equal(gardener.ContainerCPURateStat{}))
+	})
+
+	It("returns the CPU rate over the last interval", func() {
+		store.OnStat("foo", cpuUsage(0))
+		fakeClock.Increment(2 * time.Second)
+		store.OnStat("foo", cpuUsage(uint64(time.Second)))
+		fakeClock.Increment(5 * time.Second)
+		store.OnStat("foo", cpuUsage(uint64(6*time.Second)))
+
+		metrics, ok := store.Latest("foo")
+		Expect(ok).To(BeTrue())
+		Expect(metrics.CPURate.Interval).To(Equal(5 * time.Second))
+		Expect(metrics.CPURate.Percent).To(BeNumerically("~", 100, 0.001))
+	})
+
+	It("reports no CPU use when usage went backwards", func() {
+		store.OnStat("foo", cpuUsage(500))
+		fakeClock.Increment(time.Second)
+		store.OnStat("foo", cpuUsage(100))
+
+		metrics, _ := store.Latest("foo")
+		Expect(metrics.CPURate).To(Equal(gardener.ContainerCPURateStat{Interval: time.Second}))
+	})
+
+	It("keeps only the most recent samples of each container", func() {
+		for i := 0; i < 5; i++ {
+			store.OnStat("foo", cpuUsage(uint64(i)))
+			fakeClock.Increment(time.Second)
+		}
+		store.OnStat("bar", cpuUsage(42))
+
+		samples := store.Samples("foo")
+		Expect(samples).To(HaveLen(3))
+		Expect(samples[0].Metrics.CPU.Usage).To(BeEquivalentTo(2))
+		Expect(samples[0].Time.Equal(time.Unix(125, 0))).To(BeTrue())
+		Expect(samples[2].Metrics.CPU.Usage).To(BeEquivalentTo(4))
+		Expect(store.Samples("bar")).To(HaveLen(1))
+	})
+
+	It("ignores samples which are too old", func() {
+		store.OnStat("foo", cpuUsage(1))
+		fakeClock.Increment(11 * time.Second)
+
+		_, ok := store.Latest("foo")
+		Expect(ok).To(BeFalse())
+	})
+
+	It("forgets the samples of a container", func() {
+		store.OnStat("foo", cpuUsage(1))
+		store.Forget("foo")
+
+		_, ok := store.Latest("foo")
+		Expect(ok).To(BeFalse())
+		Expect(store.Samples("foo")).To(BeEmpty())
+	})
+
+	It("drops samples which arrive after a container has been forgotten", func() {
+		store.Forget("foo")
+		store.OnStat("foo", cpuUsage(1))
+
+		_, ok := store.Latest("foo")
+		Expect(ok).To(BeFalse())
+		Expect(store.Samples("foo")).To(BeEmpty())
+	})
+
+	It("drops samples of containers which are not tracked", func() {
+		store.OnStat("baz", cpuUsage(1))
+
+		_, ok := store.Latest("baz")
+		Expect(ok).To(BeFalse())
+	})
+
+	It("keeps the samples of a container which is tracked again", func() {
+		store.OnStat("foo", cpuUsage(1))
+		store.Track("foo")
+
+		Expect(store.Samples("foo")).To(HaveLen(1))
+	})
+})
@@ -1,8 +1,13 @@
 package rundmc
 
 import (
+	"encoding/json"
 	"strings"
 	"sync"
+
+	"code.cloudfoundry.org/guardian/gardener"
+	"code.cloudfoundry.org/lager"
+	"github.com/pivotal-golang/clock"
 )
 
 //go:generate counterfeiter . Properties
@@ -10,34 +15,191 @@ import (
 type Properties interface {
 	Set(handle string, key string, value string)
 	Get(handle string, key string) (string, bool)
+	HasKeySpace(handle string) bool
 }
 
+// DefaultMaxEvents is the number of events kept for each container; older
+// events are dropped as new ones arrive
+const DefaultMaxEvents = 100
+
+const eventsKey = "rundmc.events"
+
 type events struct {
-	props Properties
-	mu    sync.Mutex
+	log       lager.Logger
+	props     Properties
+	clock     clock.Clock
+	maxEvents int
+
+	mu          sync.Mutex
+	recent      map[string][]gardener.Event
+	subscribers map[int]*subscriber
+	nextID      int
+}
+
+type subscriber struct {
+	id      int
+	handle  string
+	ch      chan gardener.Event
+	dropped uint64
 }
 
-func NewEventStore(props Properties) *events {
+// NewEventStore returns an event log which keeps the most recent maxEvents
+// events of each container in memory. The history does not survive a restart,
+// other than the events which earlier versions kept in the rundmc.events
+// property.
+func NewEventStore(log lager.Logger, props Properties, clock clock.Clock, maxEvents int) *events {
+	if maxEvents <= 0 {
+		maxEvents = DefaultMaxEvents
+	}
+
 	return &events{
-		props: props,
+		log:         log.Session("events"),
+		props:       props,
+		clock:       clock,
+		maxEvents:   maxEvents,
+		recent:      make(map[string][]gardener.Event),
+		subscribers: make(map[int]*subscriber),
 	}
 }
 
-func (e *events) OnEvent(handle, event string) error {
+func (e *events) OnEvent(handle string, event gardener.Event) error {
+	event.Handle = handle
+	if event.Time.IsZero() {
+		event.Time = e.clock.Now()
+	}
+
 	e.mu.Lock()
 	defer e.mu.Unlock()
 
-	events := append(e.Events(handle), event)
-	e.props.Set(handle, "rundmc.events", strings.Join(events, ","))
+	// late events, e.g. from runc events after the container has been
+	// destroyed, must not bring back the history of a destroyed container
+	if event.Type != gardener.EventCreated && !e.props.HasKeySpace(handle) {
+		return nil
+	}
+
+	recent := e.recent[handle]
+	if len(recent) < e.maxEvents {
+		recent = append(recent, event)
+	} else {
+		copy(recent, recent[1:])
+		recent[len(recent)-1] = event
+	}
+	e.recent[handle] = recent
+
+	e.publish(event)
 	return nil
 }
 
-func (e *events) Events(handle string) []string {
-	if value, ok := e.props.Get(handle, "rundmc.events"); ok {
-		return strings.Split(value, ",")
+func (e *events) Events(handle string) []gardener.Event {
+	e.mu.Lock()
+	recent := make([]gardener.Event, len(e.recent[handle]))
+	copy(recent, e.recent[handle])
+	e.mu.Unlock()
+
+	events := append(e.storedEvents(handle), recent...)
+	if len(events) > e.maxEvents {
+		events = events[len(events)-e.maxEvents:]
 	}
 
-	return nil
+	return events
+}
+
+// Forget drops the history of a container which has been destroyed
+func (e *events) Forget(handle string) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	delete(e.recent, handle)
+}
+
+// storedEvents returns the events which earlier versions kept in the
+// rundmc.events property, as JSON or as a comma separated list of
+// descriptions
+func (e *events) storedEvents(handle string) []gardener.Event {
+	value, ok := e.props.Get(handle, eventsKey)
+	if !ok || value == "" {
+		return nil
+	}
+
+	var events []gardener.Event
+	if err := json.Unmarshal([]byte(value), &events); err == nil {
+		return events
+	}
+
+	for _, description := range strings.Split(value, ",") {
+		events = append(events, legacyEvent(handle, description))
+	}
+
+	return events
+}
+
+// Subscribe delivers events of the given container, or of every container if
+// handle is empty. Events are dropped rather than block the container if the
+// subscriber falls behind; drops are counted and logged.
+func (e *events) Subscribe(handle string) (<-chan gardener.Event, func()) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	id := e.nextID
+	e.nextID++
+
+	sub := &subscriber{id: id, handle: handle, ch: make(chan gardener.Event, e.maxEvents)}
+	e.subscribers[id] = sub
+
+	var once sync.Once
+	return sub.ch, func() {
+		once.Do(func() {
+			e.mu.Lock()
+			defer e.mu.Unlock()
+
+			delete(e.subscribers, id)
+			close(sub.ch)
+
+			if sub.dropped > 0 {
+				e.log.Info("unsubscribed-after-dropping-events", lager.Data{"subscriber": id, "handle": handle, "dropped": sub.dropped})
+			}
+		})
+	}
+}
+
+func (e *events) publish(event gardener.Event) {
+	for _, sub := range e.subscribers {
+		if sub.handle != "" && sub.handle != event.Handle {
+			continue
+		}
+
+		select {
+		case sub.ch <- event:
+		default:
+			e.dropped(sub, event)
+		}
+	}
+}
+
+// dropped counts an event which a subscriber was too far behind to receive,
+// logging the first drop and every maxEvents drops after it
+func (e *events) dropped(sub *subscriber, event gardener.Event) {
+	sub.dropped++
+	if sub.dropped == 1 || sub.dropped%uint64(e.maxEvents) == 0 {
+		e.log.Info("subscriber-fell-behind", lager.Data{
+			"subscriber": sub.id,
+			"handle":     event.Handle,
+			"type":       event.Type,
+			"dropped":    sub.dropped,
+		})
+	}
+}
+
+func legacyEvent(handle, description string) gardener.Event {
+	if description == "Out of memory" {
+		return gardener.Event{Handle: handle, Type: gardener.EventOutOfMemory}
+	}
+
+	return gardener.Event{
+		Handle: handle,
+		Type:   "legacy",
+		Data:   map[string]string{"description": description},
+	}
 }
 
 type states struct {
@@ -55,10 +217,26 @@ func (s *states) StoreStopped(handle string) {
 }
 
 func (s *states) IsStopped(handle string) bool {
+	return s.is(handle, "stopped")
+}
+
+func (s *states) StorePaused(handle string) {
+	s.props.Set(handle, "rundmc.state", "paused")
+}
+
+func (s *states) StoreRunning(handle string) {
+	s.props.Set(handle, "rundmc.state", "running")
+}
+
+func (s *states) IsPaused(handle string) bool {
+	return s.is(handle, "paused")
+}
+
+func (s *states) is(handle, state string) bool {
 	value, ok := s.props.Get(handle, "rundmc.state")
 	if !ok {
 		return false
 	}
 
-	return value == "stopped"
+	return value == state
 }
@@ -2,69 +2,211 @@ package rundmc_test
 
 import (
 	"fmt"
+	"time"
 
+	"code.cloudfoundry.org/guardian/gardener"
 	"code.cloudfoundry.org/guardian/rundmc"
 	fakes "code.cloudfoundry.org/guardian/rundmc/rundmcfakes"
+	"code.cloudfoundry.org/lager/lagertest"
 	. "github.com/onsi/ginkgo"
 	. "github.com/onsi/gomega"
+	"github.com/onsi/gomega/gbytes"
+	"github.com/pivotal-golang/clock/fakeclock"
 )
 
 var _ = Describe("Event Store", func() {
 	var (
-		props *fakes.FakeProperties
+		props     *fakes.FakeProperties
+		fakeClock *fakeclock.FakeClock
+		stored    map[string]string
+		logger    *lagertest.TestLogger
+		events    gardener.EventLog
 	)
 
 	BeforeEach(func() {
 		props = new(fakes.FakeProperties)
+		fakeClock = fakeclock.NewFakeClock(time.Unix(123, 0))
+
+		stored = make(map[string]string)
+		props.SetStub = func(handle, key, value string) {
+			stored[handle+"/"+key] = value
+		}
+		props.GetStub = func(handle, key string) (string, bool) {
+			value, ok := stored[handle+"/"+key]
+			return value, ok
+		}
+		props.HasKeySpaceReturns(true)
+
+		logger = lagertest.NewTestLogger("test")
+		events = rundmc.NewEventStore(logger, props, fakeClock, 3)
 	})
 
-	It("stashes events on the property manager under the 'rundmc.events' key", func() {
-		events := rundmc.NewEventStore(props)
-		events.OnEvent("foo", "bar")
+	It("keeps events out of the property manager", func() {
+		Expect(events.OnEvent("foo", gardener.Event{Type: gardener.EventOutOfMemory})).To(Succeed())
 
-		Expect(props.SetCallCount()).To(Equal(1))
+		Expect(props.SetCallCount()).To(Equal(0))
+		Expect(events.Events("foo")).To(HaveLen(1))
+	})
 
-		handle, key, value := props.SetArgsForCall(0)
-		Expect(handle).To(Equal("foo"))
-		Expect(key).To(Equal("rundmc.events"))
-		Expect(value).To(Equal("bar"))
+	It("timestamps and retrieves events in the order they happened", func() {
+		Expect(events.OnEvent("foo", gardener.Event{Type: gardener.EventCreated})).To(Succeed())
+		fakeClock.Increment(time.Second)
+		Expect(events.OnEvent("foo", gardener.Event{
+			Type: gardener.EventProcessExited,
+			Data: map[string]string{"exit_status": "2"},
+		})).To(Succeed())
+
+		recorded := events.Events("foo")
+		Expect(recorded).To(HaveLen(2))
+		Expect(recorded[0].Handle).To(Equal("foo"))
+		Expect(recorded[0].Type).To(Equal(gardener.EventCreated))
+		Expect(recorded[0].Time.Equal(time.Unix(123, 0))).To(BeTrue())
+		Expect(recorded[1].Type).To(Equal(gardener.EventProcessExited))
+		Expect(recorded[1].Time.Equal(time.Unix(124, 0))).To(BeTrue())
+		Expect(recorded[1].Data).To(HaveKeyWithValue("exit_status", "2"))
+	})
+
+	It("keeps only the most recent events of each container", func() {
+		for i := 0; i < 5; i++ {
+			Expect(events.OnEvent("foo", gardener.Event{
+				Type: gardener.EventNetIn,
+				Data: map[string]string{"host_port": fmt.Sprintf("%d", i)},
+			})).To(Succeed())
+		}
+		Expect(events.OnEvent("bar", gardener.Event{Type: gardener.EventCreated})).To(Succeed())
+
+		recorded := events.Events("foo")
+		Expect(recorded).To(HaveLen(3))
+		Expect(recorded[0].Data["host_port"]).To(Equal("2"))
+		Expect(recorded[2].Data["host_port"]).To(Equal("4"))
+		Expect(events.Events("bar")).To(HaveLen(1))
 	})
 
-	It("stashes further events on the same property using a CSV for the value", func() {
-		props.GetReturns("bar", true)
+	It("forgets the events of a container", func() {
+		Expect(events.OnEvent("foo", gardener.Event{Type: gardener.EventCreated})).To(Succeed())
+		Expect(events.OnEvent("bar", gardener.Event{Type: gardener.EventCreated})).To(Succeed())
 
-		events := rundmc.NewEventStore(props)
-		events.OnEvent("foo", "baz")
+		events.Forget("foo")
 
-		Expect(props.SetCallCount()).To(Equal(1))
-		handle, key, value := props.SetArgsForCall(0)
-		Expect(handle).To(Equal("foo"))
-		Expect(key).To(Equal("rundmc.events"))
-		Expect(value).To(Equal("bar,baz"))
+		Expect(events.Events("foo")).To(BeEmpty())
+		Expect(events.Events("bar")).To(HaveLen(1))
 	})
 
-	It("retrieves events from the property manager", func() {
-		props.GetStub = func(handle, key string) (string, bool) {
-			return fmt.Sprintf("%s,%s", handle, key), true
-		}
+	Context("when the container is unknown or has been destroyed", func() {
+		BeforeEach(func() {
+			props.HasKeySpaceReturns(false)
+		})
+
+		It("ignores the event", func() {
+			ch, unsubscribe := events.Subscribe("foo")
+			defer unsubscribe()
+
+			Expect(events.OnEvent("foo", gardener.Event{Type: gardener.EventOutOfMemory})).To(Succeed())
+
+			Expect(events.Events("foo")).To(BeEmpty())
+			Consistently(ch).ShouldNot(Receive())
+		})
 
-		events := rundmc.NewEventStore(props)
-		Expect(events.Events("some-container")).To(Equal([]string{
-			"some-container", "rundmc.events",
-		}))
+		It("still records the creation of a new container", func() {
+			Expect(events.OnEvent("foo", gardener.Event{Type: gardener.EventCreated})).To(Succeed())
+
+			recorded := events.Events("foo")
+			Expect(recorded).To(HaveLen(1))
+			Expect(recorded[0].Type).To(Equal(gardener.EventCreated))
+		})
 	})
 
-	It("returns no events when the property hasn't been set or cant be retrieved", func() {
-		props.GetReturns("bar", false)
+	It("reads events stored as a CSV by earlier versions", func() {
+		stored["foo/rundmc.events"] = "Out of memory,something else"
+
+		recorded := events.Events("foo")
+		Expect(recorded).To(HaveLen(2))
+		Expect(recorded[0].Type).To(Equal(gardener.EventOutOfMemory))
+		Expect(recorded[1].String()).To(Equal("something else"))
+	})
+
+	It("returns the events stored by earlier versions before new events", func() {
+		stored["foo/rundmc.events"] = "Out of memory"
+		Expect(events.OnEvent("foo", gardener.Event{Type: gardener.EventStopped})).To(Succeed())
+
+		recorded := events.Events("foo")
+		Expect(recorded).To(HaveLen(2))
+		Expect(recorded[0].Type).To(Equal(gardener.EventOutOfMemory))
+		Expect(recorded[1].Type).To(Equal(gardener.EventStopped))
+	})
 
-		events := rundmc.NewEventStore(props)
+	It("returns no events when the property hasn't been set", func() {
 		Expect(events.Events("some-container")).To(HaveLen(0))
 	})
 
 	It("returns no events when the property is empty", func() {
-		events := rundmc.NewEventStore(props)
+		stored["some-container/rundmc.events"] = ""
 		Expect(events.Events("some-container")).To(HaveLen(0))
 	})
+
+	Describe("subscribing", func() {
+		It("delivers new events for the subscribed handle only", func() {
+			ch, unsubscribe := events.Subscribe("foo")
+			defer unsubscribe()
+
+			Expect(events.OnEvent("bar", gardener.Event{Type: gardener.EventCreated})).To(Succeed())
+			Expect(events.OnEvent("foo", gardener.Event{Type: gardener.EventStopped})).To(Succeed())
+
+			var event gardener.Event
+			Eventually(ch).Should(Receive(&event))
+			Expect(event.Handle).To(Equal("foo"))
+			Expect(event.Type).To(Equal(gardener.EventStopped))
+			Consistently(ch).ShouldNot(Receive())
+		})
+
+		It("delivers events for every container when no handle is given", func() {
+			ch, unsubscribe := events.Subscribe("")
+			defer unsubscribe()
+
+			Expect(events.OnEvent("bar", gardener.Event{Type: gardener.EventCreated})).To(Succeed())
+			Expect(events.OnEvent("foo", gardener.Event{Type: gardener.EventStopped})).To(Succeed())
+
+			var first, second gardener.Event
+			Eventually(ch).Should(Receive(&first))
+			Eventually(ch).Should(Receive(&second))
+			Expect(first.Handle).To(Equal("bar"))
+			Expect(second.Handle).To(Equal("foo"))
+		})
+
+		It("closes the channel when unsubscribed", func() {
+			ch, unsubscribe := events.Subscribe("foo")
+			unsubscribe()
+			unsubscribe()
+
+			Eventually(ch).Should(BeClosed())
+			Expect(events.OnEvent("foo", gardener.Event{Type: gardener.EventStopped})).To(Succeed())
+		})
+
+		It("drops events rather than blocking when the subscriber falls behind", func() {
+			ch, unsubscribe := events.Subscribe("foo")
+			defer unsubscribe()
+
+			for i := 0; i < 10; i++ {
+				Expect(events.OnEvent("foo", gardener.Event{Type: gardener.EventNetOut})).To(Succeed())
+			}
+
+			Expect(ch).To(HaveLen(3))
+		})
+
+		It("counts and logs the events dropped for a subscriber which falls behind", func() {
+			_, unsubscribe := events.Subscribe("foo")
+
+			for i := 0; i < 10; i++ {
+				Expect(events.OnEvent("foo", gardener.Event{Type: gardener.EventNetOut})).To(Succeed())
+			}
+			Expect(logger).To(gbytes.Say(`subscriber-fell-behind.*"dropped":1`))
+			Expect(logger).To(gbytes.Say(`subscriber-fell-behind.*"dropped":3`))
+			Expect(logger).To(gbytes.Say(`subscriber-fell-behind.*"dropped":6`))
+
+			unsubscribe()
+			Expect(logger).To(gbytes.Say(`unsubscribed-after-dropping-events.*"dropped":7`))
+		})
+	})
 })
 
 var _ = Describe("States Store", func() {
@@ -132,4 +274,66 @@ var _ = Describe("States Store", func() {
 			})
 		})
 	})
+
+	It("stashes the paused state under the 'rundmc.state' key", func() {
+		states := rundmc.NewStateStore(props)
+		states.StorePaused("foo")
+
+		handle, key, value := props.SetArgsForCall(0)
+		Expect(handle).To(Equal("foo"))
+		Expect(key).To(Equal("rundmc.state"))
+		Expect(value).To(Equal("paused"))
+	})
+
+	It("stashes the running state under the 'rundmc.state' key", func() {
+		states := rundmc.NewStateStore(props)
+		states.StoreRunning("foo")
+
+		handle, key, value := props.SetArgsForCall(0)
+		Expect(handle).To(Equal("foo"))
+		Expect(key).To(Equal("rundmc.state"))
+		Expect(value).To(Equal("running"))
+	})
+
+	Describe("IsPaused", func() {
+		var (
+			state map[string]string
+		)
+
+		BeforeEach(func() {
+			state = make(map[string]string)
+
+			props.GetStub = func(handle, key string) (string, bool) {
+				Expect(handle).To(Equal("some-handle"))
+				v, ok := state[key]
+				return v, ok
+			}
+		})
+
+		Context("when the rundmc.state has the value 'paused'", func() {
+			It("returns true", func() {
+				state["rundmc.state"] = "paused"
+
+				states := rundmc.NewStateStore(props)
+				Expect(states.IsPaused("some-handle")).To(BeTrue())
+				Expect(states.IsStopped("some-handle")).To(BeFalse())
+			})
+		})
+
+		Context("when the container has been resumed", func() {
+			It("returns false", func() {
+				state["rundmc.state"] = "running"
+
+				states := rundmc.NewStateStore(props)
+				Expect(states.IsPaused("some-handle")).To(BeFalse())
+			})
+		})
+
+		Context("when the rundmc.state has no value", func() {
+			It("returns false", func() {
+				states := rundmc.NewStateStore(props)
+				Expect(states.IsPaused("some-handle")).To(BeFalse())
+			})
+		})
+	})
 })
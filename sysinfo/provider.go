@@ -1,6 +1,10 @@
 package sysinfo
 
-import "github.com/cloudfoundry/gosigar"
+import (
+	"runtime"
+
+	"github.com/cloudfoundry/gosigar"
+)
 
 type Provider struct {
 	depotPath string
@@ -34,6 +38,10 @@ func (provider Provider) TotalDisk() (uint64, error) {
 	return fromKBytesToBytes(disk.Total), nil
 }
 
+func (provider Provider) CPUCores() (int, error) {
+	return runtime.NumCPU(), nil
+}
+
 func fromKBytesToBytes(kbytes uint64) uint64 {
 	return kbytes * 1024
 }
@@ -35,4 +35,17 @@ var _ = Describe("SystemInfo", func() {
 			Expect(totalDisk).To(BeNumerically(">", 0))
 		})
 	})
+
+	Describe("CPUCores", func() {
+		BeforeEach(func() {
+			provider = sysinfo.NewProvider("/")
+		})
+
+		It("provides a nonzero number of cores", func() {
+			cores, err := provider.CPUCores()
+			Expect(err).ToNot(HaveOccurred())
+
+			Expect(cores).To(BeNumerically(">", 0))
+		})
+	})
 })
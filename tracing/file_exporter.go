@@ -0,0 +1,39 @@
+package tracing
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"sync"
+)
+
+// FileExporter appends each span to a file as a line of JSON
+type FileExporter struct {
+	mu      sync.Mutex
+	file    io.WriteCloser
+	encoder *json.Encoder
+}
+
+func NewFileExporter(path string) (*FileExporter, error) {
+	file, err := os.OpenFile(path, os.O_WRONLY|os.O_CREATE|os.O_APPEND, 0600)
+	if err != nil {
+		return nil, fmt.Errorf("open trace file: %s", err)
+	}
+
+	return &FileExporter{file: file, encoder: json.NewEncoder(file)}, nil
+}
+
+func (e *FileExporter) Export(span Span) error {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	return e.encoder.Encode(span)
+}
+
+func (e *FileExporter) Close() error {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	return e.file.Close()
+}
@@ -0,0 +1,73 @@
+package tracing_test
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"code.cloudfoundry.org/guardian/tracing"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("FileExporter", func() {
+	var (
+		tmpDir string
+		path   string
+	)
+
+	BeforeEach(func() {
+		var err error
+		tmpDir, err = ioutil.TempDir("", "traces")
+		Expect(err).NotTo(HaveOccurred())
+		path = filepath.Join(tmpDir, "traces.json")
+	})
+
+	AfterEach(func() {
+		Expect(os.RemoveAll(tmpDir)).To(Succeed())
+	})
+
+	It("appends each span to the file as a line of JSON", func() {
+		exporter, err := tracing.NewFileExporter(path)
+		Expect(err).NotTo(HaveOccurred())
+
+		Expect(exporter.Export(tracing.Span{TraceID: "abc", SpanID: "1", Name: "create"})).To(Succeed())
+		Expect(exporter.Export(tracing.Span{TraceID: "abc", SpanID: "2", ParentSpanID: "1", Name: "network", Error: "boom"})).To(Succeed())
+		Expect(exporter.Close()).To(Succeed())
+
+		contents, err := ioutil.ReadFile(path)
+		Expect(err).NotTo(HaveOccurred())
+
+		lines := strings.Split(strings.TrimSpace(string(contents)), "\n")
+		Expect(lines).To(HaveLen(2))
+
+		var span tracing.Span
+		Expect(json.Unmarshal([]byte(lines[1]), &span)).To(Succeed())
+		Expect(span.Name).To(Equal("network"))
+		Expect(span.ParentSpanID).To(Equal("1"))
+		Expect(span.Error).To(Equal("boom"))
+	})
+
+	It("keeps the spans already in the file", func() {
+		Expect(ioutil.WriteFile(path, []byte("{}\n"), 0600)).To(Succeed())
+
+		exporter, err := tracing.NewFileExporter(path)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(exporter.Export(tracing.Span{Name: "create"})).To(Succeed())
+		Expect(exporter.Close()).To(Succeed())
+
+		contents, err := ioutil.ReadFile(path)
+		Expect(err).NotTo(HaveOccurred())
+		Expect(strings.Count(string(contents), "\n")).To(Equal(2))
+	})
+
+	Context("when the file cannot be opened", func() {
+		It("returns an error", func() {
+			_, err := tracing.NewFileExporter(filepath.Join(tmpDir, "missing", "traces.json"))
+			Expect(err).To(MatchError(ContainSubstring("open trace file")))
+		})
+	})
+})
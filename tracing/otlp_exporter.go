@@ -0,0 +1,236 @@
+package tracing
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"sort"
+	"strconv"
+	"sync"
+	"time"
+
+	"code.cloudfoundry.org/lager"
+	"github.com/pivotal-golang/clock"
+)
+
+// DefaultOTLPMaxQueued is how many spans the OTLP exporter holds between
+// sends before it drops new ones
+const DefaultOTLPMaxQueued = 4096
+
+const (
+	otlpSpanKindInternal = 1
+	otlpStatusError      = 2
+)
+
+var ErrQueueFull = errors.New("span queue is full")
+
+// OTLPExporter sends the spans it is given to an OTLP/HTTP collector, such as
+// http://localhost:4318/v1/traces, in batches every Interval
+type OTLPExporter struct {
+	Endpoint  string
+	Interval  time.Duration
+	MaxQueued int
+	Logger    lager.Logger
+	Clock     clock.Clock
+	Client    *http.Client
+
+	mu      sync.Mutex
+	queued  []Span
+	stopped chan struct{}
+	done    chan struct{}
+}
+
+func NewOTLPExporter(logger lager.Logger, endpoint string, interval time.Duration, clock clock.Clock) *OTLPExporter {
+	return &OTLPExporter{
+		Endpoint:  endpoint,
+		Interval:  interval,
+		MaxQueued: DefaultOTLPMaxQueued,
+		Logger:    logger,
+		Clock:     clock,
+		Client:    &http.Client{Timeout: 10 * time.Second},
+
+		stopped: make(chan struct{}),
+	}
+}
+
+// Export queues the span to be sent with the next batch
+func (e *OTLPExporter) Export(span Span) error {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	if len(e.queued) >= e.MaxQueued {
+		return ErrQueueFull
+	}
+
+	e.queued = append(e.queued, span)
+	return nil
+}
+
+func (e *OTLPExporter) Start() {
+	logger := e.Logger.Session("otlp-exporter", lager.Data{"endpoint": e.Endpoint, "interval": e.Interval.String()})
+	logger.Info("starting")
+	ticker := e.Clock.NewTicker(e.Interval)
+	e.done = make(chan struct{})
+
+	go func() {
+		defer close(e.done)
+		defer ticker.Stop()
+
+		logger.Info("started")
+		defer logger.Info("finished")
+
+		for {
+			select {
+			case <-ticker.C():
+				e.flush(logger)
+			case <-e.stopped:
+				e.flush(logger)
+				return
+			}
+		}
+	}()
+}
+
+// Stop sends the spans which are still queued and stops sending, returning
+// once they have been sent
+func (e *OTLPExporter) Stop() {
+	close(e.stopped)
+
+	if e.done != nil {
+		<-e.done
+	}
+}
+
+func (e *OTLPExporter) flush(logger lager.Logger) {
+	e.mu.Lock()
+	spans := e.queued
+	e.queued = nil
+	e.mu.Unlock()
+
+	if len(spans) == 0 {
+		return
+	}
+
+	if err := e.send(spans); err != nil {
+		logger.Error("send-failed", err, lager.Data{"spans": len(spans)})
+	}
+}
+
+func (e *OTLPExporter) send(spans []Span) error {
+	body, err := json.Marshal(otlpRequest(spans))
+	if err != nil {
+		return err
+	}
+
+	resp, err := e.Client.Post(e.Endpoint, "application/json", bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return fmt.Errorf("collector returned status %d", resp.StatusCode)
+	}
+
+	return nil
+}
+
+// The OTLP/HTTP JSON encoding of an ExportTraceServiceRequest
+type otlpTraces struct {
+	ResourceSpans []otlpResourceSpans `json:"resourceSpans"`
+}
+
+type otlpResourceSpans struct {
+	Resource   otlpResource     `json:"resource"`
+	ScopeSpans []otlpScopeSpans `json:"scopeSpans"`
+}
+
+type otlpResource struct {
+	Attributes []otlpAttribute `json:"attributes"`
+}
+
+type otlpScopeSpans struct {
+	Scope otlpScope  `json:"scope"`
+	Spans []otlpSpan `json:"spans"`
+}
+
+type otlpScope struct {
+	Name string `json:"name"`
+}
+
+type otlpSpan struct {
+	TraceID           string          `json:"traceId"`
+	SpanID            string          `json:"spanId"`
+	ParentSpanID      string          `json:"parentSpanId,omitempty"`
+	Name              string          `json:"name"`
+	Kind              int             `json:"kind"`
+	StartTimeUnixNano string          `json:"startTimeUnixNano"`
+	EndTimeUnixNano   string          `json:"endTimeUnixNano"`
+	Attributes        []otlpAttribute `json:"attributes,omitempty"`
+	Status            otlpStatus      `json:"status"`
+}
+
+type otlpAttribute struct {
+	Key   string    `json:"key"`
+	Value otlpValue `json:"value"`
+}
+
+type otlpValue struct {
+	StringValue string `json:"stringValue"`
+}
+
+type otlpStatus struct {
+	Code    int    `json:"code,omitempty"`
+	Message string `json:"message,omitempty"`
+}
+
+func otlpRequest(spans []Span) otlpTraces {
+	otlpSpans := make([]otlpSpan, 0, len(spans))
+	for _, span := range spans {
+		s := otlpSpan{
+			TraceID:           span.TraceID,
+			SpanID:            span.SpanID,
+			ParentSpanID:      span.ParentSpanID,
+			Name:              span.Name,
+			Kind:              otlpSpanKindInternal,
+			StartTimeUnixNano: strconv.FormatInt(span.Start.UnixNano(), 10),
+			EndTimeUnixNano:   strconv.FormatInt(span.End.UnixNano(), 10),
+			Attributes:        otlpAttributes(span.Attributes),
+		}
+
+		if span.Error != "" {
+			s.Status = otlpStatus{Code: otlpStatusError, Message: span.Error}
+		}
+
+		otlpSpans = append(otlpSpans, s)
+	}
+
+	return otlpTraces{
+		ResourceSpans: []otlpResourceSpans{{
+			Resource: otlpResource{
+				Attributes: otlpAttributes(map[string]string{"service.name": "guardian"}),
+			},
+			ScopeSpans: []otlpScopeSpans{{
+				Scope: otlpScope{Name: "code.cloudfoundry.org/guardian"},
+				Spans: otlpSpans,
+			}},
+		}},
+	}
+}
+
+func otlpAttributes(attributes map[string]string) []otlpAttribute {
+	keys := make([]string, 0, len(attributes))
+	for key := range attributes {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	otlpAttrs := make([]otlpAttribute, 0, len(keys))
+	for _, key := range keys {
+		otlpAttrs = append(otlpAttrs, otlpAttribute{Key: key, Value: otlpValue{StringValue: attributes[key]}})
+	}
+
+	return otlpAttrs
+}
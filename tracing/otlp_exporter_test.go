@@ -0,0 +1,132 @@
+package tracing_test
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"time"
+
+	"code.cloudfoundry.org/guardian/tracing"
+	"code.cloudfoundry.org/lager/lagertest"
+	"github.com/pivotal-golang/clock/fakeclock"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+)
+
+var _ = Describe("OTLPExporter", func() {
+	type request struct {
+		contentType string
+		body        map[string]interface{}
+	}
+
+	var (
+		server     *httptest.Server
+		requests   chan request
+		statusCode int
+		fakeClock  *fakeclock.FakeClock
+		exporter   *tracing.OTLPExporter
+	)
+
+	BeforeEach(func() {
+		requests = make(chan request, 10)
+		statusCode = http.StatusOK
+		server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			body, err := ioutil.ReadAll(r.Body)
+			Expect(err).NotTo(HaveOccurred())
+
+			var decoded map[string]interface{}
+			Expect(json.Unmarshal(body, &decoded)).To(Succeed())
+			requests <- request{contentType: r.Header.Get("Content-Type"), body: decoded}
+
+			w.WriteHeader(statusCode)
+		}))
+
+		fakeClock = fakeclock.NewFakeClock(time.Unix(123, 0))
+		exporter = tracing.NewOTLPExporter(lagertest.NewTestLogger("test"), server.URL+"/v1/traces", time.Second, fakeClock)
+	})
+
+	AfterEach(func() {
+		server.Close()
+	})
+
+	spansOf := func(req request) []interface{} {
+		resourceSpans := req.body["resourceSpans"].([]interface{})
+		scopeSpans := resourceSpans[0].(map[string]interface{})["scopeSpans"].([]interface{})
+		return scopeSpans[0].(map[string]interface{})["spans"].([]interface{})
+	}
+
+	It("sends the queued spans to the collector every interval", func() {
+		exporter.Start()
+		defer exporter.Stop()
+
+		Expect(exporter.Export(tracing.Span{
+			TraceID:    "0af7651916cd43dd8448eb211c80319c",
+			SpanID:     "b7ad6b7169203331",
+			Name:       "create",
+			Start:      time.Unix(1, 0),
+			End:        time.Unix(2, 0),
+			Error:      "boom",
+			Attributes: map[string]string{"handle": "banana"},
+		})).To(Succeed())
+		Expect(exporter.Export(tracing.Span{Name: "network", ParentSpanID: "b7ad6b7169203331"})).To(Succeed())
+		Consistently(requests).ShouldNot(Receive())
+
+		fakeClock.Increment(time.Second)
+
+		var req request
+		Eventually(requests).Should(Receive(&req))
+		Expect(req.contentType).To(Equal("application/json"))
+
+		spans := spansOf(req)
+		Expect(spans).To(HaveLen(2))
+		Expect(spans[0]).To(Equal(map[string]interface{}{
+			"traceId":           "0af7651916cd43dd8448eb211c80319c",
+			"spanId":            "b7ad6b7169203331",
+			"name":              "create",
+			"kind":              float64(1),
+			"startTimeUnixNano": "1000000000",
+			"endTimeUnixNano":   "2000000000",
+			"attributes": []interface{}{
+				map[string]interface{}{"key": "handle", "value": map[string]interface{}{"stringValue": "banana"}},
+			},
+			"status": map[string]interface{}{"code": float64(2), "message": "boom"},
+		}))
+		Expect(spans[1].(map[string]interface{})["parentSpanId"]).To(Equal("b7ad6b7169203331"))
+	})
+
+	It("does not send anything when there are no spans", func() {
+		exporter.Start()
+		defer exporter.Stop()
+
+		fakeClock.Increment(time.Second)
+		Consistently(requests).ShouldNot(Receive())
+	})
+
+	It("sends the spans which are still queued before it stops", func() {
+		exporter.Start()
+		Expect(exporter.Export(tracing.Span{Name: "create"})).To(Succeed())
+		exporter.Stop()
+
+		Expect(requests).To(Receive())
+	})
+
+	It("stops straight away when it was never started", func() {
+		done := make(chan struct{})
+		go func() {
+			exporter.Stop()
+			close(done)
+		}()
+
+		Eventually(done).Should(BeClosed())
+	})
+
+	Context("when the queue is full", func() {
+		It("drops new spans", func() {
+			exporter.MaxQueued = 1
+			Expect(exporter.Export(tracing.Span{Name: "create"})).To(Succeed())
+			Expect(exporter.Export(tracing.Span{Name: "destroy"})).To(MatchError(tracing.ErrQueueFull))
+		})
+	})
+})
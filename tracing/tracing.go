@@ -0,0 +1,214 @@
+package tracing
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	mathrand "math/rand"
+	"os"
+	"os/exec"
+	"strings"
+	"sync"
+	"time"
+
+	"code.cloudfoundry.org/lager"
+)
+
+//go:generate counterfeiter . Exporter
+
+const (
+	// TraceIDKey is the lager data key under which the trace id is logged
+	TraceIDKey = "trace-id"
+
+	// EnvVar is the environment variable through which the trace id is
+	// passed to the binaries run on behalf of a traced operation
+	EnvVar = "GARDEN_TRACE_ID"
+)
+
+// Span is one timed piece of work within a trace
+type Span struct {
+	TraceID      string            `json:"trace_id"`
+	SpanID       string            `json:"span_id"`
+	ParentSpanID string            `json:"parent_span_id,omitempty"`
+	Name         string            `json:"name"`
+	Start        time.Time         `json:"start"`
+	End          time.Time         `json:"end"`
+	Error        string            `json:"error,omitempty"`
+	Attributes   map[string]string `json:"attributes,omitempty"`
+}
+
+// Exporter sends finished spans somewhere they can be looked at
+type Exporter interface {
+	Export(span Span) error
+}
+
+// Tracer starts a trace for each API call. A nil Tracer, or one without an
+// Exporter, still creates trace ids so that they can be logged.
+type Tracer struct {
+	Exporter Exporter
+}
+
+// Start starts a trace, returning a session of log which carries the trace
+// id in every line it logs, and the root span of the trace
+func (t *Tracer) Start(log lager.Logger, name string, data ...lager.Data) (lager.Logger, *ActiveSpan) {
+	traceID := newID(16)
+
+	sessionData := lager.Data{TraceIDKey: traceID}
+	for _, d := range data {
+		for key, value := range d {
+			sessionData[key] = value
+		}
+	}
+
+	var exporter Exporter
+	if t != nil {
+		exporter = t.Exporter
+	}
+
+	span := &ActiveSpan{
+		span:     newSpan(traceID, "", name, sessionData),
+		exporter: exporter,
+	}
+
+	traced := &tracedLogger{Logger: log.Session(name, sessionData), span: span}
+	span.log = traced
+	return traced, span
+}
+
+// StartSpan starts a child of the span log is traced under. If log is not
+// traced it is returned unchanged, along with a nil span, which can still be
+// ended.
+func StartSpan(log lager.Logger, name string) (lager.Logger, *ActiveSpan) {
+	traced, ok := log.(*tracedLogger)
+	if !ok {
+		return log, nil
+	}
+
+	span := &ActiveSpan{
+		span:     newSpan(traced.span.span.TraceID, traced.span.span.SpanID, name, nil),
+		exporter: traced.span.exporter,
+	}
+
+	child := &tracedLogger{Logger: traced.Logger, span: span}
+	span.log = child
+	return child, span
+}
+
+// TraceID returns the id of the trace log is traced under, if any
+func TraceID(log lager.Logger) string {
+	if traced, ok := log.(*tracedLogger); ok {
+		return traced.span.span.TraceID
+	}
+
+	return ""
+}
+
+// SetEnv passes the trace id of log to cmd through EnvVar. An empty
+// cmd.Env is taken to mean the environment of this process.
+func SetEnv(log lager.Logger, cmd *exec.Cmd) {
+	traceID := TraceID(log)
+	if traceID == "" {
+		return
+	}
+
+	if cmd.Env == nil {
+		cmd.Env = os.Environ()
+	}
+
+	cmd.Env = append(cmd.Env, fmt.Sprintf("%s=%s", EnvVar, traceID))
+}
+
+// FromEnv returns the trace id passed in env by SetEnv, if any
+func FromEnv(env []string) string {
+	prefix := EnvVar + "="
+	for i := len(env) - 1; i >= 0; i-- {
+		if strings.HasPrefix(env[i], prefix) {
+			return strings.TrimPrefix(env[i], prefix)
+		}
+	}
+
+	return ""
+}
+
+// ActiveSpan is a span which has not yet ended
+type ActiveSpan struct {
+	span     Span
+	exporter Exporter
+	log      lager.Logger
+}
+
+// End ends the span with the outcome of its work and exports it. It is safe
+// to call on a nil span.
+func (s *ActiveSpan) End(err error) {
+	if s == nil {
+		return
+	}
+
+	s.span.End = time.Now()
+	if err != nil {
+		s.span.Error = err.Error()
+	}
+
+	if s.exporter == nil {
+		return
+	}
+
+	if err := s.exporter.Export(s.span); err != nil {
+		s.log.Error("export-span-failed", err, lager.Data{"span": s.span.Name})
+	}
+}
+
+// tracedLogger is a lager.Logger whose sessions stay under the same span
+type tracedLogger struct {
+	lager.Logger
+	span *ActiveSpan
+}
+
+func (l *tracedLogger) Session(task string, data ...lager.Data) lager.Logger {
+	return &tracedLogger{Logger: l.Logger.Session(task, data...), span: l.span}
+}
+
+func newSpan(traceID, parentSpanID, name string, data lager.Data) Span {
+	span := Span{
+		TraceID:      traceID,
+		SpanID:       newID(8),
+		ParentSpanID: parentSpanID,
+		Name:         name,
+		Start:        time.Now(),
+	}
+
+	for key, value := range data {
+		if key == TraceIDKey {
+			continue
+		}
+
+		if span.Attributes == nil {
+			span.Attributes = make(map[string]string)
+		}
+		span.Attributes[key] = fmt.Sprintf("%v", value)
+	}
+
+	return span
+}
+
+var (
+	fallbackIDsMu sync.Mutex
+	fallbackIDs   = mathrand.New(mathrand.NewSource(time.Now().UnixNano()))
+)
+
+// newID returns a random id of size bytes. Ids only need to be unique rather
+// than unguessable, so if the system runs out of randomness they are made
+// from a pseudo-random source instead of failing the call being traced.
+func newID(size int) string {
+	id := make([]byte, size)
+	if _, err := rand.Read(id); err != nil {
+		fallbackIDsMu.Lock()
+		defer fallbackIDsMu.Unlock()
+
+		for i := range id {
+			id[i] = byte(fallbackIDs.Intn(256))
+		}
+	}
+
+	return hex.EncodeToString(id)
+}
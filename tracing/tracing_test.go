@@ -0,0 +1,143 @@
+package tracing_test
+
+import (
+	"errors"
+	"os/exec"
+
+	"code.cloudfoundry.org/guardian/tracing"
+	"code.cloudfoundry.org/guardian/tracing/tracingfakes"
+	"code.cloudfoundry.org/lager"
+	"code.cloudfoundry.org/lager/lagertest"
+
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	"github.com/onsi/gomega/gbytes"
+)
+
+var _ = Describe("Tracer", func() {
+	var (
+		logger   *lagertest.TestLogger
+		exporter *tracingfakes.FakeExporter
+		tracer   *tracing.Tracer
+	)
+
+	BeforeEach(func() {
+		logger = lagertest.NewTestLogger("test")
+		exporter = new(tracingfakes.FakeExporter)
+		tracer = &tracing.Tracer{Exporter: exporter}
+	})
+
+	It("logs the trace id in every line of the session and its sub-sessions", func() {
+		log, _ := tracer.Start(logger, "create", lager.Data{"handle": "banana"})
+		traceID := tracing.TraceID(log)
+		Expect(traceID).To(MatchRegexp("^[0-9a-f]{32}$"))
+
+		log.Info("started")
+		log.Session("network").Session("iptables").Info("ran")
+
+		Expect(logger.LogMessages()).To(Equal([]string{"test.create.started", "test.create.network.iptables.ran"}))
+		for _, log := range logger.Logs() {
+			Expect(log.Data).To(HaveKeyWithValue(tracing.TraceIDKey, traceID))
+			Expect(log.Data).To(HaveKeyWithValue("handle", "banana"))
+		}
+	})
+
+	It("creates a new trace id for each trace", func() {
+		first, _ := tracer.Start(logger, "create")
+		second, _ := tracer.Start(logger, "create")
+		Expect(tracing.TraceID(first)).NotTo(Equal(tracing.TraceID(second)))
+	})
+
+	It("exports the root span when it ends", func() {
+		log, span := tracer.Start(logger, "create", lager.Data{"handle": "banana"})
+		Expect(exporter.ExportCallCount()).To(Equal(0))
+
+		span.End(errors.New("boom"))
+		Expect(exporter.ExportCallCount()).To(Equal(1))
+
+		exported := exporter.ExportArgsForCall(0)
+		Expect(exported.TraceID).To(Equal(tracing.TraceID(log)))
+		Expect(exported.SpanID).To(MatchRegexp("^[0-9a-f]{16}$"))
+		Expect(exported.ParentSpanID).To(BeEmpty())
+		Expect(exported.Name).To(Equal("create"))
+		Expect(exported.Error).To(Equal("boom"))
+		Expect(exported.Attributes).To(Equal(map[string]string{"handle": "banana"}))
+		Expect(exported.End).To(BeTemporally(">=", exported.Start))
+	})
+
+	It("exports child spans under their parent", func() {
+		log, root := tracer.Start(logger, "create")
+		childLog, child := tracing.StartSpan(log.Session("containerizer"), "runc-create")
+		Expect(tracing.TraceID(childLog)).To(Equal(tracing.TraceID(log)))
+
+		child.End(nil)
+		root.End(nil)
+
+		exportedChild := exporter.ExportArgsForCall(0)
+		exportedRoot := exporter.ExportArgsForCall(1)
+		Expect(exportedChild.Name).To(Equal("runc-create"))
+		Expect(exportedChild.TraceID).To(Equal(exportedRoot.TraceID))
+		Expect(exportedChild.ParentSpanID).To(Equal(exportedRoot.SpanID))
+		Expect(exportedChild.Error).To(BeEmpty())
+	})
+
+	Context("when the span cannot be exported", func() {
+		It("logs the failure", func() {
+			exporter.ExportReturns(errors.New("disk full"))
+
+			_, span := tracer.Start(logger, "create")
+			span.End(nil)
+
+			Expect(logger).To(gbytes.Say("export-span-failed"))
+		})
+	})
+
+	Context("when the tracer is nil", func() {
+		It("still logs a trace id", func() {
+			var nilTracer *tracing.Tracer
+			log, span := nilTracer.Start(logger, "create")
+			span.End(nil)
+
+			Expect(tracing.TraceID(log)).NotTo(BeEmpty())
+		})
+	})
+
+	Describe("StartSpan", func() {
+		Context("when the logger is not traced", func() {
+			It("returns the logger and a span which can be ended", func() {
+				log, span := tracing.StartSpan(logger, "runc-create")
+				Expect(log).To(Equal(logger))
+				Expect(span).To(BeNil())
+				span.End(nil)
+			})
+		})
+	})
+
+	Describe("passing the trace id to commands", func() {
+		It("adds it to the environment of the command", func() {
+			log, _ := tracer.Start(logger, "create")
+			cmd := exec.Command("iptables")
+			cmd.Env = []string{"PATH=/bin"}
+
+			tracing.SetEnv(log, cmd)
+			Expect(cmd.Env).To(Equal([]string{"PATH=/bin", tracing.EnvVar + "=" + tracing.TraceID(log)}))
+			Expect(tracing.FromEnv(cmd.Env)).To(Equal(tracing.TraceID(log)))
+		})
+
+		It("keeps the inherited environment", func() {
+			log, _ := tracer.Start(logger, "create")
+			cmd := exec.Command("iptables")
+
+			tracing.SetEnv(log, cmd)
+			Expect(len(cmd.Env)).To(BeNumerically(">", 1))
+			Expect(tracing.FromEnv(cmd.Env)).To(Equal(tracing.TraceID(log)))
+		})
+
+		It("leaves the command alone when the logger is not traced", func() {
+			cmd := exec.Command("iptables")
+			tracing.SetEnv(logger, cmd)
+			Expect(cmd.Env).To(BeNil())
+			Expect(tracing.FromEnv(cmd.Env)).To(BeEmpty())
+		})
+	})
+})
@@ -0,0 +1,75 @@
+// This file was generated by counterfeiter
+package tracingfakes
+
+import (
+	"code.cloudfoundry.org/guardian/tracing"
+	"sync"
+)
+
+type FakeExporter struct {
+	ExportStub        func(span tracing.Span) error
+	exportMutex       sync.RWMutex
+	exportArgsForCall []struct {
+		span tracing.Span
+	}
+	exportReturns struct {
+		result1 error
+	}
+	invocations      map[string][][]interface{}
+	invocationsMutex sync.RWMutex
+}
+
+func (fake *FakeExporter) Export(span tracing.Span) error {
+	fake.exportMutex.Lock()
+	fake.exportArgsForCall = append(fake.exportArgsForCall, struct {
+		span tracing.Span
+	}{span})
+	fake.recordInvocation("Export", []interface{}{span})
+	fake.exportMutex.Unlock()
+	if fake.ExportStub != nil {
+		return fake.ExportStub(span)
+	} else {
+		return fake.exportReturns.result1
+	}
+}
+
+func (fake *FakeExporter) ExportCallCount() int {
+	fake.exportMutex.RLock()
+	defer fake.exportMutex.RUnlock()
+	return len(fake.exportArgsForCall)
+}
+
+func (fake *FakeExporter) ExportArgsForCall(i int) tracing.Span {
+	fake.exportMutex.RLock()
+	defer fake.exportMutex.RUnlock()
+	return fake.exportArgsForCall[i].span
+}
+
+func (fake *FakeExporter) ExportReturns(result1 error) {
+	fake.ExportStub = nil
+	fake.exportReturns = struct {
+		result1 error
+	}{result1}
+}
+
+func (fake *FakeExporter) Invocations() map[string][][]interface{} {
+	fake.invocationsMutex.RLock()
+	defer fake.invocationsMutex.RUnlock()
+	fake.exportMutex.RLock()
+	defer fake.exportMutex.RUnlock()
+	return fake.invocations
+}
+
+func (fake *FakeExporter) recordInvocation(key string, args []interface{}) {
+	fake.invocationsMutex.Lock()
+	defer fake.invocationsMutex.Unlock()
+	if fake.invocations == nil {
+		fake.invocations = map[string][][]interface{}{}
+	}
+	if fake.invocations[key] == nil {
+		fake.invocations[key] = [][]interface{}{}
+	}
+	fake.invocations[key] = append(fake.invocations[key], args)
+}
+
+var _ tracing.Exporter = new(FakeExporter)